@@ -0,0 +1,108 @@
+// CLAUDE:SUMMARY HTTP API error bodies {"error", "code"}: one table maps typed errors to an HTTP status and a stable machine-readable code, shared by every handler.
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hazyhaar/chrc/veille"
+	"github.com/hazyhaar/pkg/horosafe"
+)
+
+// apiError is the JSON body of every error response. Code is stable and
+// meant for clients to branch on; Error is the human message and may change.
+type apiError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// apiErrors maps typed errors to their HTTP status and code, first match
+// first: the horosafe errors come before ErrInvalidInput so a wrapped SSRF
+// rejection keeps its own code.
+var apiErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{veille.ErrDuplicateSource, http.StatusConflict, "duplicate_source"},
+	{veille.ErrHostDenied, http.StatusForbidden, "host_denied"},
+	{veille.ErrQuotaExceeded, http.StatusTooManyRequests, "quota_exceeded"},
+	{horosafe.ErrSSRF, http.StatusBadRequest, "ssrf_blocked"},
+	{horosafe.ErrUnsafeScheme, http.StatusBadRequest, "unsafe_scheme"},
+	{horosafe.ErrPathTraversal, http.StatusBadRequest, "path_traversal"},
+	{veille.ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+	{veille.ErrSourceTypeMismatch, http.StatusUnprocessableEntity, "source_type_mismatch"},
+	{veille.ErrCircuitOpen, http.StatusServiceUnavailable, "circuit_open"},
+	{veille.ErrBodyTooLarge, http.StatusBadGateway, "body_too_large"},
+	{veille.ErrSourceNotFound, http.StatusNotFound, "not_found"},
+	{veille.ErrExtractionNotFound, http.StatusNotFound, "not_found"},
+	{veille.ErrNoRawBody, http.StatusConflict, "no_raw_body"},
+	{veille.ErrBufferDisabled, http.StatusNotImplemented, "buffer_disabled"},
+	{veille.ErrVectorsDisabled, http.StatusNotImplemented, "vectors_disabled"},
+	{veille.ErrNoCatalog, http.StatusNotImplemented, "no_catalog"},
+	{veille.ErrClosed, http.StatusServiceUnavailable, "service_closed"},
+	{errRegistryNotFound, http.StatusNotFound, "not_found"},
+	{errUserNotFound, http.StatusNotFound, "not_found"},
+	{errWrongPassword, http.StatusForbidden, "wrong_password"},
+	{errWeakPassword, http.StatusBadRequest, "weak_password"},
+	{errRefreshInvalid, http.StatusUnauthorized, "refresh_invalid"},
+	{errRefreshReused, http.StatusUnauthorized, "refresh_reused"},
+}
+
+// statusCodes are the codes of errors without a typed mapping.
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          "bad_request",
+	http.StatusUnauthorized:        "unauthenticated",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusConflict:            "conflict",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusNotImplemented:      "not_implemented",
+	http.StatusBadGateway:          "upstream_error",
+	http.StatusServiceUnavailable:  "unavailable",
+	http.StatusInternalServerError: "internal",
+}
+
+// classifyError returns the HTTP status and code of err, or fallback and
+// its status code when err has no typed mapping.
+func classifyError(err error, fallback int) (int, string) {
+	for _, e := range apiErrors {
+		if errors.Is(err, e.err) {
+			return e.status, e.code
+		}
+	}
+	return fallback, statusCode(fallback)
+}
+
+// statusCode returns the generic code of an HTTP status.
+func statusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= 500 {
+		return "internal"
+	}
+	return "bad_request"
+}
+
+// writeError writes err with an explicit status. The code is err's typed
+// code when that maps to the same status, else the status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	code := statusCode(status)
+	if s, c := classifyError(err, status); s == status {
+		code = c
+	}
+	writeJSON(w, status, apiError{Error: err.Error(), Code: code})
+}
+
+// writeTypedError writes err with the status and code of its typed mapping,
+// or fallback for untyped errors.
+func writeTypedError(w http.ResponseWriter, fallback int, err error) {
+	status, code := classifyError(err, fallback)
+	writeJSON(w, status, apiError{Error: err.Error(), Code: code})
+}
+
+// writeErrorMessage writes a handler-built message with an explicit code.
+func writeErrorMessage(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, apiError{Error: msg, Code: code})
+}
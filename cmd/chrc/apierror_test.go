@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille"
+	"github.com/hazyhaar/pkg/horosafe"
+)
+
+func decodeAPIError(t *testing.T, rec *httptest.ResponseRecorder) apiError {
+	t.Helper()
+	var body apiError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func TestWriteTypedError_Mapping(t *testing.T) {
+	// WHAT: Each typed error, even wrapped, is written with its HTTP status, stable code and the original message.
+	// WHY: Clients branch on code; a handler must not be able to drift from the shared mapping.
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{veille.ErrDuplicateSource, 409, "duplicate_source"},
+		{veille.ErrHostDenied, 403, "host_denied"},
+		{veille.ErrQuotaExceeded, 429, "quota_exceeded"},
+		{horosafe.ErrSSRF, 400, "ssrf_blocked"},
+		{horosafe.ErrUnsafeScheme, 400, "unsafe_scheme"},
+		{horosafe.ErrPathTraversal, 400, "path_traversal"},
+		{veille.ErrInvalidInput, 400, "invalid_input"},
+		{veille.ErrSourceTypeMismatch, 422, "source_type_mismatch"},
+		{veille.ErrCircuitOpen, 503, "circuit_open"},
+		{veille.ErrBodyTooLarge, 502, "body_too_large"},
+		{veille.ErrSourceNotFound, 404, "not_found"},
+		{veille.ErrExtractionNotFound, 404, "not_found"},
+		{veille.ErrNoRawBody, 409, "no_raw_body"},
+		{veille.ErrBufferDisabled, 501, "buffer_disabled"},
+		{veille.ErrVectorsDisabled, 501, "vectors_disabled"},
+		{veille.ErrNoCatalog, 501, "no_catalog"},
+		{veille.ErrClosed, 503, "service_closed"},
+		{errRegistryNotFound, 404, "not_found"},
+		{errUserNotFound, 404, "not_found"},
+		{errWrongPassword, 403, "wrong_password"},
+		{errWeakPassword, 400, "weak_password"},
+		{errRefreshInvalid, 401, "refresh_invalid"},
+		{errRefreshReused, 401, "refresh_reused"},
+	}
+	for _, c := range cases {
+		err := fmt.Errorf("add source: %w", c.err)
+		rec := httptest.NewRecorder()
+		writeTypedError(rec, 500, err)
+		body := decodeAPIError(t, rec)
+		if rec.Code != c.status || body.Code != c.code {
+			t.Errorf("%v: got %d %q, want %d %q", c.err, rec.Code, body.Code, c.status, c.code)
+		}
+		if body.Error != err.Error() {
+			t.Errorf("%v: message %q", c.err, body.Error)
+		}
+	}
+}
+
+func TestWriteTypedError_Fallback(t *testing.T) {
+	// WHAT: Untyped errors get the fallback status and its generic code; writeError keeps its explicit status.
+	// WHY: Every error body carries a code, and an explicit status is never overridden by a mismatching typed code.
+	rec := httptest.NewRecorder()
+	writeTypedError(rec, 502, errors.New("upstream down"))
+	if body := decodeAPIError(t, rec); rec.Code != 502 || body.Code != "upstream_error" {
+		t.Errorf("fallback: got %d %q", rec.Code, body.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	writeError(rec, 400, fmt.Errorf("%w: bad tag", veille.ErrInvalidInput))
+	if body := decodeAPIError(t, rec); rec.Code != 400 || body.Code != "invalid_input" {
+		t.Errorf("typed explicit: got %d %q", rec.Code, body.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	writeError(rec, 500, veille.ErrDuplicateSource)
+	if body := decodeAPIError(t, rec); rec.Code != 500 || body.Code != "internal" {
+		t.Errorf("mismatching explicit: got %d %q", rec.Code, body.Code)
+	}
+}
+
+// shardPool resolves every dossier to one shard.
+type shardPool struct{ db *sql.DB }
+
+func (p shardPool) Resolve(context.Context, string) (*sql.DB, error) { return p.db, nil }
+
+func TestRestoreUnknownSource_NotFound(t *testing.T) {
+	// WHAT: Restoring an unknown source ID answers 404 not_found.
+	// WHY: A missing source is a client error, not a server failure.
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := veille.ApplySchema(db); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	svc, err := veille.New(shardPool{db}, nil, nil)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	writeTypedError(rec, 500, svc.RestoreSource(context.Background(), "d1", "nope"))
+	if body := decodeAPIError(t, rec); rec.Code != 404 || body.Code != "not_found" {
+		t.Errorf("restore unknown: got %d %q, want 404 not_found", rec.Code, body.Code)
+	}
+}
@@ -0,0 +1,163 @@
+// CLAUDE:SUMMARY Audit log — records chrc auth and user mutations with their actor; admin query filters audit_log (pkg/audit) by action, actor, dossier and time range, paginates by cursor, redacts secrets.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hazyhaar/pkg/audit"
+	"github.com/hazyhaar/pkg/redact"
+)
+
+const (
+	auditDefaultLimit = 100
+	auditMaxLimit     = 1000
+)
+
+var auditRedactor = redact.New(redact.Defaults())
+
+// auditSecretKey matches parameter names whose values are never returned.
+var auditSecretKey = regexp.MustCompile(`(?i)(pass(word)?|secret|token|api_?key|authorization|cookie|credential)`)
+
+// auditQuery filters audit_log. Zero fields do not filter. Times are Unix
+// milliseconds; before is the cursor (rowid) of the previous page. userID
+// is the acting user (audit_log.user_id); dossierID the dossier_id of the
+// entry's parameters.
+type auditQuery struct {
+	action    string
+	userID    string
+	dossierID string
+	since     int64
+	until     int64
+	before    int64
+	limit     int
+}
+
+type auditEntry struct {
+	ID         int64  `json:"id"`
+	Timestamp  int64  `json:"timestamp"`
+	Action     string `json:"action"`
+	UserID     string `json:"user_id"`
+	DossierID  string `json:"dossier_id,omitempty"`
+	Parameters string `json:"parameters"`
+}
+
+// auditDossier extracts the dossier_id of the parameters column; entries
+// whose parameters are not JSON have none.
+const auditDossier = `COALESCE(CASE WHEN json_valid(parameters) THEN json_extract(parameters, '$.dossier_id') END, '')`
+
+// logAudit records a chrc mutation performed by userID ("" if unknown).
+// params is marshalled as the entry's JSON parameters.
+func logAudit(l audit.Logger, userID, action string, params map[string]any) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		b = []byte("{}")
+	}
+	l.LogAsync(&audit.Entry{Action: action, UserID: userID, Parameters: string(b)})
+}
+
+// queryAudit returns matching entries, most recent first, and the cursor of
+// the next page ("" when this page is the last).
+func queryAudit(ctx context.Context, db *sql.DB, q auditQuery) ([]auditEntry, string, error) {
+	if q.limit <= 0 {
+		q.limit = auditDefaultLimit
+	}
+	q.limit = min(q.limit, auditMaxLimit)
+
+	var where []string
+	var args []any
+	if q.action != "" {
+		where, args = append(where, "action = ?"), append(args, q.action)
+	}
+	if q.userID != "" {
+		where, args = append(where, "user_id = ?"), append(args, q.userID)
+	}
+	if q.dossierID != "" {
+		where, args = append(where, auditDossier+" = ?"), append(args, q.dossierID)
+	}
+	if q.since > 0 {
+		where, args = append(where, "timestamp >= ?"), append(args, q.since)
+	}
+	if q.until > 0 {
+		where, args = append(where, "timestamp < ?"), append(args, q.until)
+	}
+	if q.before > 0 {
+		where, args = append(where, "rowid < ?"), append(args, q.before)
+	}
+	query := `SELECT rowid, timestamp, action, COALESCE(user_id, ''), ` + auditDossier + `, COALESCE(parameters, '') FROM audit_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY rowid DESC LIMIT ?"
+	args = append(args, q.limit+1)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("query audit: %w", err)
+	}
+	defer rows.Close()
+	entries := []auditEntry{}
+	for rows.Next() {
+		var e auditEntry
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Action, &e.UserID, &e.DossierID, &e.Parameters); err != nil {
+			return nil, "", err
+		}
+		e.Parameters = redactAuditParams(e.Parameters)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if len(entries) > q.limit {
+		entries = entries[:q.limit]
+		next = strconv.FormatInt(entries[len(entries)-1].ID, 10)
+	}
+	return entries, next, nil
+}
+
+// redactAuditParams masks the values of secret-looking keys of a JSON
+// object, then scrubs known secret patterns (tokens, keys) from the rest.
+func redactAuditParams(params string) string {
+	var obj map[string]any
+	if json.Unmarshal([]byte(params), &obj) == nil {
+		maskSecrets(obj)
+		if b, err := json.Marshal(obj); err == nil {
+			params = string(b)
+		}
+	}
+	return auditRedactor.Sanitize(params)
+}
+
+func maskSecrets(obj map[string]any) {
+	for k, v := range obj {
+		if auditSecretKey.MatchString(k) {
+			obj[k] = "***"
+			continue
+		}
+		if nested, ok := v.(map[string]any); ok {
+			maskSecrets(nested)
+		}
+	}
+}
+
+// parseAuditTime accepts RFC 3339 or Unix milliseconds. "" is 0.
+func parseAuditTime(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ms, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("date invalide %q (RFC 3339 ou millisecondes Unix)", s)
+	}
+	return t.UnixMilli(), nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille"
+	"github.com/hazyhaar/pkg/audit"
+)
+
+func openAuditDB(t *testing.T, entries ...*audit.Entry) *sql.DB {
+	t.Helper()
+	db, logger := newAuditLogger(t)
+	for _, e := range entries {
+		logger.LogAsync(e)
+	}
+	logger.Close() // flushes pending entries
+	return db
+}
+
+// newAuditLogger opens a catalog DB with an initialized audit logger.
+func newAuditLogger(t *testing.T) (*sql.DB, *audit.SQLiteLogger) {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "catalog.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	logger := audit.NewSQLiteLogger(db)
+	if err := logger.Init(); err != nil {
+		t.Fatalf("audit init: %v", err)
+	}
+	return db, logger
+}
+
+func TestQueryAudit_Filters(t *testing.T) {
+	// WHAT: Mutations made by two users (veille service calls and a chrc user mutation) come back filtered by actor, dossier and action, newest first, paginated.
+	// WHY: Admins investigate "who changed what" through the API; the filters must match what is actually recorded.
+	catalog, logger := newAuditLogger(t)
+	shard, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open shard: %v", err)
+	}
+	shard.SetMaxOpenConns(1)
+	t.Cleanup(func() { shard.Close() })
+	if err := veille.ApplySchema(shard); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	svc, err := veille.New(shardPool{shard}, nil, nil,
+		veille.WithAudit(logger), veille.WithURLValidator(func(string) error { return nil }))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	alice := veille.WithActor(context.Background(), "u-alice")
+	bob := veille.WithActor(context.Background(), "u-bob")
+	s1 := &veille.Source{Name: "One", URL: "https://one.example", SourceType: "web", Enabled: true}
+	s2 := &veille.Source{Name: "Two", URL: "https://two.example", SourceType: "web", Enabled: true}
+	if err := svc.AddSource(alice, "d1", s1); err != nil {
+		t.Fatalf("add s1: %v", err)
+	}
+	if err := svc.AddSource(bob, "d2", s2); err != nil {
+		t.Fatalf("add s2: %v", err)
+	}
+	if err := svc.DeleteSource(alice, "d1", s1.ID); err != nil {
+		t.Fatalf("delete s1: %v", err)
+	}
+	logAudit(logger, veille.ActorFrom(bob), "create_user", map[string]any{"target_user_id": "u-carol", "role": "user"})
+	logger.Close() // flushes pending entries
+	ctx := context.Background()
+
+	actions := func(entries []auditEntry) string {
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Action)
+		}
+		return strings.Join(got, ",")
+	}
+	byAlice, _, err := queryAudit(ctx, catalog, auditQuery{userID: "u-alice"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if actions(byAlice) != "delete_source,add_source" || byAlice[0].DossierID != "d1" {
+		t.Errorf("user u-alice: %+v", byAlice)
+	}
+	byBob, _, err := queryAudit(ctx, catalog, auditQuery{userID: "u-bob"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if actions(byBob) != "create_user,add_source" || byBob[1].DossierID != "d2" {
+		t.Errorf("user u-bob: %+v", byBob)
+	}
+
+	adds, _, err := queryAudit(ctx, catalog, auditQuery{action: "add_source"})
+	if err != nil || len(adds) != 2 || adds[0].UserID != "u-bob" {
+		t.Errorf("action filter: %+v, %v", adds, err)
+	}
+	d1, _, err := queryAudit(ctx, catalog, auditQuery{dossierID: "d1"})
+	if err != nil || actions(d1) != "delete_source,add_source" || !strings.Contains(d1[0].Parameters, s1.ID) {
+		t.Errorf("dossier filter: %+v, %v", d1, err)
+	}
+
+	page, next, err := queryAudit(ctx, catalog, auditQuery{limit: 3})
+	if err != nil || len(page) != 3 || next == "" {
+		t.Fatalf("page 1: %d entries, next %q, err %v", len(page), next, err)
+	}
+	rest, next, err := queryAudit(ctx, catalog, auditQuery{limit: 3, before: page[2].ID})
+	if err != nil || len(rest) != 1 || next != "" {
+		t.Errorf("page 2: %d entries, next %q, err %v", len(rest), next, err)
+	}
+
+	future, _, err := queryAudit(ctx, catalog, auditQuery{since: page[0].Timestamp + 60_000})
+	if err != nil || len(future) != 0 {
+		t.Errorf("since filter: %d entries, err %v", len(future), err)
+	}
+}
+
+func TestQueryAudit_RedactsSecrets(t *testing.T) {
+	db := openAuditDB(t, &audit.Entry{Action: "update_source", UserID: "d1",
+		Parameters: `{"source_id":"s1","config":{"password":"hunter2","api_key":"abc"}}`})
+
+	entries, _, err := queryAudit(context.Background(), db, auditQuery{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("query: %d entries, err %v", len(entries), err)
+	}
+	p := entries[0].Parameters
+	if strings.Contains(p, "hunter2") || strings.Contains(p, "abc") || !strings.Contains(p, "s1") {
+		t.Errorf("parameters not redacted: %s", p)
+	}
+}
+
+func TestParseAuditTime(t *testing.T) {
+	if ms, err := parseAuditTime("2026-01-02T00:00:00Z"); err != nil || ms != 1767312000000 {
+		t.Errorf("RFC 3339: %d, %v", ms, err)
+	}
+	if ms, err := parseAuditTime("1767312000000"); err != nil || ms != 1767312000000 {
+		t.Errorf("millis: %d, %v", ms, err)
+	}
+	if _, err := parseAuditTime("yesterday"); err == nil {
+		t.Error("expected error")
+	}
+}
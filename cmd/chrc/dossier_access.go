@@ -0,0 +1,131 @@
+// CLAUDE:SUMMARY Dossier ownership — non-admin users only list and reach the shards they own (others answer 404), and the cross-dossier search scope of a session.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hazyhaar/chrc/veille"
+	"github.com/hazyhaar/pkg/auth"
+)
+
+// requireDossierAccess answers 404 when the route's {dossierID} is not an
+// active shard owned by the caller, so other tenants' dossiers cannot be
+// enumerated. Admins reach every dossier; routes without {dossierID} pass.
+func requireDossierAccess(db *sql.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			c := auth.GetClaims(r.Context())
+			if dossierID == "" || (c != nil && c.Role == "admin") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var owner string
+			err := db.QueryRowContext(r.Context(),
+				`SELECT owner_id FROM shards WHERE id = ? AND status = 'active'`, dossierID).Scan(&owner)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if err != nil || c == nil || owner != c.UserID {
+				writeErrorMessage(w, 404, "not_found", "dossier introuvable")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// sessionScope is the cross-dossier search scope of c, same rule as
+// listDossiers.
+func sessionScope(c *auth.HorosClaims) veille.SearchScope {
+	return veille.SearchScope{OwnerID: c.UserID, Admin: c.Role == "admin"}
+}
+
+// searchScopeMiddleware attaches the session's dossier scope and audit actor
+// to gateway requests carrying valid claims; anonymous calls are refused by
+// veille_search_all. MCP calls get theirs from mcpAuthenticator.
+func searchScopeMiddleware(policy tokenPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c := auth.GetClaims(r.Context()); c != nil && policy.check(c) == nil {
+			ctx := veille.WithSearchScope(r.Context(), sessionScope(c))
+			r = r.WithContext(veille.WithActor(ctx, c.UserID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mcpAuthenticator verifies the bearer token of an MCP call like a session
+// cookie — signature and expiry by auth.Middleware, then policy — and
+// returns its user with the session's search scope.
+func mcpAuthenticator(secret []byte, policy tokenPolicy) veille.MCPAuthenticator {
+	verify := auth.Middleware(secret)
+	return func(ctx context.Context, token string) (veille.MCPCaller, error) {
+		var cookie headerRecorder
+		auth.SetTokenCookie(&cookie, token, "", false)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/mcp", nil)
+		if err != nil {
+			return veille.MCPCaller{}, err
+		}
+		for _, ck := range (&http.Response{Header: cookie.Header()}).Cookies() {
+			req.AddCookie(ck)
+		}
+		var c *auth.HorosClaims
+		verify(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			c = auth.GetClaims(r.Context())
+		})).ServeHTTP(&headerRecorder{}, req)
+		if c == nil {
+			return veille.MCPCaller{}, errInvalidToken
+		}
+		if err := policy.check(c); err != nil {
+			return veille.MCPCaller{}, err
+		}
+		return veille.MCPCaller{UserID: c.UserID, Scope: sessionScope(c)}, nil
+	}
+}
+
+// headerRecorder is a ResponseWriter that keeps headers and drops the body,
+// for auth helpers run outside an HTTP exchange.
+type headerRecorder struct{ h http.Header }
+
+func (w *headerRecorder) Header() http.Header {
+	if w.h == nil {
+		w.h = http.Header{}
+	}
+	return w.h
+}
+func (w *headerRecorder) Write(b []byte) (int, error) { return len(b), nil }
+func (w *headerRecorder) WriteHeader(int)             {}
+
+// listDossiers returns the active dossiers visible to c: all of them for an
+// admin, the ones c owns otherwise.
+func listDossiers(ctx context.Context, db *sql.DB, c *auth.HorosClaims) ([]map[string]string, error) {
+	query := `SELECT id, name FROM shards WHERE status = 'active' ORDER BY name`
+	var args []any
+	if c == nil || c.Role != "admin" {
+		userID := ""
+		if c != nil {
+			userID = c.UserID
+		}
+		query = `SELECT id, name FROM shards WHERE status = 'active' AND owner_id = ? AND owner_id != '' ORDER BY name`
+		args = append(args, userID)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	dossiers := []map[string]string{}
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		dossiers = append(dossiers, map[string]string{"id": id, "name": name})
+	}
+	return dossiers, rows.Err()
+}
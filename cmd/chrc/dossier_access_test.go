@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hazyhaar/pkg/auth"
+)
+
+func openShardsDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE shards (
+		id TEXT PRIMARY KEY, owner_id TEXT NOT NULL DEFAULT '', name TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'active')`); err != nil {
+		t.Fatalf("shards table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO shards (id, owner_id, name) VALUES
+		('dA', 'userA', 'Dossier A'), ('dB', 'userB', 'Dossier B')`); err != nil {
+		t.Fatalf("seed shards: %v", err)
+	}
+	return db
+}
+
+func TestRequireDossierAccess(t *testing.T) {
+	// WHAT: User A reads their dossier's sources but gets 404 on user B's; an admin reaches both.
+	// WHY: Dossier IDs must not let one tenant read or even detect another tenant's data.
+	db := openShardsDB(t)
+	r := chi.NewRouter()
+	r.Use(auth.Middleware(testSecret[:]))
+	r.Group(func(r chi.Router) {
+		r.Use(requireSession(tokenPolicy{}))
+		r.Use(requireDossierAccess(db))
+		r.Get("/api/dossiers/{dossierID}/sources", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, 200, []string{"source"})
+		})
+	})
+
+	get := func(claims *auth.HorosClaims, dossierID string) int {
+		req := httptest.NewRequest("GET", "/api/dossiers/"+dossierID+"/sources", nil)
+		for _, c := range sessionCookies(t, claims) {
+			req.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	userA := &auth.HorosClaims{UserID: "userA", Role: "user"}
+	admin := &auth.HorosClaims{UserID: "root", Role: "admin"}
+
+	if code := get(userA, "dA"); code != 200 {
+		t.Errorf("A on own dossier: got %d, want 200", code)
+	}
+	if code := get(userA, "dB"); code != 404 {
+		t.Errorf("A on B's dossier: got %d, want 404", code)
+	}
+	if code := get(userA, "missing"); code != 404 {
+		t.Errorf("A on unknown dossier: got %d, want 404", code)
+	}
+	if code := get(admin, "dB"); code != 200 {
+		t.Errorf("admin on B's dossier: got %d, want 200", code)
+	}
+}
+
+func TestListDossiers_ScopedToOwner(t *testing.T) {
+	db := openShardsDB(t)
+	ctx := context.Background()
+
+	got, err := listDossiers(ctx, db, &auth.HorosClaims{UserID: "userA", Role: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0]["id"] != "dA" {
+		t.Errorf("user A sees %v, want only dA", got)
+	}
+	all, err := listDossiers(ctx, db, &auth.HorosClaims{UserID: "root", Role: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("admin sees %d dossiers, want 2", len(all))
+	}
+}
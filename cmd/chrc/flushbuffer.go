@@ -0,0 +1,29 @@
+// CLAUDE:SUMMARY One-shot buffer replay (-flush-buffer): re-inserts BUFFER_DIR files into their dossier shards and prints the JSON report.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hazyhaar/chrc/veille"
+)
+
+// runFlushBuffer flushes the buffer once and fails if any file was left in
+// place or quarantined.
+func runFlushBuffer(ctx context.Context, svc *veille.Service, out io.Writer) error {
+	rep, err := svc.FlushBuffer(ctx)
+	if err != nil {
+		return fmt.Errorf("flush buffer: %w", err)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rep); err != nil {
+		return err
+	}
+	if rep.Failed > 0 || rep.Quarantined > 0 {
+		return fmt.Errorf("flush buffer: %d failed, %d quarantined of %d files", rep.Failed, rep.Quarantined, rep.Scanned)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// CLAUDE:SUMMARY Login brute-force guard — sliding window of failed logins per IP and per email, exponential lockout, reset on success.
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loginLimiter counts failed logins per key (IP, email) over a sliding
+// window. Reaching max failures locks the key for lockout, doubled at each
+// new lockout up to maxLockout. A successful login resets the key.
+// Counters live in memory: each instance throttles on its own.
+type loginLimiter struct {
+	max        int
+	window     time.Duration
+	lockout    time.Duration
+	maxLockout time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*loginEntry
+}
+
+type loginEntry struct {
+	failures    []time.Time // within window, oldest first
+	lockouts    int
+	lockedUntil time.Time
+	lastSeen    time.Time
+}
+
+func newLoginLimiter(max int, window, lockout, maxLockout time.Duration) *loginLimiter {
+	return &loginLimiter{
+		max:        max,
+		window:     window,
+		lockout:    lockout,
+		maxLockout: maxLockout,
+		now:        time.Now,
+		entries:    make(map[string]*loginEntry),
+	}
+}
+
+// allow reports whether every key may attempt a login, and otherwise how
+// long until the longest lockout ends.
+func (l *loginLimiter) allow(keys ...string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	var wait time.Duration
+	for _, k := range keys {
+		if e := l.entries[k]; e != nil && e.lockedUntil.After(now) {
+			wait = max(wait, e.lockedUntil.Sub(now))
+		}
+	}
+	return wait, wait == 0
+}
+
+// fail records a failed login for every key.
+func (l *loginLimiter) fail(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for _, k := range keys {
+		e := l.entries[k]
+		if e == nil {
+			e = &loginEntry{}
+			l.entries[k] = e
+		}
+		e.lastSeen = now
+		e.failures = append(pruneBefore(e.failures, now.Add(-l.window)), now)
+		if len(e.failures) < l.max {
+			continue
+		}
+		e.lockouts++
+		d := l.lockout << (e.lockouts - 1)
+		if d > l.maxLockout || d <= 0 {
+			d = l.maxLockout
+		}
+		e.lockedUntil = now.Add(d)
+		e.failures = nil
+	}
+}
+
+// success resets every key.
+func (l *loginLimiter) success(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, k := range keys {
+		delete(l.entries, k)
+	}
+}
+
+// cleanup drops keys that are not locked and have been idle for longer than
+// both the window and the maximum lockout.
+func (l *loginLimiter) cleanup() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	idle := max(l.window, l.maxLockout)
+	for k, e := range l.entries {
+		if !e.lockedUntil.After(now) && now.Sub(e.lastSeen) > idle {
+			delete(l.entries, k)
+		}
+	}
+}
+
+// run calls cleanup every interval until ctx is done.
+func (l *loginLimiter) run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			l.cleanup()
+		}
+	}
+}
+
+func pruneBefore(ts []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(ts) && !ts[i].After(cutoff) {
+		i++
+	}
+	return ts[i:]
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestLoginLimiter_LockoutAndReset(t *testing.T) {
+	// WHAT: N failures lock the key with a doubling lockout; a successful login resets it.
+	// WHY: Password guessing must slow down exponentially without locking out the real user for good.
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	l := newLoginLimiter(3, 10*time.Minute, time.Minute, 10*time.Minute)
+	l.now = clock.now
+	keys := []string{"ip:192.0.2.1", "email:a@example.com"}
+
+	for i := 0; i < 2; i++ {
+		l.fail(keys...)
+	}
+	if _, ok := l.allow(keys...); !ok {
+		t.Fatal("locked before reaching the limit")
+	}
+	l.fail(keys...)
+	wait, ok := l.allow(keys...)
+	if ok || wait != time.Minute {
+		t.Fatalf("after 3 failures: ok=%v wait=%v, want locked for 1m", ok, wait)
+	}
+
+	// Second lockout doubles.
+	clock.advance(time.Minute)
+	for i := 0; i < 3; i++ {
+		l.fail(keys...)
+	}
+	if wait, _ := l.allow(keys...); wait != 2*time.Minute {
+		t.Errorf("second lockout: wait=%v, want 2m", wait)
+	}
+
+	// The other keys are not affected; success resets.
+	if _, ok := l.allow("ip:192.0.2.2"); !ok {
+		t.Error("unrelated IP locked")
+	}
+	l.success(keys...)
+	if _, ok := l.allow(keys...); !ok {
+		t.Error("still locked after success")
+	}
+	l.fail(keys...)
+	if _, ok := l.allow(keys...); !ok {
+		t.Error("one failure after reset must not lock")
+	}
+}
+
+func TestLoginLimiter_SlidingWindow(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	l := newLoginLimiter(3, 10*time.Minute, time.Minute, time.Hour)
+	l.now = clock.now
+
+	l.fail("k")
+	l.fail("k")
+	clock.advance(11 * time.Minute) // both failures leave the window
+	l.fail("k")
+	if _, ok := l.allow("k"); !ok {
+		t.Error("expired failures still counted")
+	}
+
+	clock.advance(2 * time.Hour)
+	l.cleanup()
+	if len(l.entries) != 0 {
+		t.Errorf("cleanup kept %d idle entries", len(l.entries))
+	}
+}
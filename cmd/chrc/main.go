@@ -9,15 +9,19 @@ import (
 	"embed"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/hazyhaar/chrc/veille"
@@ -26,7 +30,6 @@ import (
 	"github.com/hazyhaar/pkg/auth"
 	"github.com/hazyhaar/pkg/connectivity"
 	"github.com/hazyhaar/pkg/dbopen"
-	"github.com/hazyhaar/pkg/horosafe"
 	"github.com/hazyhaar/pkg/idgen"
 	"github.com/hazyhaar/pkg/ratelimit"
 	"github.com/hazyhaar/pkg/redact"
@@ -35,6 +38,8 @@ import (
 	"github.com/hazyhaar/pkg/trace"
 	tenant "github.com/hazyhaar/usertenant"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/crypto/bcrypt"
 	_ "modernc.org/sqlite"
 )
@@ -48,13 +53,22 @@ var engineRedactor = redact.New(
 )
 
 func main() {
-	if err := run(); err != nil {
+	checkFTS := flag.String("check-fts", "", `check the FTS index of a dossier ID (or "all") and exit`)
+	reindex := flag.String("reindex", "", `rebuild the FTS index of a dossier ID (or "all") and exit`)
+	flushBuffer := flag.Bool("flush-buffer", false, "replay BUFFER_DIR files into their dossier shards and exit")
+	flag.Parse()
+
+	cmd := ftsCommand{target: *checkFTS}
+	if *reindex != "" {
+		cmd = ftsCommand{target: *reindex, rebuild: true}
+	}
+	if err := run(cmd, *flushBuffer); err != nil {
 		slog.Error("fatal", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func run(fts ftsCommand, flushBuffer bool) error {
 	port := env("PORT", "8085")
 	secretInput := os.Getenv("SESSION_SECRET")
 	if secretInput == "" {
@@ -72,6 +86,75 @@ func run() error {
 	bufferDir := env("BUFFER_DIR", "buffer/pending")
 	mcpTransport := env("MCP_TRANSPORT", "")
 	logLevel := env("LOG_LEVEL", "info")
+	metricsEnabled := env("METRICS_ENABLED", "") == "1"
+	dedupContent := env("DEDUP_CONTENT", "") == "1"
+	retainRawBodies := env("RETAIN_RAW_BODIES", "") == "1"
+	rawBodiesPerSource, err := strconv.Atoi(env("RAW_BODIES_PER_SOURCE", "3"))
+	if err != nil || rawBodiesPerSource <= 0 {
+		return fmt.Errorf("RAW_BODIES_PER_SOURCE: want a positive integer, got %q", os.Getenv("RAW_BODIES_PER_SOURCE"))
+	}
+	typeMismatch := veille.SourceTypePolicy(env("SOURCE_TYPE_MISMATCH", string(veille.SourceTypeWarn)))
+	if typeMismatch != veille.SourceTypeWarn && typeMismatch != veille.SourceTypeCorrect && typeMismatch != veille.SourceTypeReject {
+		return fmt.Errorf("SOURCE_TYPE_MISMATCH: unknown policy %q (want warn, correct or reject)", typeMismatch)
+	}
+	schedPolicy := veille.SchedulerPolicy(env("SCHEDULER_POLICY", string(veille.SchedulerFair)))
+	if schedPolicy != veille.SchedulerFair && schedPolicy != veille.SchedulerFIFO {
+		return fmt.Errorf("SCHEDULER_POLICY: unknown policy %q (want fair or fifo)", schedPolicy)
+	}
+	schedMaxJobs, err := strconv.Atoi(env("SCHEDULER_MAX_JOBS", "0"))
+	if err != nil || schedMaxJobs < 0 {
+		return fmt.Errorf("SCHEDULER_MAX_JOBS: want a non-negative integer, got %q", os.Getenv("SCHEDULER_MAX_JOBS"))
+	}
+	schedWorkers, err := strconv.Atoi(env("SCHEDULER_WORKERS", "1"))
+	if err != nil || schedWorkers <= 0 {
+		return fmt.Errorf("SCHEDULER_WORKERS: want a positive integer, got %q", os.Getenv("SCHEDULER_WORKERS"))
+	}
+	schedMaxPerDossier, err := strconv.Atoi(env("SCHEDULER_MAX_PER_DOSSIER", "0"))
+	if err != nil || schedMaxPerDossier < 0 {
+		return fmt.Errorf("SCHEDULER_MAX_PER_DOSSIER: want a non-negative integer, got %q", os.Getenv("SCHEDULER_MAX_PER_DOSSIER"))
+	}
+	fetchMaxBytes, err := strconv.ParseInt(env("FETCH_MAX_BYTES", "10485760"), 10, 64)
+	if err != nil || fetchMaxBytes <= 0 {
+		return fmt.Errorf("FETCH_MAX_BYTES: want a positive integer, got %q", os.Getenv("FETCH_MAX_BYTES"))
+	}
+	healthCooldown, err := time.ParseDuration(env("HEALTH_NOTIFY_COOLDOWN", "1h"))
+	if err != nil || healthCooldown <= 0 {
+		return fmt.Errorf("HEALTH_NOTIFY_COOLDOWN: want a positive duration, got %q", os.Getenv("HEALTH_NOTIFY_COOLDOWN"))
+	}
+	bcryptCost, err := strconv.Atoi(env("BCRYPT_COST", strconv.Itoa(bcrypt.DefaultCost)))
+	if err != nil || bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		return fmt.Errorf("BCRYPT_COST: want an integer in [%d, %d], got %q",
+			bcrypt.MinCost, bcrypt.MaxCost, os.Getenv("BCRYPT_COST"))
+	}
+
+	accessTTL, err := time.ParseDuration(env("ACCESS_TOKEN_TTL", "15m"))
+	if err != nil || accessTTL <= 0 {
+		return fmt.Errorf("ACCESS_TOKEN_TTL: want a positive duration, got %q", os.Getenv("ACCESS_TOKEN_TTL"))
+	}
+	refreshTTL, err := time.ParseDuration(env("REFRESH_TOKEN_TTL", "720h"))
+	if err != nil || refreshTTL <= 0 {
+		return fmt.Errorf("REFRESH_TOKEN_TTL: want a positive duration, got %q", os.Getenv("REFRESH_TOKEN_TTL"))
+	}
+
+	loginMaxFailures, err := strconv.Atoi(env("LOGIN_MAX_FAILURES", "5"))
+	if err != nil || loginMaxFailures <= 0 {
+		return fmt.Errorf("LOGIN_MAX_FAILURES: want a positive integer, got %q", os.Getenv("LOGIN_MAX_FAILURES"))
+	}
+	var loginWindow, loginLockout, loginLockoutMax time.Duration
+	for _, d := range []struct {
+		key, def string
+		dst      *time.Duration
+	}{
+		{"LOGIN_WINDOW", "15m", &loginWindow},
+		{"LOGIN_LOCKOUT", "1m", &loginLockout},
+		{"LOGIN_LOCKOUT_MAX", "1h", &loginLockoutMax},
+	} {
+		v, err := time.ParseDuration(env(d.key, d.def))
+		if err != nil || v <= 0 {
+			return fmt.Errorf("%s: want a positive duration, got %q", d.key, os.Getenv(d.key))
+		}
+		*d.dst = v
+	}
 
 	// Logging.
 	var lvl slog.Level
@@ -121,6 +204,9 @@ func run() error {
 	if err := migrateAuthColumns(catalogDB); err != nil {
 		return fmt.Errorf("migrate auth columns: %w", err)
 	}
+	if err := migrateRefreshTokens(catalogDB); err != nil {
+		return fmt.Errorf("migrate refresh tokens: %w", err)
+	}
 
 	// Global admin tables (engines + source registry).
 	if err := migrateGlobalTables(catalogDB); err != nil {
@@ -148,7 +234,7 @@ func run() error {
 	limiter.StartReloader(ctx)
 
 	// Seed admin user if no admin exists.
-	if err := seedAdmin(ctx, catalogDB); err != nil {
+	if err := seedAdmin(ctx, catalogDB, bcryptCost); err != nil {
 		return fmt.Errorf("seed admin: %w", err)
 	}
 
@@ -170,26 +256,76 @@ func run() error {
 	router.RegisterLocal("github_fetch", veille.NewGitHubService(""))
 	router.RegisterLocal("api_fetch", veille.NewAPIService())
 
+	// Prometheus metrics (opt-in via METRICS_ENABLED=1).
+	svcOpts := []veille.ServiceOption{veille.WithCatalogDB(catalogDB), veille.WithRouter(router), veille.WithAudit(auditLogger)}
+	var metricsReg *prometheus.Registry
+	if metricsEnabled {
+		metricsReg = prometheus.NewRegistry()
+		m, err := veille.NewPrometheusMetrics(metricsReg)
+		if err != nil {
+			return fmt.Errorf("metrics: %w", err)
+		}
+		svcOpts = append(svcOpts, veille.WithMetrics(m))
+	}
+
 	// Veille service.
-	svc, err := veille.New(pool, &veille.Config{
-		DataDir:   dataDir,
-		BufferDir: bufferDir,
-	}, logger, veille.WithCatalogDB(catalogDB), veille.WithRouter(router), veille.WithAudit(auditLogger))
+	veilleCfg := &veille.Config{
+		DataDir:      dataDir,
+		BufferDir:    bufferDir,
+		DedupContent: dedupContent,
+
+		RetainRawBodies:    retainRawBodies,
+		RawBodiesPerSource: rawBodiesPerSource,
+		SourceTypeMismatch: typeMismatch,
+
+		HealthWebhookURL:     os.Getenv("HEALTH_WEBHOOK_URL"),
+		HealthWebhookSecret:  os.Getenv("HEALTH_WEBHOOK_SECRET"),
+		HealthNotifyCooldown: healthCooldown,
+	}
+	veilleCfg.Scheduler.Policy = schedPolicy
+	veilleCfg.Scheduler.MaxJobsPerTick = schedMaxJobs
+	veilleCfg.Scheduler.Workers = schedWorkers
+	veilleCfg.Scheduler.MaxPerDossier = schedMaxPerDossier
+	veilleCfg.Fetch.MaxResponseBytes = fetchMaxBytes
+	veilleCfg.Fetch.UserAgent = env("FETCH_USER_AGENT", "chrc-veille/1.0")
+	veilleCfg.Fetch.AllowHosts = envList("FETCH_ALLOW_HOSTS")
+	veilleCfg.Fetch.DenyHosts = envList("FETCH_DENY_HOSTS")
+	veilleCfg.Fetch.Proxy = os.Getenv("FETCH_PROXY")
+	veilleCfg.Fetch.AllowPrivateProxy = env("FETCH_PROXY_PRIVATE", "") == "1"
+	svc, err := veille.New(pool, veilleCfg, logger, svcOpts...)
 	if err != nil {
 		return fmt.Errorf("veille service: %w", err)
 	}
-	defer svc.Close()
+	defer func() {
+		// Drains in-flight fetches before the pool and catalog close.
+		if err := svc.Close(); err != nil {
+			slog.Error("veille close", "error", err)
+		}
+	}()
+
+	// One-shot: FTS check / rebuild.
+	if fts.target != "" {
+		return fts.run(ctx, catalogDB, svc, os.Stdout)
+	}
+	// One-shot: buffer replay.
+	if flushBuffer {
+		return runFlushBuffer(ctx, svc, os.Stdout)
+	}
 
 	// Register veille handlers on connectivity router (serves Gateway + local calls).
 	svc.RegisterConnectivity(router)
 
-	// Optional MCP QUIC.
+	tokens := tokenPolicyFromEnv()
+
+	// Optional MCP QUIC. Calls carrying a session JWT in _meta.authorization
+	// run as that user (audit actor, veille_search_all scope).
 	if mcpTransport == "quic" {
 		mcpSrv := mcp.NewServer(&mcp.Implementation{
 			Name:    "veille",
 			Version: "1.0.0",
 		}, nil)
 		svc.RegisterMCP(mcpSrv)
+		mcpSrv.AddReceivingMiddleware(veille.MCPAuthMiddleware(mcpAuthenticator(jwtSecret, tokens)))
 
 		quicAddr := env("MCP_QUIC_ADDR", ":9444")
 		certFile := env("TLS_CERT", "")
@@ -222,7 +358,10 @@ func run() error {
 	svc.Start(ctx)
 
 	// User service (DB operations for auth).
-	users := &userService{db: catalogDB, pool: pool}
+	users := &userService{db: catalogDB, pool: pool, bcryptCost: bcryptCost}
+	refresh := &refreshService{db: catalogDB, ttl: refreshTTL}
+	loginLimits := newLoginLimiter(loginMaxFailures, loginWindow, loginLockout, loginLockoutMax)
+	go loginLimits.run(ctx, time.Minute)
 
 	// Router.
 	r := chi.NewRouter()
@@ -235,8 +374,13 @@ func run() error {
 		writeJSON(w, 200, map[string]string{"status": "ok"})
 	})
 
+	if metricsReg != nil {
+		r.Handle("/metrics", promhttp.HandlerFor(metricsReg, promhttp.HandlerOpts{}))
+	}
+
 	// Connectivity gateway — expose local handlers over HTTP for cross-process calls.
-	r.Mount("/connectivity", http.StripPrefix("/connectivity", router.Gateway()))
+	// veille_search_all only answers callers with a valid session.
+	r.Mount("/connectivity", searchScopeMiddleware(tokens, http.StripPrefix("/connectivity", router.Gateway())))
 
 	// Public auth endpoints (no session required).
 	loginRL := limiter.HTTPMiddleware(5, time.Minute)
@@ -249,23 +393,90 @@ func run() error {
 			writeError(w, 400, err)
 			return
 		}
+		// Failed attempts are counted per IP and per email; success resets both.
+		limitKeys := []string{"ip:" + clientIP(r), "email:" + strings.ToLower(req.Email)}
+		if wait, ok := loginLimits.allow(limitKeys...); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			writeErrorMessage(w, 429, "rate_limited", "trop de tentatives, reessayer plus tard")
+			return
+		}
 		claims, err := users.authenticate(r.Context(), req.Email, req.Password)
 		if err != nil {
-			writeJSON(w, 401, map[string]string{"error": "identifiants invalides"})
+			loginLimits.fail(limitKeys...)
+			logAudit(auditLogger, "", "login_failed", map[string]any{"email": req.Email, "ip": clientIP(r)})
+			writeErrorMessage(w, 401, "invalid_credentials", "identifiants invalides")
+			return
+		}
+		loginLimits.success(limitKeys...)
+		logAudit(auditLogger, claims.UserID, "login", map[string]any{"ip": clientIP(r)})
+		tokens.stamp(claims)
+		token, err := auth.GenerateToken(jwtSecret, claims, accessTTL)
+		if err != nil {
+			writeTypedError(w, 500, err)
+			return
+		}
+		refreshToken, err := refresh.issue(r.Context(), claims.UserID, deviceOf(r))
+		if err != nil {
+			writeTypedError(w, 500, err)
+			return
+		}
+		secure := r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+		auth.SetTokenCookie(w, token, "", secure)
+		setRefreshCookie(w, refreshToken, refreshTTL, secure)
+		writeJSON(w, 200, map[string]string{"id": claims.UserID, "name": claims.Username, "role": claims.Role})
+	})
+
+	// Refresh: rotates the refresh token and issues a new access token.
+	// Reusing an already rotated token revokes its whole family.
+	r.Post("/api/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(refreshCookie)
+		if err != nil {
+			writeError(w, 401, errRefreshInvalid)
+			return
+		}
+		userID, next, err := refresh.rotate(r.Context(), cookie.Value, deviceOf(r))
+		if err != nil {
+			if errors.Is(err, errRefreshReused) {
+				slog.Warn("refresh token reuse, family revoked", "user_id", userID, "ip", r.RemoteAddr)
+			}
+			if errors.Is(err, errRefreshInvalid) || errors.Is(err, errRefreshReused) {
+				clearRefreshCookie(w)
+				writeError(w, 401, err)
+				return
+			}
+			writeTypedError(w, 500, err)
+			return
+		}
+		claims, err := users.claims(r.Context(), userID)
+		if err != nil {
+			_ = refresh.revokeUser(r.Context(), userID)
+			clearRefreshCookie(w)
+			writeErrorMessage(w, 401, "unauthenticated", "non authentifie")
 			return
 		}
-		token, err := auth.GenerateToken(jwtSecret, claims, 30*24*time.Hour)
+		tokens.stamp(claims)
+		token, err := auth.GenerateToken(jwtSecret, claims, accessTTL)
 		if err != nil {
-			writeError(w, 500, err)
+			writeTypedError(w, 500, err)
 			return
 		}
 		secure := r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
 		auth.SetTokenCookie(w, token, "", secure)
+		setRefreshCookie(w, next, refreshTTL, secure)
 		writeJSON(w, 200, map[string]string{"id": claims.UserID, "name": claims.Username, "role": claims.Role})
 	})
 
-	r.Post("/api/auth/logout", func(w http.ResponseWriter, _ *http.Request) {
+	r.Post("/api/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(refreshCookie); err == nil {
+			if err := refresh.revoke(r.Context(), cookie.Value); err != nil {
+				slog.Warn("logout: revoke refresh token", "error", err)
+			}
+		}
+		if c := auth.GetClaims(r.Context()); c != nil {
+			logAudit(auditLogger, c.UserID, "logout", map[string]any{})
+		}
 		auth.ClearTokenCookie(w, "")
+		clearRefreshCookie(w)
 		writeJSON(w, 200, map[string]string{"status": "ok"})
 	})
 
@@ -284,13 +495,45 @@ func run() error {
 
 	// All API endpoints require a valid session.
 	r.Group(func(r chi.Router) {
-		r.Use(requireSession)
+		r.Use(requireSession(tokens))
+		r.Use(requireDossierAccess(catalogDB)) // every {dossierID} route is owner-scoped
 
 		r.Get("/api/auth/me", func(w http.ResponseWriter, r *http.Request) {
 			c := auth.GetClaims(r.Context())
 			writeJSON(w, 200, map[string]string{"id": c.UserID, "name": c.Username, "role": c.Role})
 		})
 
+		// Self-service password change. Other sessions are revoked; the
+		// caller gets a fresh refresh token.
+		r.Post("/api/auth/password", func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				CurrentPassword string `json:"current_password"`
+				NewPassword     string `json:"new_password"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, 400, err)
+				return
+			}
+			c := auth.GetClaims(r.Context())
+			if err := users.changePassword(r.Context(), c.UserID, req.CurrentPassword, req.NewPassword); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if err := refresh.revokeUser(r.Context(), c.UserID); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			logAudit(auditLogger, c.UserID, "change_password", map[string]any{})
+			next, err := refresh.issue(r.Context(), c.UserID, deviceOf(r))
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			secure := r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+			setRefreshCookie(w, next, refreshTTL, secure)
+			writeJSON(w, 200, map[string]string{"status": "ok"})
+		})
+
 		// Admin: user management.
 		r.Route("/api/admin/users", func(r chi.Router) {
 			r.Use(requireAdmin)
@@ -298,7 +541,7 @@ func run() error {
 			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 				list, err := users.listUsers(r.Context())
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, list)
@@ -323,15 +566,39 @@ func run() error {
 					writeError(w, 400, err)
 					return
 				}
+				logAudit(auditLogger, veille.ActorFrom(r.Context()), "create_user",
+					map[string]any{"target_user_id": user["id"], "email": user["email"], "role": user["role"]})
 				writeJSON(w, 201, user)
 			})
 
+			r.Post("/{userID}/reset-password", func(w http.ResponseWriter, r *http.Request) {
+				userID := chi.URLParam(r, "userID")
+				var req struct {
+					Password string `json:"password"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					writeError(w, 400, err)
+					return
+				}
+				if err := users.setPassword(r.Context(), userID, req.Password); err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				if err := refresh.revokeUser(r.Context(), userID); err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				logAudit(auditLogger, veille.ActorFrom(r.Context()), "reset_password", map[string]any{"target_user_id": userID})
+				writeJSON(w, 200, map[string]string{"status": "ok"})
+			})
+
 			r.Delete("/{userID}", func(w http.ResponseWriter, r *http.Request) {
 				userID := chi.URLParam(r, "userID")
 				if err := users.deleteUser(r.Context(), userID); err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
+				logAudit(auditLogger, veille.ActorFrom(r.Context()), "delete_user", map[string]any{"target_user_id": userID})
 				writeJSON(w, 200, map[string]string{"status": "deleted"})
 			})
 		})
@@ -342,7 +609,7 @@ func run() error {
 			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 				engines, err := listGlobalEngines(r.Context(), catalogDB)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, engines)
@@ -393,7 +660,7 @@ func run() error {
 					id, req.Name, req.Strategy, req.URLTemplate, req.APIConfig, req.Selectors,
 					req.RateLimitMs, req.MaxPages, enabled, now, now)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 201, map[string]string{"id": id, "name": req.Name})
@@ -424,7 +691,7 @@ func run() error {
 					req.Name, req.Strategy, req.URLTemplate, req.APIConfig, req.Selectors,
 					req.RateLimitMs, req.MaxPages, enabled, now, id)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, map[string]string{"id": id, "status": "updated"})
@@ -434,7 +701,7 @@ func run() error {
 				_, err := catalogDB.ExecContext(r.Context(),
 					`DELETE FROM global_search_engines WHERE id = ?`, id)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, map[string]string{"status": "deleted"})
@@ -447,7 +714,7 @@ func run() error {
 			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 				entries, err := listSourceRegistry(r.Context(), catalogDB)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, entries)
@@ -492,7 +759,7 @@ func run() error {
 					id, req.Name, req.URL, req.SourceType, req.Category, req.ConfigJSON,
 					req.Description, req.FetchInterval, enabled, now, now)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 201, map[string]string{"id": id, "name": req.Name})
@@ -523,7 +790,7 @@ func run() error {
 					req.Name, req.URL, req.SourceType, req.Category, req.ConfigJSON,
 					req.Description, req.FetchInterval, enabled, now, id)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, map[string]string{"id": id, "status": "updated"})
@@ -533,7 +800,7 @@ func run() error {
 				_, err := catalogDB.ExecContext(r.Context(),
 					`DELETE FROM source_registry WHERE id = ?`, id)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, map[string]string{"status": "deleted"})
@@ -546,7 +813,7 @@ func run() error {
 			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 				overview, err := buildOverview(r.Context(), catalogDB, pool, svc)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, overview)
@@ -556,7 +823,7 @@ func run() error {
 				limit := queryInt(r, "limit", 50)
 				entries, err := svc.SearchLog(r.Context(), dossierID, limit)
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 200, entries)
@@ -586,20 +853,138 @@ func run() error {
 					Enabled:    true,
 				}
 				if err := svc.AddQuestion(r.Context(), dossierID, q); err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				writeJSON(w, 201, map[string]string{"id": q.ID, "status": "promoted"})
 			})
 		})
 
+		// Admin: audit log (filtered, paginated, redacted).
+		r.Route("/api/admin/audit", func(r chi.Router) {
+			r.Use(requireAdmin)
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				qs := r.URL.Query()
+				q := auditQuery{
+					action:    qs.Get("action"),
+					userID:    qs.Get("user"),
+					dossierID: qs.Get("dossier"),
+					limit:     queryInt(r, "limit", auditDefaultLimit),
+				}
+				var err error
+				if q.since, err = parseAuditTime(qs.Get("since")); err != nil {
+					writeError(w, 400, err)
+					return
+				}
+				if q.until, err = parseAuditTime(qs.Get("until")); err != nil {
+					writeError(w, 400, err)
+					return
+				}
+				if c := qs.Get("cursor"); c != "" {
+					if q.before, err = strconv.ParseInt(c, 10, 64); err != nil {
+						writeError(w, 400, fmt.Errorf("cursor invalide"))
+						return
+					}
+				}
+				entries, next, err := queryAudit(r.Context(), catalogDB, q)
+				if err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, map[string]any{"entries": entries, "next_cursor": next})
+			})
+		})
+
+		// Admin: shard schema migrations (dry run).
+		r.Route("/api/admin/migrations", func(r chi.Router) {
+			r.Use(requireAdmin)
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				statuses, err := svc.CheckShardMigrations(r.Context())
+				if err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, statuses)
+			})
+		})
+
+		// Admin: FTS check + rebuild of one dossier.
+		r.With(requireAdmin).Post("/api/admin/dossiers/{dossierID}/reindex", func(w http.ResponseWriter, r *http.Request) {
+			rep := svc.ReindexFTS(r.Context(), chi.URLParam(r, "dossierID"), true)
+			if rep.Error != "" {
+				writeJSON(w, 500, rep)
+				return
+			}
+			writeJSON(w, 200, rep)
+		})
+
+		// Admin: replay BUFFER_DIR into the dossier shards. The buffer is the
+		// RAG hand-off and flushing removes its files: explicit consume=true.
+		r.With(requireAdmin).Post("/api/admin/buffer/flush", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("consume") != "true" {
+				writeErrorMessage(w, 400, "invalid_input", "consume=true requis : le flush supprime les fichiers du buffer")
+				return
+			}
+			rep, err := svc.FlushBuffer(r.Context())
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, rep)
+		})
+
+		// Admin: scheduler concurrency and in-flight jobs.
+		r.With(requireAdmin).Get("/api/admin/scheduler", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, 200, svc.SchedulerStatus())
+		})
+
+		// Admin: per-dossier source quota override.
+		r.Route("/api/admin/dossiers/{dossierID}/quota", func(r chi.Router) {
+			r.Use(requireAdmin)
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				q, err := svc.GetSourceQuota(r.Context(), chi.URLParam(r, "dossierID"))
+				if err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, q)
+			})
+			r.Put("/", func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					MaxSources int `json:"max_sources"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					writeError(w, 400, err)
+					return
+				}
+				dossierID := chi.URLParam(r, "dossierID")
+				if err := svc.SetSourceQuota(r.Context(), dossierID, req.MaxSources); err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				q, err := svc.GetSourceQuota(r.Context(), dossierID)
+				if err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, q)
+			})
+			r.Delete("/", func(w http.ResponseWriter, r *http.Request) {
+				if err := svc.DeleteSourceQuota(r.Context(), chi.URLParam(r, "dossierID")); err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, map[string]string{"status": "deleted"})
+			})
+		})
+
 		// Admin: source health (auto-repair).
 		r.Route("/api/admin/source-health", func(r chi.Router) {
 			r.Use(requireAdmin)
 			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 				health, err := svc.ListSourceHealth(r.Context())
 				if err != nil {
-					writeError(w, 500, err)
+					writeTypedError(w, 500, err)
 					return
 				}
 				if health == nil {
@@ -640,7 +1025,7 @@ func run() error {
 			dossierID := chi.URLParam(r, "dossierID")
 			sourceID := chi.URLParam(r, "id")
 			if err := svc.ResetSource(r.Context(), dossierID, sourceID); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, map[string]string{"status": "reset"})
@@ -650,7 +1035,7 @@ func run() error {
 		r.Get("/api/source-registry", func(w http.ResponseWriter, r *http.Request) {
 			entries, err := listSourceRegistry(r.Context(), catalogDB)
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, entries)
@@ -658,25 +1043,11 @@ func run() error {
 
 		// Dossiers: list, create, delete.
 		r.Get("/api/dossiers", func(w http.ResponseWriter, r *http.Request) {
-			rows, err := catalogDB.QueryContext(r.Context(),
-				`SELECT id, name FROM shards WHERE status = 'active' ORDER BY name`)
+			dossiers, err := listDossiers(r.Context(), catalogDB, auth.GetClaims(r.Context()))
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
-			defer rows.Close()
-			var dossiers []map[string]string
-			for rows.Next() {
-				var id, name string
-				if err := rows.Scan(&id, &name); err != nil {
-					writeError(w, 500, err)
-					return
-				}
-				dossiers = append(dossiers, map[string]string{"id": id, "name": name})
-			}
-			if dossiers == nil {
-				dossiers = []map[string]string{}
-			}
 			writeJSON(w, 200, dossiers)
 		})
 
@@ -698,9 +1069,10 @@ func run() error {
 				ownerID = c.UserID
 			}
 			if err := pool.CreateShard(r.Context(), dossierID, ownerID, req.Name); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
+			logAudit(auditLogger, ownerID, "create_dossier", map[string]any{"dossier_id": dossierID, "name": req.Name})
 			writeJSON(w, 201, map[string]string{"id": dossierID, "name": req.Name})
 		})
 
@@ -711,10 +1083,20 @@ func run() error {
 				writeError(w, 400, fmt.Errorf("dossierID requis"))
 				return
 			}
+			// Shared bodies are released only once the shard is gone.
+			refs, err := svc.DossierContentRefs(r.Context(), dossierID)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
 			if err := pool.DeleteShard(r.Context(), dossierID); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
+			logAudit(auditLogger, veille.ActorFrom(r.Context()), "delete_dossier", map[string]any{"dossier_id": dossierID})
+			if err := svc.ReleaseContent(r.Context(), refs); err != nil {
+				slog.Warn("release shared content", "dossier_id", dossierID, "error", err)
+			}
 			writeJSON(w, 200, map[string]string{"status": "deleted"})
 		})
 
@@ -722,50 +1104,55 @@ func run() error {
 		r.Post("/api/dossiers/{dossierID}/sources/from-registry/{regID}", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
 			regID := chi.URLParam(r, "regID")
-			var name, url, sourceType, configJSON string
-			var fetchInterval int64
-			err := catalogDB.QueryRowContext(r.Context(),
-				`SELECT name, url, source_type, config_json, fetch_interval FROM source_registry WHERE id = ? AND enabled = 1`, regID).
-				Scan(&name, &url, &sourceType, &configJSON, &fetchInterval)
+			src, err := addFromRegistry(r.Context(), catalogDB, svc.AddSource, dossierID, regID)
 			if err != nil {
-				writeError(w, 404, fmt.Errorf("source not found in registry"))
+				writeTypedError(w, 500, err)
 				return
 			}
-			src := &veille.Source{
-				Name:          name,
-				URL:           url,
-				SourceType:    sourceType,
-				FetchInterval: fetchInterval,
-				Enabled:       true,
-				ConfigJSON:    configJSON,
+			writeJSON(w, 201, src)
+		})
+
+		// Bulk add: every enabled registry source of a category.
+		r.Post("/api/dossiers/{dossierID}/sources/from-category/{category}", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			category := chi.URLParam(r, "category")
+			seed, err := addFromCategory(r.Context(), catalogDB, svc.AddSource, dossierID, category)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
 			}
-			if err := svc.AddSource(r.Context(), dossierID, src); err != nil {
-				switch {
-				case errors.Is(err, veille.ErrDuplicateSource):
-					writeError(w, 409, err)
-				case errors.Is(err, veille.ErrInvalidInput),
-					errors.Is(err, horosafe.ErrSSRF),
-					errors.Is(err, horosafe.ErrPathTraversal),
-					errors.Is(err, horosafe.ErrUnsafeScheme):
-					writeError(w, 400, err)
-				case errors.Is(err, veille.ErrQuotaExceeded):
-					writeError(w, 429, err)
-				default:
-					writeError(w, 500, err)
-				}
+			writeJSON(w, 200, seed)
+		})
+
+		// Preview: fetch + extract a URL without creating a source.
+		r.Post("/api/preview-fetch", func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				URL        string `json:"url"`
+				SourceType string `json:"source_type"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, 400, err)
 				return
 			}
-			writeJSON(w, 201, src)
+			preview, err := svc.PreviewFetch(r.Context(), req.URL, req.SourceType)
+			if err != nil {
+				writeTypedError(w, 502, err)
+				return
+			}
+			writeJSON(w, 200, preview)
 		})
 
 		// Sources.
 		r.Post("/api/dossiers/{dossierID}/sources", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
 			var req struct {
-				Name          string `json:"name"`
-				URL           string `json:"url"`
-				SourceType    string `json:"source_type"`
-				FetchInterval int64  `json:"fetch_interval"`
+				Name          string            `json:"name"`
+				URL           string            `json:"url"`
+				SourceType    string            `json:"source_type"`
+				FetchInterval int64             `json:"fetch_interval"`
+				Tags          []string          `json:"tags"`
+				Headers       map[string]string `json:"headers"`
+				BasicAuth     *veille.BasicAuth `json:"basic_auth"`
 			}
 			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 				writeError(w, 400, err)
@@ -777,21 +1164,12 @@ func run() error {
 				SourceType:    req.SourceType,
 				FetchInterval: req.FetchInterval,
 				Enabled:       true,
+				Tags:          req.Tags,
+				Headers:       req.Headers,
+				BasicAuth:     req.BasicAuth,
 			}
 			if err := svc.AddSource(r.Context(), dossierID, src); err != nil {
-				switch {
-				case errors.Is(err, veille.ErrDuplicateSource):
-					writeError(w, 409, err)
-				case errors.Is(err, veille.ErrInvalidInput),
-					errors.Is(err, horosafe.ErrSSRF),
-					errors.Is(err, horosafe.ErrPathTraversal),
-					errors.Is(err, horosafe.ErrUnsafeScheme):
-					writeError(w, 400, err)
-				case errors.Is(err, veille.ErrQuotaExceeded):
-					writeError(w, 429, err)
-				default:
-					writeError(w, 500, err)
-				}
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 201, src)
@@ -799,22 +1177,38 @@ func run() error {
 
 		r.Get("/api/dossiers/{dossierID}/sources", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
-			sources, err := svc.ListSources(r.Context(), dossierID)
+			sources, err := svc.ListSources(r.Context(), dossierID, veille.ListOpts{Tag: r.URL.Query().Get("tag")})
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, sources)
 		})
 
+		r.Get("/api/dossiers/{dossierID}/tags", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			tags, err := svc.ListTags(r.Context(), dossierID)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if tags == nil {
+				tags = []string{}
+			}
+			writeJSON(w, 200, tags)
+		})
+
 		r.Put("/api/dossiers/{dossierID}/sources/{id}", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
 			sourceID := chi.URLParam(r, "id")
 			var req struct {
-				Name          string `json:"name"`
-				URL           string `json:"url"`
-				Enabled       *bool  `json:"enabled"`
-				FetchInterval int64  `json:"fetch_interval"`
+				Name          string            `json:"name"`
+				URL           string            `json:"url"`
+				Enabled       *bool             `json:"enabled"`
+				FetchInterval int64             `json:"fetch_interval"`
+				Tags          []string          `json:"tags"`       // nil keeps the current tags
+				Headers       map[string]string `json:"headers"`    // nil keeps, {} clears
+				BasicAuth     *veille.BasicAuth `json:"basic_auth"` // nil keeps, empty username clears
 			}
 			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 				writeError(w, 400, err)
@@ -825,42 +1219,67 @@ func run() error {
 				Name:          req.Name,
 				URL:           req.URL,
 				FetchInterval: req.FetchInterval,
+				Tags:          req.Tags,
+				Headers:       req.Headers,
+				BasicAuth:     req.BasicAuth,
 			}
 			if req.Enabled != nil {
 				src.Enabled = *req.Enabled
 			}
 			if err := svc.UpdateSource(r.Context(), dossierID, src); err != nil {
-				switch {
-				case errors.Is(err, veille.ErrDuplicateSource):
-					writeError(w, 409, err)
-				case errors.Is(err, veille.ErrInvalidInput),
-					errors.Is(err, horosafe.ErrSSRF),
-					errors.Is(err, horosafe.ErrPathTraversal),
-					errors.Is(err, horosafe.ErrUnsafeScheme):
-					writeError(w, 400, err)
-				default:
-					writeError(w, 500, err)
-				}
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, src)
 		})
 
+		// Soft delete (restorable); ?purge=true hard-deletes with all content.
 		r.Delete("/api/dossiers/{dossierID}/sources/{id}", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
 			sourceID := chi.URLParam(r, "id")
+			if r.URL.Query().Get("purge") == "true" {
+				if err := svc.PurgeSource(r.Context(), dossierID, sourceID); err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, map[string]string{"status": "purged"})
+				return
+			}
 			if err := svc.DeleteSource(r.Context(), dossierID, sourceID); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, map[string]string{"status": "deleted"})
 		})
 
+		r.Get("/api/dossiers/{dossierID}/sources/deleted", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			sources, err := svc.ListDeletedSources(r.Context(), dossierID)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if sources == nil {
+				sources = []*veille.Source{}
+			}
+			writeJSON(w, 200, sources)
+		})
+
+		r.Post("/api/dossiers/{dossierID}/sources/{id}/restore", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			sourceID := chi.URLParam(r, "id")
+			if err := svc.RestoreSource(r.Context(), dossierID, sourceID); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]string{"status": "restored"})
+		})
+
 		r.Post("/api/dossiers/{dossierID}/sources/{id}/fetch", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
 			sourceID := chi.URLParam(r, "id")
 			if err := svc.FetchNow(r.Context(), dossierID, sourceID); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, map[string]string{"status": "fetched"})
@@ -870,21 +1289,291 @@ func run() error {
 			dossierID := chi.URLParam(r, "dossierID")
 			sourceID := chi.URLParam(r, "id")
 			limit := queryInt(r, "limit", 50)
-			exts, err := svc.ListExtractions(r.Context(), dossierID, sourceID, limit)
+			exts, err := svc.ListExtractions(r.Context(), dossierID, sourceID, limit, veille.ListOpts{Lang: r.URL.Query().Get("lang")})
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, exts)
 		})
 
+		r.Get("/api/dossiers/{dossierID}/extractions", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			limit := queryInt(r, "limit", 50)
+			exts, next, err := svc.ListDossierExtractions(r.Context(), dossierID, limit, r.URL.Query().Get("cursor"),
+				veille.ListOpts{Lang: r.URL.Query().Get("lang"), Pinned: r.URL.Query().Get("pinned") == "true"})
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if exts == nil {
+				exts = []*veille.DossierExtraction{}
+			}
+			writeJSON(w, 200, map[string]any{"extractions": exts, "next_cursor": next})
+		})
+
+		// Activity feed: poll with since=<next_since> for what happened after.
+		r.Get("/api/dossiers/{dossierID}/activity", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			limit := queryInt(r, "limit", 50)
+			events, next, err := svc.ActivityFeed(r.Context(), dossierID, r.URL.Query().Get("since"), limit)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if events == nil {
+				events = []*veille.ActivityEvent{}
+			}
+			writeJSON(w, 200, map[string]any{"events": events, "next_since": next})
+		})
+
+		// Pin (PUT) / unpin (DELETE): pinned extractions survive retention pruning.
+		setPinned := func(pinned bool) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				err := svc.SetPinned(r.Context(), chi.URLParam(r, "dossierID"), chi.URLParam(r, "extID"), pinned)
+				if err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, map[string]bool{"pinned": pinned})
+			}
+		}
+		r.Put("/api/dossiers/{dossierID}/extractions/{extID}/pin", setPinned(true))
+		r.Delete("/api/dossiers/{dossierID}/extractions/{extID}/pin", setPinned(false))
+
+		r.Get("/api/dossiers/{dossierID}/extractions/{extID}/links", func(w http.ResponseWriter, r *http.Request) {
+			links, err := svc.ListLinks(r.Context(), chi.URLParam(r, "dossierID"), chi.URLParam(r, "extID"))
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if links == nil {
+				links = []string{}
+			}
+			writeJSON(w, 200, map[string]any{"links": links})
+		})
+
+		// Re-run extraction on the kept raw body (RETAIN_RAW_BODIES).
+		r.Post("/api/dossiers/{dossierID}/extractions/{extID}/reprocess", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			e, err := svc.Reprocess(r.Context(), dossierID, chi.URLParam(r, "extID"))
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, e)
+		})
+
+		// Click-through log: an extraction opened from results ({"query": "..."}, optional body).
+		r.Post("/api/dossiers/{dossierID}/extractions/{extID}/open", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			var req struct {
+				Query string `json:"query"`
+			}
+			if err := json.NewDecoder(io.LimitReader(r.Body, 8<<10)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				writeError(w, 400, err)
+				return
+			}
+			if err := svc.LogResultOpen(r.Context(), dossierID, chi.URLParam(r, "extID"), req.Query); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]string{"status": "ok"})
+		})
+
+		// Nearest extractions by embedding (needs horosembed + vecbridge on the router).
+		r.Get("/api/dossiers/{dossierID}/extractions/{extID}/related", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			related, err := svc.Related(r.Context(), dossierID, chi.URLParam(r, "extID"), queryInt(r, "top_k", 10))
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]any{"related": related})
+		})
+
+		// Recurring terms over recent extractions (?window=7d&limit=20).
+		r.Get("/api/dossiers/{dossierID}/trends", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			window, err := parseWindow(r.URL.Query().Get("window"))
+			if err != nil {
+				writeError(w, 400, err)
+				return
+			}
+			rep, err := svc.Trends(r.Context(), dossierID, veille.TrendOpts{Window: window, Limit: queryInt(r, "limit", 0)})
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, rep)
+		})
+
+		// Source health webhook of the dossier (else HEALTH_WEBHOOK_URL).
+		r.Get("/api/dossiers/{dossierID}/health-webhook", func(w http.ResponseWriter, r *http.Request) {
+			hook, err := svc.GetHealthWebhook(r.Context(), chi.URLParam(r, "dossierID"))
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if hook == nil {
+				writeError(w, 404, errors.New("no health webhook for this dossier"))
+				return
+			}
+			writeJSON(w, 200, hook)
+		})
+
+		r.Put("/api/dossiers/{dossierID}/health-webhook", func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				URL    string `json:"url"`
+				Secret string `json:"secret"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, 400, err)
+				return
+			}
+			if err := svc.SetHealthWebhook(r.Context(), chi.URLParam(r, "dossierID"), req.URL, req.Secret); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]string{"status": "ok"})
+		})
+
+		r.Delete("/api/dossiers/{dossierID}/health-webhook", func(w http.ResponseWriter, r *http.Request) {
+			if err := svc.DeleteHealthWebhook(r.Context(), chi.URLParam(r, "dossierID")); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]string{"status": "deleted"})
+		})
+
+		// Extraction retention: policy per dossier, enforced by the purger.
+		r.Get("/api/dossiers/{dossierID}/retention", func(w http.ResponseWriter, r *http.Request) {
+			policy, err := svc.GetRetention(r.Context(), chi.URLParam(r, "dossierID"))
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			if policy == nil {
+				writeError(w, 404, errors.New("no retention policy for this dossier"))
+				return
+			}
+			writeJSON(w, 200, policy)
+		})
+
+		r.Put("/api/dossiers/{dossierID}/retention", func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				KeepDays int `json:"keep_days"`
+				MaxRows  int `json:"max_rows"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, 400, err)
+				return
+			}
+			if err := svc.SetRetention(r.Context(), chi.URLParam(r, "dossierID"), req.KeepDays, req.MaxRows); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]string{"status": "ok"})
+		})
+
+		r.Delete("/api/dossiers/{dossierID}/retention", func(w http.ResponseWriter, r *http.Request) {
+			if err := svc.DeleteRetention(r.Context(), chi.URLParam(r, "dossierID")); err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]string{"status": "deleted"})
+		})
+
+		r.Post("/api/dossiers/{dossierID}/retention/prune", func(w http.ResponseWriter, r *http.Request) {
+			n, err := svc.PruneExtractions(r.Context(), chi.URLParam(r, "dossierID"))
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]int64{"pruned": n})
+		})
+
+		// Templates: source + question definitions, no data.
+		r.Get("/api/dossiers/{dossierID}/template", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			tmpl, err := svc.ExportTemplate(r.Context(), dossierID)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, tmpl)
+		})
+
+		r.Post("/api/dossiers/{dossierID}/template", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			var tmpl veille.Template
+			if err := json.NewDecoder(r.Body).Decode(&tmpl); err != nil {
+				writeError(w, 400, err)
+				return
+			}
+			res, err := svc.ApplyTemplate(r.Context(), dossierID, &tmpl)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, res)
+		})
+
+		// OPML import: raw XML body or multipart field "file", 5 MB max.
+		r.Post("/api/dossiers/{dossierID}/sources/import-opml", func(w http.ResponseWriter, r *http.Request) {
+			dossierID := chi.URLParam(r, "dossierID")
+			r.Body = http.MaxBytesReader(w, r.Body, 5<<20)
+			var body io.Reader = r.Body
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				f, _, err := r.FormFile("file")
+				if err != nil {
+					writeError(w, 400, err)
+					return
+				}
+				defer f.Close()
+				body = f
+			}
+			results, err := svc.ImportOPML(r.Context(), dossierID, body)
+			if err != nil {
+				writeTypedError(w, 500, err)
+				return
+			}
+			writeJSON(w, 200, map[string]any{"results": results})
+		})
+
+		// Bulk enable/disable: {"ids": [...], "enabled": bool} → {"updated", "not_found"}.
+		bulkEnable := func(set func(context.Context, string, []string, bool) (*veille.BulkEnableResult, error)) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					IDs     []string `json:"ids"`
+					Enabled *bool    `json:"enabled"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					writeError(w, 400, err)
+					return
+				}
+				if req.Enabled == nil {
+					writeError(w, 400, fmt.Errorf("enabled requis"))
+					return
+				}
+				res, err := set(r.Context(), chi.URLParam(r, "dossierID"), req.IDs, *req.Enabled)
+				if err != nil {
+					writeTypedError(w, 500, err)
+					return
+				}
+				writeJSON(w, 200, res)
+			}
+		}
+		r.Post("/api/dossiers/{dossierID}/sources/bulk-enable", bulkEnable(svc.SetSourcesEnabled))
+		r.Post("/api/dossiers/{dossierID}/questions/bulk-enable", bulkEnable(svc.SetQuestionsEnabled))
+
 		r.Get("/api/dossiers/{dossierID}/sources/{id}/history", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
 			sourceID := chi.URLParam(r, "id")
 			limit := queryInt(r, "limit", 50)
 			hist, err := svc.FetchHistory(r.Context(), dossierID, sourceID, limit)
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, hist)
@@ -895,9 +1584,12 @@ func run() error {
 			dossierID := chi.URLParam(r, "dossierID")
 			q := r.URL.Query().Get("q")
 			limit := queryInt(r, "limit", 20)
-			results, err := svc.Search(r.Context(), dossierID, q, limit)
+			results, err := svc.Search(r.Context(), dossierID, q, limit, veille.ListOpts{
+				Tag:  r.URL.Query().Get("tag"),
+				Lang: r.URL.Query().Get("lang"),
+			})
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, results)
@@ -907,7 +1599,7 @@ func run() error {
 			dossierID := chi.URLParam(r, "dossierID")
 			stats, err := svc.Stats(r.Context(), dossierID)
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, stats)
@@ -917,24 +1609,30 @@ func run() error {
 		r.Post("/api/dossiers/{dossierID}/questions", func(w http.ResponseWriter, r *http.Request) {
 			dossierID := chi.URLParam(r, "dossierID")
 			var req struct {
-				Text        string `json:"text"`
-				Keywords    string `json:"keywords"`
-				Channels    string `json:"channels"`
-				ScheduleMs  int64  `json:"schedule_ms"`
-				MaxResults  int    `json:"max_results"`
-				FollowLinks *bool  `json:"follow_links"`
+				Text             string `json:"text"`
+				Keywords         string `json:"keywords"`
+				Channels         string `json:"channels"`
+				ScheduleMs       int64  `json:"schedule_ms"`
+				MaxResults       int    `json:"max_results"`
+				FollowLinks      *bool  `json:"follow_links"`
+				FollowDepth      int    `json:"follow_depth"`
+				NotifyMinResults int    `json:"notify_min_results"`
+				NotifyPattern    string `json:"notify_pattern"`
 			}
 			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 				writeError(w, 400, err)
 				return
 			}
 			q := &veille.TrackedQuestion{
-				Text:       req.Text,
-				Keywords:   req.Keywords,
-				Channels:   req.Channels,
-				ScheduleMs: req.ScheduleMs,
-				MaxResults: req.MaxResults,
-				Enabled:    true,
+				Text:             req.Text,
+				Keywords:         req.Keywords,
+				Channels:         req.Channels,
+				ScheduleMs:       req.ScheduleMs,
+				MaxResults:       req.MaxResults,
+				FollowDepth:      req.FollowDepth,
+				NotifyMinResults: req.NotifyMinResults,
+				NotifyPattern:    req.NotifyPattern,
+				Enabled:          true,
 			}
 			if req.FollowLinks != nil {
 				q.FollowLinks = *req.FollowLinks
@@ -942,7 +1640,7 @@ func run() error {
 				q.FollowLinks = true
 			}
 			if err := svc.AddQuestion(r.Context(), dossierID, q); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 201, q)
@@ -952,7 +1650,7 @@ func run() error {
 			dossierID := chi.URLParam(r, "dossierID")
 			questions, err := svc.ListQuestions(r.Context(), dossierID)
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, questions)
@@ -962,25 +1660,31 @@ func run() error {
 			dossierID := chi.URLParam(r, "dossierID")
 			questionID := chi.URLParam(r, "id")
 			var req struct {
-				Text        string `json:"text"`
-				Keywords    string `json:"keywords"`
-				Channels    string `json:"channels"`
-				ScheduleMs  int64  `json:"schedule_ms"`
-				MaxResults  int    `json:"max_results"`
-				FollowLinks *bool  `json:"follow_links"`
-				Enabled     *bool  `json:"enabled"`
+				Text             string `json:"text"`
+				Keywords         string `json:"keywords"`
+				Channels         string `json:"channels"`
+				ScheduleMs       int64  `json:"schedule_ms"`
+				MaxResults       int    `json:"max_results"`
+				FollowLinks      *bool  `json:"follow_links"`
+				FollowDepth      int    `json:"follow_depth"`
+				NotifyMinResults int    `json:"notify_min_results"`
+				NotifyPattern    string `json:"notify_pattern"`
+				Enabled          *bool  `json:"enabled"`
 			}
 			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 				writeError(w, 400, err)
 				return
 			}
 			q := &veille.TrackedQuestion{
-				ID:         questionID,
-				Text:       req.Text,
-				Keywords:   req.Keywords,
-				Channels:   req.Channels,
-				ScheduleMs: req.ScheduleMs,
-				MaxResults: req.MaxResults,
+				ID:               questionID,
+				Text:             req.Text,
+				Keywords:         req.Keywords,
+				Channels:         req.Channels,
+				ScheduleMs:       req.ScheduleMs,
+				MaxResults:       req.MaxResults,
+				FollowDepth:      req.FollowDepth,
+				NotifyMinResults: req.NotifyMinResults,
+				NotifyPattern:    req.NotifyPattern,
 			}
 			if req.FollowLinks != nil {
 				q.FollowLinks = *req.FollowLinks
@@ -989,7 +1693,7 @@ func run() error {
 				q.Enabled = *req.Enabled
 			}
 			if err := svc.UpdateQuestion(r.Context(), dossierID, q); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, q)
@@ -999,7 +1703,7 @@ func run() error {
 			dossierID := chi.URLParam(r, "dossierID")
 			questionID := chi.URLParam(r, "id")
 			if err := svc.DeleteQuestion(r.Context(), dossierID, questionID); err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, map[string]string{"status": "deleted"})
@@ -1010,7 +1714,7 @@ func run() error {
 			questionID := chi.URLParam(r, "id")
 			count, err := svc.RunQuestionNow(r.Context(), dossierID, questionID)
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, map[string]any{"status": "ok", "new_results": count})
@@ -1022,7 +1726,7 @@ func run() error {
 			limit := queryInt(r, "limit", 50)
 			results, err := svc.QuestionResults(r.Context(), dossierID, questionID, limit)
 			if err != nil {
-				writeError(w, 500, err)
+				writeTypedError(w, 500, err)
 				return
 			}
 			writeJSON(w, 200, results)
@@ -1064,23 +1768,11 @@ func run() error {
 
 // --- Auth middleware ---
 
-// requireSession returns 401 JSON if no valid JWT claims in context.
-// Used on API routes. auth.Middleware (applied globally) does the soft parsing.
-func requireSession(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if auth.GetClaims(r.Context()) == nil {
-			writeJSON(w, 401, map[string]string{"error": "non authentifie"})
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}
-
 func requireAdmin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		c := auth.GetClaims(r.Context())
 		if c == nil || c.Role != "admin" {
-			writeJSON(w, 403, map[string]string{"error": "admin requis"})
+			writeErrorMessage(w, 403, "admin_required", "admin requis")
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -1111,7 +1803,7 @@ func migrateAuthColumns(db *sql.DB) error {
 	return nil
 }
 
-func seedAdmin(ctx context.Context, db *sql.DB) error {
+func seedAdmin(ctx context.Context, db *sql.DB, bcryptCost int) error {
 	var count int
 	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE role = 'admin' AND status = 'active'`).Scan(&count); err != nil {
 		return err
@@ -1119,7 +1811,7 @@ func seedAdmin(ctx context.Context, db *sql.DB) error {
 	if count > 0 {
 		return nil
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte("admin123!!!"), bcrypt.DefaultCost)
+	hash, err := bcrypt.GenerateFromPassword([]byte("admin123!!!"), bcryptCost)
 	if err != nil {
 		return err
 	}
@@ -1135,8 +1827,9 @@ func seedAdmin(ctx context.Context, db *sql.DB) error {
 }
 
 type userService struct {
-	db   *sql.DB
-	pool *tenant.Pool
+	db         *sql.DB
+	pool       *tenant.Pool
+	bcryptCost int // target cost for new hashes; lower-cost hashes are upgraded on login
 }
 
 func (s *userService) authenticate(ctx context.Context, email, password string) (*auth.HorosClaims, error) {
@@ -1150,6 +1843,7 @@ func (s *userService) authenticate(ctx context.Context, email, password string)
 	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
 		return nil, fmt.Errorf("wrong password")
 	}
+	s.rehashIfWeak(ctx, userID, hash, password)
 	return &auth.HorosClaims{
 		UserID:   userID,
 		Username: name,
@@ -1158,6 +1852,39 @@ func (s *userService) authenticate(ctx context.Context, email, password string)
 	}, nil
 }
 
+// claims loads the session claims of an active user (token refresh).
+func (s *userService) claims(ctx context.Context, userID string) (*auth.HorosClaims, error) {
+	var name, role, email string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT name, role, email FROM users WHERE id = ? AND status = 'active'`, userID).
+		Scan(&name, &role, &email)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return &auth.HorosClaims{UserID: userID, Username: name, Role: role, Email: email}, nil
+}
+
+// rehashIfWeak re-hashes password at the target cost when the stored hash
+// uses a lower one. Best-effort: the login succeeds even if the update fails.
+func (s *userService) rehashIfWeak(ctx context.Context, userID, hash, password string) {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil || cost >= s.bcryptCost {
+		return
+	}
+	newHash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err != nil {
+		slog.Warn("rehash password", "user_id", userID, "error", err)
+		return
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = ? WHERE id = ? AND password_hash = ?`,
+		string(newHash), userID, hash); err != nil {
+		slog.Warn("rehash password: update", "user_id", userID, "error", err)
+		return
+	}
+	slog.Info("password rehashed", "user_id", userID, "from_cost", cost, "to_cost", s.bcryptCost)
+}
+
 func (s *userService) listUsers(ctx context.Context) ([]map[string]any, error) {
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT id, name, email, role, status, created_at FROM users ORDER BY created_at`)
@@ -1187,7 +1914,10 @@ func (s *userService) createUser(ctx context.Context, email, name, password, rol
 	if email == "" || password == "" {
 		return nil, fmt.Errorf("email et mot de passe requis")
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := checkPasswordPolicy(email, password); err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
 	if err != nil {
 		return nil, err
 	}
@@ -1203,6 +1933,59 @@ func (s *userService) createUser(ctx context.Context, email, name, password, rol
 	return map[string]string{"id": id, "name": name, "email": email, "role": role}, nil
 }
 
+// minPasswordLen is the minimum password length in characters.
+const minPasswordLen = 10
+
+var (
+	errWeakPassword  = errors.New("mot de passe trop faible")
+	errWrongPassword = errors.New("mot de passe actuel incorrect")
+	errUserNotFound  = errors.New("utilisateur introuvable")
+)
+
+// checkPasswordPolicy enforces the minimum length and rejects the email as password.
+func checkPasswordPolicy(email, password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLen {
+		return fmt.Errorf("%w: %d caracteres minimum", errWeakPassword, minPasswordLen)
+	}
+	if email != "" && strings.EqualFold(password, email) {
+		return fmt.Errorf("%w: ne doit pas etre l'email", errWeakPassword)
+	}
+	return nil
+}
+
+// changePassword replaces the password of userID after checking current.
+func (s *userService) changePassword(ctx context.Context, userID, current, next string) error {
+	var hash string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT password_hash FROM users WHERE id = ? AND status = 'active'`, userID).Scan(&hash)
+	if err != nil {
+		return errUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(current)); err != nil {
+		return errWrongPassword
+	}
+	return s.setPassword(ctx, userID, next)
+}
+
+// setPassword hashes password at the target cost and stores it (admin reset).
+func (s *userService) setPassword(ctx context.Context, userID, password string) error {
+	var email string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT email FROM users WHERE id = ? AND status = 'active'`, userID).Scan(&email)
+	if err != nil {
+		return errUserNotFound
+	}
+	if err := checkPasswordPolicy(email, password); err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE users SET password_hash = ? WHERE id = ?`, string(hash), userID)
+	return err
+}
+
 func (s *userService) deleteUser(ctx context.Context, userID string) error {
 	_, err := s.db.ExecContext(ctx,
 		`UPDATE users SET status = 'deleted' WHERE id = ?`, userID)
@@ -1218,16 +2001,32 @@ func env(key, def string) string {
 	return def
 }
 
+// envList splits a comma-separated variable, dropping empty items.
+func envList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// clientIP returns the host part of r.RemoteAddr.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func writeJSON(w http.ResponseWriter, code int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-func writeError(w http.ResponseWriter, code int, err error) {
-	writeJSON(w, code, map[string]string{"error": err.Error()})
-}
-
 func queryInt(r *http.Request, key string, def int) int {
 	s := r.URL.Query().Get(key)
 	if s == "" {
@@ -1240,6 +2039,23 @@ func queryInt(r *http.Request, key string, def int) int {
 	return v
 }
 
+// parseWindow accepts a number of days ("7d") or a Go duration ("36h").
+// "" is 0.
+func parseWindow(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("fenetre invalide %q (ex. 7d, 36h)", s)
+}
+
 
 // --- Global tables migration ---
 
@@ -0,0 +1,182 @@
+// CLAUDE:SUMMARY Refresh tokens — hashed server-side in refresh_tokens, rotated on every use, family revoked on reuse or logout.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hazyhaar/pkg/idgen"
+)
+
+const refreshCookie = "refresh_token"
+
+var (
+	errRefreshInvalid = errors.New("refresh token invalide")
+	errRefreshReused  = errors.New("refresh token deja utilise")
+)
+
+func migrateRefreshTokens(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          TEXT PRIMARY KEY,
+    family_id   TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    token_hash  TEXT NOT NULL UNIQUE,
+    user_agent  TEXT NOT NULL DEFAULT '',
+    ip          TEXT NOT NULL DEFAULT '',
+    created_at  INTEGER NOT NULL,
+    expires_at  INTEGER NOT NULL,
+    rotated_at  INTEGER,
+    revoked_at  INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);`)
+	return err
+}
+
+// device is the client metadata recorded with a refresh token.
+type device struct {
+	userAgent string
+	ip        string
+}
+
+func deviceOf(r *http.Request) device {
+	return device{userAgent: r.UserAgent(), ip: r.RemoteAddr}
+}
+
+// refreshService issues and rotates refresh tokens. Only the SHA-256 of a
+// token is stored; every rotation marks the presented token used and issues
+// a new one in the same family. Presenting a used token again means it was
+// stolen: the whole family is revoked.
+type refreshService struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// issue starts a new token family for userID (login).
+func (s *refreshService) issue(ctx context.Context, userID string, d device) (string, error) {
+	return s.insert(ctx, s.db, idgen.New(), userID, d)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (s *refreshService) insert(ctx context.Context, db execer, familyID, userID string, d device) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (id, family_id, user_id, token_hash, user_agent, ip, created_at, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		idgen.New(), familyID, userID, hashToken(token), d.userAgent, d.ip,
+		now.UnixMilli(), now.Add(s.ttl).UnixMilli())
+	if err != nil {
+		return "", fmt.Errorf("insert refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// rotate consumes token and returns its user and a new token of the same
+// family. A token already rotated revokes the family and returns
+// errRefreshReused (with the family user).
+func (s *refreshService) rotate(ctx context.Context, token string, d device) (userID, next string, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", "", err
+	}
+	defer tx.Rollback()
+
+	var id, familyID string
+	var expiresAt int64
+	var rotatedAt, revokedAt sql.NullInt64
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, family_id, user_id, expires_at, rotated_at, revoked_at FROM refresh_tokens WHERE token_hash = ?`,
+		hashToken(token)).Scan(&id, &familyID, &userID, &expiresAt, &rotatedAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", errRefreshInvalid
+	}
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now().UnixMilli()
+	switch {
+	case revokedAt.Valid:
+		return "", "", errRefreshInvalid
+	case rotatedAt.Valid:
+		if err := revokeFamily(ctx, tx, familyID, now); err != nil {
+			return "", "", err
+		}
+		if err := tx.Commit(); err != nil {
+			return "", "", err
+		}
+		return userID, "", errRefreshReused
+	case expiresAt <= now:
+		return "", "", errRefreshInvalid
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET rotated_at = ? WHERE id = ?`, now, id); err != nil {
+		return "", "", err
+	}
+	next, err = s.insert(ctx, tx, familyID, userID, d)
+	if err != nil {
+		return "", "", err
+	}
+	return userID, next, tx.Commit()
+}
+
+// revoke revokes the family of token (logout). Unknown tokens are ignored.
+func (s *refreshService) revoke(ctx context.Context, token string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = ?
+		 WHERE revoked_at IS NULL AND family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = ?)`,
+		time.Now().UnixMilli(), hashToken(token))
+	return err
+}
+
+// revokeUser revokes every refresh token of userID.
+func (s *refreshService) revokeUser(ctx context.Context, userID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`,
+		time.Now().UnixMilli(), userID)
+	return err
+}
+
+func revokeFamily(ctx context.Context, db execer, familyID string, now int64) error {
+	_, err := db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`, now, familyID)
+	return err
+}
+
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// setRefreshCookie stores token in an httpOnly cookie scoped to /api/auth.
+func setRefreshCookie(w http.ResponseWriter, token string, ttl time.Duration, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookie,
+		Value:    token,
+		Path:     "/api/auth",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func clearRefreshCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: refreshCookie, Path: "/api/auth", MaxAge: -1, HttpOnly: true})
+}
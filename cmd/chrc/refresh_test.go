@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func openRefreshDB(t *testing.T) *refreshService {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db.SetMaxOpenConns(1) // one connection = one in-memory database
+	t.Cleanup(func() { db.Close() })
+	if err := migrateRefreshTokens(db); err != nil {
+		t.Fatalf("migrate refresh tokens: %v", err)
+	}
+	return &refreshService{db: db, ttl: time.Hour}
+}
+
+func TestRefresh_Rotation(t *testing.T) {
+	// WHAT: Each refresh consumes the presented token and returns a new one for the same user.
+	// WHY: A refresh token must be single-use so a leaked copy has a short useful life.
+	s := openRefreshDB(t)
+	ctx := context.Background()
+	d := device{userAgent: "test", ip: "127.0.0.1"}
+
+	t1, err := s.issue(ctx, "u1", d)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	user, t2, err := s.rotate(ctx, t1, d)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if user != "u1" || t2 == "" || t2 == t1 {
+		t.Fatalf("rotate: user=%q next=%q", user, t2)
+	}
+	if _, _, err := s.rotate(ctx, t2, d); err != nil {
+		t.Errorf("rotate new token: %v", err)
+	}
+
+	var stored int
+	s.db.QueryRow(`SELECT COUNT(*) FROM refresh_tokens WHERE token_hash = ?`, t1).Scan(&stored)
+	if stored != 0 {
+		t.Error("raw token stored in clear")
+	}
+	if _, _, err := s.rotate(ctx, "unknown", d); !errors.Is(err, errRefreshInvalid) {
+		t.Errorf("unknown token: err = %v, want errRefreshInvalid", err)
+	}
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	// WHAT: Presenting an already rotated token fails and revokes every token of its family.
+	// WHY: Reuse means the token was copied; the legitimate holder's session must die too.
+	s := openRefreshDB(t)
+	ctx := context.Background()
+	d := device{}
+
+	t1, _ := s.issue(ctx, "u1", d)
+	other, _ := s.issue(ctx, "u1", d) // another device, another family
+	_, t2, err := s.rotate(ctx, t1, d)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	user, _, err := s.rotate(ctx, t1, d)
+	if !errors.Is(err, errRefreshReused) || user != "u1" {
+		t.Fatalf("reuse: user=%q err=%v, want u1 / errRefreshReused", user, err)
+	}
+	if _, _, err := s.rotate(ctx, t2, d); !errors.Is(err, errRefreshInvalid) {
+		t.Errorf("family token after reuse: err = %v, want errRefreshInvalid", err)
+	}
+	if _, _, err := s.rotate(ctx, other, d); err != nil {
+		t.Errorf("other family must survive: %v", err)
+	}
+}
+
+func TestRefresh_LogoutRevokes(t *testing.T) {
+	s := openRefreshDB(t)
+	ctx := context.Background()
+
+	t1, _ := s.issue(ctx, "u1", device{})
+	if err := s.revoke(ctx, t1); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, _, err := s.rotate(ctx, t1, device{}); !errors.Is(err, errRefreshInvalid) {
+		t.Errorf("after logout: err = %v, want errRefreshInvalid", err)
+	}
+}
@@ -0,0 +1,106 @@
+// CLAUDE:SUMMARY Source registry → dossier: single add by registry ID and bulk add of a whole category, sharing one code path.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hazyhaar/chrc/veille"
+	"github.com/hazyhaar/pkg/horosafe"
+)
+
+// errRegistryNotFound is returned for an unknown or disabled registry entry.
+var errRegistryNotFound = errors.New("source not found in registry")
+
+// addSourceFunc is the subset of veille.Service used to add a source.
+type addSourceFunc func(ctx context.Context, dossierID string, src *veille.Source) error
+
+// registrySource builds a veille.Source from an enabled registry row.
+func registrySource(ctx context.Context, db *sql.DB, regID string) (*veille.Source, error) {
+	src := &veille.Source{Enabled: true}
+	err := db.QueryRowContext(ctx,
+		`SELECT name, url, source_type, config_json, fetch_interval FROM source_registry WHERE id = ? AND enabled = 1`, regID).
+		Scan(&src.Name, &src.URL, &src.SourceType, &src.ConfigJSON, &src.FetchInterval)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errRegistryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// addFromRegistry adds the registry entry regID to dossierID.
+func addFromRegistry(ctx context.Context, db *sql.DB, add addSourceFunc, dossierID, regID string) (*veille.Source, error) {
+	src, err := registrySource(ctx, db, regID)
+	if err != nil {
+		return nil, err
+	}
+	if err := add(ctx, dossierID, src); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// categorySeed counts the outcome of addFromCategory.
+type categorySeed struct {
+	Added   int `json:"added"`
+	Skipped int `json:"skipped"`
+}
+
+// addFromCategory adds every enabled registry entry of category to dossierID
+// through addFromRegistry. Duplicates and rejected URLs are skipped; once the
+// dossier quota is reached the remaining entries are skipped too. Any other
+// error aborts with the counts so far.
+func addFromCategory(ctx context.Context, db *sql.DB, add addSourceFunc, dossierID, category string) (categorySeed, error) {
+	var seed categorySeed
+	rows, err := db.QueryContext(ctx,
+		`SELECT id FROM source_registry WHERE category = ? AND enabled = 1 ORDER BY name`, category)
+	if err != nil {
+		return seed, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return seed, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return seed, err
+	}
+	if len(ids) == 0 {
+		return seed, fmt.Errorf("%w: %q", errRegistryNotFound, category)
+	}
+
+	for i, id := range ids {
+		_, err := addFromRegistry(ctx, db, add, dossierID, id)
+		switch {
+		case err == nil:
+			seed.Added++
+		case errors.Is(err, veille.ErrQuotaExceeded):
+			seed.Skipped += len(ids) - i
+			return seed, nil
+		case isSourceRejection(err), errors.Is(err, errRegistryNotFound):
+			seed.Skipped++
+		default:
+			return seed, err
+		}
+	}
+	return seed, nil
+}
+
+// isSourceRejection reports whether AddSource refused the source itself
+// (duplicate, invalid or unsafe URL) rather than failing.
+func isSourceRejection(err error) bool {
+	return errors.Is(err, veille.ErrDuplicateSource) ||
+		errors.Is(err, veille.ErrInvalidInput) ||
+		errors.Is(err, horosafe.ErrSSRF) ||
+		errors.Is(err, horosafe.ErrPathTraversal) ||
+		errors.Is(err, horosafe.ErrUnsafeScheme)
+}
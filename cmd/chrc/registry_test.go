@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille"
+)
+
+// fakeDossier records added sources and rejects repeated URLs like AddSource.
+type fakeDossier struct {
+	urls  map[string]bool
+	quota int
+}
+
+func (f *fakeDossier) add(_ context.Context, _ string, src *veille.Source) error {
+	if f.urls[src.URL] {
+		return veille.ErrDuplicateSource
+	}
+	if f.quota > 0 && len(f.urls) >= f.quota {
+		return veille.ErrQuotaExceeded
+	}
+	f.urls[src.URL] = true
+	return nil
+}
+
+func openRegistryDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := migrateGlobalTables(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO source_registry (id, name, url, category, enabled, created_at, updated_at) VALUES
+		('r1', 'One', 'https://one.example/feed', 'seed', 1, 0, 0),
+		('r2', 'Two', 'https://two.example/feed', 'seed', 1, 0, 0),
+		('r3', 'Three', 'https://three.example/feed', 'seed', 1, 0, 0),
+		('r4', 'Off', 'https://off.example/feed', 'seed', 0, 0, 0),
+		('r5', 'Other', 'https://other.example/feed', 'other', 1, 0, 0)`); err != nil {
+		t.Fatalf("seed registry: %v", err)
+	}
+	return db
+}
+
+func TestAddFromCategory(t *testing.T) {
+	// WHAT: A category's three enabled sources are added; a second call skips them all as duplicates.
+	// WHY: Bulk seeding must be idempotent and must not pull disabled or other-category entries.
+	db := openRegistryDB(t)
+	d := &fakeDossier{urls: map[string]bool{}}
+	ctx := context.Background()
+
+	seed, err := addFromCategory(ctx, db, d.add, "dA", "seed")
+	if err != nil {
+		t.Fatalf("first seed: %v", err)
+	}
+	if seed != (categorySeed{Added: 3}) {
+		t.Errorf("first seed = %+v, want 3 added", seed)
+	}
+	if len(d.urls) != 3 || d.urls["https://off.example/feed"] || d.urls["https://other.example/feed"] {
+		t.Errorf("dossier sources = %v", d.urls)
+	}
+
+	seed, err = addFromCategory(ctx, db, d.add, "dA", "seed")
+	if err != nil {
+		t.Fatalf("second seed: %v", err)
+	}
+	if seed != (categorySeed{Skipped: 3}) {
+		t.Errorf("second seed = %+v, want 3 skipped", seed)
+	}
+}
+
+func TestAddFromCategory_Quota(t *testing.T) {
+	// WHAT: With room for two sources, two are added and the rest skipped without error.
+	// WHY: Partial success is expected when the dossier quota is reached mid-category.
+	db := openRegistryDB(t)
+	d := &fakeDossier{urls: map[string]bool{}, quota: 2}
+
+	seed, err := addFromCategory(context.Background(), db, d.add, "dA", "seed")
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if seed != (categorySeed{Added: 2, Skipped: 1}) {
+		t.Errorf("seed = %+v, want 2 added 1 skipped", seed)
+	}
+}
+
+func TestAddFromCategory_Unknown(t *testing.T) {
+	db := openRegistryDB(t)
+	d := &fakeDossier{urls: map[string]bool{}}
+	if _, err := addFromCategory(context.Background(), db, d.add, "dA", "nope"); !errors.Is(err, errRegistryNotFound) {
+		t.Errorf("err = %v, want errRegistryNotFound", err)
+	}
+}
@@ -0,0 +1,66 @@
+// CLAUDE:SUMMARY One-shot FTS maintenance (-check-fts / -reindex): checks or rebuilds the search index of one dossier or all, prints JSON reports.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hazyhaar/chrc/veille"
+)
+
+// ftsCommand is the one-shot mode selected by -check-fts or -reindex.
+// target is a dossier ID or "all" (every active shard).
+type ftsCommand struct {
+	target  string
+	rebuild bool
+}
+
+// run reports on every targeted dossier and fails if any report has an
+// error, or drift without a rebuild.
+func (c ftsCommand) run(ctx context.Context, catalogDB *sql.DB, svc *veille.Service, out io.Writer) error {
+	ids := []string{c.target}
+	if c.target == "all" {
+		var err error
+		if ids, err = activeDossiers(ctx, catalogDB); err != nil {
+			return fmt.Errorf("list dossiers: %w", err)
+		}
+	}
+	reports := make([]veille.FTSReport, 0, len(ids))
+	var failed int
+	for _, id := range ids {
+		rep := svc.ReindexFTS(ctx, id, c.rebuild)
+		if rep.Error != "" || (rep.Drift && !c.rebuild) {
+			failed++
+		}
+		reports = append(reports, rep)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(reports); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("fts: %d of %d dossiers need attention", failed, len(ids))
+	}
+	return nil
+}
+
+func activeDossiers(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id FROM shards WHERE status = 'active' ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,86 @@
+// CLAUDE:SUMMARY Session token policy — JWT issuer/audience stamped at login and enforced by requireSession.
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hazyhaar/chrc/veille"
+	"github.com/hazyhaar/pkg/auth"
+)
+
+var (
+	errWrongIssuer   = errors.New("emetteur du jeton invalide")
+	errWrongAudience = errors.New("audience du jeton invalide")
+	errInvalidToken  = errors.New("jeton invalide ou expire")
+)
+
+// tokenPolicy is the expected issuer and audiences of session tokens. An
+// empty field is not checked (tokens minted before the policy was set).
+type tokenPolicy struct {
+	issuer    string
+	audiences []string // any of them is accepted; the first is stamped at login
+}
+
+// tokenPolicyFromEnv reads AUTH_ISSUER and AUTH_AUDIENCE (comma-separated).
+func tokenPolicyFromEnv() tokenPolicy {
+	var p tokenPolicy
+	p.issuer = strings.TrimSpace(env("AUTH_ISSUER", ""))
+	for _, a := range strings.Split(env("AUTH_AUDIENCE", ""), ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			p.audiences = append(p.audiences, a)
+		}
+	}
+	if p.issuer == "" || len(p.audiences) == 0 {
+		slog.Warn("session tokens: issuer/audience not enforced, set AUTH_ISSUER and AUTH_AUDIENCE",
+			"issuer", p.issuer, "audiences", p.audiences)
+	}
+	return p
+}
+
+// stamp sets the policy issuer and audience on claims about to be signed.
+func (p tokenPolicy) stamp(c *auth.HorosClaims) {
+	if p.issuer != "" {
+		c.Issuer = p.issuer
+	}
+	if len(p.audiences) > 0 {
+		c.Audience = []string{p.audiences[0]}
+	}
+}
+
+// check rejects claims whose iss or aud do not match the policy.
+func (p tokenPolicy) check(c *auth.HorosClaims) error {
+	if p.issuer != "" && c.Issuer != p.issuer {
+		return errWrongIssuer
+	}
+	if len(p.audiences) > 0 && !slices.ContainsFunc(c.Audience, func(a string) bool {
+		return slices.Contains(p.audiences, a)
+	}) {
+		return errWrongAudience
+	}
+	return nil
+}
+
+// requireSession returns 401 JSON if no valid JWT claims are in context or
+// if they fail policy, and otherwise records the user as the veille actor
+// (audit). Used on API routes; auth.Middleware (applied globally) does the
+// soft parsing.
+func requireSession(policy tokenPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c := auth.GetClaims(r.Context())
+			if c == nil {
+				writeErrorMessage(w, 401, "unauthenticated", "non authentifie")
+				return
+			}
+			if err := policy.check(c); err != nil {
+				writeError(w, 401, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(veille.WithActor(r.Context(), c.UserID)))
+		})
+	}
+}
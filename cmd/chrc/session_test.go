@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/pkg/auth"
+)
+
+var testSecret = sha256.Sum256([]byte("test-secret"))
+
+// sessionCookies signs claims and returns them as session cookies.
+func sessionCookies(t *testing.T, claims *auth.HorosClaims) []*http.Cookie {
+	t.Helper()
+	token, err := auth.GenerateToken(testSecret[:], claims, time.Hour)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	cw := httptest.NewRecorder()
+	auth.SetTokenCookie(cw, token, "", false)
+	return cw.Result().Cookies()
+}
+
+// sessionRequest runs a request carrying claims through auth.Middleware and
+// requireSession(policy).
+func sessionRequest(t *testing.T, policy tokenPolicy, claims *auth.HorosClaims) int {
+	t.Helper()
+	h := auth.Middleware(testSecret[:])(requireSession(policy)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(200)
+	})))
+	req := httptest.NewRequest("GET", "/api/auth/me", nil)
+	for _, c := range sessionCookies(t, claims) {
+		req.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequireSession_IssuerAudience(t *testing.T) {
+	// WHAT: Tokens are accepted only with the expected issuer and one of the expected audiences.
+	// WHY: A token minted for another service sharing the secret must not open a chrc session.
+	policy := tokenPolicy{issuer: "chrc", audiences: []string{"veille", "veille-admin"}}
+	stamped := func() *auth.HorosClaims {
+		c := &auth.HorosClaims{UserID: "u1", Username: "u", Role: "user"}
+		policy.stamp(c)
+		return c
+	}
+
+	if code := sessionRequest(t, policy, stamped()); code != 200 {
+		t.Errorf("stamped token: got %d, want 200", code)
+	}
+
+	second := stamped()
+	second.Audience = []string{"veille-admin"}
+	if code := sessionRequest(t, policy, second); code != 200 {
+		t.Errorf("second audience: got %d, want 200", code)
+	}
+
+	wrongAud := stamped()
+	wrongAud.Audience = []string{"sas_ingester"}
+	if code := sessionRequest(t, policy, wrongAud); code != 401 {
+		t.Errorf("wrong audience: got %d, want 401", code)
+	}
+
+	wrongIss := stamped()
+	wrongIss.Issuer = "other"
+	if code := sessionRequest(t, policy, wrongIss); code != 401 {
+		t.Errorf("wrong issuer: got %d, want 401", code)
+	}
+
+	// Without a policy, any valid token passes (backward compatibility).
+	if code := sessionRequest(t, tokenPolicy{}, wrongIss); code != 200 {
+		t.Errorf("no policy: got %d, want 200", code)
+	}
+}
+
+func TestMCPAuthenticator(t *testing.T) {
+	// WHAT: An MCP bearer token is accepted like a session cookie and yields the session's search scope.
+	// WHY: veille_search_all over MCP/QUIC is scoped by this token; a forged, expired or foreign token must not pass.
+	policy := tokenPolicy{issuer: "chrc", audiences: []string{"veille"}}
+	authenticate := mcpAuthenticator(testSecret[:], policy)
+	sign := func(c *auth.HorosClaims, ttl time.Duration) string {
+		token, err := auth.GenerateToken(testSecret[:], c, ttl)
+		if err != nil {
+			t.Fatalf("generate token: %v", err)
+		}
+		return token
+	}
+	claims := &auth.HorosClaims{UserID: "u1", Username: "u", Role: "admin"}
+	policy.stamp(claims)
+
+	caller, err := authenticate(context.Background(), sign(claims, time.Hour))
+	if err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+	if caller.UserID != "u1" || caller.Scope.OwnerID != "u1" || !caller.Scope.Admin {
+		t.Errorf("caller = %+v, want u1 admin scope", caller)
+	}
+
+	if _, err := authenticate(context.Background(), "not-a-jwt"); err == nil {
+		t.Error("garbage token accepted")
+	}
+	if _, err := authenticate(context.Background(), sign(claims, -time.Minute)); err == nil {
+		t.Error("expired token accepted")
+	}
+	foreign := *claims
+	foreign.Issuer = "other"
+	if _, err := authenticate(context.Background(), sign(&foreign, time.Hour)); err == nil {
+		t.Error("foreign issuer accepted")
+	}
+}
@@ -1,5 +1,17 @@
 var Api = (function () {
-    function request(method, path, body) {
+    var refreshing = null;
+
+    // refresh rotates the refresh token once for all concurrent 401s.
+    function refresh() {
+        if (!refreshing) {
+            refreshing = fetch('/api/auth/refresh', { method: 'POST', credentials: 'same-origin' })
+                .then(function (resp) { refreshing = null; return resp.ok; },
+                      function () { refreshing = null; return false; });
+        }
+        return refreshing;
+    }
+
+    function request(method, path, body, retried) {
         var opts = {
             method: method,
             credentials: 'same-origin',
@@ -14,8 +26,16 @@ var Api = (function () {
             .then(function (resp) {
                 EventBus.emit('loading:end');
                 if (resp.status === 401) {
-                    EventBus.emit('auth:failed');
-                    return Promise.reject(new Error('Unauthorized'));
+                    if (retried) {
+                        EventBus.emit('auth:failed');
+                        return Promise.reject(new Error('Unauthorized'));
+                    }
+                    // Access token expired: rotate the refresh token and retry once.
+                    return refresh().then(function (ok) {
+                        if (ok) return request(method, path, body, true);
+                        EventBus.emit('auth:failed');
+                        return Promise.reject(new Error('Unauthorized'));
+                    });
                 }
                 if (!resp.ok) {
                     return resp.json().catch(function () { return {}; }).then(function (data) {
@@ -29,8 +49,9 @@ var Api = (function () {
             })
             .catch(function (err) {
                 EventBus.emit('loading:end');
-                if (err.message !== 'Unauthorized') {
+                if (err.message !== 'Unauthorized' && !err.shown) {
                     Toast.error(err.message);
+                    err.shown = true;
                 }
                 return Promise.reject(err);
             });
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	_ "modernc.org/sqlite"
+)
+
+func openUsersDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE users (
+		id TEXT PRIMARY KEY, name TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'active', created_at INTEGER NOT NULL DEFAULT 0)`); err != nil {
+		t.Fatalf("users table: %v", err)
+	}
+	if err := migrateAuthColumns(db); err != nil {
+		t.Fatalf("migrate auth columns: %v", err)
+	}
+	return db
+}
+
+func storedCost(t *testing.T, db *sql.DB, email string) int {
+	t.Helper()
+	var hash string
+	if err := db.QueryRow(`SELECT password_hash FROM users WHERE email = ?`, email).Scan(&hash); err != nil {
+		t.Fatalf("read hash: %v", err)
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		t.Fatalf("bcrypt cost: %v", err)
+	}
+	return cost
+}
+
+func TestCreateUser_UsesConfiguredCost(t *testing.T) {
+	// WHAT: New password hashes use the configured bcrypt cost, not bcrypt.DefaultCost.
+	// WHY: BCRYPT_COST lets sensitive deployments raise the work factor.
+	db := openUsersDB(t)
+	s := &userService{db: db, bcryptCost: bcrypt.MinCost + 1}
+
+	if _, err := s.createUser(context.Background(), "a@example.com", "A", "secret-pass", "user"); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if got := storedCost(t, db, "a@example.com"); got != bcrypt.MinCost+1 {
+		t.Errorf("cost: got %d, want %d", got, bcrypt.MinCost+1)
+	}
+}
+
+func TestAuthenticate_RehashesWeakHash(t *testing.T) {
+	// WHAT: A successful login with a hash below the target cost upgrades the stored hash.
+	// WHY: Raising BCRYPT_COST must eventually apply to existing users without a password reset.
+	db := openUsersDB(t)
+	ctx := context.Background()
+
+	old := &userService{db: db, bcryptCost: bcrypt.MinCost}
+	if _, err := old.createUser(ctx, "b@example.com", "B", "secret-pass", "user"); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	s := &userService{db: db, bcryptCost: bcrypt.MinCost + 2}
+	if _, err := s.authenticate(ctx, "b@example.com", "wrong"); err == nil {
+		t.Fatal("wrong password should fail")
+	}
+	if got := storedCost(t, db, "b@example.com"); got != bcrypt.MinCost {
+		t.Fatalf("failed login must not rehash: cost %d", got)
+	}
+
+	if _, err := s.authenticate(ctx, "b@example.com", "secret-pass"); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if got := storedCost(t, db, "b@example.com"); got != bcrypt.MinCost+2 {
+		t.Errorf("cost after login: got %d, want %d", got, bcrypt.MinCost+2)
+	}
+	if _, err := s.authenticate(ctx, "b@example.com", "secret-pass"); err != nil {
+		t.Errorf("login with rehashed password: %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	// WHAT: A wrong current password is rejected; after a change only the new password logs in.
+	// WHY: Self-service change must prove knowledge of the old password and take effect at once.
+	db := openUsersDB(t)
+	ctx := context.Background()
+	s := &userService{db: db, bcryptCost: bcrypt.MinCost}
+
+	u, err := s.createUser(ctx, "c@example.com", "C", "first-password", "user")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := s.changePassword(ctx, u["id"], "not-the-password", "second-password"); !errors.Is(err, errWrongPassword) {
+		t.Fatalf("wrong current: err = %v, want errWrongPassword", err)
+	}
+	if err := s.changePassword(ctx, u["id"], "first-password", "second-password"); err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+	if _, err := s.authenticate(ctx, "c@example.com", "second-password"); err != nil {
+		t.Errorf("login with new password: %v", err)
+	}
+	if _, err := s.authenticate(ctx, "c@example.com", "first-password"); err == nil {
+		t.Error("old password still accepted")
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	db := openUsersDB(t)
+	ctx := context.Background()
+	s := &userService{db: db, bcryptCost: bcrypt.MinCost}
+
+	if _, err := s.createUser(ctx, "d@example.com", "D", "short", "user"); !errors.Is(err, errWeakPassword) {
+		t.Errorf("short password: err = %v, want errWeakPassword", err)
+	}
+	u, err := s.createUser(ctx, "longer-email@example.com", "D", "valid-password", "user")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := s.setPassword(ctx, u["id"], "Longer-Email@example.com"); !errors.Is(err, errWeakPassword) {
+		t.Errorf("email as password: err = %v, want errWeakPassword", err)
+	}
+	if err := s.setPassword(ctx, "missing", "valid-password"); !errors.Is(err, errUserNotFound) {
+		t.Errorf("unknown user: err = %v, want errUserNotFound", err)
+	}
+}
@@ -6,6 +6,7 @@
 //	domwatch -config domwatch.yaml          # observe pages from YAML config
 //	domwatch -url https://example.com       # quick single-page observation
 //	domwatch -profile https://example.com   # profile a single page
+//	domwatch -profile https://example.com -screenshot -full-page
 package main
 
 import (
@@ -29,6 +30,8 @@ func main() {
 	configPath := flag.String("config", "", "path to domwatch.yaml config file")
 	singleURL := flag.String("url", "", "observe a single URL (stdout sink)")
 	profileURL := flag.String("profile", "", "profile a single URL and exit")
+	screenshot := flag.Bool("screenshot", false, "with -profile: include a PNG screenshot (base64)")
+	fullPage := flag.Bool("full-page", false, "with -screenshot: capture the whole page, not the viewport")
 	logLevel := flag.String("log-level", "info", "log level: debug, info, warn, error")
 	flag.Parse()
 
@@ -48,15 +51,16 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	if err := run(ctx, logger, *configPath, *singleURL, *profileURL); err != nil {
+	shot := domwatch.ProfileOptions{Screenshot: *screenshot, FullPage: *fullPage}
+	if err := run(ctx, logger, *configPath, *singleURL, *profileURL, shot); err != nil {
 		logger.Error("domwatch: fatal", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, logger *slog.Logger, configPath, singleURL, profileURL string) error {
+func run(ctx context.Context, logger *slog.Logger, configPath, singleURL, profileURL string, shot domwatch.ProfileOptions) error {
 	if profileURL != "" {
-		return runProfile(ctx, logger, profileURL)
+		return runProfile(ctx, logger, profileURL, shot)
 	}
 
 	if singleURL != "" {
@@ -70,7 +74,7 @@ func run(ctx context.Context, logger *slog.Logger, configPath, singleURL, profil
 	return fmt.Errorf("usage: domwatch -config <file> | -url <url> | -profile <url>")
 }
 
-func runProfile(ctx context.Context, logger *slog.Logger, url string) error {
+func runProfile(ctx context.Context, logger *slog.Logger, url string, shot domwatch.ProfileOptions) error {
 	cfg := defaultConfig()
 	stdout := domwatch.NewStdoutSink(nil)
 	w := domwatch.New(cfg, logger, stdout)
@@ -80,7 +84,7 @@ func runProfile(ctx context.Context, logger *slog.Logger, url string) error {
 	}
 	defer w.Stop()
 
-	prof, err := w.ProfilePage(ctx, url, idgen.New())
+	prof, err := w.ProfilePage(ctx, url, idgen.New(), shot)
 	if err != nil {
 		return fmt.Errorf("profile: %w", err)
 	}
@@ -127,7 +131,17 @@ func runConfig(ctx context.Context, logger *slog.Logger, path string) error {
 		case "stdout":
 			sinks = append(sinks, domwatch.NewStdoutSink(nil))
 		case "webhook":
-			sinks = append(sinks, domwatch.NewWebhookSink(sc.URL, logger))
+			sinks = append(sinks, domwatch.NewWebhookSinkFromConfig(sc, logger))
+		case "file":
+			fs, err := domwatch.NewFileSink(sc.Path, domwatch.FileSinkOptions{
+				MaxBytes: sc.MaxBytes,
+				MaxAge:   sc.MaxAge,
+				Logger:   logger,
+			})
+			if err != nil {
+				return fmt.Errorf("file sink: %w", err)
+			}
+			sinks = append(sinks, fs)
 		default:
 			logger.Warn("domwatch: unknown sink type", "type", sc.Type)
 		}
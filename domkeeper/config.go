@@ -29,6 +29,8 @@ type SchedulerConfig struct {
 	MaxFailCount     int           `yaml:"max_fail_count"`
 	Visibility       time.Duration `yaml:"visibility"`
 	PollInterval     time.Duration `yaml:"poll_interval"`
+	// ReExtractLimit caps the pages queued by one ReExtract call.
+	ReExtractLimit int `yaml:"reextract_limit"`
 }
 
 func (c *Config) defaults() {
@@ -59,6 +61,9 @@ func (c *Config) defaults() {
 	if c.Scheduler.PollInterval <= 0 {
 		c.Scheduler.PollInterval = 5 * time.Second
 	}
+	if c.Scheduler.ReExtractLimit <= 0 {
+		c.Scheduler.ReExtractLimit = 500
+	}
 }
 
 // LoadConfigFile reads a YAML config file.
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Registers domkeeper service handlers (search, rules, stats, GPU) on a connectivity Router.
+// CLAUDE:SUMMARY Registers domkeeper service handlers (search, rules, stats, GPU, re-extraction) on a connectivity Router.
 package domkeeper
 
 import (
@@ -23,6 +23,7 @@ import (
 //	domkeeper_stats          — get domkeeper statistics
 //	domkeeper_gpu_stats      — get GPU pricing and threshold data
 //	domkeeper_gpu_threshold  — recompute GPU serverless vs dedicated decision
+//	domkeeper_reextract      — queue re-extraction of pages matching a url_pattern
 func (k *Keeper) RegisterConnectivity(router *connectivity.Router) {
 	router.RegisterLocal("domkeeper_search", k.handleSearch)
 	router.RegisterLocal("domkeeper_premium_search", k.handlePremiumSearch)
@@ -32,6 +33,7 @@ func (k *Keeper) RegisterConnectivity(router *connectivity.Router) {
 	router.RegisterLocal("domkeeper_delete_rule", k.handleDeleteRule)
 	router.RegisterLocal("domkeeper_gpu_stats", k.handleGPUStats)
 	router.RegisterLocal("domkeeper_gpu_threshold", k.handleGPUThreshold)
+	router.RegisterLocal("domkeeper_reextract", k.handleReExtract)
 }
 
 func (k *Keeper) handleSearch(ctx context.Context, payload []byte) ([]byte, error) {
@@ -166,3 +168,19 @@ func (k *Keeper) handleGPUThreshold(ctx context.Context, payload []byte) ([]byte
 	}
 	return json.Marshal(threshold)
 }
+
+// handleReExtract accepts a domregistry CorrectionEvent (or any payload with
+// url_pattern), so it can be set as domregistry's on_accept_service.
+func (k *Keeper) handleReExtract(ctx context.Context, payload []byte) ([]byte, error) {
+	var req struct {
+		URLPattern string `json:"url_pattern"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	queued, err := k.ReExtract(ctx, req.URLPattern)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]any{"status": "queued", "url_pattern": req.URLPattern, "queued": queued})
+}
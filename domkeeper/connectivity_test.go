@@ -3,8 +3,11 @@ package domkeeper
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"testing"
 
+	"github.com/hazyhaar/chrc/domkeeper/internal/schedule"
 	"github.com/hazyhaar/chrc/domkeeper/internal/store"
 	"github.com/hazyhaar/pkg/connectivity"
 	"github.com/hazyhaar/pkg/dbopen"
@@ -188,3 +191,51 @@ func TestConn_GPUThreshold(t *testing.T) {
 		t.Errorf("BacklogUnits = %d, want 50", threshold.BacklogUnits)
 	}
 }
+
+func TestConn_ReExtract(t *testing.T) {
+	// WHAT: domkeeper_reextract queues one refresh job per stored page matching the pattern.
+	// WHY: A domregistry correction must refresh stale content, and only that content.
+	k, router := testKeeperConn(t)
+	ctx := context.Background()
+	k.logger = slog.Default()
+	var jobs []schedule.RefreshJob
+	k.publish = func(_ context.Context, _ string, payload []byte) error {
+		var j schedule.RefreshJob
+		json.Unmarshal(payload, &j)
+		jobs = append(jobs, j)
+		return nil
+	}
+
+	k.AddRule(ctx, &store.Rule{
+		ID: "r1", Name: "news", URLPattern: "https://news.example.com/*",
+		ExtractMode: "auto", TrustLevel: "official", Enabled: true,
+	})
+	for i, u := range []string{"https://news.example.com/a", "https://news.example.com/b", "https://news.example.com/a", "https://other.example.com/a"} {
+		k.store.InsertContent(ctx, &store.Content{
+			ID: fmt.Sprint("c", i), RuleID: "r1", PageURL: u,
+			ContentHash: fmt.Sprint("h", i), ExtractedText: "text", TrustLevel: "official",
+		})
+	}
+
+	payload, _ := json.Marshal(map[string]any{"url_pattern": "https://news.example.com/*", "profile_id": "p1"})
+	resp, err := router.Call(ctx, "domkeeper_reextract", payload)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	var out struct {
+		Queued int `json:"queued"`
+	}
+	json.Unmarshal(resp, &out)
+	if out.Queued != 2 || len(jobs) != 2 {
+		t.Fatalf("queued = %d, jobs = %d, want 2", out.Queued, len(jobs))
+	}
+	for _, j := range jobs {
+		if j.RuleID != "r1" || j.PageURL == "https://other.example.com/a" {
+			t.Errorf("unexpected job %+v", j)
+		}
+	}
+
+	if _, err := router.Call(ctx, "domkeeper_reextract", []byte(`{}`)); err == nil {
+		t.Error("expected error without url_pattern")
+	}
+}
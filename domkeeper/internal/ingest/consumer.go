@@ -113,6 +113,11 @@ func (c *Consumer) HandleBatch(ctx context.Context, batch mutation.Batch) error
 // This is the primary extraction trigger.
 func (c *Consumer) HandleSnapshot(ctx context.Context, snap mutation.Snapshot) error {
 	log := c.logger.With("snapshot_id", snap.ID, "page_url", snap.PageURL, "page_id", snap.PageID)
+	if snap.IsDiff() {
+		// Diff-mode snapshot: no HTML to extract from, the next keyframe is.
+		log.Debug("ingest: diff snapshot skipped")
+		return nil
+	}
 	log.Info("ingest: snapshot received", "html_size", len(snap.HTML))
 
 	entry := &store.IngestEntry{
@@ -230,6 +235,7 @@ func (c *Consumer) extractAndStore(ctx context.Context, rule *store.Rule, snap m
 		ExtractedText: cleanText,
 		ExtractedHTML: result.HTML,
 		Title:         result.Title,
+		Metadata:      resultMetadata(result),
 		TrustLevel:    rule.TrustLevel,
 	}
 
@@ -269,6 +275,20 @@ func (c *Consumer) extractAndStore(ctx context.Context, rule *store.Rule, snap m
 	return 1, nil
 }
 
+// resultMetadata encodes the article metadata found by the readability
+// mode as the content metadata JSON, or "" when there is none.
+func resultMetadata(r *extract.Result) string {
+	if r.Author == "" && r.Published == "" {
+		return ""
+	}
+	meta := struct {
+		Author    string `json:"author,omitempty"`
+		Published string `json:"published,omitempty"`
+	}{r.Author, r.Published}
+	b, _ := json.Marshal(meta)
+	return string(b)
+}
+
 // ExtractFromHTML runs extraction directly on raw HTML for a specific rule.
 // Used by the manual ingest command.
 func (c *Consumer) ExtractFromHTML(ctx context.Context, ruleID string, pageURL string, rawHTML []byte) (int, error) {
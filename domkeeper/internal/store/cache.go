@@ -123,6 +123,44 @@ func (s *Store) LatestContentByRule(ctx context.Context, ruleID string) (*Conten
 	return c, nil
 }
 
+// ContentPage is a page with stored content for a rule.
+type ContentPage struct {
+	RuleID  string `json:"rule_id"`
+	PageURL string `json:"page_url"`
+	PageID  string `json:"page_id,omitempty"`
+}
+
+// ListContentPages returns the distinct (rule, page) pairs of enabled rules
+// whose stored content page_url matches the GLOB urlPattern, most recently
+// extracted first.
+func (s *Store) ListContentPages(ctx context.Context, urlPattern string, limit int) ([]*ContentPage, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT c.rule_id, c.page_url, MAX(c.page_id)
+		FROM content_cache c
+		JOIN extraction_rules r ON r.id = c.rule_id
+		WHERE r.enabled = 1 AND c.page_url GLOB ?
+		GROUP BY c.rule_id, c.page_url
+		ORDER BY MAX(c.extracted_at) DESC
+		LIMIT ?`, urlPattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pages []*ContentPage
+	for rows.Next() {
+		p := &ContentPage{}
+		if err := rows.Scan(&p.RuleID, &p.PageURL, &p.PageID); err != nil {
+			return nil, err
+		}
+		pages = append(pages, p)
+	}
+	return pages, rows.Err()
+}
+
 // DeleteExpiredContent removes content past its expires_at timestamp.
 func (s *Store) DeleteExpiredContent(ctx context.Context) (int64, error) {
 	now := time.Now().UnixMilli()
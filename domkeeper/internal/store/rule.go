@@ -16,7 +16,7 @@ type Rule struct {
 	URLPattern  string   `json:"url_pattern"`
 	PageID      string   `json:"page_id,omitempty"`
 	Selectors   []string `json:"selectors"`
-	ExtractMode string   `json:"extract_mode"` // "css", "xpath", "density", "auto"
+	ExtractMode string   `json:"extract_mode"` // "css", "xpath", "density", "readability", "auto"
 	TrustLevel  string   `json:"trust_level"`  // "official", "institutional", "community", "unverified"
 	FolderID    string   `json:"folder_id,omitempty"`
 	Enabled     bool     `json:"enabled"`
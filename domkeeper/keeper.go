@@ -11,6 +11,7 @@
 //   - Content deduplication: SHA-256 hash prevents redundant storage
 //   - Overlapping chunks: RAG-ready text fragments with FTS5 search
 //   - Auto-repair: profile-driven rule creation, failure tracking
+//   - Re-extraction: ReExtract queues refresh jobs when a profile is corrected
 //   - MCP tools: search, add/list/delete rules, folders, ingest status
 //   - Connectivity: registers local handlers for inter-service routing
 //
@@ -26,13 +27,16 @@ package domkeeper
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/hazyhaar/chrc/chunk"
 	"github.com/hazyhaar/chrc/domkeeper/internal/ingest"
 	"github.com/hazyhaar/chrc/domkeeper/internal/schedule"
 	"github.com/hazyhaar/chrc/domkeeper/internal/store"
 	"github.com/hazyhaar/chrc/domwatch/mutation"
+	"github.com/hazyhaar/chrc/extract"
 	"github.com/hazyhaar/pkg/vtq"
 )
 
@@ -44,6 +48,9 @@ type Keeper struct {
 	queue     *vtq.Q
 	logger    *slog.Logger
 	config    *Config
+
+	// publish enqueues a refresh job; queue.Publish outside tests.
+	publish func(ctx context.Context, id string, payload []byte) error
 }
 
 // New creates a Keeper instance. Opens the SQLite database and initialises
@@ -92,6 +99,7 @@ func New(cfg *Config, logger *slog.Logger) (*Keeper, error) {
 		queue:     q,
 		logger:    logger,
 		config:    cfg,
+		publish:   q.Publish,
 	}, nil
 }
 
@@ -133,6 +141,9 @@ func (k *Keeper) Search(ctx context.Context, opts store.SearchOptions) ([]*store
 
 // AddRule creates a new extraction rule.
 func (k *Keeper) AddRule(ctx context.Context, rule *store.Rule) error {
+	if rule.ExtractMode != "" && !extract.ValidMode(rule.ExtractMode) {
+		return fmt.Errorf("unknown extract_mode %q (valid: %s)", rule.ExtractMode, strings.Join(extract.Modes, ", "))
+	}
 	return k.store.InsertRule(ctx, rule)
 }
 
@@ -103,7 +103,7 @@ func (k *Keeper) registerAddRuleTool(srv *mcp.Server) {
 			"url_pattern":  map[string]any{"type": "string", "description": "URL glob pattern to match (e.g. https://example.com/*)"},
 			"page_id":      map[string]any{"type": "string", "description": "Optional: specific domwatch page_id"},
 			"selectors":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "CSS selectors or XPath expressions"},
-			"extract_mode": map[string]any{"type": "string", "enum": []any{"css", "xpath", "density", "auto"}, "description": "Extraction mode (default: auto)"},
+			"extract_mode": map[string]any{"type": "string", "enum": []any{"css", "xpath", "density", "readability", "auto"}, "description": "Extraction mode (default: auto)"},
 			"trust_level":  map[string]any{"type": "string", "enum": []any{"official", "institutional", "community", "unverified"}, "description": "Trust level"},
 			"folder_id":    map[string]any{"type": "string", "description": "Target folder ID"},
 			"priority":     map[string]any{"type": "integer", "description": "Priority (higher = first)"},
@@ -0,0 +1,43 @@
+// CLAUDE:SUMMARY ReExtract — queues VTQ refresh jobs for stored pages matching a URL pattern (domregistry correction hook).
+package domkeeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hazyhaar/chrc/domkeeper/internal/schedule"
+	"github.com/hazyhaar/pkg/idgen"
+)
+
+// ReExtract queues a refresh job on the domkeeper_refresh queue for every
+// page whose stored content matches urlPattern (GLOB, same syntax as rule
+// url_pattern), most recent first. At most Scheduler.ReExtractLimit pages
+// are queued so a broad pattern cannot stall the caller; extraction itself
+// runs when the jobs are consumed. Returns the number of jobs queued.
+func (k *Keeper) ReExtract(ctx context.Context, urlPattern string) (int, error) {
+	if urlPattern == "" {
+		return 0, fmt.Errorf("url_pattern required")
+	}
+	pages, err := k.store.ListContentPages(ctx, urlPattern, k.config.Scheduler.ReExtractLimit)
+	if err != nil {
+		return 0, fmt.Errorf("list pages: %w", err)
+	}
+
+	var queued int
+	for _, p := range pages {
+		payload, _ := json.Marshal(schedule.RefreshJob{
+			RuleID:  p.RuleID,
+			PageURL: p.PageURL,
+			PageID:  p.PageID,
+		})
+		if err := k.publish(ctx, idgen.New(), payload); err != nil {
+			k.logger.Warn("domkeeper: re-extract publish failed", "rule_id", p.RuleID, "page_url", p.PageURL, "error", err)
+			continue
+		}
+		queued++
+	}
+
+	k.logger.Info("domkeeper: re-extraction queued", "url_pattern", urlPattern, "pages", len(pages), "queued", queued)
+	return queued, nil
+}
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Configuration struct and defaults for domregistry — DB path, auto-accept, degraded threshold.
+// CLAUDE:SUMMARY Configuration struct and defaults for domregistry — DB path, auto-accept, degraded threshold, accept hook.
 package domregistry
 
 // Config holds the domregistry configuration.
@@ -11,6 +11,11 @@ type Config struct {
 	// DegradedThreshold is the success_rate below which a profile is marked degraded.
 	// Default: 0.5
 	DegradedThreshold float64 `json:"degraded_threshold" yaml:"degraded_threshold"`
+
+	// OnAcceptService is the connectivity service called with a
+	// CorrectionEvent each time a correction is accepted, e.g.
+	// "domkeeper_reextract". Empty disables the hook.
+	OnAcceptService string `json:"on_accept_service" yaml:"on_accept_service"`
 }
 
 func (c *Config) defaults() {
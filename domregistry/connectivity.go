@@ -15,14 +15,18 @@ import (
 //
 // Registered services:
 //
-//	domregistry_search_profiles   — search profiles by domain
+//	domregistry_search_profiles   — search profiles by domain, ranked by score
 //	domregistry_get_profile       — get a profile by ID
 //	domregistry_publish_profile   — publish or update a profile
 //	domregistry_submit_correction — submit an extractor correction
 //	domregistry_report_failure    — report a profile failure
 //	domregistry_leaderboard       — get domain leaderboard
 //	domregistry_stats             — get registry statistics
+//
+// The router is also used to call Config.OnAcceptService when a correction
+// is accepted.
 func (r *Registry) RegisterConnectivity(router *connectivity.Router) {
+	r.router = router
 	router.RegisterLocal("domregistry_search_profiles", r.handleSearchProfiles)
 	router.RegisterLocal("domregistry_get_profile", r.handleGetProfile)
 	router.RegisterLocal("domregistry_publish_profile", r.handlePublishProfile)
@@ -33,23 +37,12 @@ func (r *Registry) RegisterConnectivity(router *connectivity.Router) {
 }
 
 func (r *Registry) handleSearchProfiles(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		Domain     string `json:"domain"`
-		TrustLevel string `json:"trust_level"`
-		Limit      int    `json:"limit"`
-	}
+	var req SearchOptions
 	if err := json.Unmarshal(payload, &req); err != nil {
 		return nil, fmt.Errorf("decode: %w", err)
 	}
 
-	if req.Domain != "" {
-		profiles, err := r.SearchProfiles(ctx, req.Domain)
-		if err != nil {
-			return nil, err
-		}
-		return json.Marshal(profiles)
-	}
-	profiles, err := r.ListProfiles(ctx, req.TrustLevel, req.Limit)
+	profiles, err := r.SearchProfiles(ctx, req)
 	if err != nil {
 		return nil, err
 	}
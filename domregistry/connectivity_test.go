@@ -3,6 +3,7 @@ package domregistry
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"testing"
 
@@ -184,3 +185,55 @@ func TestConn_Stats(t *testing.T) {
 		t.Errorf("Profiles = %d, want 0", stats.Profiles)
 	}
 }
+
+func TestConn_SearchProfiles_RankedByScore(t *testing.T) {
+	// WHAT: Failure reports push an official profile below a clean community one; min_score filters.
+	// WHY: Instances importing a profile must get the one that currently works, not the most "official".
+	r, router := testRegistryConn(t)
+	ctx := context.Background()
+
+	r.PublishProfile(ctx, &store.Profile{
+		ID: "official", URLPattern: "https://example.com/news/*", Domain: "example.com",
+		Extractors: `{}`, DOMProfile: `{}`, TrustLevel: "official",
+	})
+	r.PublishProfile(ctx, &store.Profile{
+		ID: "community", URLPattern: "https://example.com/*", Domain: "example.com",
+		Extractors: `{}`, DOMProfile: `{}`, TrustLevel: "community",
+	})
+
+	search := func(minScore float64) []*store.Profile {
+		t.Helper()
+		payload, _ := json.Marshal(map[string]any{"domain": "example.com", "min_score": minScore})
+		resp, err := router.Call(ctx, "domregistry_search_profiles", payload)
+		if err != nil {
+			t.Fatalf("Call: %v", err)
+		}
+		var profiles []*store.Profile
+		json.Unmarshal(resp, &profiles)
+		return profiles
+	}
+
+	got := search(0)
+	if len(got) != 2 || got[0].ID != "official" || got[0].Score <= got[1].Score {
+		t.Fatalf("before reports: got %+v, want official first", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		r.ReportFailure(ctx, &store.Report{
+			ID: fmt.Sprint("rep", i), ProfileID: "official", InstanceID: "inst-1", ErrorType: "selector_broken",
+		})
+	}
+
+	got = search(0)
+	if len(got) != 2 || got[0].ID != "community" {
+		t.Fatalf("after reports: got %+v, want community first", got)
+	}
+	if got[1].Score >= got[0].Score {
+		t.Errorf("scores not descending: %f, %f", got[0].Score, got[1].Score)
+	}
+
+	got = search(0.5)
+	if len(got) != 1 || got[0].ID != "community" {
+		t.Errorf("min_score 0.5: got %+v, want only community", got)
+	}
+}
@@ -24,6 +24,9 @@ type Profile struct {
 	Contributors []string `json:"contributors"` // instance IDs
 	CreatedAt    int64    `json:"created_at"`
 	UpdatedAt    int64    `json:"updated_at"`
+
+	// Score is the reliability score, set by search only (see ProfileScore).
+	Score float64 `json:"score,omitempty"`
 }
 
 // InsertProfile inserts a new community profile.
@@ -0,0 +1,82 @@
+// CLAUDE:SUMMARY Profile reliability score — trust level weight, penalised by recent failure reports, boosted by accepted corrections.
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ScoreWindow is how far back failure reports count against a profile.
+const ScoreWindow = 7 * 24 * time.Hour
+
+// Score tuning. A profile loses half its trust weight after two recent
+// failure reports; each accepted correction adds a small bonus, capped.
+const (
+	failurePenalty  = 0.5
+	correctionBonus = 0.05
+	maxCorrections  = 4
+)
+
+// TrustWeight is the base score of a trust level.
+func TrustWeight(level string) float64 {
+	switch level {
+	case "official":
+		return 1.0
+	case "institutional":
+		return 0.8
+	case "community":
+		return 0.6
+	default:
+		return 0.4
+	}
+}
+
+// ProfileScore combines trust level, failure reports within ScoreWindow and
+// accepted corrections into a reliability score (higher is better).
+func ProfileScore(trustLevel string, recentFailures, acceptedCorrections int) float64 {
+	score := TrustWeight(trustLevel) / (1 + failurePenalty*float64(recentFailures))
+	return score + correctionBonus*float64(min(acceptedCorrections, maxCorrections))
+}
+
+// ScoreProfiles sets Score on each profile, counting failure reports
+// created at or after since (Unix ms) and all accepted corrections.
+func (s *Store) ScoreProfiles(ctx context.Context, profiles []*Profile, since int64) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+	failures, err := s.countByProfile(ctx, `
+		SELECT profile_id, COUNT(*) FROM reports
+		WHERE created_at >= ? GROUP BY profile_id`, since)
+	if err != nil {
+		return err
+	}
+	accepted, err := s.countByProfile(ctx, `
+		SELECT profile_id, COUNT(*) FROM corrections
+		WHERE validated = 1 GROUP BY profile_id`)
+	if err != nil {
+		return err
+	}
+	for _, p := range profiles {
+		p.Score = ProfileScore(p.TrustLevel, failures[p.ID], accepted[p.ID])
+	}
+	return nil
+}
+
+func (s *Store) countByProfile(ctx context.Context, query string, args ...any) (map[string]int, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
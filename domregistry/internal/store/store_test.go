@@ -365,3 +365,34 @@ func TestDomainLeaderboard(t *testing.T) {
 		t.Errorf("profile_count for a.com: got %d, want 2", entries[0].ProfileCount)
 	}
 }
+
+func TestScoreProfiles(t *testing.T) {
+	// WHAT: Only reports inside the window count; accepted corrections add a capped bonus.
+	// WHY: A profile fixed long ago must not stay penalised forever.
+	s := testStore(t)
+	ctx := context.Background()
+
+	s.InsertProfile(ctx, &Profile{
+		ID: "p1", URLPattern: "https://a.com/*", Domain: "a.com",
+		Extractors: `{}`, DOMProfile: `{}`, TrustLevel: "community",
+	})
+	s.InsertReport(ctx, &Report{ID: "old", ProfileID: "p1", InstanceID: "i", CreatedAt: 1000})
+	s.InsertReport(ctx, &Report{ID: "new", ProfileID: "p1", InstanceID: "i", CreatedAt: 5000})
+	s.InsertCorrection(ctx, &Correction{ID: "c1", ProfileID: "p1", InstanceID: "i", NewExtractors: `{}`})
+	s.AcceptCorrection(ctx, "c1")
+
+	profiles, _ := s.ListProfiles(ctx, "", 0)
+	if err := s.ScoreProfiles(ctx, profiles, 2000); err != nil {
+		t.Fatalf("score: %v", err)
+	}
+	if want := ProfileScore("community", 1, 1); profiles[0].Score != want {
+		t.Errorf("score = %f, want %f", profiles[0].Score, want)
+	}
+
+	if ProfileScore("official", 0, 0) != 1.0 {
+		t.Errorf("clean official profile should score 1.0")
+	}
+	if ProfileScore("community", 0, 100) != ProfileScore("community", 0, maxCorrections) {
+		t.Errorf("correction bonus should be capped")
+	}
+}
@@ -36,28 +36,32 @@ func inputSchema(properties map[string]any, required []string) map[string]any {
 // --- search_profiles ---
 
 type searchProfilesRequest struct {
-	Domain     string `json:"domain"`
-	TrustLevel string `json:"trust_level,omitempty"`
-	Limit      int    `json:"limit,omitempty"`
+	Domain     string  `json:"domain"`
+	TrustLevel string  `json:"trust_level,omitempty"`
+	MinScore   float64 `json:"min_score,omitempty"`
+	Limit      int     `json:"limit,omitempty"`
 }
 
 func (r *Registry) registerSearchProfilesTool(srv *mcp.Server) {
 	tool := &mcp.Tool{
 		Name:        "domregistry_search_profiles",
-		Description: "Search community DOM profiles by domain. Returns extraction strategies and DOM structure for matching sites.",
+		Description: "Search community DOM profiles by domain, most reliable first. Returns extraction strategies, DOM structure and a reliability score (trust level, recent failures, accepted corrections) for matching sites.",
 		InputSchema: inputSchema(map[string]any{
 			"domain":      map[string]any{"type": "string", "description": "Domain to search for (e.g. example.com)"},
 			"trust_level": map[string]any{"type": "string", "enum": []any{"official", "institutional", "community"}, "description": "Filter by trust level"},
+			"min_score":   map[string]any{"type": "number", "description": "Minimum reliability score (official profile with no failures = 1.0)"},
 			"limit":       map[string]any{"type": "integer", "description": "Max results (default 20)"},
 		}, []string{"domain"}),
 	}
 
 	endpoint := func(ctx context.Context, req any) (any, error) {
 		rr := req.(*searchProfilesRequest)
-		if rr.Domain != "" {
-			return r.SearchProfiles(ctx, rr.Domain)
-		}
-		return r.ListProfiles(ctx, rr.TrustLevel, rr.Limit)
+		return r.SearchProfiles(ctx, SearchOptions{
+			Domain:     rr.Domain,
+			TrustLevel: rr.TrustLevel,
+			MinScore:   rr.MinScore,
+			Limit:      rr.Limit,
+		})
 	}
 
 	decode := func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Main domregistry orchestrator — profile CRUD, correction submission with auto-accept, failure reporting, stats.
+// CLAUDE:SUMMARY Main domregistry orchestrator — profile CRUD, score-ranked search, correction submission with auto-accept, accept hook, failure reporting, stats.
 // Package domregistry is the community registry for shared DOM profiles.
 //
 // It centralises extraction profiles from all domkeeper instances. Profiles
@@ -11,6 +11,7 @@
 //	Pull:  domkeeper starts crawling a new domain → queries registry → imports profile
 //	Push:  domkeeper's auto-repair fixes an extractor → submits correction to registry
 //	Report: an extractor fails locally → domkeeper reports failure → registry adjusts success_rate
+//	Accept: a correction is accepted → registry calls Config.OnAcceptService → domkeeper re-extracts
 //
 // Usage:
 //
@@ -22,16 +23,33 @@ package domregistry
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
+	"sort"
+	"time"
 
 	"github.com/hazyhaar/chrc/domregistry/internal/store"
+	"github.com/hazyhaar/pkg/connectivity"
 )
 
+// notifyTimeout bounds one accept hook call.
+const notifyTimeout = 30 * time.Second
+
 // Registry is the main domregistry orchestrator.
 type Registry struct {
 	store  *store.Store
 	logger *slog.Logger
 	config *Config
+	router *connectivity.Router // set by RegisterConnectivity; carries the accept hook
+}
+
+// CorrectionEvent is the payload sent to Config.OnAcceptService when a
+// correction is accepted.
+type CorrectionEvent struct {
+	CorrectionID string `json:"correction_id"`
+	ProfileID    string `json:"profile_id"`
+	URLPattern   string `json:"url_pattern"`
+	Domain       string `json:"domain"`
 }
 
 // New creates a Registry instance. Opens the SQLite database and initialises the schema.
@@ -75,9 +93,47 @@ func (r *Registry) GetProfileByPattern(ctx context.Context, pattern string) (*Pr
 	return r.store.GetProfileByPattern(ctx, pattern)
 }
 
-// SearchProfiles returns profiles for a domain.
-func (r *Registry) SearchProfiles(ctx context.Context, domain string) ([]*Profile, error) {
-	return r.store.ListProfilesByDomain(ctx, domain)
+// SearchOptions filters and limits SearchProfiles.
+type SearchOptions struct {
+	Domain     string  `json:"domain"`      // exact domain; empty lists all profiles
+	TrustLevel string  `json:"trust_level"` // only when Domain is empty
+	MinScore   float64 `json:"min_score"`   // drop profiles scoring below
+	Limit      int     `json:"limit"`       // 0 = no limit
+}
+
+// SearchProfiles returns profiles ranked by reliability score, highest
+// first, with Score set. The score weighs the trust level, failure reports
+// of the last week and accepted corrections (see store.ProfileScore), so a
+// frequently failing official profile sinks below a clean community one.
+func (r *Registry) SearchProfiles(ctx context.Context, opts SearchOptions) ([]*Profile, error) {
+	var profiles []*Profile
+	var err error
+	if opts.Domain != "" {
+		profiles, err = r.store.ListProfilesByDomain(ctx, opts.Domain)
+	} else {
+		profiles, err = r.store.ListProfiles(ctx, opts.TrustLevel, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-store.ScoreWindow).UnixMilli()
+	if err := r.store.ScoreProfiles(ctx, profiles, since); err != nil {
+		return nil, err
+	}
+
+	ranked := profiles[:0]
+	for _, p := range profiles {
+		if p.Score >= opts.MinScore {
+			ranked = append(ranked, p)
+		}
+	}
+	// Stable: equal scores keep the store order (success_rate, total_uses).
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	if opts.Limit > 0 && len(ranked) > opts.Limit {
+		ranked = ranked[:opts.Limit]
+	}
+	return ranked, nil
 }
 
 // ListProfiles returns profiles with optional trust level filter.
@@ -133,6 +189,7 @@ func (r *Registry) SubmitCorrection(ctx context.Context, c *Correction) error {
 		}
 		r.logger.Info("domregistry: correction auto-accepted",
 			"correction_id", c.ID, "profile_id", c.ProfileID, "instance_id", c.InstanceID)
+		r.notifyAccepted(ctx, c.ID, c.ProfileID)
 	} else {
 		r.logger.Info("domregistry: correction pending review",
 			"correction_id", c.ID, "profile_id", c.ProfileID, "instance_id", c.InstanceID)
@@ -143,7 +200,48 @@ func (r *Registry) SubmitCorrection(ctx context.Context, c *Correction) error {
 
 // AcceptCorrection manually accepts a correction.
 func (r *Registry) AcceptCorrection(ctx context.Context, correctionID string) error {
-	return r.store.AcceptCorrection(ctx, correctionID)
+	c, err := r.store.GetCorrection(ctx, correctionID)
+	if err != nil || c == nil || c.Validated != 0 {
+		return err // unknown or already resolved: no-op, no event
+	}
+	if err := r.store.AcceptCorrection(ctx, correctionID); err != nil {
+		return err
+	}
+	r.notifyAccepted(ctx, c.ID, c.ProfileID)
+	return nil
+}
+
+// notifyAccepted sends a CorrectionEvent to Config.OnAcceptService in the
+// background. Failures are logged: the correction stays accepted.
+func (r *Registry) notifyAccepted(ctx context.Context, correctionID, profileID string) {
+	if r.config.OnAcceptService == "" || r.router == nil {
+		return
+	}
+	p, err := r.store.GetProfile(ctx, profileID)
+	if err != nil || p == nil {
+		r.logger.Warn("domregistry: accept hook skipped, profile not found",
+			"correction_id", correctionID, "profile_id", profileID, "error", err)
+		return
+	}
+	payload, _ := json.Marshal(CorrectionEvent{
+		CorrectionID: correctionID,
+		ProfileID:    p.ID,
+		URLPattern:   p.URLPattern,
+		Domain:       p.Domain,
+	})
+
+	service := r.config.OnAcceptService
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+		defer cancel()
+		if _, err := r.router.Call(ctx, service, payload); err != nil {
+			r.logger.Warn("domregistry: accept hook failed",
+				"service", service, "correction_id", correctionID, "error", err)
+			return
+		}
+		r.logger.Info("domregistry: accept hook sent",
+			"service", service, "correction_id", correctionID, "url_pattern", p.URLPattern)
+	}()
 }
 
 // RejectCorrection manually rejects a correction.
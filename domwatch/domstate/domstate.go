@@ -0,0 +1,75 @@
+// CLAUDE:SUMMARY Parses serialised HTML into a mutation.State (stable path → node) for keyframe + diff snapshots.
+// Package domstate builds mutation.State values from HTML. It lives outside
+// mutation so that the contract package stays stdlib-only; consumers
+// replaying diff-mode snapshots parse each keyframe with Parse, then apply
+// the following diffs with mutation.Apply.
+package domstate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+	"golang.org/x/net/html"
+)
+
+// Parse parses serialised HTML into a mutation.State.
+func Parse(raw []byte) (mutation.State, error) {
+	doc, err := html.Parse(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("domstate: parse: %w", err)
+	}
+	s := make(mutation.State)
+	walkState(s, doc, "")
+	return s, nil
+}
+
+func walkState(s mutation.State, parent *html.Node, parentPath string) {
+	counts := make(map[string]int)
+	for c := parent.FirstChild; c != nil; c = c.NextSibling {
+		if key := stateKey(c); key != "" {
+			counts[key]++
+		}
+	}
+	seen := make(map[string]int)
+	for c := parent.FirstChild; c != nil; c = c.NextSibling {
+		key := stateKey(c)
+		if key == "" {
+			continue
+		}
+		seen[key]++
+		path := parentPath + "/" + key
+		if counts[key] > 1 {
+			path = fmt.Sprintf("%s[%d]", path, seen[key])
+		}
+
+		if c.Type == html.TextNode {
+			s[path] = mutation.Node{Text: c.Data}
+			continue
+		}
+		n := mutation.Node{Tag: c.Data}
+		if len(c.Attr) > 0 {
+			n.Attrs = make(map[string]string, len(c.Attr))
+			for _, a := range c.Attr {
+				n.Attrs[a.Key] = a.Val
+			}
+		}
+		s[path] = n
+		walkState(s, c, path)
+	}
+}
+
+// stateKey returns the path segment (without index) of n, or "" to skip it.
+func stateKey(n *html.Node) string {
+	switch n.Type {
+	case html.ElementNode:
+		return strings.ToLower(n.Data)
+	case html.TextNode:
+		if strings.TrimSpace(n.Data) == "" {
+			return ""
+		}
+		return "text()"
+	}
+	return ""
+}
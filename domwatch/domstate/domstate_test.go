@@ -0,0 +1,70 @@
+package domstate
+
+import (
+	"testing"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+func mustParse(t *testing.T, html string) mutation.State {
+	t.Helper()
+	s, err := Parse([]byte(html))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return s
+}
+
+func TestParse_Paths(t *testing.T) {
+	s := mustParse(t, `<html><body><ul><li>a</li><li class="x">b</li></ul><!-- c -->
+		<p>  </p></body></html>`)
+	if n := s["/html/body/ul/li[2]"]; n.Tag != "li" || n.Attrs["class"] != "x" {
+		t.Errorf("li[2]: %+v", n)
+	}
+	if n := s["/html/body/ul/li[1]/text()"]; n.Text != "a" {
+		t.Errorf("li[1] text: %+v", n)
+	}
+	if _, ok := s["/html/body/p/text()"]; ok {
+		t.Error("whitespace-only text kept")
+	}
+	if _, ok := s["/html/body/p"]; !ok {
+		t.Error("unindexed single p missing")
+	}
+}
+
+func TestParse_DiffReconstructsState(t *testing.T) {
+	// WHAT: keyframe state + successive diffs equals the state parsed from the final HTML.
+	// WHY: Consumers of diff mode only ever receive the keyframe HTML and the diffs.
+	pages := []string{
+		`<html><body><p class="price">10</p><div id="stock">in stock</div></body></html>`,
+		`<html><body><p class="price">12</p><div id="stock">in stock</div></body></html>`,
+		`<html><body><p class="price">12</p><span>new</span></body></html>`,
+		`<html><body><p class="price sale">9</p><span>new</span><span>more</span></body></html>`,
+	}
+	state := mustParse(t, pages[0])
+	prev := state
+	for i, html := range pages[1:] {
+		cur := mustParse(t, html)
+		d := mutation.Diff(prev, cur)
+		if d.Empty() {
+			t.Fatalf("step %d: empty diff", i+1)
+		}
+		state = mutation.Apply(state, d)
+		prev = cur
+	}
+	want := mustParse(t, pages[len(pages)-1])
+	if d := mutation.Diff(state, want); !d.Empty() {
+		t.Errorf("reconstructed state differs: %+v", d)
+	}
+}
+
+func TestParse_TextChangeDiff(t *testing.T) {
+	d := mutation.Diff(
+		mustParse(t, `<p>in stock</p>`),
+		mustParse(t, `<p>sold out</p>`),
+	)
+	if len(d.Added)+len(d.Removed) != 0 || len(d.Changed) != 1 ||
+		d.Changed[0].Path != "/html/body/p/text()" || d.Changed[0].Node.Text != "sold out" {
+		t.Errorf("text change: %+v", d)
+	}
+}
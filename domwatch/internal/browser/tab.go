@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Wraps a Rod page with stealth navigation, resource blocking, and full DOM serialization.
+// CLAUDE:SUMMARY Wraps a Rod page with stealth navigation, resource blocking, full DOM serialization, and PNG screenshots.
 package browser
 
 import (
@@ -81,6 +81,18 @@ func (t *Tab) GetFullDOM(ctx context.Context) ([]byte, error) {
 	return []byte(res.Value.Str()), nil
 }
 
+// Screenshot captures a PNG of the viewport, or of the whole scrollable
+// page when fullPage is set.
+func (t *Tab) Screenshot(ctx context.Context, fullPage bool) ([]byte, error) {
+	png, err := t.Page.Context(ctx).Screenshot(fullPage, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("browser: screenshot: %w", err)
+	}
+	return png, nil
+}
+
 // EnableDOMTracking calls DOM.getDocument with depth=-1 to make all nodes
 // trackable by CDP. This is a critical constraint: without it, mutations
 // on deep nodes are silently ignored.
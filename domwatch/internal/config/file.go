@@ -25,6 +25,7 @@ type BrowserConfig struct {
 	ResourceBlocking []string      `yaml:"resource_blocking"`
 	Stealth          string        `yaml:"stealth"` // headless | headful
 	XvfbDisplay      string        `yaml:"xvfb_display"`
+	ScreenshotDir    string        `yaml:"screenshot_dir"` // profile screenshots written here instead of inlined
 }
 
 // PageConfig defines a page to observe.
@@ -36,6 +37,8 @@ type PageConfig struct {
 	Filters          []string      `yaml:"filters"`
 	SnapshotInterval time.Duration `yaml:"snapshot_interval"`
 	Profile          bool          `yaml:"profile"`
+	DiffMode         bool          `yaml:"diff_mode"`         // snapshots as diffs + periodic keyframes
+	KeyframeInterval time.Duration `yaml:"keyframe_interval"` // diff mode: full snapshot period (default 10 × snapshot_interval)
 }
 
 // DebounceConfig controls mutation batching.
@@ -46,9 +49,15 @@ type DebounceConfig struct {
 
 // SinkConfig defines an output backend.
 type SinkConfig struct {
-	Type          string `yaml:"type"`   // stdout | webhook | callback
-	URL           string `yaml:"url"`    // for webhook
-	SubjectPrefix string `yaml:"subject_prefix"` // for nats
+	Type          string        `yaml:"type"`           // stdout | webhook | file | callback
+	URL           string        `yaml:"url"`            // for webhook
+	SubjectPrefix string        `yaml:"subject_prefix"` // for nats
+	Secret        string        `yaml:"secret"`         // webhook: HMAC-SHA256 key for X-Signature-256
+	BatchSize     int           `yaml:"batch_size"`     // webhook: mutation batches per POST (<= 1 = no batching)
+	FlushInterval time.Duration `yaml:"flush_interval"` // webhook: max delay of a partial batch (default 1s)
+	Path          string        `yaml:"path"`           // file: active JSONL file
+	MaxBytes      int64         `yaml:"max_bytes"`      // file: rotate at this size (0 = never)
+	MaxAge        time.Duration `yaml:"max_age"`        // file: rotate at this age (0 = never)
 }
 
 // LoadFile reads a YAML configuration file.
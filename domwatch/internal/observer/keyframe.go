@@ -0,0 +1,43 @@
+// CLAUDE:SUMMARY Diff-mode snapshot state: decides keyframe vs diff emission and tracks the previous DOM state.
+package observer
+
+import (
+	"time"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+// keyframer turns successive full DOM states into keyframes and diffs.
+// A keyframe is due on the first snapshot, after force (doc_reset), and
+// once interval has elapsed since the previous keyframe.
+type keyframer struct {
+	interval time.Duration
+	prev     mutation.State
+	prevID   string
+	last     time.Time // last keyframe
+	forced   bool
+}
+
+func newKeyframer(interval time.Duration) *keyframer {
+	return &keyframer{interval: interval}
+}
+
+// force makes the next snapshot a keyframe.
+func (k *keyframer) force() { k.forced = true }
+
+// next records cur as the snapshot id taken at now and returns what to emit:
+// keyframe = true (full HTML), or a diff against the previous snapshot and
+// its ID. skip is true when nothing changed since the previous snapshot.
+func (k *keyframer) next(id string, cur mutation.State, now time.Time) (keyframe bool, diff *mutation.StateDiff, baseRef string, skip bool) {
+	if k.prev == nil || k.forced || now.Sub(k.last) >= k.interval {
+		k.prev, k.prevID, k.last, k.forced = cur, id, now, false
+		return true, nil, "", false
+	}
+	d := mutation.Diff(k.prev, cur)
+	if d.Empty() {
+		return false, nil, "", true
+	}
+	baseRef = k.prevID
+	k.prev, k.prevID = cur, id
+	return false, &d, baseRef, false
+}
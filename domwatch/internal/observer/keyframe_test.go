@@ -0,0 +1,39 @@
+package observer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+func TestKeyframer_Cadence(t *testing.T) {
+	// WHAT: First snapshot is a keyframe, then diffs until the interval elapses or a reset forces one.
+	// WHY: Consumers resync from keyframes; unchanged DOMs must not emit empty diffs.
+	k := newKeyframer(time.Hour)
+	t0 := time.Unix(0, 0)
+	a := mutation.State{"/html": {Tag: "html"}}
+	b := mutation.State{"/html": {Tag: "html"}, "/html/body": {Tag: "body"}}
+
+	if kf, _, _, _ := k.next("s1", a, t0); !kf {
+		t.Fatal("first snapshot must be a keyframe")
+	}
+	kf, diff, base, skip := k.next("s2", b, t0.Add(time.Minute))
+	if kf || skip || diff == nil || base != "s1" || len(diff.Added) != 1 {
+		t.Fatalf("second snapshot: kf=%v skip=%v base=%q diff=%+v", kf, skip, base, diff)
+	}
+	if _, _, _, skip := k.next("s3", b, t0.Add(2*time.Minute)); !skip {
+		t.Error("unchanged state must be skipped")
+	}
+	_, _, base, _ = k.next("s4", a, t0.Add(3*time.Minute))
+	if base != "s2" {
+		t.Errorf("diff after skip: base %q, want s2", base)
+	}
+	if kf, _, _, _ := k.next("s5", a, t0.Add(time.Hour)); !kf {
+		t.Error("keyframe due after interval")
+	}
+	k.force()
+	if kf, _, _, _ := k.next("s6", b, t0.Add(time.Hour+time.Minute)); !kf {
+		t.Error("forced keyframe not emitted")
+	}
+}
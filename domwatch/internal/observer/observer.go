@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/hazyhaar/chrc/domwatch/domstate"
 	"github.com/hazyhaar/chrc/domwatch/internal/browser"
 	"github.com/hazyhaar/chrc/domwatch/internal/sink"
 	"github.com/hazyhaar/chrc/domwatch/mutation"
@@ -50,8 +51,14 @@ type Observer struct {
 	// Snapshot interval.
 	snapshotInterval time.Duration
 
+	// Diff mode: nil when snapshots always carry the full HTML.
+	keyframes *keyframer
+
 	// Filters.
 	filters []string
+
+	// Selector scope: only mutations inside these subtrees are reported.
+	scope *scope
 }
 
 // Config for creating an Observer.
@@ -63,6 +70,15 @@ type Config struct {
 	SnapshotInterval time.Duration
 	Filters          []string
 	Logger           *slog.Logger
+
+	// Selectors restricts reported mutations to the subtrees matching these
+	// CSS selectors. Empty = whole document.
+	Selectors []string
+
+	// DiffMode emits snapshots as diffs against the previous one, with a
+	// full-HTML keyframe every KeyframeInterval (default 10 × SnapshotInterval).
+	DiffMode         bool
+	KeyframeInterval time.Duration
 }
 
 // New creates an Observer for the given tab.
@@ -88,6 +104,13 @@ func New(cfg Config) *Observer {
 		dedup:            newDeduper(),
 		snapshotInterval: cfg.SnapshotInterval,
 		filters:          cfg.Filters,
+		scope:            newScope(cfg.Selectors),
+	}
+	if cfg.DiffMode {
+		if cfg.KeyframeInterval <= 0 {
+			cfg.KeyframeInterval = 10 * cfg.SnapshotInterval
+		}
+		o.keyframes = newKeyframer(cfg.KeyframeInterval)
 	}
 
 	o.debouncer = newDebouncer(debounceConfig{
@@ -118,6 +141,7 @@ func (o *Observer) Start() error {
 	if err := o.injectJS(); err != nil {
 		return fmt.Errorf("observer: inject JS: %w", err)
 	}
+	o.refreshScope()
 
 	// Initial snapshot.
 	o.emitSnapshot()
@@ -244,17 +268,27 @@ func (o *Observer) loop() {
 	snapTicker := time.NewTicker(o.snapshotInterval)
 	defer snapTicker.Stop()
 
+	var scopeC <-chan time.Time
+	if o.scope.enabled() {
+		scopeTicker := time.NewTicker(scopeRefreshInterval)
+		defer scopeTicker.Stop()
+		scopeC = scopeTicker.C
+	}
+
 	for {
 		select {
 		case <-o.ctx.Done():
 			return
 
 		case rr := <-o.rawCh:
-			if o.dedup.isDuplicate(rr) {
+			if o.dedup.isDuplicate(rr) || !o.scope.allows(rr.record) {
 				continue
 			}
 			o.debouncer.add(rr.record)
 
+		case <-scopeC:
+			o.refreshScope()
+
 		case <-o.debouncer.timerC():
 			o.debouncer.flush()
 
@@ -304,13 +338,33 @@ func (o *Observer) emitSnapshot() {
 		return
 	}
 
+	now := time.Now()
 	snap := mutation.Snapshot{
 		ID:        idgen.New(),
 		PageURL:   o.tab.PageURL,
 		PageID:    o.tab.PageID,
 		HTML:      html,
 		HTMLHash:  mutation.HashHTML(html),
-		Timestamp: time.Now().UnixMilli(),
+		Timestamp: now.UnixMilli(),
+	}
+
+	if o.keyframes != nil {
+		state, err := domstate.Parse(html)
+		if err != nil {
+			// Emit the full HTML and resync diffs from the next snapshot.
+			o.logger.Error("observer: parse DOM state", "error", err)
+			o.keyframes.force()
+			snap.Keyframe = true
+		} else {
+			keyframe, diff, base, skip := o.keyframes.next(snap.ID, state, now)
+			if skip {
+				return
+			}
+			snap.Keyframe = keyframe
+			if !keyframe {
+				snap.HTML, snap.Diff, snap.BaseRef = nil, diff, base
+			}
+		}
 	}
 
 	o.snapshotRef.Store(snap.ID)
@@ -320,7 +374,7 @@ func (o *Observer) emitSnapshot() {
 	}
 
 	o.logger.Info("observer: snapshot emitted",
-		"url", o.tab.PageURL, "id", snap.ID, "size", len(html))
+		"url", o.tab.PageURL, "id", snap.ID, "size", len(html), "diff", snap.IsDiff())
 }
 
 // SetContext allows the parent watcher to pass its context.
@@ -45,6 +45,21 @@
     return getXPath(parent) + '/' + step;
   }
 
+  // Resolves CSS selectors to the XPaths of all matching elements
+  // (selector-scoped observation). Invalid selectors are ignored.
+  window.__domwatcher_scope_paths = function(sels) {
+    var paths = [];
+    for (var i = 0; i < sels.length; i++) {
+      var els;
+      try { els = document.querySelectorAll(sels[i]); } catch(e) { continue; }
+      for (var j = 0; j < els.length; j++) {
+        var p = getXPath(els[j]);
+        if (p && paths.indexOf(p) < 0) paths.push(p);
+      }
+    }
+    return JSON.stringify(paths);
+  };
+
   function send(records) {
     if (typeof window.__domwatcher_binding === 'function') {
       window.__domwatcher_binding(JSON.stringify(records));
@@ -0,0 +1,91 @@
+// CLAUDE:SUMMARY Selector-scoped observation: resolves CSS selectors to XPath roots in-page and drops mutations outside them before debounce.
+package observer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+// scopeRefreshInterval bounds how long an element matching a selector can
+// exist before its mutations are reported (late-rendered widgets).
+const scopeRefreshInterval = 30 * time.Second
+
+// scope holds the XPaths of the elements matching the page selectors.
+// An empty selector list means the whole document is in scope.
+type scope struct {
+	selectors []string
+
+	mu    sync.RWMutex
+	roots []string
+}
+
+func newScope(selectors []string) *scope {
+	return &scope{selectors: selectors}
+}
+
+// enabled reports whether observation is restricted to selectors.
+func (s *scope) enabled() bool { return len(s.selectors) > 0 }
+
+// setRoots replaces the scope roots.
+func (s *scope) setRoots(roots []string) {
+	s.mu.Lock()
+	s.roots = roots
+	s.mu.Unlock()
+}
+
+// allows reports whether rec falls within a scope root. doc_reset always
+// passes: consumers need it to resync.
+func (s *scope) allows(rec mutation.Record) bool {
+	if !s.enabled() || rec.Op == mutation.OpDocReset {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, root := range s.roots {
+		if withinPath(rec.XPath, root) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinPath reports whether xpath is root or a descendant of root, on
+// step boundaries: /html/body/div does not contain /html/body/div[2].
+func withinPath(xpath, root string) bool {
+	if root == "" || !strings.HasPrefix(xpath, root) {
+		return false
+	}
+	return len(xpath) == len(root) || xpath[len(root)] == '/'
+}
+
+// scopeScript returns the JS expression resolving selectors to XPaths via
+// the helper installed by observer.js.
+func scopeScript(selectors []string) string {
+	sels, _ := json.Marshal(selectors)
+	return fmt.Sprintf(`() => window.__domwatcher_scope_paths ? window.__domwatcher_scope_paths(%s) : "[]"`, sels)
+}
+
+// refreshScope re-resolves the selectors in the page. On failure the
+// previous roots are kept.
+func (o *Observer) refreshScope() {
+	if !o.scope.enabled() {
+		return
+	}
+	res, err := o.tab.Page.Context(o.ctx).Eval(scopeScript(o.scope.selectors))
+	if err != nil {
+		o.logger.Warn("observer: resolve selectors failed", "error", err)
+		return
+	}
+	var roots []string
+	if err := json.Unmarshal([]byte(res.Value.Str()), &roots); err != nil {
+		o.logger.Warn("observer: parse selector paths", "error", err)
+		return
+	}
+	o.scope.setRoots(roots)
+	o.logger.Debug("observer: scope refreshed", "url", o.tab.PageURL, "roots", len(roots))
+}
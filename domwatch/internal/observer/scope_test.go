@@ -0,0 +1,71 @@
+package observer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+func TestScope_OnlyTargetedRegion(t *testing.T) {
+	// WHAT: With selectors, mutations in the ad banner are dropped and the price change is kept.
+	// WHY: Price/stock watchers must not emit a batch for every unrelated widget update.
+	s := newScope([]string{".price", "#stock-status"})
+	// Roots as resolved by __domwatcher_scope_paths on the test page:
+	// <main><span class="price">..</span><p id="stock-status">..</p></main><aside class="ad">..</aside>
+	s.setRoots([]string{"/html/body/main/span", "/html/body/main/p"})
+
+	records := []mutation.Record{
+		{Op: mutation.OpText, XPath: "/html/body/main/span/text()", Value: "9.99"},
+		{Op: mutation.OpAttr, XPath: "/html/body/aside/div[3]", Name: "style", Value: "left:10px"},
+		{Op: mutation.OpInsert, XPath: "/html/body/aside/div[4]", Tag: "div"},
+		{Op: mutation.OpText, XPath: "/html/body/main/p/text()", Value: "out of stock"},
+		{Op: mutation.OpRemove, XPath: "/html/body/main/p/*[removed]"},
+		{Op: mutation.OpAttr, XPath: "/html/body/main", Name: "class", Value: "loaded"},
+		{Op: mutation.OpDocReset},
+	}
+	var kept []string
+	for _, r := range records {
+		if s.allows(r) {
+			kept = append(kept, string(r.Op)+" "+r.XPath)
+		}
+	}
+	want := []string{
+		"text /html/body/main/span/text()",
+		"text /html/body/main/p/text()",
+		"remove /html/body/main/p/*[removed]",
+		"doc_reset ",
+	}
+	if strings.Join(kept, "|") != strings.Join(want, "|") {
+		t.Errorf("kept %q, want %q", kept, want)
+	}
+}
+
+func TestScope_NoSelectorsKeepsAll(t *testing.T) {
+	s := newScope(nil)
+	if !s.allows(mutation.Record{Op: mutation.OpText, XPath: "/html/body/aside/text()"}) {
+		t.Error("no selectors must observe the whole document")
+	}
+	s = newScope([]string{".price"})
+	if s.allows(mutation.Record{Op: mutation.OpText, XPath: "/html/body/p/text()"}) {
+		t.Error("unresolved selectors must not match anything")
+	}
+}
+
+func TestWithinPath_StepBoundary(t *testing.T) {
+	cases := []struct {
+		xpath, root string
+		want        bool
+	}{
+		{"/html/body/div", "/html/body/div", true},
+		{"/html/body/div/span", "/html/body/div", true},
+		{"/html/body/div[2]", "/html/body/div", false},
+		{"/html/body/divider", "/html/body/div", false},
+		{"/html/body", "/html/body/div", false},
+	}
+	for _, c := range cases {
+		if got := withinPath(c.xpath, c.root); got != c.want {
+			t.Errorf("withinPath(%q, %q) = %v, want %v", c.xpath, c.root, got, c.want)
+		}
+	}
+}
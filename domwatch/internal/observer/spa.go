@@ -25,13 +25,15 @@ func (o *Observer) handleNavigate(newURL string) {
 			return
 		case rr := <-o.rawCh:
 			// Mutations still arriving — keep processing them normally and reset timer.
-			if !o.dedup.isDuplicate(rr) {
+			if !o.dedup.isDuplicate(rr) && o.scope.allows(rr.record) {
 				o.debouncer.add(rr.record)
 			}
 			timer.Reset(settle)
 		case <-timer.C:
-			// DOM has settled — flush pending mutations and take a new snapshot.
+			// DOM has settled — flush pending mutations, re-resolve the
+			// selector scope for the new view and take a new snapshot.
 			o.debouncer.flush()
+			o.refreshScope()
 			o.emitSnapshot()
 			return
 		}
@@ -63,7 +65,11 @@ func (o *Observer) handleDocReset() {
 	if err := o.injectJS(); err != nil {
 		o.logger.Error("observer: re-inject JS failed", "error", err)
 	}
+	o.refreshScope()
 
-	// New snapshot after reset.
+	// New snapshot after reset — always a keyframe in diff mode.
+	if o.keyframes != nil {
+		o.keyframes.force()
+	}
 	o.emitSnapshot()
 }
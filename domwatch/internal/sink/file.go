@@ -0,0 +1,241 @@
+// CLAUDE:SUMMARY Writes mutation events as buffered JSON lines to a file, rotating by size or age and gzipping rotated files.
+package sink
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+// FileOptions configures a File sink. Zero values disable the matching
+// rotation trigger.
+type FileOptions struct {
+	MaxBytes int64         // rotate once the active file reaches this size
+	MaxAge   time.Duration // rotate once the active file is older than this
+	Logger   *slog.Logger
+}
+
+// File writes JSON lines (same envelopes as Stdout) to an append-only file.
+// When a rotation triggers, the active file is renamed to
+// <base>-<timestamp><ext> and compressed to .gz in the background; writing
+// resumes in a fresh file at the original path. Writes are buffered and
+// flushed on rotation and Close.
+type File struct {
+	path   string
+	opts   FileOptions
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	f        *os.File
+	buf      *bufio.Writer
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	compress sync.WaitGroup
+}
+
+// NewFile opens (or appends to) path, creating parent directories.
+func NewFile(path string, opts FileOptions) (*File, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file sink: empty path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("file sink: mkdir: %w", err)
+	}
+	s := &File{path: path, opts: opts, logger: opts.Logger}
+	if s.logger == nil {
+		s.logger = slog.Default()
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *File) Send(_ context.Context, batch mutation.Batch) error {
+	return s.write(envelope{Type: "batch", Data: batch})
+}
+
+func (s *File) SendSnapshot(_ context.Context, snap mutation.Snapshot) error {
+	return s.write(envelope{Type: "snapshot", Data: snap})
+}
+
+func (s *File) SendProfile(_ context.Context, prof mutation.Profile) error {
+	return s.write(envelope{Type: "profile", Data: prof})
+}
+
+// Rotate forces a rotation of the active file, if it is not empty.
+func (s *File) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("file sink: closed")
+	}
+	return s.rotateLocked()
+}
+
+// Close flushes buffered lines, closes the file and waits for pending
+// compressions.
+func (s *File) Close() error {
+	s.mu.Lock()
+	var err error
+	if !s.closed {
+		s.closed = true
+		err = s.closeLocked()
+	}
+	s.mu.Unlock()
+	s.compress.Wait()
+	return err
+}
+
+func (s *File) write(env envelope) error {
+	line, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("file sink: marshal: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("file sink: closed")
+	}
+	if s.shouldRotate() {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+	n, err := s.buf.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("file sink: write: %w", err)
+	}
+	return nil
+}
+
+func (s *File) shouldRotate() bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.opts.MaxBytes > 0 && s.size >= s.opts.MaxBytes {
+		return true
+	}
+	return s.opts.MaxAge > 0 && time.Since(s.openedAt) >= s.opts.MaxAge
+}
+
+func (s *File) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("file sink: open: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("file sink: stat: %w", err)
+	}
+	s.f = f
+	s.buf = bufio.NewWriterSize(f, 64<<10)
+	s.size = info.Size()
+	s.openedAt = time.Now()
+	return nil
+}
+
+func (s *File) closeLocked() error {
+	flushErr := s.buf.Flush()
+	closeErr := s.f.Close()
+	if flushErr != nil {
+		return fmt.Errorf("file sink: flush: %w", flushErr)
+	}
+	return closeErr
+}
+
+func (s *File) rotateLocked() error {
+	if s.size == 0 {
+		return nil
+	}
+	if err := s.closeLocked(); err != nil {
+		return err
+	}
+	rotated := s.rotatedName(time.Now())
+	if err := os.Rename(s.path, rotated); err != nil {
+		// Keep writing to the same file rather than losing events.
+		if openErr := s.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("file sink: rotate: %w", err)
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	s.compress.Add(1)
+	go func() {
+		defer s.compress.Done()
+		if err := gzipFile(rotated); err != nil {
+			s.logger.Warn("file sink: compress rotated file failed", "file", rotated, "error", err)
+		}
+	}()
+	return nil
+}
+
+// rotatedName returns a name for the rotated file that does not exist yet:
+// events.jsonl -> events-20260102T150405.000000000.jsonl.
+func (s *File) rotatedName(now time.Time) string {
+	ext := filepath.Ext(s.path)
+	base := strings.TrimSuffix(s.path, ext)
+	stamp := now.UTC().Format("20060102T150405.000000000")
+	name := base + "-" + stamp + ext
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, stamp, i, ext)
+	}
+	return name
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// gzipFile compresses path to path.gz and removes path on success.
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := path + ".gz.tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,147 @@
+package sink
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+// readJSONL parses every line of r as an envelope and returns the count.
+func readJSONL(t *testing.T, r io.Reader) int {
+	t.Helper()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1<<20), 1<<20)
+	n := 0
+	for sc.Scan() {
+		var env struct {
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &env); err != nil {
+			t.Fatalf("line %d is not JSON: %v", n+1, err)
+		}
+		if env.Type == "" {
+			t.Fatalf("line %d has no type", n+1)
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	return n
+}
+
+// countLines counts envelopes across the active file and gzipped rotations.
+func countLines(t *testing.T, dir string) (active, rotated, files int) {
+	t.Helper()
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case e.Name() == "events.jsonl":
+			active += readJSONL(t, f)
+		case strings.HasSuffix(e.Name(), ".jsonl.gz"):
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatalf("%s: %v", e.Name(), err)
+			}
+			rotated += readJSONL(t, zr)
+			files++
+		default:
+			t.Errorf("unexpected file %s", e.Name())
+		}
+		f.Close()
+	}
+	return active, rotated, files
+}
+
+func TestFile_RotatesAndGzips(t *testing.T) {
+	// WHAT: Events survive a forced rotation and a size rotation; every file parses as JSONL.
+	// WHY: Offline analysis reads both the active file and the gzipped history.
+	dir := t.TempDir()
+	s, err := NewFile(filepath.Join(dir, "events.jsonl"), FileOptions{MaxBytes: 4 << 10})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ctx := context.Background()
+
+	const n = 200
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Send(ctx, mutation.Batch{ID: "b", PageID: "p", Seq: uint64(i),
+				Records: []mutation.Record{{Op: mutation.OpText, XPath: "/html/body/p", Value: strings.Repeat("x", 40)}}})
+		}(i)
+		if i == n/2 {
+			if err := s.Rotate(); err != nil {
+				t.Fatalf("rotate: %v", err)
+			}
+		}
+	}
+	wg.Wait()
+	s.SendSnapshot(ctx, mutation.Snapshot{ID: "snap", HTML: []byte("<html></html>")})
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	active, rotated, files := countLines(t, dir)
+	if files < 2 {
+		t.Errorf("got %d rotated files, want at least 2", files)
+	}
+	if active+rotated != n+1 {
+		t.Errorf("got %d events (%d active + %d rotated), want %d", active+rotated, active, rotated, n+1)
+	}
+}
+
+func TestFile_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFile(filepath.Join(dir, "events.jsonl"), FileOptions{MaxAge: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ctx := context.Background()
+	s.Send(ctx, mutation.Batch{Seq: 1})
+	time.Sleep(20 * time.Millisecond)
+	s.Send(ctx, mutation.Batch{Seq: 2})
+	s.Close()
+
+	active, rotated, files := countLines(t, dir)
+	if files != 1 || active != 1 || rotated != 1 {
+		t.Errorf("got active=%d rotated=%d files=%d, want 1/1/1", active, rotated, files)
+	}
+}
+
+func TestFile_BufferedUntilClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	s, err := NewFile(path, FileOptions{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	s.Send(context.Background(), mutation.Batch{Seq: 1})
+	if info, _ := os.Stat(path); info.Size() != 0 {
+		t.Errorf("expected buffered write, file has %d bytes", info.Size())
+	}
+	s.Close()
+	if info, _ := os.Stat(path); info.Size() == 0 {
+		t.Error("Close did not flush")
+	}
+	if err := s.Send(context.Background(), mutation.Batch{Seq: 2}); err == nil {
+		t.Error("send after close should fail")
+	}
+}
@@ -1,24 +1,51 @@
-// CLAUDE:SUMMARY POSTs mutation events as JSON to a webhook URL with retry and exponential backoff.
+// CLAUDE:SUMMARY POSTs mutation events as JSON to a webhook URL with HMAC-SHA256 signing, optional batching, and retry with exponential backoff.
 package sink
 
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/hazyhaar/chrc/domwatch/mutation"
 )
 
+// SignatureHeader carries the HMAC-SHA256 of the request body as
+// "sha256=<hex>" when a secret is configured.
+const SignatureHeader = "X-Signature-256"
+
+// DefaultFlushInterval is the flush period of a batching webhook when none is set.
+const DefaultFlushInterval = time.Second
+
 // Webhook POSTs JSON to a URL with retry and exponential backoff.
+//
+// With a batch size > 1, mutation batches are buffered and POSTed together
+// as a JSON array of envelopes, when the buffer is full or every flush
+// interval. Snapshots and profiles are never buffered; pending batches are
+// flushed before them so the receiver sees events in order.
 type Webhook struct {
-	url        string
-	client     *http.Client
-	maxRetries int
-	logger     *slog.Logger
+	url           string
+	client        *http.Client
+	maxRetries    int
+	backoff       time.Duration
+	secret        []byte
+	batchSize     int
+	flushInterval time.Duration
+	logger        *slog.Logger
+
+	mu      sync.Mutex // guards pending
+	pending []envelope
+	postMu  sync.Mutex // serialises POSTs to keep event order
+	stop    chan struct{}
+	done    chan struct{}
+	closed  sync.Once
 }
 
 // WebhookOption configures a Webhook sink.
@@ -29,9 +56,43 @@ func WithWebhookRetries(n int) WebhookOption {
 	return func(w *Webhook) { w.maxRetries = n }
 }
 
+// WithWebhookBackoff sets the delay before the first retry; it doubles on
+// each further attempt. Default: 1s.
+func WithWebhookBackoff(d time.Duration) WebhookOption {
+	return func(w *Webhook) {
+		if d > 0 {
+			w.backoff = d
+		}
+	}
+}
+
 // WithWebhookLogger sets a custom logger.
 func WithWebhookLogger(l *slog.Logger) WebhookOption {
-	return func(w *Webhook) { w.logger = l }
+	return func(w *Webhook) {
+		if l != nil {
+			w.logger = l
+		}
+	}
+}
+
+// WithWebhookSecret signs every request body with HMAC-SHA256 using secret.
+// An empty secret disables signing.
+func WithWebhookSecret(secret string) WebhookOption {
+	return func(w *Webhook) {
+		if secret != "" {
+			w.secret = []byte(secret)
+		}
+	}
+}
+
+// WithWebhookBatch coalesces up to size mutation batches per POST, flushed
+// at least every interval (DefaultFlushInterval if <= 0). size <= 1 disables
+// batching: one POST per event, body = single envelope.
+func WithWebhookBatch(size int, interval time.Duration) WebhookOption {
+	return func(w *Webhook) {
+		w.batchSize = size
+		w.flushInterval = interval
+	}
 }
 
 // NewWebhook creates a Webhook sink targeting the given URL.
@@ -40,38 +101,143 @@ func NewWebhook(url string, opts ...WebhookOption) *Webhook {
 		url:        url,
 		client:     &http.Client{Timeout: 10 * time.Second},
 		maxRetries: 3,
+		backoff:    time.Second,
 		logger:     slog.Default(),
 	}
 	for _, o := range opts {
 		o(w)
 	}
+	if w.batching() {
+		if w.flushInterval <= 0 {
+			w.flushInterval = DefaultFlushInterval
+		}
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go w.flushLoop()
+	}
 	return w
 }
 
 func (w *Webhook) Send(ctx context.Context, batch mutation.Batch) error {
-	return w.post(ctx, "batch", batch)
+	env := envelope{Type: "batch", Data: batch}
+	if !w.batching() {
+		return w.postJSON(ctx, env)
+	}
+
+	w.mu.Lock()
+	w.pending = append(w.pending, env)
+	full := len(w.pending) >= w.batchSize
+	w.mu.Unlock()
+
+	if full {
+		return w.flush(ctx)
+	}
+	return nil
 }
 
 func (w *Webhook) SendSnapshot(ctx context.Context, snap mutation.Snapshot) error {
-	return w.post(ctx, "snapshot", snap)
+	return w.sendNow(ctx, envelope{Type: "snapshot", Data: snap})
 }
 
 func (w *Webhook) SendProfile(ctx context.Context, prof mutation.Profile) error {
-	return w.post(ctx, "profile", prof)
+	return w.sendNow(ctx, envelope{Type: "profile", Data: prof})
+}
+
+// Close stops the flush loop and delivers pending batches.
+func (w *Webhook) Close() error {
+	if !w.batching() {
+		return nil
+	}
+	var err error
+	w.closed.Do(func() {
+		close(w.stop)
+		<-w.done
+		ctx, cancel := context.WithTimeout(context.Background(), w.client.Timeout*time.Duration(w.maxRetries+1))
+		defer cancel()
+		err = w.flush(ctx)
+	})
+	return err
 }
 
-func (w *Webhook) Close() error { return nil }
+func (w *Webhook) batching() bool { return w.batchSize > 1 }
 
-func (w *Webhook) post(ctx context.Context, typ string, data any) error {
-	body, err := json.Marshal(envelope{Type: typ, Data: data})
+// sendNow posts env immediately, after any pending batches.
+func (w *Webhook) sendNow(ctx context.Context, env envelope) error {
+	if w.batching() {
+		if err := w.flush(ctx); err != nil {
+			w.logger.Warn("webhook: flush before send failed", "type", env.Type, "error", err)
+		}
+	}
+	return w.postJSON(ctx, env)
+}
+
+func (w *Webhook) flushLoop() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if err := w.flush(context.Background()); err != nil {
+				w.logger.Warn("webhook: periodic flush failed", "error", err)
+			}
+		}
+	}
+}
+
+// flush POSTs the pending batches as one JSON array. Batches that cannot be
+// delivered after all retries are dropped.
+func (w *Webhook) flush(ctx context.Context) error {
+	w.postMu.Lock()
+	defer w.postMu.Unlock()
+
+	w.mu.Lock()
+	items := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(items)
 	if err != nil {
 		return fmt.Errorf("webhook: marshal: %w", err)
 	}
+	if err := w.post(ctx, body); err != nil {
+		return fmt.Errorf("webhook: dropped %d batches: %w", len(items), err)
+	}
+	return nil
+}
+
+func (w *Webhook) postJSON(ctx context.Context, env envelope) error {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("webhook: marshal: %w", err)
+	}
+	w.postMu.Lock()
+	defer w.postMu.Unlock()
+	return w.post(ctx, body)
+}
+
+// Sign returns the SignatureHeader value for body under secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) error {
+	var signature string
+	if len(w.secret) > 0 {
+		signature = Sign(w.secret, body)
+	}
 
 	var lastErr error
 	for attempt := 0; attempt <= w.maxRetries; attempt++ {
 		if attempt > 0 {
-			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+			backoff := w.backoff << uint(attempt-1)
 			select {
 			case <-time.After(backoff):
 			case <-ctx.Done():
@@ -84,6 +250,9 @@ func (w *Webhook) post(ctx context.Context, typ string, data any) error {
 			return fmt.Errorf("webhook: new request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
+		if signature != "" {
+			req.Header.Set(SignatureHeader, signature)
+		}
 
 		resp, err := w.client.Do(req)
 		if err != nil {
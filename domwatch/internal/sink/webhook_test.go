@@ -0,0 +1,164 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/domwatch/mutation"
+)
+
+type receiver struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	sigs   []string
+}
+
+func (rc *receiver) handler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	rc.bodies = append(rc.bodies, body)
+	rc.sigs = append(rc.sigs, r.Header.Get(SignatureHeader))
+	rc.mu.Unlock()
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.bodies)
+}
+
+func TestWebhook_SignsBody(t *testing.T) {
+	// WHAT: Each POST carries X-Signature-256 = HMAC-SHA256(secret, body).
+	// WHY: Receivers must be able to reject forged mutation events.
+	rc := &receiver{}
+	srv := httptest.NewServer(http.HandlerFunc(rc.handler))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, WithWebhookSecret("s3cret"))
+	if err := w.Send(context.Background(), mutation.Batch{ID: "b1", PageID: "p"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if rc.count() != 1 {
+		t.Fatalf("got %d requests, want 1", rc.count())
+	}
+	if want := Sign([]byte("s3cret"), rc.bodies[0]); rc.sigs[0] != want {
+		t.Errorf("signature: got %q, want %q", rc.sigs[0], want)
+	}
+	if rc.sigs[0] == Sign([]byte("other"), rc.bodies[0]) {
+		t.Error("signature does not depend on the secret")
+	}
+	var env struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(rc.bodies[0], &env); err != nil || env.Type != "batch" {
+		t.Errorf("unbatched body must be a single envelope: %s", rc.bodies[0])
+	}
+}
+
+func TestWebhook_NoSecretNoHeader(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(http.HandlerFunc(rc.handler))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL)
+	w.SendProfile(context.Background(), mutation.Profile{PageURL: "https://example.com"})
+	if rc.count() != 1 || rc.sigs[0] != "" {
+		t.Errorf("unexpected signature header %q", rc.sigs)
+	}
+}
+
+func TestWebhook_RetriesUntilReceiverRecovers(t *testing.T) {
+	// WHAT: 5xx responses are retried with backoff until success.
+	// WHY: A momentarily-down receiver must not lose mutation events.
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, WithWebhookBackoff(time.Millisecond))
+	if err := w.Send(context.Background(), mutation.Batch{ID: "b1"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("got %d calls, want 3", calls.Load())
+	}
+
+	w = NewWebhook(srv.URL, WithWebhookRetries(0), WithWebhookBackoff(time.Millisecond))
+	calls.Store(0)
+	if err := w.Send(context.Background(), mutation.Batch{ID: "b2"}); err == nil {
+		t.Error("expected error when retries are exhausted")
+	}
+}
+
+func TestWebhook_BatchesBySize(t *testing.T) {
+	// WHAT: batch_size mutation batches are coalesced into one signed JSON array.
+	// WHY: High-frequency pages would otherwise issue one POST per debounce window.
+	rc := &receiver{}
+	srv := httptest.NewServer(http.HandlerFunc(rc.handler))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, WithWebhookSecret("k"), WithWebhookBatch(3, time.Hour))
+	defer w.Close()
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		w.Send(ctx, mutation.Batch{Seq: uint64(i)})
+	}
+	if rc.count() != 0 {
+		t.Fatalf("posted before batch was full: %d", rc.count())
+	}
+	w.Send(ctx, mutation.Batch{Seq: 2})
+	if rc.count() != 1 {
+		t.Fatalf("got %d requests, want 1", rc.count())
+	}
+
+	var items []struct {
+		Type string         `json:"type"`
+		Data mutation.Batch `json:"data"`
+	}
+	if err := json.Unmarshal(rc.bodies[0], &items); err != nil {
+		t.Fatalf("batched body is not an array: %v", err)
+	}
+	if len(items) != 3 || items[2].Data.Seq != 2 {
+		t.Errorf("got %+v, want 3 batches in order", items)
+	}
+	if rc.sigs[0] != Sign([]byte("k"), rc.bodies[0]) {
+		t.Error("batched body signature mismatch")
+	}
+}
+
+func TestWebhook_FlushIntervalAndClose(t *testing.T) {
+	// WHAT: A partial batch is flushed by the interval timer, and by Close.
+	// WHY: Low-traffic pages must not hold events until the buffer fills.
+	rc := &receiver{}
+	srv := httptest.NewServer(http.HandlerFunc(rc.handler))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, WithWebhookBatch(100, 20*time.Millisecond))
+	w.Send(context.Background(), mutation.Batch{Seq: 1})
+	deadline := time.Now().Add(2 * time.Second)
+	for rc.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if rc.count() != 1 {
+		t.Fatalf("interval flush: got %d requests, want 1", rc.count())
+	}
+
+	w2 := NewWebhook(srv.URL, WithWebhookBatch(100, time.Hour))
+	w2.Send(context.Background(), mutation.Batch{Seq: 2})
+	if err := w2.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if rc.count() != 2 {
+		t.Errorf("close flush: got %d requests, want 2", rc.count())
+	}
+}
@@ -0,0 +1,84 @@
+// CLAUDE:SUMMARY Flattened DOM State keyed by stable paths, with Diff/Apply to emit and replay keyframe + diff snapshots.
+package mutation
+
+import (
+	"maps"
+	"sort"
+)
+
+// Node is one element or text node of a State.
+type Node struct {
+	Tag   string            `json:"tag,omitempty"`   // element name; "" for text nodes
+	Attrs map[string]string `json:"attrs,omitempty"` // element attributes
+	Text  string            `json:"text,omitempty"`  // text content, text nodes only
+}
+
+// State is a flattened DOM: stable path → node. Paths use the same form as
+// Record.XPath (/html/body/div[2]/text()), the index being present only
+// when siblings share the tag. Comments and whitespace-only text are dropped.
+// domstate.Parse builds a State from serialised HTML (e.g. a keyframe).
+type State map[string]Node
+
+// NodeChange is a node added or changed at Path.
+type NodeChange struct {
+	Path string `json:"path"`
+	Node Node   `json:"node"`
+}
+
+// StateDiff turns one State into the next. Entries are sorted by path.
+type StateDiff struct {
+	Added   []NodeChange `json:"added,omitempty"`
+	Removed []string     `json:"removed,omitempty"`
+	Changed []NodeChange `json:"changed,omitempty"`
+}
+
+// Empty reports whether d changes nothing.
+func (d StateDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff returns the changes turning prev into cur. Apply(prev, Diff(prev, cur))
+// equals cur.
+func Diff(prev, cur State) StateDiff {
+	var d StateDiff
+	for path, n := range cur {
+		old, ok := prev[path]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, NodeChange{Path: path, Node: n})
+		case !nodeEqual(old, n):
+			d.Changed = append(d.Changed, NodeChange{Path: path, Node: n})
+		}
+	}
+	for path := range prev {
+		if _, ok := cur[path]; !ok {
+			d.Removed = append(d.Removed, path)
+		}
+	}
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].Path < d.Added[j].Path })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].Path < d.Changed[j].Path })
+	sort.Strings(d.Removed)
+	return d
+}
+
+// Apply returns a copy of s with d applied; s is not modified.
+func Apply(s State, d StateDiff) State {
+	out := maps.Clone(s)
+	if out == nil {
+		out = make(State)
+	}
+	for _, path := range d.Removed {
+		delete(out, path)
+	}
+	for _, c := range d.Added {
+		out[c.Path] = c.Node
+	}
+	for _, c := range d.Changed {
+		out[c.Path] = c.Node
+	}
+	return out
+}
+
+func nodeEqual(a, b Node) bool {
+	return a.Tag == b.Tag && a.Text == b.Text && maps.Equal(a.Attrs, b.Attrs)
+}
@@ -0,0 +1,82 @@
+package mutation
+
+import (
+	"encoding/json"
+	"maps"
+	"testing"
+)
+
+func stateEqual(a, b State) bool {
+	return maps.EqualFunc(a, b, nodeEqual)
+}
+
+func TestDiff_AddRemoveChange(t *testing.T) {
+	prev := State{
+		"/html":                 {Tag: "html"},
+		"/html/body":            {Tag: "body"},
+		"/html/body/p":          {Tag: "p", Attrs: map[string]string{"class": "price"}},
+		"/html/body/p/text()":   {Text: "10 EUR"},
+		"/html/body/div":        {Tag: "div"},
+		"/html/body/div/text()": {Text: "in stock"},
+	}
+	cur := State{
+		"/html":               {Tag: "html"},
+		"/html/body":          {Tag: "body"},
+		"/html/body/p":        {Tag: "p", Attrs: map[string]string{"class": "price sale"}},
+		"/html/body/p/text()": {Text: "8 EUR"},
+		"/html/body/span":     {Tag: "span"},
+	}
+
+	d := Diff(prev, cur)
+	if len(d.Added) != 1 || d.Added[0].Path != "/html/body/span" {
+		t.Errorf("added: %+v", d.Added)
+	}
+	if len(d.Removed) != 2 || d.Removed[0] != "/html/body/div" || d.Removed[1] != "/html/body/div/text()" {
+		t.Errorf("removed: %+v", d.Removed)
+	}
+	if len(d.Changed) != 2 || d.Changed[0].Path != "/html/body/p" || d.Changed[1].Node.Text != "8 EUR" {
+		t.Errorf("changed: %+v", d.Changed)
+	}
+	if got := Apply(prev, d); !stateEqual(got, cur) {
+		t.Errorf("Apply(prev, Diff(prev, cur)) != cur: %+v", got)
+	}
+	if _, ok := prev["/html/body/span"]; ok {
+		t.Error("Apply modified its input")
+	}
+}
+
+func TestDiff_Empty(t *testing.T) {
+	s := State{"/html": {Tag: "html", Attrs: map[string]string{"lang": "fr"}}}
+	if d := Diff(s, State{"/html": {Tag: "html", Attrs: map[string]string{"lang": "fr"}}}); !d.Empty() {
+		t.Errorf("identical states: got %+v", d)
+	}
+	if d := Diff(nil, s); len(d.Added) != 1 {
+		t.Errorf("from nil: got %+v", d)
+	}
+}
+
+func TestSnapshot_DiffRoundtrip(t *testing.T) {
+	// WHAT: A diff snapshot survives JSON with its diff and base ref; full snapshots omit them.
+	// WHY: Snapshot is the public contract; existing consumers must see unchanged JSON.
+	full, _ := MarshalSnapshot(&Snapshot{ID: "s1", HTML: []byte("<p>x</p>")})
+	var raw map[string]any
+	json.Unmarshal(full, &raw)
+	for _, k := range []string{"keyframe", "diff", "base_ref"} {
+		if _, ok := raw[k]; ok {
+			t.Errorf("full snapshot JSON has %q", k)
+		}
+	}
+
+	d := &StateDiff{Removed: []string{"/html/body/p"}}
+	data, err := MarshalSnapshot(&Snapshot{ID: "s2", Diff: d, BaseRef: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalSnapshot(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsDiff() || got.BaseRef != "s1" || got.Diff.Removed[0] != "/html/body/p" {
+		t.Errorf("roundtrip: %+v", got)
+	}
+}
@@ -11,6 +11,14 @@ type Profile struct {
 	ContentSelectors []string           `json:"content_selectors"`
 	Fingerprint      string             `json:"fingerprint"`       // structural hash
 	TextDensityMap   map[string]float64 `json:"text_density_map"` // XPath → text/markup ratio
+
+	// Optional screenshot, on request (debugging stealth/detection).
+	// Screenshot is the PNG (base64 in JSON) unless it was written to disk,
+	// in which case ScreenshotPath is set instead. Both empty on failure,
+	// with the reason in ScreenshotError.
+	Screenshot      []byte `json:"screenshot,omitempty"`
+	ScreenshotPath  string `json:"screenshot_path,omitempty"`
+	ScreenshotError string `json:"screenshot_error,omitempty"`
 }
 
 // Landmark is an HTML5 landmark element found in the DOM.
@@ -4,11 +4,21 @@ package mutation
 // Snapshot is a complete DOM photo. Emitted at startup, periodically
 // (default 4h), and after every doc_reset. The raw HTML is the immutable
 // asset — the founding principle of the canvas.
+//
+// For pages in diff mode, only keyframes (Keyframe = true) carry HTML; the
+// snapshots in between carry Diff against the previous snapshot (BaseRef).
+// State = Apply(...Apply(domstate.Parse(keyframe.HTML), diff1)..., diffN).
 type Snapshot struct {
-	ID        string `json:"id"`        // UUIDv7
-	PageURL   string `json:"page_url"`
-	PageID    string `json:"page_id"`
-	HTML      []byte `json:"html"`      // full serialised DOM
-	HTMLHash  string `json:"html_hash"` // SHA-256 hex
-	Timestamp int64  `json:"timestamp"` // epoch milliseconds
+	ID        string     `json:"id"` // UUIDv7
+	PageURL   string     `json:"page_url"`
+	PageID    string     `json:"page_id"`
+	HTML      []byte     `json:"html"`               // full serialised DOM (nil for diff snapshots)
+	HTMLHash  string     `json:"html_hash"`          // SHA-256 hex of the full DOM, diff snapshots included
+	Timestamp int64      `json:"timestamp"`          // epoch milliseconds
+	Keyframe  bool       `json:"keyframe,omitempty"` // diff mode: full-state resync point
+	Diff      *StateDiff `json:"diff,omitempty"`     // diff mode: changes since BaseRef
+	BaseRef   string     `json:"base_ref,omitempty"` // diff mode: ID of the snapshot Diff applies to
 }
+
+// IsDiff reports whether s carries a diff instead of the full HTML.
+func (s *Snapshot) IsDiff() bool { return s.Diff != nil }
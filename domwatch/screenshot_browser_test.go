@@ -0,0 +1,48 @@
+//go:build browser
+
+// Run with a local Chrome/Chromium: go test -tags browser ./domwatch/
+
+package domwatch
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProfilePage_Screenshot(t *testing.T) {
+	// WHAT: ProfilePage with Screenshot returns non-empty PNG bytes from the shared browser.
+	// WHY: Operators debug stealth/detection issues from what the browser actually rendered.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><main><h1>Screenshot</h1><p>domwatch</p></main></body></html>`))
+	}))
+	defer srv.Close()
+
+	cfg := &Config{Browser: BrowserConfig{
+		Stealth:         "headless",
+		MemoryLimit:     1 << 30,
+		RecycleInterval: time.Hour,
+	}}
+	w := New(cfg, nil, NewCallbackSink(nil, nil, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := w.Start(ctx); err != nil {
+		t.Skipf("browser unavailable: %v", err)
+	}
+	defer w.Stop()
+
+	for _, full := range []bool{false, true} {
+		prof, err := w.ProfilePage(ctx, srv.URL, "shot", ProfileOptions{Screenshot: true, FullPage: full})
+		if err != nil {
+			t.Fatalf("profile (full=%v): %v", full, err)
+		}
+		if !bytes.HasPrefix(prof.Screenshot, []byte("\x89PNG\r\n\x1a\n")) {
+			t.Errorf("full=%v: not a PNG (%d bytes, error %q)", full, len(prof.Screenshot), prof.ScreenshotError)
+		}
+	}
+}
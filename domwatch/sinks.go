@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Re-exports sink types and provides factory functions for stdout, webhook, and callback sinks.
+// CLAUDE:SUMMARY Re-exports sink types and provides factory functions for stdout, webhook, file, and callback sinks.
 package domwatch
 
 import (
@@ -23,6 +23,34 @@ func NewWebhookSink(url string, logger *slog.Logger) Sink {
 	return sink.NewWebhook(url, sink.WithWebhookLogger(logger))
 }
 
+// NewWebhookSinkFromConfig creates a webhook sink from a YAML sink entry:
+// HMAC-SHA256 signing with Secret, batching with BatchSize/FlushInterval.
+func NewWebhookSinkFromConfig(sc SinkConfig, logger *slog.Logger) Sink {
+	return sink.NewWebhook(sc.URL,
+		sink.WithWebhookLogger(logger),
+		sink.WithWebhookSecret(sc.Secret),
+		sink.WithWebhookBatch(sc.BatchSize, sc.FlushInterval),
+	)
+}
+
+// WebhookSignatureHeader is the request header carrying "sha256=<hex>",
+// the HMAC-SHA256 of the body under the sink secret.
+const WebhookSignatureHeader = sink.SignatureHeader
+
+// SignWebhookBody returns the expected WebhookSignatureHeader value, for receivers.
+func SignWebhookBody(secret, body []byte) string {
+	return sink.Sign(secret, body)
+}
+
+// FileSinkOptions configures NewFileSink rotation (MaxBytes, MaxAge).
+type FileSinkOptions = sink.FileOptions
+
+// NewFileSink creates a JSON-lines file sink rotating by size or age;
+// rotated files are gzipped. Buffered lines are flushed on Close (Watcher.Stop).
+func NewFileSink(path string, opts FileSinkOptions) (Sink, error) {
+	return sink.NewFile(path, opts)
+}
+
 // BatchFunc is called for each batch.
 type BatchFunc = sink.BatchFunc
 
@@ -13,7 +13,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/go-rod/rod"
 	"github.com/hazyhaar/pkg/connectivity"
@@ -123,7 +128,10 @@ func (w *Watcher) ObservePage(ctx context.Context, pageCfg config.PageConfig) er
 		DebounceMax:      w.cfg.Debounce.MaxBuffer,
 		SnapshotInterval: pageCfg.SnapshotInterval,
 		Filters:          pageCfg.Filters,
+		Selectors:        pageCfg.Selectors,
 		Logger:           w.logger,
+		DiffMode:         pageCfg.DiffMode,
+		KeyframeInterval: pageCfg.KeyframeInterval,
 	})
 	obs.SetContext(ctx)
 
@@ -144,9 +152,19 @@ func (w *Watcher) ObservePage(ctx context.Context, pageCfg config.PageConfig) er
 	return nil
 }
 
+// ProfileOptions adds optional captures to ProfilePage.
+type ProfileOptions struct {
+	Screenshot bool // capture a PNG of the page after load
+	FullPage   bool // whole scrollable page instead of the viewport
+	// ScreenshotDir writes the PNG there (Profile.ScreenshotPath) instead of
+	// inlining it. Default: Browser.ScreenshotDir; empty = inline.
+	ScreenshotDir string
+}
+
 // ProfilePage runs the profiler on a URL and emits the result to sinks.
-func (w *Watcher) ProfilePage(ctx context.Context, pageURL, pageID string) (*mutation.Profile, error) {
-	// Open a fresh tab for profiling.
+// A failed screenshot never fails the profile.
+func (w *Watcher) ProfilePage(ctx context.Context, pageURL, pageID string, opts ...ProfileOptions) (*mutation.Profile, error) {
+	// Open a fresh tab for profiling, in the shared browser.
 	tab, err := browser.OpenTab(ctx, w.mgr, pageURL, pageID, browser.LevelHeadless)
 	if err != nil {
 		return nil, fmt.Errorf("domwatch: profile open tab: %w", err)
@@ -158,6 +176,10 @@ func (w *Watcher) ProfilePage(ctx context.Context, pageURL, pageID string) (*mut
 		return nil, err
 	}
 
+	if len(opts) > 0 && opts[0].Screenshot {
+		w.attachScreenshot(ctx, tab, prof, opts[0])
+	}
+
 	if err := w.sinkR.SendProfile(ctx, *prof); err != nil {
 		w.logger.Error("domwatch: send profile failed", "error", err)
 	}
@@ -165,6 +187,51 @@ func (w *Watcher) ProfilePage(ctx context.Context, pageURL, pageID string) (*mut
 	return prof, nil
 }
 
+// attachScreenshot captures tab into prof, inline or as a file.
+func (w *Watcher) attachScreenshot(ctx context.Context, tab *browser.Tab, prof *mutation.Profile, opts ProfileOptions) {
+	png, err := tab.Screenshot(ctx, opts.FullPage)
+	if err != nil {
+		w.logger.Warn("domwatch: screenshot failed", "url", tab.PageURL, "error", err)
+		prof.ScreenshotError = err.Error()
+		return
+	}
+
+	dir := opts.ScreenshotDir
+	if dir == "" {
+		dir = w.cfg.Browser.ScreenshotDir
+	}
+	if dir == "" {
+		prof.Screenshot = png
+		return
+	}
+
+	name := screenshotName(tab.PageID, time.Now())
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(dir, 0o755); err == nil {
+		err = os.WriteFile(path, png, 0o644)
+	}
+	if err != nil {
+		w.logger.Warn("domwatch: write screenshot failed", "path", path, "error", err)
+		prof.ScreenshotError = err.Error()
+		return
+	}
+	prof.ScreenshotPath = path
+}
+
+// screenshotName builds a file name safe for any page ID.
+func screenshotName(pageID string, now time.Time) string {
+	id := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, pageID)
+	if id == "" {
+		id = "profile"
+	}
+	return fmt.Sprintf("%s-%d.png", id, now.UnixMilli())
+}
+
 // Stop gracefully shuts down all observers and the browser.
 func (w *Watcher) Stop() {
 	w.mu.Lock()
@@ -270,7 +337,10 @@ func (w *Watcher) observePageLocked(ctx context.Context, pageCfg config.PageConf
 		DebounceMax:      w.cfg.Debounce.MaxBuffer,
 		SnapshotInterval: pageCfg.SnapshotInterval,
 		Filters:          pageCfg.Filters,
+		Selectors:        pageCfg.Selectors,
 		Logger:           w.logger,
+		DiffMode:         pageCfg.DiffMode,
+		KeyframeInterval: pageCfg.KeyframeInterval,
 	})
 	obs.SetContext(ctx)
 
@@ -318,17 +388,22 @@ func (w *Watcher) handleObserve(ctx context.Context, payload []byte) ([]byte, er
 }
 
 // handleProfile is the connectivity handler for DOM profiling.
-// Payload: {"url": "...", "page_id": "..."}
+// Payload: {"url": "...", "page_id": "...", "screenshot": false, "full_page": false}
 func (w *Watcher) handleProfile(ctx context.Context, payload []byte) ([]byte, error) {
 	var req struct {
-		URL    string `json:"url"`
-		PageID string `json:"page_id"`
+		URL        string `json:"url"`
+		PageID     string `json:"page_id"`
+		Screenshot bool   `json:"screenshot"`
+		FullPage   bool   `json:"full_page"`
 	}
 	if err := json.Unmarshal(payload, &req); err != nil {
 		return nil, fmt.Errorf("domwatch_profile: unmarshal: %w", err)
 	}
 
-	prof, err := w.ProfilePage(ctx, req.URL, req.PageID)
+	prof, err := w.ProfilePage(ctx, req.URL, req.PageID, ProfileOptions{
+		Screenshot: req.Screenshot,
+		FullPage:   req.FullPage,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hazyhaar/pkg/docpipe"
 	"github.com/hazyhaar/chrc/domkeeper"
@@ -659,3 +660,101 @@ func (s *sliceIter) Reset() error {
 	s.pos = 0
 	return nil
 }
+
+// --- E2E: domregistry correction → domkeeper re-extraction ---
+
+func TestE2E_CorrectionTriggersReExtract(t *testing.T) {
+	// WHAT: Accepting a correction calls domkeeper_reextract through the shared router.
+	// WHY: Content extracted with the old selectors must be refreshed once a correction lands.
+	dir := t.TempDir()
+	router := connectivity.New()
+	ctx := context.Background()
+
+	dk, err := domkeeper.New(&domkeeper.Config{DBPath: filepath.Join(dir, "keeper.db")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dk.Close() })
+	dk.RegisterConnectivity(router)
+
+	// Spy between the registry and domkeeper, forwarding the event as-is.
+	results := make(chan int, 1)
+	router.RegisterLocal("test_reextract", func(ctx context.Context, payload []byte) ([]byte, error) {
+		resp, err := router.Call(ctx, "domkeeper_reextract", payload)
+		if err != nil {
+			return nil, err
+		}
+		var out struct {
+			Queued int `json:"queued"`
+		}
+		json.Unmarshal(resp, &out)
+		results <- out.Queued
+		return resp, nil
+	})
+
+	reg, err := domregistry.New(&domregistry.Config{
+		DBPath:          filepath.Join(dir, "registry.db"),
+		OnAcceptService: "test_reextract",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { reg.Close() })
+	reg.RegisterConnectivity(router)
+
+	// Content already extracted by domkeeper for two article pages.
+	if err := dk.AddRule(ctx, &domkeeper.Rule{
+		ID: "r1", Name: "articles", URLPattern: "https://example.com/articles/*",
+		ExtractMode: "css", Selectors: []string{"h1"}, TrustLevel: "community", Enabled: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for i, u := range []string{"https://example.com/articles/1", "https://example.com/articles/2"} {
+		dk.Store().InsertContent(ctx, &domkeeper.Content{
+			ID: u, RuleID: "r1", PageURL: u, ContentHash: hex.EncodeToString([]byte{byte(i)}),
+			ExtractedText: "stale", TrustLevel: "community",
+		})
+	}
+
+	publishResp := callConn(t, router, "domregistry_publish_profile", map[string]any{
+		"url_pattern": "https://example.com/articles/*",
+		"domain":      "example.com",
+		"extractors":  `{"body":".article-body"}`,
+		"dom_profile": `{}`,
+		"trust_level": "community",
+	})
+	var profile struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(publishResp, &profile)
+
+	corrResp := callConn(t, router, "domregistry_submit_correction", map[string]any{
+		"profile_id":     profile.ID,
+		"instance_id":    "worker-01",
+		"new_extractors": `{"body":".content"}`,
+		"reason":         "selector_broken",
+	})
+	var correction struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(corrResp, &correction)
+
+	// New instance: the correction stays pending until accepted by hand.
+	select {
+	case <-results:
+		t.Fatal("re-extraction triggered before acceptance")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := reg.AcceptCorrection(ctx, correction.ID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	select {
+	case queued := <-results:
+		if queued != 2 {
+			t.Errorf("queued = %d, want 2", queued)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("re-extraction not triggered after acceptance")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/hazyhaar/chrc/veille"
 	"github.com/hazyhaar/pkg/connectivity"
+	"github.com/hazyhaar/pkg/docpipe"
 
 	_ "modernc.org/sqlite"
 )
@@ -82,3 +83,38 @@ func TestE2E_ConnectivityBridge(t *testing.T) {
 		t.Errorf("extractions after dedup: got %d, want 2", len(exts2))
 	}
 }
+
+func TestE2E_VeilleOnSharedRouter(t *testing.T) {
+	// WHAT: veille registers on a router shared with docpipe; veille_add_source then veille_stats go through it.
+	// WHY: veille must compose on the connectivity.Router like the other chrc packages.
+	pool := newTestPool()
+	defer pool.Close()
+	svc, err := veille.New(pool, nil, nil, veille.WithURLValidator(func(_ string) error { return nil }))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+
+	router := connectivity.New()
+	docpipe.New(docpipe.Config{}).RegisterConnectivity(router)
+	svc.RegisterConnectivity(router)
+
+	callConn(t, router, "veille_add_source", map[string]any{
+		"dossier_id": "d1", "name": "Shared", "url": "https://shared.example.com",
+	})
+	var stats veille.SpaceStats
+	resp := callConn(t, router, "veille_stats", map[string]any{"dossier_id": "d1"})
+	if err := json.Unmarshal(resp, &stats); err != nil {
+		t.Fatalf("unmarshal stats: %v (raw: %s)", err, resp)
+	}
+	if stats.Sources != 1 {
+		t.Errorf("sources: got %d, want 1", stats.Sources)
+	}
+
+	var detect map[string]any
+	if err := json.Unmarshal(callConn(t, router, "docpipe_detect", map[string]any{"path": "test.md"}), &detect); err != nil {
+		t.Fatalf("unmarshal detect: %v", err)
+	}
+	if _, ok := detect["format"]; !ok {
+		t.Errorf("docpipe_detect: missing format in %v", detect)
+	}
+}
@@ -0,0 +1,109 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/hazyhaar/chrc/horosembed"
+	"github.com/hazyhaar/chrc/vecbridge"
+	"github.com/hazyhaar/chrc/veille"
+	"github.com/hazyhaar/horosvec"
+	"github.com/hazyhaar/pkg/connectivity"
+	"github.com/hazyhaar/pkg/dbopen"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestE2E_VeilleRelatedExtractions(t *testing.T) {
+	// WHAT: With horosembed + vecbridge on the router, stored extractions are embedded and Related finds an extraction and its near-duplicate.
+	// WHY: Related is the only consumer of the vector mapping written at insert time; both sides must agree on vector IDs and the dossier filter.
+	const dim = 8
+	ctx := context.Background()
+	router := connectivity.New()
+	horosembed.RegisterConnectivity(router, &hashEmbedder{dim: dim})
+
+	vecSvc, err := vecbridge.NewFromDB(dbopen.OpenMemory(t), horosvec.DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedVecs := make([][]float32, 20)
+	seedIDs := make([][]byte, 20)
+	for i := range seedVecs {
+		v := make([]float32, dim)
+		for j := range v {
+			v[j] = rand.Float32() - 0.5
+		}
+		seedVecs[i] = v
+		seedIDs[i] = []byte{0xEE, byte(i)}
+	}
+	if err := vecSvc.Index.Build(ctx, &sliceIter{vecs: seedVecs, ids: seedIDs}); err != nil {
+		t.Fatal(err)
+	}
+	vecSvc.RegisterConnectivity(router)
+
+	// Two items with the same title and text (near-duplicates: distinct
+	// URLs and hashes), one unrelated.
+	router.RegisterLocal("news_fetch", func(_ context.Context, _ []byte) ([]byte, error) {
+		return json.Marshal(map[string]any{"extractions": []map[string]any{
+			{"title": "Solar output record", "content": "Solar farms produced a record share of power this week.", "url": "https://news.example.com/a", "content_hash": "a"},
+			{"title": "Solar output record", "content": "Solar farms produced a record share of power this week.", "url": "https://mirror.example.com/a", "content_hash": "a-mirror"},
+			{"title": "Chess final", "content": "The chess final went to a tiebreak after twelve draws.", "url": "https://news.example.com/b", "content_hash": "b"},
+		}})
+	})
+
+	pool := newTestPool()
+	defer pool.Close()
+	svc, err := veille.New(pool, nil, nil, veille.WithRouter(router))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	src := &veille.Source{Name: "News", URL: "https://news.example.com", SourceType: "news", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add source: %v", err)
+	}
+	if err := svc.FetchNow(ctx, "d1", src.ID); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	exts, _ := svc.ListExtractions(ctx, "d1", src.ID, 10)
+	if len(exts) != 3 {
+		t.Fatalf("extractions: got %d, want 3", len(exts))
+	}
+	byURL := map[string]string{}
+	for _, e := range exts {
+		byURL[e.URL] = e.ID
+	}
+	orig, mirror := byURL["https://news.example.com/a"], byURL["https://mirror.example.com/a"]
+
+	related, err := svc.Related(ctx, "d1", orig, 2)
+	if err != nil {
+		t.Fatalf("related: %v", err)
+	}
+	got := map[string]bool{}
+	for _, r := range related {
+		got[r.Extraction.ID] = true
+	}
+	if len(related) != 2 || !got[orig] || !got[mirror] {
+		t.Errorf("related: got %v, want the extraction and its mirror", got)
+	}
+
+	if _, err := svc.Related(ctx, "d1", "missing", 2); !errors.Is(err, veille.ErrExtractionNotFound) {
+		t.Errorf("unknown extraction: got %v, want ErrExtractionNotFound", err)
+	}
+}
+
+func TestE2E_VeilleRelatedDisabled(t *testing.T) {
+	// WHAT: Without embed and vector services on the router, Related returns ErrVectorsDisabled.
+	// WHY: The feature is gated on configuration; callers map the error to "not available".
+	pool := newTestPool()
+	defer pool.Close()
+	svc, err := veille.New(pool, nil, nil, veille.WithRouter(connectivity.New()))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if _, err := svc.Related(context.Background(), "d1", "any", 5); !errors.Is(err, veille.ErrVectorsDisabled) {
+		t.Errorf("got %v, want ErrVectorsDisabled", err)
+	}
+}
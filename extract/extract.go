@@ -1,10 +1,12 @@
-// CLAUDE:SUMMARY Entry point for the extraction pipeline with auto/css/xpath/density mode dispatch.
+// CLAUDE:SUMMARY Entry point for the extraction pipeline with auto/css/xpath/density/readability mode dispatch.
 // Package extract implements the content extraction pipeline.
 //
 // It supports multiple extraction modes:
 //   - css:     Extract content matching CSS selectors
 //   - xpath:   Extract content matching XPath expressions
 //   - density: Extract content based on text-to-markup density analysis
+//   - readability: Article body only, boilerplate stripped, with author
+//     and publish date
 //   - auto:    Try CSS/XPath selectors first, fall back to density
 //
 // The pipeline: raw HTML -> parse -> select regions -> clean -> extract text.
@@ -26,12 +28,29 @@ type Result struct {
 	HTML  string // extracted HTML (cleaned)
 	Title string // page title if found
 	Hash  string // SHA-256 of extracted text
+
+	// Set by the readability mode only.
+	Author    string // article author, from meta tags or byline
+	Published string // publish date as found in the page (usually ISO 8601)
+}
+
+// Modes lists the valid extraction modes.
+var Modes = []string{"css", "xpath", "density", "readability", "auto"}
+
+// ValidMode reports whether mode is a known extraction mode.
+func ValidMode(mode string) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
 }
 
 // Options controls extraction behaviour.
 type Options struct {
 	Selectors   []string // CSS selectors or XPath expressions
-	Mode        string   // "css", "xpath", "density", "auto"
+	Mode        string   // "css", "xpath", "density", "readability", "auto"
 	MinTextLen  int      // minimum text length to accept (default: 50)
 	TrustLevel  string   // propagated to result
 }
@@ -63,6 +82,8 @@ func Extract(rawHTML []byte, opts Options) (*Result, error) {
 		return extractXPath(doc, opts.Selectors, title, opts.MinTextLen)
 	case "density":
 		return extractDensity(doc, title, opts.MinTextLen)
+	case "readability":
+		return extractReadability(doc, title, opts.MinTextLen)
 	case "auto":
 		// Try selectors first (if any), fall back to density.
 		if len(opts.Selectors) > 0 {
@@ -141,3 +141,69 @@ func TestNormaliseForHash(t *testing.T) {
 		t.Errorf("NormaliseForHash: %q != %q", a, b)
 	}
 }
+
+var articleHTML = []byte(`<!DOCTYPE html>
+<html>
+<head>
+<title>Launch report</title>
+<meta name="author" content="Jane Doe">
+<meta property="article:published_time" content="2026-03-01T08:00:00Z">
+</head>
+<body>
+<header class="site-header"><a href="/">Daily Site</a></header>
+<nav><a href="/news">News</a> <a href="/sports">Sports</a></nav>
+<article>
+<h1>Rocket launch succeeds</h1>
+<p>The rocket lifted off at dawn and reached orbit nine minutes later, the agency said in a statement.</p>
+<div class="ad-slot">Buy cheap watches now</div>
+<div class="share-buttons"><a href="#">Share on social</a></div>
+<p>Engineers will now test the payload over the coming weeks before the commercial service starts.</p>
+<form><input name="email"><button>Subscribe</button></form>
+</article>
+<aside class="related">Related: ten other launches</aside>
+<footer>Copyright 2026 Daily Site</footer>
+</body>
+</html>`)
+
+func TestExtract_Readability(t *testing.T) {
+	// WHAT: readability keeps only the article paragraphs and reads author/date from meta tags.
+	// WHY: Site chrome, ads and widgets must not pollute stored content or chunks.
+	res, err := Extract(articleHTML, Options{Mode: "readability"})
+	if err != nil {
+		t.Fatalf("extract readability: %v", err)
+	}
+	for _, want := range []string{"Rocket launch succeeds", "lifted off at dawn", "test the payload"} {
+		if !strings.Contains(res.Text, want) {
+			t.Errorf("missing %q in %q", want, res.Text)
+		}
+	}
+	for _, noise := range []string{"Daily Site", "Sports", "cheap watches", "Share on social", "Subscribe", "Related", "Copyright"} {
+		if strings.Contains(res.Text, noise) || strings.Contains(res.HTML, noise) {
+			t.Errorf("boilerplate %q survived: %q", noise, res.Text)
+		}
+	}
+	if res.Author != "Jane Doe" || res.Published != "2026-03-01T08:00:00Z" {
+		t.Errorf("metadata: author %q, published %q", res.Author, res.Published)
+	}
+	if res.Title != "Launch report" {
+		t.Errorf("title: %q", res.Title)
+	}
+}
+
+func TestExtract_ReadabilityBylineAndDensityFallback(t *testing.T) {
+	page := []byte(`<html><body><nav><a href="/">Home</a></nav>
+<div id="content"><span class="byline">By John Smith</span> <time datetime="2026-02-02">Feb 2</time>
+<p>No article element here, but this block has by far the most running text on the page, so density finds it.</p>
+<p>A second paragraph keeps the ratio of text to markup high enough to win.</p></div>
+<footer>About us</footer></body></html>`)
+	res, err := Extract(page, Options{Mode: "readability"})
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if !strings.Contains(res.Text, "density finds it") || strings.Contains(res.Text, "About us") {
+		t.Errorf("text: %q", res.Text)
+	}
+	if res.Author != "John Smith" || res.Published != "2026-02-02" {
+		t.Errorf("metadata: author %q, published %q", res.Author, res.Published)
+	}
+}
@@ -0,0 +1,249 @@
+// CLAUDE:SUMMARY Readability-style extraction — article body only (nav, footer, ads, forms stripped) plus author and publish date metadata.
+package extract
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// extractReadability returns the article body of the page with boilerplate
+// stripped, and fills Author and Published from page metadata.
+//
+// The body is the largest <article> when the page has one, the densest
+// content node otherwise. Boilerplate inside the body (nav, aside, ads,
+// share widgets, forms, embeds) is removed before text and HTML are built.
+func extractReadability(doc *html.Node, title string, minLen int) (*Result, error) {
+	res := &Result{
+		Title:     title,
+		Author:    findAuthor(doc),
+		Published: findPublished(doc),
+	}
+
+	root := findArticle(doc)
+	if root == nil {
+		body := findBody(doc)
+		if body == nil {
+			body = doc
+		}
+		root = findDensestNode(body, minLen)
+		if root == nil {
+			root = body
+		}
+	}
+
+	clean := cloneTree(root)
+	stripNoise(clean)
+
+	text := collectCleanText(clean)
+	if len(text) < minLen {
+		res.Hash = hashText("")
+		return res, nil
+	}
+	res.Text = text
+	res.HTML = renderNode(clean)
+	res.Hash = hashText(text)
+	return res, nil
+}
+
+// findArticle returns the <article> with the most clean text, or nil.
+func findArticle(doc *html.Node) *html.Node {
+	var best *html.Node
+	bestLen := 0
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Article {
+			if l := len(collectCleanText(n)); l > bestLen {
+				best, bestLen = n, l
+			}
+			return // nested articles (comments, teasers) belong to this one
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return best
+}
+
+// cloneTree deep-copies n so stripping never alters the parsed document.
+func cloneTree(n *html.Node) *html.Node {
+	c := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      append([]html.Attribute(nil), n.Attr...),
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.AppendChild(cloneTree(child))
+	}
+	return c
+}
+
+// stripNoise removes boilerplate and non-content elements under n.
+func stripNoise(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type == html.ElementNode && isReadabilityNoise(c):
+			n.RemoveChild(c)
+		default:
+			stripNoise(c)
+		}
+		c = next
+	}
+}
+
+// isReadabilityNoise extends isBoilerplate with elements that never carry
+// article text.
+func isReadabilityNoise(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Form, atom.Button,
+		atom.Iframe, atom.Object, atom.Embed, atom.Svg, atom.Canvas,
+		atom.Input, atom.Select, atom.Textarea:
+		return true
+	}
+	if isBoilerplate(n) {
+		return true
+	}
+	for _, attr := range n.Attr {
+		if attr.Key != "class" && attr.Key != "id" {
+			continue
+		}
+		for _, tok := range strings.FieldsFunc(strings.ToLower(attr.Val), isTokenSep) {
+			if noiseTokens[tok] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTokenSep(r rune) bool { return r == ' ' || r == '-' || r == '_' }
+
+// noiseTokens are whole class/id tokens (split on space, - and _) marking
+// ads and promos; matching whole tokens keeps "header" or "shadow" safe.
+var noiseTokens = map[string]bool{
+	"ad": true, "ads": true, "advertisement": true, "sponsor": true,
+	"sponsored": true, "promo": true, "newsletter": true, "subscribe": true,
+	"paywall": true, "outbrain": true, "taboola": true,
+}
+
+// findAuthor returns the author from meta tags, rel/itemprop markup or a
+// byline element, in that order.
+func findAuthor(doc *html.Node) string {
+	if v := findMeta(doc, "author", "article:author", "twitter:creator", "dc.creator"); v != "" {
+		return v
+	}
+	var author string
+	var walk func(*html.Node) bool
+	walk = func(n *html.Node) bool {
+		if n.Type == html.ElementNode {
+			if attrVal(n, "rel") == "author" || attrVal(n, "itemprop") == "author" ||
+				hasClassToken(n, "byline") || hasClassToken(n, "author") {
+				if t := strings.TrimSpace(collectText(n)); t != "" && len(t) <= 100 {
+					author = strings.TrimSpace(strings.TrimPrefix(t, "By "))
+					return true
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if walk(c) {
+				return true
+			}
+		}
+		return false
+	}
+	walk(doc)
+	return author
+}
+
+// findPublished returns the publish date as found in the page (usually
+// ISO 8601): meta tags, itemprop datePublished, then the first <time datetime>.
+func findPublished(doc *html.Node) string {
+	if v := findMeta(doc, "article:published_time", "date", "pubdate", "dc.date", "datepublished"); v != "" {
+		return v
+	}
+	var published string
+	var walk func(*html.Node) bool
+	walk = func(n *html.Node) bool {
+		if n.Type == html.ElementNode {
+			if attrVal(n, "itemprop") == "datePublished" {
+				if v := attrVal(n, "content"); v != "" {
+					published = v
+				} else if v := attrVal(n, "datetime"); v != "" {
+					published = v
+				}
+			}
+			if published == "" && n.DataAtom == atom.Time {
+				published = attrVal(n, "datetime")
+			}
+			if published != "" {
+				return true
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if walk(c) {
+				return true
+			}
+		}
+		return false
+	}
+	walk(doc)
+	return published
+}
+
+// findMeta returns the content of the first <meta> whose name or property
+// matches one of keys (case-insensitive), in keys order.
+func findMeta(doc *html.Node, keys ...string) string {
+	found := make(map[string]string)
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Meta {
+			key := strings.ToLower(attrVal(n, "name"))
+			if key == "" {
+				key = strings.ToLower(attrVal(n, "property"))
+			}
+			if key == "" {
+				key = strings.ToLower(attrVal(n, "itemprop"))
+			}
+			if v := strings.TrimSpace(attrVal(n, "content")); key != "" && v != "" {
+				if _, ok := found[key]; !ok {
+					found[key] = v
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	for _, k := range keys {
+		if v := found[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func attrVal(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClassToken(n *html.Node, tok string) bool {
+	for _, f := range strings.Fields(strings.ToLower(attrVal(n, "class"))) {
+		if f == tok {
+			return true
+		}
+	}
+	return false
+}
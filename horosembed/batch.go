@@ -0,0 +1,88 @@
+// CLAUDE:SUMMARY Sub-batching Embedder wrapper — splits oversized EmbedBatch calls into MaxBatch chunks with bounded concurrency, order preserved.
+package horosembed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// batchEmbedder splits EmbedBatch calls larger than maxBatch into
+// sub-batches, at most concurrency of them in flight. Results are
+// reassembled in input order.
+type batchEmbedder struct {
+	Embedder
+	maxBatch    int
+	concurrency int
+}
+
+// NewBatched wraps emb so that EmbedBatch never sends more than maxBatch
+// texts in one call. Up to concurrency sub-batches run at once (1 =
+// sequential); the first error cancels the remaining sub-batches.
+// maxBatch <= 0 returns emb unchanged.
+func NewBatched(emb Embedder, maxBatch, concurrency int) Embedder {
+	if maxBatch <= 0 {
+		return emb
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &batchEmbedder{Embedder: emb, maxBatch: maxBatch, concurrency: concurrency}
+}
+
+func (b *batchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) <= b.maxBatch {
+		return b.Embedder.EmbedBatch(ctx, texts)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := make([][]float32, len(texts))
+	sem := make(chan struct{}, b.concurrency)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for start := 0; start < len(texts); start += b.maxBatch {
+		end := min(start+b.maxBatch, len(texts))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			vecs, err := b.Embedder.EmbedBatch(ctx, texts[start:end])
+			if err == nil && len(vecs) != end-start {
+				err = fmt.Errorf("got %d vectors, want %d", len(vecs), end-start)
+			}
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("sub-batch [%d:%d]: %w", start, end, err)
+					cancel()
+				})
+				return
+			}
+			copy(result[start:end], vecs)
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
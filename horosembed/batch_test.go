@@ -0,0 +1,101 @@
+package horosembed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingEmbedder returns [index] vectors for texts "0", "1", ... and
+// records the size of every EmbedBatch call.
+type recordingEmbedder struct {
+	noopEmbedder
+	mu       sync.Mutex
+	calls    []int
+	delay    time.Duration
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	failOn   string
+}
+
+func (r *recordingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	n := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		p := r.peak.Load()
+		if n <= p || r.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	r.mu.Lock()
+	r.calls = append(r.calls, len(texts))
+	r.mu.Unlock()
+
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		if t == r.failOn {
+			return nil, errors.New("boom")
+		}
+		out[i] = []float32{float32(len(t)), float32(t[0])}
+	}
+	return out, nil
+}
+
+func TestBatched_SplitsInOrder(t *testing.T) {
+	// WHAT: MaxBatch=2 with 5 inputs makes 3 underlying calls (2, 2, 1) and keeps input order.
+	// WHY: A GPU server must never receive more than MaxBatch texts; callers index vectors by position.
+	rec := &recordingEmbedder{}
+	emb := NewBatched(rec, 2, 1)
+
+	texts := []string{"a", "b", "c", "d", "e"}
+	vecs, err := emb.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.calls) != 3 || rec.calls[0] != 2 || rec.calls[1] != 2 || rec.calls[2] != 1 {
+		t.Fatalf("calls = %v, want [2 2 1]", rec.calls)
+	}
+	for i, v := range vecs {
+		if v[1] != float32(texts[i][0]) {
+			t.Errorf("vecs[%d] belongs to %q, want %q", i, string(rune(v[1])), texts[i])
+		}
+	}
+}
+
+func TestBatched_BoundedConcurrency(t *testing.T) {
+	rec := &recordingEmbedder{delay: 20 * time.Millisecond}
+	emb := NewBatched(rec, 1, 3)
+
+	texts := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	vecs, err := emb.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := rec.peak.Load(); p < 2 || p > 3 {
+		t.Errorf("peak in-flight = %d, want 2..3", p)
+	}
+	for i, v := range vecs {
+		if v[1] != float32(texts[i][0]) {
+			t.Errorf("vecs[%d] out of order", i)
+		}
+	}
+}
+
+func TestBatched_ErrorStops(t *testing.T) {
+	rec := &recordingEmbedder{failOn: "b"}
+	emb := NewBatched(rec, 1, 1)
+	if _, err := emb.EmbedBatch(context.Background(), []string{"a", "b", "c", "d"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(rec.calls) != 2 {
+		t.Errorf("calls after failure = %v, want 2 calls", rec.calls)
+	}
+}
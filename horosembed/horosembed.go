@@ -53,6 +53,14 @@ type Config struct {
 	// BatchSize is the maximum number of texts per HTTP request. Default: 32.
 	BatchSize int `json:"batch_size" yaml:"batch_size"`
 
+	// MaxBatch caps the texts per sub-batch of one EmbedBatch call, whatever
+	// the backend (see NewBatched). 0 disables the wrapper.
+	MaxBatch int `json:"max_batch" yaml:"max_batch"`
+
+	// MaxConcurrency is the number of sub-batches in flight when MaxBatch is
+	// set and the embedder is a remote server. Default: 2.
+	MaxConcurrency int `json:"max_concurrency" yaml:"max_concurrency"`
+
 	// Timeout per HTTP request. Default: 30s.
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
 
@@ -67,6 +75,9 @@ func (c *Config) defaults() {
 	if c.Timeout <= 0 {
 		c.Timeout = 30 * time.Second
 	}
+	if c.MaxConcurrency <= 0 {
+		c.MaxConcurrency = 2
+	}
 	if c.Logger == nil {
 		c.Logger = slog.Default()
 	}
@@ -74,6 +85,7 @@ func (c *Config) defaults() {
 
 // New creates an Embedder from config. If Endpoint is empty, returns a
 // NoopEmbedder that produces zero vectors of the configured dimension.
+// With MaxBatch set, the embedder is wrapped by NewBatched.
 func New(cfg Config) Embedder {
 	cfg.defaults()
 	if cfg.Endpoint == "" {
@@ -81,9 +93,9 @@ func New(cfg Config) Embedder {
 		if dim <= 0 {
 			dim = 768
 		}
-		return &noopEmbedder{dim: dim, model: cfg.Model}
+		return NewBatched(&noopEmbedder{dim: dim, model: cfg.Model}, cfg.MaxBatch, 1)
 	}
-	return newOpenAIClient(cfg)
+	return NewBatched(newOpenAIClient(cfg), cfg.MaxBatch, cfg.MaxConcurrency)
 }
 
 // noopEmbedder returns zero vectors — useful for testing without a server.
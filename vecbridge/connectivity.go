@@ -14,8 +14,8 @@ import (
 //
 // Registered services:
 //
-//	horosvec_search — ANN search by query vector
-//	horosvec_insert — insert vectors into the index
+//	horosvec_search — ANN search by query vector (optional metric, metadata filter)
+//	horosvec_insert — insert vectors into the index (optional metadata)
 //	horosvec_stats  — index statistics
 func (s *Service) RegisterConnectivity(router *connectivity.Router) {
 	router.RegisterLocal("horosvec_search", s.handleSearch)
@@ -25,8 +25,10 @@ func (s *Service) RegisterConnectivity(router *connectivity.Router) {
 
 func (s *Service) handleSearch(ctx context.Context, payload []byte) ([]byte, error) {
 	var req struct {
-		Vector []float32 `json:"vector"`
-		TopK   int       `json:"top_k"`
+		Vector []float32         `json:"vector"`
+		TopK   int               `json:"top_k"`
+		Metric string            `json:"metric"`
+		Filter map[string]string `json:"filter"`
 	}
 	if err := json.Unmarshal(payload, &req); err != nil {
 		return nil, fmt.Errorf("decode: %w", err)
@@ -34,26 +36,23 @@ func (s *Service) handleSearch(ctx context.Context, payload []byte) ([]byte, err
 	if req.TopK <= 0 {
 		req.TopK = 10
 	}
-
-	results, err := s.Index.Search(req.Vector, req.TopK)
+	metric, err := ParseMetric(req.Metric)
 	if err != nil {
 		return nil, err
 	}
 
-	out := make([]map[string]any, len(results))
-	for i, res := range results {
-		out[i] = map[string]any{
-			"id":    hex.EncodeToString(res.ID),
-			"score": res.Score,
-		}
+	results, err := s.SearchFiltered(req.Vector, req.TopK, metric, req.Filter)
+	if err != nil {
+		return nil, err
 	}
-	return json.Marshal(map[string]any{"results": out})
+	return json.Marshal(map[string]any{"results": resultsJSON(results)})
 }
 
 func (s *Service) handleInsert(_ context.Context, payload []byte) ([]byte, error) {
 	var req struct {
-		IDs     []string    `json:"ids"`
-		Vectors [][]float32 `json:"vectors"`
+		IDs      []string            `json:"ids"`
+		Vectors  [][]float32         `json:"vectors"`
+		Metadata []map[string]string `json:"metadata"`
 	}
 	if err := json.Unmarshal(payload, &req); err != nil {
 		return nil, fmt.Errorf("decode: %w", err)
@@ -69,7 +68,7 @@ func (s *Service) handleInsert(_ context.Context, payload []byte) ([]byte, error
 		}
 	}
 
-	if err := s.Index.Insert(req.Vectors, ids); err != nil {
+	if err := s.insertWithMetadata(req.Vectors, ids, req.Metadata); err != nil {
 		return nil, err
 	}
 	return json.Marshal(map[string]any{"inserted": len(req.Vectors), "count": s.Index.Count()})
@@ -77,7 +76,23 @@ func (s *Service) handleInsert(_ context.Context, payload []byte) ([]byte, error
 
 func (s *Service) handleStats(_ context.Context, _ []byte) ([]byte, error) {
 	return json.Marshal(map[string]any{
-		"count":         s.Index.Count(),
-		"needs_rebuild": s.Index.NeedsRebuild(),
+		"count":           s.Index.Count(),
+		"needs_rebuild":   s.Index.NeedsRebuild(),
+		"metric":          s.metric,
+		"filter_strategy": s.filter,
 	})
 }
+
+// resultsJSON renders results with hex-encoded IDs.
+func resultsJSON(results []SearchResult) []map[string]any {
+	out := make([]map[string]any, len(results))
+	for i, res := range results {
+		out[i] = map[string]any{
+			"id":        hex.EncodeToString(res.ID),
+			"score":     res.Score,
+			"raw_score": res.RawScore,
+			"metric":    res.Metric,
+		}
+	}
+	return out
+}
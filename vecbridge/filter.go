@@ -0,0 +1,283 @@
+// CLAUDE:SUMMARY Metadata filtering — vecbridge_metadata table keyed by vector ID, pre-filter (exact scan) or post-filter (expanding ANN) search.
+package vecbridge
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FilterStrategy selects how a metadata filter is applied to a search.
+//
+// Post-filtering asks horosvec for more neighbours than requested and drops
+// the non-matching ones, widening the search until topK matches are found
+// or the whole index was scanned. It stays on the ANN path, but recall
+// drops for selective filters: matching vectors that the beam search never
+// reaches are missed, and each widening costs a full search.
+//
+// Pre-filtering restricts the candidate set to the matching IDs and ranks
+// them by exact distance. Recall is exact, cost grows with the number of
+// matching vectors: use it for selective filters.
+type FilterStrategy string
+
+const (
+	FilterPost FilterStrategy = "post" // default
+	FilterPre  FilterStrategy = "pre"
+)
+
+// postFilterExpand is the growth factor of the post-filter candidate set.
+const postFilterExpand = 4
+
+const metadataSchema = `CREATE TABLE IF NOT EXISTS vecbridge_metadata (
+    ext_id BLOB NOT NULL,
+    key    TEXT NOT NULL,
+    value  TEXT NOT NULL,
+    PRIMARY KEY (ext_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_vecbridge_metadata_kv ON vecbridge_metadata(key, value)`
+
+// ParseFilterStrategy validates a strategy name. "" means FilterPost.
+func ParseFilterStrategy(s string) (FilterStrategy, error) {
+	switch f := FilterStrategy(s); f {
+	case "":
+		return FilterPost, nil
+	case FilterPost, FilterPre:
+		return f, nil
+	default:
+		return "", fmt.Errorf("vecbridge: unknown filter strategy %q (valid: post, pre)", s)
+	}
+}
+
+// initMetadata creates the metadata table.
+func (s *Service) initMetadata() error {
+	if _, err := s.db.Exec(metadataSchema); err != nil {
+		return fmt.Errorf("vecbridge: init metadata: %w", err)
+	}
+	return nil
+}
+
+// SetMetadata replaces the metadata attached to the vector id.
+func (s *Service) SetMetadata(id []byte, md map[string]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM vecbridge_metadata WHERE ext_id = ?`, id); err != nil {
+		return fmt.Errorf("vecbridge: clear metadata: %w", err)
+	}
+	for k, v := range md {
+		if _, err := tx.Exec(`INSERT INTO vecbridge_metadata (ext_id, key, value) VALUES (?, ?, ?)`, id, k, v); err != nil {
+			return fmt.Errorf("vecbridge: set metadata: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Metadata returns the metadata attached to the vector id (empty if none).
+func (s *Service) Metadata(id []byte) (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT key, value FROM vecbridge_metadata WHERE ext_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	md := make(map[string]string)
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, err
+		}
+		md[k] = v
+	}
+	return md, rows.Err()
+}
+
+// insertWithMetadata inserts vectors and, when given, one metadata map per ID.
+func (s *Service) insertWithMetadata(vecs [][]float32, ids [][]byte, md []map[string]string) error {
+	if len(md) > 0 && len(md) != len(ids) {
+		return fmt.Errorf("vecbridge: metadata/ids length mismatch: %d vs %d", len(md), len(ids))
+	}
+	if err := s.Index.Insert(s.prepareVectors(vecs), ids); err != nil {
+		return err
+	}
+	for i, m := range md {
+		if len(m) == 0 {
+			continue
+		}
+		if err := s.SetMetadata(ids[i], m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchFiltered is Search restricted to vectors whose metadata contains
+// every key/value pair of filter, applied with the service FilterStrategy.
+// A nil or empty filter is a plain Search.
+func (s *Service) SearchFiltered(query []float32, topK int, metric Metric, filter map[string]string) ([]SearchResult, error) {
+	query, metric, err := s.searchQuery(query, metric)
+	if err != nil {
+		return nil, err
+	}
+	if len(filter) == 0 {
+		results, err := s.Index.Search(query, topK)
+		if err != nil {
+			return nil, err
+		}
+		return scoreResults(results, metric), nil
+	}
+
+	var results []SearchResult
+	if s.filter == FilterPre {
+		results, err = s.preFilterSearch(query, topK, filter)
+	} else {
+		results, err = s.postFilterSearch(query, topK, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scoreSearchResults(results, metric), nil
+}
+
+// postFilterSearch widens the ANN search until topK candidates match.
+func (s *Service) postFilterSearch(query []float32, topK int, filter map[string]string) ([]SearchResult, error) {
+	total := s.Index.Count()
+	k := topK * postFilterExpand
+	for {
+		if k > total {
+			k = total
+		}
+		results, err := s.Index.Search(query, k)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([][]byte, len(results))
+		for i, r := range results {
+			ids[i] = r.ID
+		}
+		match, err := s.matchFilter(filter, ids)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]SearchResult, 0, topK)
+		for _, r := range results {
+			if match[string(r.ID)] {
+				out = append(out, SearchResult{ID: r.ID, RawScore: r.Score})
+				if len(out) == topK {
+					break
+				}
+			}
+		}
+		if len(out) == topK || k >= total {
+			return out, nil
+		}
+		k *= postFilterExpand
+	}
+}
+
+// preFilterSearch ranks the matching vectors by exact squared L2 distance.
+func (s *Service) preFilterSearch(query []float32, topK int, filter map[string]string) ([]SearchResult, error) {
+	sub, args := filterQuery(filter, nil)
+	rows, err := s.db.Query(`SELECT ext_id, vector FROM vec_nodes WHERE ext_id IN (`+sub+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("vecbridge: pre-filter: %w", err)
+	}
+	defer rows.Close()
+
+	h := &resultHeap{}
+	for rows.Next() {
+		var id, blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			return nil, err
+		}
+		vec := deserializeFloat32s(blob)
+		if len(vec) != len(query) {
+			return nil, fmt.Errorf("vecbridge: query dim %d != index dim %d", len(query), len(vec))
+		}
+		heap.Push(h, SearchResult{ID: id, RawScore: squaredL2(query, vec)})
+		if h.Len() > topK {
+			heap.Pop(h)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]SearchResult, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(h).(SearchResult)
+	}
+	return out, nil
+}
+
+// matchFilter returns the subset of ids whose metadata matches filter.
+func (s *Service) matchFilter(filter map[string]string, ids [][]byte) (map[string]bool, error) {
+	match := make(map[string]bool)
+	if len(ids) == 0 {
+		return match, nil
+	}
+	q, args := filterQuery(filter, ids)
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("vecbridge: post-filter: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id []byte
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		match[string(id)] = true
+	}
+	return match, rows.Err()
+}
+
+// filterQuery builds the query selecting the ext_ids matching every pair of
+// filter, optionally restricted to ids.
+func filterQuery(filter map[string]string, ids [][]byte) (string, []any) {
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	conds := make([]string, len(keys))
+	args := make([]any, 0, 2*len(keys)+len(ids)+1)
+	for i, k := range keys {
+		conds[i] = "(key = ? AND value = ?)"
+		args = append(args, k, filter[k])
+	}
+	q := "SELECT ext_id FROM vecbridge_metadata WHERE (" + strings.Join(conds, " OR ") + ")"
+	if len(ids) > 0 {
+		q += " AND ext_id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	q += " GROUP BY ext_id HAVING COUNT(*) = ?"
+	args = append(args, len(keys))
+	return q, args
+}
+
+func squaredL2(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return sum
+}
+
+// resultHeap is a max-heap on RawScore: the root is the farthest kept result.
+type resultHeap []SearchResult
+
+func (h resultHeap) Len() int           { return len(h) }
+func (h resultHeap) Less(i, j int) bool { return h[i].RawScore > h[j].RawScore }
+func (h resultHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x any)        { *h = append(*h, x.(SearchResult)) }
+func (h *resultHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
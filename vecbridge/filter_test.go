@@ -0,0 +1,133 @@
+package vecbridge
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/hazyhaar/horosvec"
+	"github.com/hazyhaar/pkg/connectivity"
+	"github.com/hazyhaar/pkg/dbopen"
+)
+
+// insertCategorized inserts n vectors through horosvec_insert, alternating
+// the "category" metadata between news and sport. It returns the news IDs.
+func insertCategorized(t *testing.T, router *connectivity.Router, dim, n int) map[string]bool {
+	t.Helper()
+	ids := make([]string, n)
+	vecs := make([][]float32, n)
+	md := make([]map[string]string, n)
+	news := make(map[string]bool)
+	for i := range ids {
+		ids[i] = hex.EncodeToString([]byte{0xF0, byte(i)})
+		vecs[i] = make([]float32, dim)
+		for j := range vecs[i] {
+			vecs[i][j] = rand.Float32() - 0.5
+		}
+		cat := "sport"
+		if i%2 == 0 {
+			cat = "news"
+			news[ids[i]] = true
+		}
+		md[i] = map[string]string{"category": cat, "lang": "fr"}
+	}
+	payload, _ := json.Marshal(map[string]any{"ids": ids, "vectors": vecs, "metadata": md})
+	if _, err := router.Call(context.Background(), "horosvec_insert", payload); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	return news
+}
+
+func TestFilter_SearchExcludesNonMatching(t *testing.T) {
+	// WHAT: A filtered search only returns IDs whose metadata matches, with both strategies.
+	// WHY: "nearest among category=news" must never leak a sport document, whatever the strategy.
+	for _, strategy := range []FilterStrategy{FilterPost, FilterPre} {
+		t.Run(string(strategy), func(t *testing.T) {
+			db := dbopen.OpenMemory(t)
+			svc, err := NewFromDB(db, horosvec.DefaultConfig(), nil, WithFilterStrategy(strategy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			router := connectivity.New()
+			svc.RegisterConnectivity(router)
+			buildTestIndex(t, svc, 8, 50)
+			news := insertCategorized(t, router, 8, 40)
+
+			payload, _ := json.Marshal(map[string]any{
+				"vector": []float32{0.1, -0.2, 0.3, 0, 0.1, 0.2, -0.1, 0},
+				"top_k":  10,
+				"filter": map[string]string{"category": "news", "lang": "fr"},
+			})
+			resp, err := router.Call(context.Background(), "horosvec_search", payload)
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			var out struct {
+				Results []struct {
+					ID string `json:"id"`
+				} `json:"results"`
+			}
+			json.Unmarshal(resp, &out)
+			if len(out.Results) != 10 {
+				t.Fatalf("got %d results, want 10", len(out.Results))
+			}
+			for _, r := range out.Results {
+				if !news[r.ID] {
+					t.Errorf("result %s does not match category=news", r.ID)
+				}
+			}
+
+			// A filter nothing matches returns no result.
+			res, err := svc.SearchFiltered(make([]float32, 8), 5, "", map[string]string{"category": "weather"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res) != 0 {
+				t.Errorf("unmatched filter returned %d results", len(res))
+			}
+		})
+	}
+}
+
+func TestFilter_PreFilterRanksExactly(t *testing.T) {
+	// WHAT: Pre-filter results are sorted by exact distance, nearest first.
+	// WHY: Pre-filtering trades ANN speed for exact recall; the order must be exact too.
+	db := dbopen.OpenMemory(t)
+	svc, err := NewFromDB(db, horosvec.DefaultConfig(), nil, WithFilterStrategy(FilterPre))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildTestIndex(t, svc, 4, 20)
+	vecs := [][]float32{{3, 0, 0, 0}, {1, 0, 0, 0}, {2, 0, 0, 0}, {0.5, 0, 0, 0}}
+	ids := [][]byte{{0xA0}, {0xA1}, {0xA2}, {0xA3}}
+	md := []map[string]string{{"k": "v"}, {"k": "v"}, {"k": "v"}, {"k": "other"}}
+	if err := svc.insertWithMetadata(vecs, ids, md); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := svc.SearchFiltered([]float32{1, 0, 0, 0}, 3, "", map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0xA1, 0xA2, 0xA0}
+	if len(res) != len(want) {
+		t.Fatalf("got %d results, want %d", len(res), len(want))
+	}
+	for i, r := range res {
+		if r.ID[0] != want[i] {
+			t.Errorf("result %d = %x, want %x", i, r.ID, want[i])
+		}
+	}
+	if res[0].RawScore != 0 || res[1].RawScore != 1 {
+		t.Errorf("raw scores %f, %f, want 0, 1", res[0].RawScore, res[1].RawScore)
+	}
+}
+
+func TestFilter_InvalidStrategy(t *testing.T) {
+	db := dbopen.OpenMemory(t)
+	if _, err := NewFromDB(db, horosvec.DefaultConfig(), nil, WithFilterStrategy("both")); err == nil {
+		t.Fatal("expected error for unknown filter strategy")
+	}
+}
@@ -33,9 +33,11 @@ func inputSchema(properties map[string]any, required []string) map[string]any {
 // --- search ---
 
 type searchReq struct {
-	Vector   []float32 `json:"vector"`
-	TopK     int       `json:"top_k,omitempty"`
-	EfSearch int       `json:"ef_search,omitempty"`
+	Vector   []float32         `json:"vector"`
+	TopK     int               `json:"top_k,omitempty"`
+	EfSearch int               `json:"ef_search,omitempty"`
+	Metric   string            `json:"metric,omitempty"`
+	Filter   map[string]string `json:"filter,omitempty"`
 }
 
 func (s *Service) registerSearchTool(srv *mcp.Server) {
@@ -50,6 +52,12 @@ func (s *Service) registerSearchTool(srv *mcp.Server) {
 			},
 			"top_k":     map[string]any{"type": "integer", "description": "Number of results (default: 10)"},
 			"ef_search": map[string]any{"type": "integer", "description": "Beam width for search (default: from config)"},
+			"metric":    map[string]any{"type": "string", "enum": []any{"l2", "cosine", "dot"}, "description": "Distance metric (default: the index metric); must be compatible with the index"},
+			"filter": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "string"},
+				"description":          "Metadata filter: only vectors whose metadata has every key/value pair",
+			},
 		}, []string{"vector"}),
 	}
 
@@ -59,17 +67,15 @@ func (s *Service) registerSearchTool(srv *mcp.Server) {
 		if topK <= 0 {
 			topK = 10
 		}
-		results, err := s.Index.Search(r.Vector, topK)
+		metric, err := ParseMetric(r.Metric)
 		if err != nil {
 			return nil, err
 		}
-		out := make([]map[string]any, len(results))
-		for i, res := range results {
-			out[i] = map[string]any{
-				"id":    hex.EncodeToString(res.ID),
-				"score": res.Score,
-			}
+		results, err := s.SearchFiltered(r.Vector, topK, metric, r.Filter)
+		if err != nil {
+			return nil, err
 		}
+		out := resultsJSON(results)
 		return map[string]any{"results": out, "count": len(out)}, nil
 	}
 
@@ -87,8 +93,9 @@ func (s *Service) registerSearchTool(srv *mcp.Server) {
 // --- insert ---
 
 type insertReq struct {
-	IDs     []string    `json:"ids"`
-	Vectors [][]float32 `json:"vectors"`
+	IDs      []string            `json:"ids"`
+	Vectors  [][]float32         `json:"vectors"`
+	Metadata []map[string]string `json:"metadata,omitempty"`
 }
 
 func (s *Service) registerInsertTool(srv *mcp.Server) {
@@ -106,6 +113,11 @@ func (s *Service) registerInsertTool(srv *mcp.Server) {
 				"items":       map[string]any{"type": "array", "items": map[string]any{"type": "number"}},
 				"description": "Vectors to insert",
 			},
+			"metadata": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+				"description": "Optional string attributes, one object per ID (used by search filters)",
+			},
 		}, []string{"ids", "vectors"}),
 	}
 
@@ -120,7 +132,7 @@ func (s *Service) registerInsertTool(srv *mcp.Server) {
 				ids[i] = b
 			}
 		}
-		if err := s.Index.Insert(r.Vectors, ids); err != nil {
+		if err := s.insertWithMetadata(r.Vectors, ids, r.Metadata); err != nil {
 			return nil, err
 		}
 		return map[string]any{"inserted": len(r.Vectors), "count": s.Index.Count()}, nil
@@ -195,22 +207,18 @@ func (s *Service) registerSimilarTool(srv *mcp.Server) {
 			return nil, err
 		}
 
-		results, err := s.Index.Search(vec, topK+1)
+		results, err := s.Search(vec, topK+1, "")
 		if err != nil {
 			return nil, err
 		}
 
 		// Filter out the source ID.
 		out := make([]map[string]any, 0, topK)
-		for _, res := range results {
-			resHex := hex.EncodeToString(res.ID)
-			if resHex == r.ID {
+		for _, res := range resultsJSON(results) {
+			if res["id"] == r.ID {
 				continue
 			}
-			out = append(out, map[string]any{
-				"id":    resHex,
-				"score": res.Score,
-			})
+			out = append(out, res)
 			if len(out) >= topK {
 				break
 			}
@@ -0,0 +1,163 @@
+// CLAUDE:SUMMARY Distance metric selection — index metric persisted in vecbridge_meta, per-search metric validated, scores converted from horosvec L2.
+package vecbridge
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/hazyhaar/horosvec"
+)
+
+// Metric is a vector distance metric.
+type Metric string
+
+// Supported metrics. horosvec always ranks by squared L2 distance; a
+// cosine index stores unit-length vectors so that L2 order is cosine order.
+const (
+	MetricL2     Metric = "l2"     // squared L2 distance, lower = closer
+	MetricCosine Metric = "cosine" // cosine similarity, higher = closer
+	MetricDot    Metric = "dot"    // dot product, higher = closer
+)
+
+// ErrMetricMismatch is returned when a search or a config asks for a metric
+// the index was not built with.
+var ErrMetricMismatch = errors.New("vecbridge: metric mismatch")
+
+// ParseMetric validates a metric name. "" is returned as is (index default).
+func ParseMetric(s string) (Metric, error) {
+	switch m := Metric(s); m {
+	case "", MetricL2, MetricCosine, MetricDot:
+		return m, nil
+	default:
+		return "", fmt.Errorf("vecbridge: unknown metric %q (valid: l2, cosine, dot)", s)
+	}
+}
+
+// SearchResult is one search hit.
+type SearchResult struct {
+	ID       []byte  `json:"id"`
+	Score    float64 `json:"score"`     // in the metric's unit
+	RawScore float64 `json:"raw_score"` // horosvec squared L2 distance
+	Metric   Metric  `json:"metric"`
+}
+
+const metaSchema = `CREATE TABLE IF NOT EXISTS vecbridge_meta (
+    key   TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+)`
+
+// initMetric reconciles the requested index metric with the one recorded
+// in the database. An index with vectors but no recorded metric predates
+// metric selection and is l2.
+func (s *Service) initMetric(want Metric) error {
+	if _, err := s.db.Exec(metaSchema); err != nil {
+		return fmt.Errorf("vecbridge: init meta: %w", err)
+	}
+	if want == MetricDot {
+		return fmt.Errorf("%w: horosvec ranks by L2, a dot index is not supported (use cosine with unit vectors)", ErrMetricMismatch)
+	}
+
+	var stored string
+	err := s.db.QueryRow(`SELECT value FROM vecbridge_meta WHERE key = 'metric'`).Scan(&stored)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if s.Index.Count() > 0 {
+			stored = string(MetricL2)
+		}
+	case err != nil:
+		return fmt.Errorf("vecbridge: read metric: %w", err)
+	}
+
+	switch {
+	case stored == "" && want == "":
+		s.metric = MetricL2 // empty index, nothing to record yet
+		return nil
+	case stored == "":
+		s.metric = want
+	case want == "" || Metric(stored) == want:
+		s.metric = Metric(stored)
+	default:
+		return fmt.Errorf("%w: index was built with %s, config asks %s", ErrMetricMismatch, stored, want)
+	}
+
+	_, err = s.db.Exec(`INSERT OR IGNORE INTO vecbridge_meta (key, value) VALUES ('metric', ?)`, string(s.metric))
+	return err
+}
+
+// Metric returns the metric the index was built with.
+func (s *Service) Metric() Metric { return s.metric }
+
+// Search returns the topK nearest neighbours of query under metric ("" =
+// the index metric). An l2 index only serves l2; a cosine index serves
+// cosine, dot (equal on unit vectors) and l2.
+func (s *Service) Search(query []float32, topK int, metric Metric) ([]SearchResult, error) {
+	return s.SearchFiltered(query, topK, metric, nil)
+}
+
+// searchQuery validates metric against the index metric and returns the
+// query as horosvec must receive it.
+func (s *Service) searchQuery(query []float32, metric Metric) ([]float32, Metric, error) {
+	if metric == "" {
+		metric = s.metric
+	}
+	if s.metric == MetricL2 && metric != MetricL2 {
+		return nil, "", fmt.Errorf("%w: index was built with l2, cannot search with %s", ErrMetricMismatch, metric)
+	}
+	if s.metric == MetricCosine {
+		query = normalize(query)
+	}
+	return query, metric, nil
+}
+
+func scoreResults(results []horosvec.Result, metric Metric) []SearchResult {
+	out := make([]SearchResult, len(results))
+	for i, r := range results {
+		out[i] = SearchResult{ID: r.ID, RawScore: r.Score}
+	}
+	return scoreSearchResults(out, metric)
+}
+
+// scoreSearchResults sets Score and Metric from RawScore.
+func scoreSearchResults(results []SearchResult, metric Metric) []SearchResult {
+	for i := range results {
+		r := &results[i]
+		r.Score, r.Metric = r.RawScore, metric
+		if metric != MetricL2 {
+			// Unit vectors: |a-b|² = 2 - 2·a·b.
+			r.Score = 1 - r.RawScore/2
+		}
+	}
+	return results
+}
+
+// prepareVectors returns vecs as they must be stored: unit length for a
+// cosine index, unchanged otherwise.
+func (s *Service) prepareVectors(vecs [][]float32) [][]float32 {
+	if s.metric != MetricCosine {
+		return vecs
+	}
+	out := make([][]float32, len(vecs))
+	for i, v := range vecs {
+		out[i] = normalize(v)
+	}
+	return out
+}
+
+// normalize returns a unit-length copy of v (v itself if zero).
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	inv := 1 / math.Sqrt(sum)
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(float64(x) * inv)
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package vecbridge
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/hazyhaar/horosvec"
+	"github.com/hazyhaar/pkg/dbopen"
+)
+
+func TestMetric_IncompatibleSearch(t *testing.T) {
+	// WHAT: Searching an l2 index with cosine or dot fails with ErrMetricMismatch; l2 works.
+	// WHY: horosvec scores are L2 distances; reading them as similarities would silently invert rankings.
+	db := dbopen.OpenMemory(t)
+	svc, err := NewFromDB(db, horosvec.DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vecs, _ := buildTestIndex(t, svc, 8, 50)
+
+	for _, m := range []Metric{MetricCosine, MetricDot} {
+		if _, err := svc.Search(vecs[0], 5, m); !errors.Is(err, ErrMetricMismatch) {
+			t.Errorf("search %s on l2 index: err = %v, want ErrMetricMismatch", m, err)
+		}
+	}
+	res, err := svc.Search(vecs[0], 5, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) == 0 || res[0].Metric != MetricL2 || res[0].Score != res[0].RawScore {
+		t.Errorf("default search: %+v", res)
+	}
+
+	// Reopening a populated l2 index as cosine is refused.
+	if _, err := NewFromDB(db, horosvec.DefaultConfig(), nil, WithMetric(MetricCosine)); !errors.Is(err, ErrMetricMismatch) {
+		t.Errorf("reopen as cosine: err = %v, want ErrMetricMismatch", err)
+	}
+}
+
+func TestMetric_CosineIndex(t *testing.T) {
+	// WHAT: A cosine index stores unit vectors and reports similarities (1 = same direction).
+	// WHY: Callers compare scores across queries; cosine must not depend on vector length.
+	db := dbopen.OpenMemory(t)
+	svc, err := NewFromDB(db, horosvec.DefaultConfig(), nil, WithMetric(MetricCosine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vecs := make([][]float32, 50)
+	ids := make([][]byte, len(vecs))
+	for i := range vecs {
+		vecs[i] = make([]float32, 8)
+		vecs[i][i%8] = float32(i + 1)
+		vecs[i][(i+3)%8] = 1
+		ids[i] = []byte{byte(i)}
+	}
+	vecs[0] = []float32{3, 4, 0, 0, 0, 0, 0, 0}
+	if err := svc.Index.Build(context.Background(), &sliceIter{vecs: svc.prepareVectors(vecs), ids: ids}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := svc.Search([]float32{6, 8, 0, 0, 0, 0, 0, 0}, 1, MetricCosine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].ID[0] != 0 || math.Abs(res[0].Score-1) > 1e-4 {
+		t.Errorf("cosine search: %+v, want id 0 with similarity 1", res)
+	}
+	if res[0].Metric != MetricCosine || math.Abs(res[0].RawScore) > 1e-4 {
+		t.Errorf("metric %s raw %f", res[0].Metric, res[0].RawScore)
+	}
+
+	// dot is served by a cosine index; l2 raw distances too.
+	if _, err := svc.Search(vecs[1], 3, MetricDot); err != nil {
+		t.Errorf("dot on cosine index: %v", err)
+	}
+	if _, err := svc.Search(vecs[1], 3, MetricL2); err != nil {
+		t.Errorf("l2 on cosine index: %v", err)
+	}
+}
@@ -37,6 +37,15 @@ type Config struct {
 	// CacheSize sets PRAGMA cache_size for SQLite. Default: -512000 (512 MB).
 	CacheSize int `json:"cache_size" yaml:"cache_size"`
 
+	// Metric is the index distance metric: "l2" or "cosine". Empty keeps the
+	// metric recorded in the database (l2 for a new index). A mismatch with
+	// the recorded metric is an error.
+	Metric Metric `json:"metric" yaml:"metric"`
+
+	// FilterStrategy applies metadata filters: "post" (default) or "pre".
+	// See FilterStrategy for the recall tradeoff.
+	FilterStrategy FilterStrategy `json:"filter_strategy" yaml:"filter_strategy"`
+
 	// Logger for debug/error messages.
 	Logger *slog.Logger `json:"-" yaml:"-"`
 }
@@ -55,11 +64,35 @@ type Service struct {
 	Index  *horosvec.Index
 	db     *sql.DB
 	logger *slog.Logger
+	metric Metric
+	filter FilterStrategy
+}
+
+// Option configures a Service created by NewFromDB.
+type Option func(*options)
+
+type options struct {
+	metric Metric
+	filter FilterStrategy
+}
+
+// WithMetric sets the index metric (see Config.Metric).
+func WithMetric(m Metric) Option {
+	return func(o *options) { o.metric = m }
+}
+
+// WithFilterStrategy sets how metadata filters are applied (see Config.FilterStrategy).
+func WithFilterStrategy(f FilterStrategy) Option {
+	return func(o *options) { o.filter = f }
 }
 
 // New opens the SQLite database and creates or loads a horosvec Index.
 func New(cfg Config) (*Service, error) {
 	cfg.defaults()
+	filter, err := ParseFilterStrategy(string(cfg.FilterStrategy))
+	if err != nil {
+		return nil, err
+	}
 
 	db, err := dbopen.Open(cfg.DBPath,
 		dbopen.WithMkdirAll(),
@@ -75,27 +108,56 @@ func New(cfg Config) (*Service, error) {
 		return nil, err
 	}
 
-	return &Service{
+	svc := &Service{
 		Index:  idx,
 		db:     db,
 		logger: cfg.Logger,
-	}, nil
+		filter: filter,
+	}
+	if err := svc.init(cfg.Metric); err != nil {
+		idx.Close()
+		db.Close()
+		return nil, err
+	}
+	return svc, nil
 }
 
 // NewFromDB creates a Service from an existing *sql.DB (e.g. shared with another component).
-func NewFromDB(db *sql.DB, cfg horosvec.Config, logger *slog.Logger) (*Service, error) {
+func NewFromDB(db *sql.DB, cfg horosvec.Config, logger *slog.Logger, opts ...Option) (*Service, error) {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	var o options
+	for _, fn := range opts {
+		fn(&o)
+	}
+	filter, err := ParseFilterStrategy(string(o.filter))
+	if err != nil {
+		return nil, err
+	}
 	idx, err := horosvec.New(db, cfg)
 	if err != nil {
 		return nil, err
 	}
-	return &Service{
+	svc := &Service{
 		Index:  idx,
 		db:     db,
 		logger: logger,
-	}, nil
+		filter: filter,
+	}
+	if err := svc.init(o.metric); err != nil {
+		idx.Close()
+		return nil, err
+	}
+	return svc, nil
+}
+
+// init creates the vecbridge tables and reconciles the index metric.
+func (s *Service) init(metric Metric) error {
+	if err := s.initMetadata(); err != nil {
+		return err
+	}
+	return s.initMetric(metric)
 }
 
 // Close closes the horosvec index. If the DB was opened by New, it is also closed.
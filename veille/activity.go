@@ -0,0 +1,41 @@
+// CLAUDE:SUMMARY Dossier activity feed — source mutations, fetches, new extractions and question runs as typed events in recording order with an opaque since cursor for polling.
+package veille
+
+import (
+	"context"
+	"fmt"
+)
+
+// activityCursorTag marks a since cursor as an activity sequence position;
+// cursors without it (older, timestamp-keyed) are rejected.
+const activityCursorTag = "seq"
+
+// ActivityFeed returns up to limit events of the dossier in the order they
+// were recorded (default 50, max 200). since is "" for the most recent
+// events, or the nextSince of the previous call to get only what was recorded
+// after it; nextSince is since itself when nothing new happened. Event
+// timestamps (At) follow that order only roughly: a fetch or extraction is
+// stamped when it starts but recorded when it commits.
+func (svc *Service) ActivityFeed(ctx context.Context, dossierID, since string, limit int) ([]*ActivityEvent, string, error) {
+	limit = pageLimit(limit)
+	afterSeq, tag, err := decodeCursor(since)
+	if err != nil {
+		return nil, "", err
+	}
+	if since != "" && tag != activityCursorTag {
+		return nil, "", fmt.Errorf("%w: bad cursor", ErrInvalidInput)
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, "", err
+	}
+	events, err := st.ListActivity(ctx, limit, afterSeq)
+	if err != nil {
+		return nil, "", err
+	}
+	next := since
+	if len(events) > 0 {
+		next = encodeCursor(events[len(events)-1].Seq, activityCursorTag)
+	}
+	return events, next, nil
+}
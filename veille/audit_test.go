@@ -52,6 +52,26 @@ func TestAudit_SourceDelete(t *testing.T) {
 	}
 }
 
+func TestAudit_RecordsActor(t *testing.T) {
+	// WHAT: An audited mutation records the ctx actor (WithActor) as user_id and the dossier in its parameters.
+	// WHY: Audit answers "who changed what"; the dossier alone does not say who acted.
+	svc, auditDB := setupAuditService(t)
+	ctx := WithActor(context.Background(), "u-alice")
+
+	src := &Source{Name: "Actor", URL: "https://example.com", SourceType: "web", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	svc.audit.Close()
+
+	var userID, dossierID string
+	auditDB.QueryRow(`SELECT user_id, json_extract(parameters, '$.dossier_id') FROM audit_log WHERE action = 'add_source'`).
+		Scan(&userID, &dossierID)
+	if userID != "u-alice" || dossierID != "d1" {
+		t.Errorf("audit entry: user_id %q, dossier_id %q; want u-alice, d1", userID, dossierID)
+	}
+}
+
 func TestAudit_NoAudit_NoError(t *testing.T) {
 	// WHAT: Service without audit logger does not crash.
 	// WHY: Audit is optional — services without it should work fine.
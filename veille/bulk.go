@@ -0,0 +1,85 @@
+// CLAUDE:SUMMARY Bulk enable/disable of sources and tracked questions: one transaction per shard, returns matched count and unknown IDs.
+package veille
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxBulkIDs bounds the IDs of one bulk enable/disable call.
+const maxBulkIDs = 500
+
+// BulkEnableResult reports a bulk enable/disable: Updated items matched,
+// NotFound IDs matched nothing (or a soft-deleted source) and were skipped.
+type BulkEnableResult struct {
+	Updated  int      `json:"updated"`
+	NotFound []string `json:"not_found"`
+}
+
+// SetSourcesEnabled enables or disables the given sources in one
+// transaction. A disabled source is skipped from the next scheduler tick.
+func (svc *Service) SetSourcesEnabled(ctx context.Context, dossierID string, ids []string, enabled bool) (*BulkEnableResult, error) {
+	ids, err := bulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	n, missing, err := st.SetSourcesEnabled(ctx, ids, enabled)
+	if err != nil {
+		return nil, err
+	}
+	svc.auditBulkEnable(ctx, dossierID, "sources", n, enabled)
+	return &BulkEnableResult{Updated: n, NotFound: missing}, nil
+}
+
+// SetQuestionsEnabled enables or disables the given tracked questions and
+// their auto-sources in one transaction.
+func (svc *Service) SetQuestionsEnabled(ctx context.Context, dossierID string, ids []string, enabled bool) (*BulkEnableResult, error) {
+	ids, err := bulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	n, missing, err := st.SetQuestionsEnabled(ctx, ids, enabled)
+	if err != nil {
+		return nil, err
+	}
+	svc.auditBulkEnable(ctx, dossierID, "questions", n, enabled)
+	return &BulkEnableResult{Updated: n, NotFound: missing}, nil
+}
+
+// bulkIDs validates and deduplicates the IDs of a bulk call, keeping order.
+func bulkIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: ids is required", ErrInvalidInput)
+	}
+	if len(ids) > maxBulkIDs {
+		return nil, fmt.Errorf("%w: at most %d ids", ErrInvalidInput, maxBulkIDs)
+	}
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("%w: empty id", ErrInvalidInput)
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+func (svc *Service) auditBulkEnable(ctx context.Context, dossierID, kind string, n int, enabled bool) {
+	action := "bulk_disable_" + kind
+	if enabled {
+		action = "bulk_enable_" + kind
+	}
+	svc.auditLog(ctx, action, fmt.Sprintf(`{"dossier_id":%q,"updated":%d}`, dossierID, n))
+}
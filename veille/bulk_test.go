@@ -0,0 +1,75 @@
+package veille
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestSetSourcesEnabled_DueSources(t *testing.T) {
+	// WHAT: Disabling three sources in bulk drops them from DueSources; re-enabling brings them back; unknown IDs are reported.
+	// WHY: A bulk disable must take effect on the next scheduler tick, which reads DueSources.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	st := store.NewStore(db)
+	for _, id := range []string{"s1", "s2", "s3", "s4"} {
+		if err := st.InsertSource(ctx, &store.Source{ID: id, Name: id, URL: "https://" + id + ".example", Enabled: true}); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+	dueIDs := func() map[string]bool {
+		due, err := st.DueSources(ctx, 10)
+		if err != nil {
+			t.Fatalf("due sources: %v", err)
+		}
+		ids := map[string]bool{}
+		for _, s := range due {
+			ids[s.ID] = true
+		}
+		return ids
+	}
+
+	res, err := svc.SetSourcesEnabled(ctx, "d1", []string{"s1", "s2", "s3", "s2", "nope"}, false)
+	if err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if res.Updated != 3 || len(res.NotFound) != 1 || res.NotFound[0] != "nope" {
+		t.Errorf("disable result: got %+v", res)
+	}
+	if due := dueIDs(); len(due) != 1 || !due["s4"] {
+		t.Errorf("due after disable: got %v, want only s4", due)
+	}
+
+	if _, err := svc.SetSourcesEnabled(ctx, "d1", []string{"s1", "s2", "s3"}, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if due := dueIDs(); len(due) != 4 {
+		t.Errorf("due after enable: got %v, want all 4", due)
+	}
+
+	if _, err := svc.SetSourcesEnabled(ctx, "d1", nil, false); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("empty ids: got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSetQuestionsEnabled_SyncsAutoSource(t *testing.T) {
+	// WHAT: Bulk-disabling a question also disables its auto-source.
+	// WHY: The scheduler runs questions through their auto-source; leaving it enabled would keep the question running.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	st := store.NewStore(db)
+	st.InsertQuestion(ctx, &store.TrackedQuestion{ID: "q1", Text: "Q", Enabled: true})
+	st.InsertSource(ctx, &store.Source{ID: "q1", Name: "Q", URL: "question://q1", SourceType: "question", Enabled: true})
+
+	res, err := svc.SetQuestionsEnabled(ctx, "d1", []string{"q1"}, false)
+	if err != nil || res.Updated != 1 {
+		t.Fatalf("disable: %+v, %v", res, err)
+	}
+	q, _ := st.GetQuestion(ctx, "q1")
+	src, _ := st.GetSource(ctx, "q1")
+	if q.Enabled || src.Enabled {
+		t.Errorf("enabled after disable: question %v, source %v", q.Enabled, src.Enabled)
+	}
+}
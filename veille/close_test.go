@@ -0,0 +1,102 @@
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// newSlowFetchService returns a started service whose single web source is
+// served slowly; entered is closed when the scheduled fetch reaches the server.
+func newSlowFetchService(t *testing.T, delay, shutdown time.Duration) (svc *Service, db *sql.DB, entered chan struct{}) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.Exec("PRAGMA foreign_keys=ON")
+	if err := store.ApplySchema(db); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+	catalogDB := openCatalogDB(t)
+	insertShard(t, catalogDB, "d1", "active")
+
+	entered = make(chan struct{})
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		time.Sleep(delay)
+		w.Write([]byte(`<html><head><title>Slow</title></head><body><main><p>A slow page that takes its time to answer every request.</p></main></body></html>`))
+	}))
+	t.Cleanup(site.Close)
+
+	noSSRF := func(string) error { return nil } // httptest listens on loopback
+	cfg := &Config{
+		Fetch:           fetch.Config{URLValidator: noSSRF},
+		BufferDir:       filepath.Join(t.TempDir(), "pending"),
+		ShutdownTimeout: shutdown,
+	}
+	svc, err = New(&testPool{db: db}, cfg, nil, WithURLValidator(noSSRF), WithCatalogDB(catalogDB))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	if err := svc.AddSource(context.Background(), "d1", &Source{Name: "Slow", URL: site.URL, SourceType: "web", Enabled: true}); err != nil {
+		t.Fatalf("add source: %v", err)
+	}
+	svc.Start(context.Background())
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled fetch never started")
+	}
+	return svc, db, entered
+}
+
+func TestClose_WaitsForInFlightFetch(t *testing.T) {
+	// WHAT: Close called during a slow scheduled fetch returns only after the extraction and its buffer file are written; later fetches get ErrClosed.
+	// WHY: Shutdown must not cut a fetch between the HTTP response and the store/buffer writes.
+	svc, db, _ := newSlowFetchService(t, 300*time.Millisecond, 5*time.Second)
+
+	if err := svc.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	var n int
+	db.QueryRow(`SELECT COUNT(*) FROM extractions`).Scan(&n)
+	if n != 1 {
+		t.Errorf("extractions after close: got %d, want 1 (fetch abandoned)", n)
+	}
+	files, _ := os.ReadDir(svc.config.BufferDir)
+	if len(files) != 1 || filepath.Ext(files[0].Name()) != ".md" {
+		t.Errorf("buffer after close: got %v, want one .md file", files)
+	}
+
+	srcs, _ := svc.ListSources(context.Background(), "d1")
+	if err := svc.FetchNow(context.Background(), "d1", srcs[0].ID); !errors.Is(err, ErrClosed) {
+		t.Errorf("fetch after close: got %v, want ErrClosed", err)
+	}
+}
+
+func TestClose_TimesOut(t *testing.T) {
+	// WHAT: Close returns an error when an in-flight fetch outlasts ShutdownTimeout.
+	// WHY: A hung source must not block process shutdown forever, and the caller must know jobs were cut.
+	svc, _, _ := newSlowFetchService(t, 500*time.Millisecond, 50*time.Millisecond)
+
+	start := time.Now()
+	err := svc.Close()
+	if err == nil {
+		t.Fatal("close: want a drain timeout error")
+	}
+	if d := time.Since(start); d > 400*time.Millisecond {
+		t.Errorf("close took %s, want about the 50ms timeout", d)
+	}
+	svc.jobs.Wait() // let the fetch finish before the shard closes
+}
@@ -4,10 +4,37 @@ package veille
 import (
 	"time"
 
+	"github.com/hazyhaar/chrc/veille/internal/crawl"
 	fetchpkg "github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/pipeline"
 	"github.com/hazyhaar/chrc/veille/internal/scheduler"
 )
 
+// SchedulerPolicy selects how each scheduler tick's capacity
+// (Config.Scheduler.MaxJobsPerTick) is shared between dossiers.
+type SchedulerPolicy = scheduler.Policy
+
+// Scheduler policies.
+const (
+	SchedulerFair = scheduler.PolicyFair // round-robin across dossiers (default)
+	SchedulerFIFO = scheduler.PolicyFIFO // longest-waiting sources first
+)
+
+// SchedulerStatus reports the scheduler's effective concurrency
+// (Config.Scheduler.Workers, MaxPerDossier) and the jobs running now.
+type SchedulerStatus = scheduler.Status
+
+// SourceTypePolicy selects what happens when the first response of a web,
+// rss or sitemap source looks like another type (Config.SourceTypeMismatch).
+type SourceTypePolicy = pipeline.TypeMismatchPolicy
+
+// Source type mismatch policies.
+const (
+	SourceTypeWarn    = pipeline.TypeMismatchWarn    // log, process as declared (default)
+	SourceTypeCorrect = pipeline.TypeMismatchCorrect // store the sniffed type, process as such
+	SourceTypeReject  = pipeline.TypeMismatchReject  // fetch error "type_mismatch", nothing stored
+)
+
 // Config configures the veille service.
 type Config struct {
 	// Fetch settings
@@ -26,14 +53,86 @@ type Config struct {
 	// SweepInterval is how often the sweeper probes broken sources.
 	// Default: 6 hours.
 	SweepInterval time.Duration
+
+	// SweepMaxBackoff caps the per-source wait between probes of a source
+	// that keeps failing (SweepInterval, doubled per failed probe).
+	// Default: 7 days.
+	SweepMaxBackoff time.Duration
+
+	// SourceRetention is how long a soft-deleted source is kept (restorable)
+	// before it is purged with its extractions. Default: 30 days.
+	SourceRetention time.Duration
+
+	// PurgeInterval is how often soft-deleted sources past retention are
+	// purged and dossier retention policies are enforced. Default: 24 hours.
+	PurgeInterval time.Duration
+
+	// PruneBatchSize is how many extractions a retention prune deletes per
+	// statement. Default: 500.
+	PruneBatchSize int
+
+	// DigestInterval is how often due channel digests are checked and sent
+	// (see WithDigestSender). It bounds the latency of immediate digests.
+	// Default: 1 minute.
+	DigestInterval time.Duration
+
+	// ShutdownTimeout bounds how long Close waits for in-flight fetch jobs.
+	// Default: 30 seconds.
+	ShutdownTimeout time.Duration
+
+	// PostProcessTimeout bounds each PostProcessor call per extraction.
+	// Default: 5 seconds.
+	PostProcessTimeout time.Duration
+
+	// DedupContent stores extraction HTML and text once, in a refcounted
+	// content_blobs table of the catalog database shared by all dossiers;
+	// the text is still indexed in each shard's FTS. Requires WithCatalogDB.
+	// Default: false (bodies stay in each shard).
+	DedupContent bool
+
+	// RetainRawBodies keeps the raw response of web extractions so that
+	// Reprocess can re-run extraction without re-fetching. Default: false.
+	RetainRawBodies bool
+
+	// RawBodiesPerSource caps the raw bodies kept per source, newest first.
+	// Default: 3.
+	RawBodiesPerSource int
+
+	// FollowMaxPages caps the linked pages fetched by one job of a web
+	// source with follow_depth, or one run of a question with follow_depth.
+	// Default: 20.
+	FollowMaxPages int
+
+	// FollowMaxPerHost caps the linked pages fetched per host by one such
+	// job. Default: 5.
+	FollowMaxPerHost int
+
+	// SourceTypeMismatch applies when the first response of a source
+	// (feed, sitemap, HTML or JSON, sniffed from body and Content-Type)
+	// does not match its declared type. Default: SourceTypeWarn.
+	SourceTypeMismatch SourceTypePolicy
+
+	// HealthWebhookURL receives a POST when a source goes from ok to broken
+	// or recovers, for dossiers without their own webhook
+	// (SetHealthWebhook). Empty = no global webhook.
+	HealthWebhookURL string
+
+	// HealthWebhookSecret signs global webhook bodies (HealthSignatureHeader).
+	// Empty = unsigned.
+	HealthWebhookSecret string
+
+	// HealthNotifyCooldown is the minimum delay between two health
+	// notifications of the same source; changes within it are coalesced.
+	// Default: 1 hour.
+	HealthNotifyCooldown time.Duration
 }
 
 func (c *Config) defaults() {
 	if c.Fetch.Timeout <= 0 {
 		c.Fetch.Timeout = 30 * time.Second
 	}
-	if c.Fetch.MaxBytes <= 0 {
-		c.Fetch.MaxBytes = 10 * 1024 * 1024
+	if c.Fetch.MaxResponseBytes <= 0 {
+		c.Fetch.MaxResponseBytes = 10 * 1024 * 1024
 	}
 	if c.Fetch.UserAgent == "" {
 		c.Fetch.UserAgent = "chrc-veille/1.0"
@@ -47,19 +146,70 @@ func (c *Config) defaults() {
 	if c.DataDir == "" {
 		c.DataDir = "data"
 	}
+	if c.SourceRetention <= 0 {
+		c.SourceRetention = 30 * 24 * time.Hour
+	}
+	if c.PurgeInterval <= 0 {
+		c.PurgeInterval = 24 * time.Hour
+	}
+	if c.PruneBatchSize <= 0 {
+		c.PruneBatchSize = 500
+	}
+	if c.PostProcessTimeout <= 0 {
+		c.PostProcessTimeout = 5 * time.Second
+	}
+	if c.DigestInterval <= 0 {
+		c.DigestInterval = time.Minute
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+	if c.RawBodiesPerSource <= 0 {
+		c.RawBodiesPerSource = 3
+	}
+	if c.FollowMaxPages <= 0 {
+		c.FollowMaxPages = 20
+	}
+	if c.FollowMaxPerHost <= 0 {
+		c.FollowMaxPerHost = 5
+	}
+	if c.SourceTypeMismatch == "" {
+		c.SourceTypeMismatch = SourceTypeWarn
+	}
+	if c.HealthNotifyCooldown <= 0 {
+		c.HealthNotifyCooldown = time.Hour
+	}
+}
+
+// followLimits returns the link following caps of a job.
+func (c *Config) followLimits() crawl.Limits {
+	return crawl.Limits{MaxPages: c.FollowMaxPages, MaxPerHost: c.FollowMaxPerHost}
 }
 
 func defaultConfig() *Config {
 	return &Config{
 		Fetch: fetchpkg.Config{
-			Timeout:   30 * time.Second,
-			MaxBytes:  10 * 1024 * 1024,
-			UserAgent: "chrc-veille/1.0",
+			Timeout:          30 * time.Second,
+			MaxResponseBytes: 10 * 1024 * 1024,
+			UserAgent:        "chrc-veille/1.0",
 		},
 		Scheduler: scheduler.Config{
 			CheckInterval: time.Minute,
 			MaxFailCount:  10,
 		},
-		DataDir: "data",
+		DataDir:         "data",
+		SourceRetention: 30 * 24 * time.Hour,
+		PurgeInterval:   24 * time.Hour,
+		PruneBatchSize:  500,
+		DigestInterval:  time.Minute,
+		ShutdownTimeout: 30 * time.Second,
+
+		PostProcessTimeout: 5 * time.Second,
+		RawBodiesPerSource: 3,
+		FollowMaxPages:     20,
+		FollowMaxPerHost:   5,
+		SourceTypeMismatch: SourceTypeWarn,
+
+		HealthNotifyCooldown: time.Hour,
 	}
 }
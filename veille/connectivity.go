@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Registers 15 connectivity.Router handlers for veille CRUD operations.
+// CLAUDE:SUMMARY Registers 16 connectivity.Router handlers for veille CRUD operations; tools with a shared request type (requests.go) go through callTool.
 package veille
 
 import (
@@ -17,6 +17,7 @@ func (svc *Service) RegisterConnectivity(router *connectivity.Router) {
 	router.RegisterLocal("veille_delete_source", svc.handleDeleteSource)
 	router.RegisterLocal("veille_fetch_now", svc.handleFetchNow)
 	router.RegisterLocal("veille_search", svc.handleSearchConn)
+	router.RegisterLocal("veille_search_all", svc.handleSearchAll)
 	router.RegisterLocal("veille_list_extractions", svc.handleListExtractions)
 	router.RegisterLocal("veille_stats", svc.handleStats)
 	router.RegisterLocal("veille_fetch_history", svc.handleFetchHistory)
@@ -29,51 +30,24 @@ func (svc *Service) RegisterConnectivity(router *connectivity.Router) {
 }
 
 func (svc *Service) handleAddSource(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID string `json:"dossier_id"`
-		Name      string `json:"name"`
-		URL       string `json:"url"`
-		Type      string `json:"source_type"`
-		Interval  int64  `json:"fetch_interval"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	src := &Source{
-		Name:          req.Name,
-		URL:           req.URL,
-		SourceType:    req.Type,
-		FetchInterval: req.Interval,
-		Enabled:       true,
-	}
-	if err := svc.AddSource(ctx, req.DossierID, src); err != nil {
-		return nil, err
-	}
-	return json.Marshal(src)
+	return svc.callTool(ctx, payload, &addSourceRequest{})
 }
 
 func (svc *Service) handleListSources(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID string `json:"dossier_id"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	sources, err := svc.ListSources(ctx, req.DossierID)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(sources)
+	return svc.callTool(ctx, payload, &listSourcesRequest{})
 }
 
 func (svc *Service) handleUpdateSource(ctx context.Context, payload []byte) ([]byte, error) {
 	var req struct {
-		DossierID string `json:"dossier_id"`
-		SourceID  string `json:"source_id"`
-		Name      string `json:"name"`
-		URL       string `json:"url"`
-		Enabled   *bool  `json:"enabled"`
-		Interval  int64  `json:"fetch_interval"`
+		DossierID string            `json:"dossier_id"`
+		SourceID  string            `json:"source_id"`
+		Name      string            `json:"name"`
+		URL       string            `json:"url"`
+		Enabled   *bool             `json:"enabled"`
+		Interval  int64             `json:"fetch_interval"`
+		Tags      []string          `json:"tags"`
+		Headers   map[string]string `json:"headers"`
+		BasicAuth *BasicAuth        `json:"basic_auth"`
 	}
 	if err := json.Unmarshal(payload, &req); err != nil {
 		return nil, fmt.Errorf("decode: %w", err)
@@ -83,6 +57,9 @@ func (svc *Service) handleUpdateSource(ctx context.Context, payload []byte) ([]b
 		Name:          req.Name,
 		URL:           req.URL,
 		FetchInterval: req.Interval,
+		Tags:          req.Tags,
+		Headers:       req.Headers,
+		BasicAuth:     req.BasicAuth,
 	}
 	if req.Enabled != nil {
 		src.Enabled = *req.Enabled
@@ -122,89 +99,55 @@ func (svc *Service) handleFetchNow(ctx context.Context, payload []byte) ([]byte,
 }
 
 func (svc *Service) handleSearchConn(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID string `json:"dossier_id"`
-		Query     string `json:"query"`
-		Limit     int    `json:"limit"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	results, err := svc.Search(ctx, req.DossierID, req.Query, req.Limit)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(results)
+	return svc.callTool(ctx, payload, &searchRequest{})
+}
+
+func (svc *Service) handleSearchAll(ctx context.Context, payload []byte) ([]byte, error) {
+	return svc.callTool(ctx, payload, &searchAllRequest{})
 }
 
 func (svc *Service) handleListExtractions(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID string `json:"dossier_id"`
-		SourceID  string `json:"source_id"`
-		Limit     int    `json:"limit"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	exts, err := svc.ListExtractions(ctx, req.DossierID, req.SourceID, req.Limit)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(exts)
+	return svc.callTool(ctx, payload, &listExtractionsRequest{})
 }
 
+// handleStats returns SpaceStats for a dossier. The response is the same JSON
+// as HTTP GET /api/dossiers/{dossierID}/stats, for non-MCP dashboard callers.
 func (svc *Service) handleStats(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID string `json:"dossier_id"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	stats, err := svc.Stats(ctx, req.DossierID)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(stats)
+	return svc.callTool(ctx, payload, &statsRequest{})
 }
 
 func (svc *Service) handleFetchHistory(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID string `json:"dossier_id"`
-		SourceID  string `json:"source_id"`
-		Limit     int    `json:"limit"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	history, err := svc.FetchHistory(ctx, req.DossierID, req.SourceID, req.Limit)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(history)
+	return svc.callTool(ctx, payload, &fetchHistoryRequest{})
 }
 
 // --- Questions ---
 
 func (svc *Service) handleAddQuestion(ctx context.Context, payload []byte) ([]byte, error) {
 	var req struct {
-		DossierID   string `json:"dossier_id"`
-		Text        string `json:"text"`
-		Keywords    string `json:"keywords"`
-		Channels    string `json:"channels"`
-		ScheduleMs  int64  `json:"schedule_ms"`
-		MaxResults  int    `json:"max_results"`
-		FollowLinks *bool  `json:"follow_links"`
+		DossierID        string `json:"dossier_id"`
+		Text             string `json:"text"`
+		Keywords         string `json:"keywords"`
+		Channels         string `json:"channels"`
+		ScheduleMs       int64  `json:"schedule_ms"`
+		MaxResults       int    `json:"max_results"`
+		FollowLinks      *bool  `json:"follow_links"`
+		FollowDepth      int    `json:"follow_depth"`
+		NotifyMinResults int    `json:"notify_min_results"`
+		NotifyPattern    string `json:"notify_pattern"`
 	}
 	if err := json.Unmarshal(payload, &req); err != nil {
 		return nil, fmt.Errorf("decode: %w", err)
 	}
 	q := &TrackedQuestion{
-		Text:       req.Text,
-		Keywords:   req.Keywords,
-		Channels:   req.Channels,
-		ScheduleMs: req.ScheduleMs,
-		MaxResults: req.MaxResults,
-		Enabled:    true,
+		Text:             req.Text,
+		Keywords:         req.Keywords,
+		Channels:         req.Channels,
+		ScheduleMs:       req.ScheduleMs,
+		MaxResults:       req.MaxResults,
+		FollowDepth:      req.FollowDepth,
+		NotifyMinResults: req.NotifyMinResults,
+		NotifyPattern:    req.NotifyPattern,
+		Enabled:          true,
 	}
 	if req.FollowLinks != nil {
 		q.FollowLinks = *req.FollowLinks
@@ -233,26 +176,32 @@ func (svc *Service) handleListQuestions(ctx context.Context, payload []byte) ([]
 
 func (svc *Service) handleUpdateQuestion(ctx context.Context, payload []byte) ([]byte, error) {
 	var req struct {
-		DossierID   string `json:"dossier_id"`
-		QuestionID  string `json:"question_id"`
-		Text        string `json:"text"`
-		Keywords    string `json:"keywords"`
-		Channels    string `json:"channels"`
-		ScheduleMs  int64  `json:"schedule_ms"`
-		MaxResults  int    `json:"max_results"`
-		FollowLinks *bool  `json:"follow_links"`
-		Enabled     *bool  `json:"enabled"`
+		DossierID        string `json:"dossier_id"`
+		QuestionID       string `json:"question_id"`
+		Text             string `json:"text"`
+		Keywords         string `json:"keywords"`
+		Channels         string `json:"channels"`
+		ScheduleMs       int64  `json:"schedule_ms"`
+		MaxResults       int    `json:"max_results"`
+		FollowLinks      *bool  `json:"follow_links"`
+		FollowDepth      int    `json:"follow_depth"`
+		NotifyMinResults int    `json:"notify_min_results"`
+		NotifyPattern    string `json:"notify_pattern"`
+		Enabled          *bool  `json:"enabled"`
 	}
 	if err := json.Unmarshal(payload, &req); err != nil {
 		return nil, fmt.Errorf("decode: %w", err)
 	}
 	q := &TrackedQuestion{
-		ID:         req.QuestionID,
-		Text:       req.Text,
-		Keywords:   req.Keywords,
-		Channels:   req.Channels,
-		ScheduleMs: req.ScheduleMs,
-		MaxResults: req.MaxResults,
+		ID:               req.QuestionID,
+		Text:             req.Text,
+		Keywords:         req.Keywords,
+		Channels:         req.Channels,
+		ScheduleMs:       req.ScheduleMs,
+		MaxResults:       req.MaxResults,
+		FollowDepth:      req.FollowDepth,
+		NotifyMinResults: req.NotifyMinResults,
+		NotifyPattern:    req.NotifyPattern,
 	}
 	if req.FollowLinks != nil {
 		q.FollowLinks = *req.FollowLinks
@@ -281,32 +230,9 @@ func (svc *Service) handleDeleteQuestion(ctx context.Context, payload []byte) ([
 }
 
 func (svc *Service) handleRunQuestion(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID  string `json:"dossier_id"`
-		QuestionID string `json:"question_id"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	count, err := svc.RunQuestionNow(ctx, req.DossierID, req.QuestionID)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(map[string]any{"status": "ok", "new_results": count})
+	return svc.callTool(ctx, payload, &runQuestionRequest{})
 }
 
 func (svc *Service) handleQuestionResults(ctx context.Context, payload []byte) ([]byte, error) {
-	var req struct {
-		DossierID  string `json:"dossier_id"`
-		QuestionID string `json:"question_id"`
-		Limit      int    `json:"limit"`
-	}
-	if err := json.Unmarshal(payload, &req); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
-	}
-	results, err := svc.QuestionResults(ctx, req.DossierID, req.QuestionID, req.Limit)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(results)
+	return svc.callTool(ctx, payload, &questionResultsRequest{})
 }
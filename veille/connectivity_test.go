@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/hazyhaar/chrc/veille/internal/store"
+	"github.com/hazyhaar/pkg/connectivity"
 
 	_ "modernc.org/sqlite"
 )
@@ -136,10 +140,94 @@ func TestConnectivity_FetchHistory(t *testing.T) {
 	resp := callConn(t, svc.handleFetchHistory, map[string]any{
 		"dossier_id": "d1", "source_id": "src-fh", "limit": 10,
 	})
-	var history []*FetchLogEntry
-	json.Unmarshal(resp, &history)
-	if len(history) != 1 {
-		t.Fatalf("count: got %d, want 1", len(history))
+	var page struct {
+		Entries    []*FetchLogEntry `json:"entries"`
+		NextCursor string           `json:"next_cursor"`
+	}
+	json.Unmarshal(resp, &page)
+	if len(page.Entries) != 1 {
+		t.Fatalf("count: got %d, want 1", len(page.Entries))
+	}
+	if page.NextCursor != "" {
+		t.Errorf("next_cursor: got %q, want empty on the last page", page.NextCursor)
+	}
+}
+
+func TestConnectivity_ListExtractionsCursor(t *testing.T) {
+	// WHAT: Two successive cursor calls return disjoint pages in (extracted_at, id) descending order, even when a newer extraction lands between them.
+	// WHY: Agents page large sources through the MCP list tools; a shifting offset would skip or repeat rows.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+
+	st := store.NewStore(db)
+	st.InsertSource(ctx, &store.Source{ID: "src-pg", Name: "PG", URL: "https://pg.com", Enabled: true})
+	// ext-2 and ext-3 share a timestamp so the page boundary falls inside a tie.
+	for i, at := range []int64{now - 4000, now - 3000, now - 2000, now - 2000, now - 1000} {
+		id := fmt.Sprintf("ext-%d", i)
+		if err := st.InsertExtraction(ctx, &store.Extraction{ID: id, SourceID: "src-pg", ContentHash: id, URL: "https://pg.com/" + id, ExtractedAt: at}); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+
+	type page struct {
+		Extractions []*Extraction `json:"extractions"`
+		NextCursor  string        `json:"next_cursor"`
+	}
+	var first, second page
+	json.Unmarshal(callConn(t, svc.handleListExtractions, map[string]any{
+		"dossier_id": "d1", "source_id": "src-pg", "limit": 2,
+	}), &first)
+	if len(first.Extractions) != 2 || first.NextCursor == "" {
+		t.Fatalf("first page: got %d items, cursor %q", len(first.Extractions), first.NextCursor)
+	}
+	if strings.Contains(first.NextCursor, ":") {
+		t.Errorf("cursor should be opaque, got %q", first.NextCursor)
+	}
+
+	st.InsertExtraction(ctx, &store.Extraction{ID: "ext-new", SourceID: "src-pg", ContentHash: "new", URL: "https://pg.com/new", ExtractedAt: now})
+
+	json.Unmarshal(callConn(t, svc.handleListExtractions, map[string]any{
+		"dossier_id": "d1", "source_id": "src-pg", "limit": 2, "cursor": first.NextCursor,
+	}), &second)
+
+	var got []string
+	for _, e := range append(first.Extractions, second.Extractions...) {
+		got = append(got, e.ID)
+	}
+	want := []string{"ext-4", "ext-3", "ext-2", "ext-1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("pages: got %v, want %v", got, want)
+	}
+
+	if _, err := svc.handleListExtractions(ctx, []byte(`{"dossier_id":"d1","source_id":"src-pg","cursor":"not-a-cursor"}`)); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("bad cursor: got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestConnectivity_ListExtractionsLimitClamped(t *testing.T) {
+	// WHAT: A limit above the 200 maximum is capped to 200, not reset to the default of 50.
+	// WHY: An agent asking for a big page must get the largest allowed one, not fewer rows than the default-sized ask would suggest.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+
+	st := store.NewStore(db)
+	st.InsertSource(ctx, &store.Source{ID: "src-big", Name: "Big", URL: "https://big.com", Enabled: true})
+	for i := range maxPageSize + 10 {
+		id := fmt.Sprintf("ext-%03d", i)
+		st.InsertExtraction(ctx, &store.Extraction{ID: id, SourceID: "src-big", ContentHash: id, URL: "https://big.com/" + id, ExtractedAt: now - int64(i)})
+	}
+
+	var page struct {
+		Extractions []*Extraction `json:"extractions"`
+		NextCursor  string        `json:"next_cursor"`
+	}
+	json.Unmarshal(callConn(t, svc.handleListExtractions, map[string]any{
+		"dossier_id": "d1", "source_id": "src-big", "limit": 1000,
+	}), &page)
+	if len(page.Extractions) != maxPageSize || page.NextCursor == "" {
+		t.Errorf("limit 1000: got %d items, cursor %q, want %d and a next page", len(page.Extractions), page.NextCursor, maxPageSize)
 	}
 }
 
@@ -164,3 +252,69 @@ func TestConnectivity_DeleteSource(t *testing.T) {
 		t.Errorf("after delete: got %d sources, want 0", len(after))
 	}
 }
+
+func TestConnectivity_StatsViaRouter(t *testing.T) {
+	// WHAT: veille_stats called through a connectivity.Router returns the dossier counts.
+	// WHY: Cross-service dashboards call it via the router, not the MCP tool; shape must match HTTP /stats.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+
+	st := store.NewStore(db)
+	st.InsertSource(ctx, &store.Source{ID: "src-r1", Name: "R1", URL: "https://r1.com", Enabled: true})
+	st.InsertSource(ctx, &store.Source{ID: "src-r2", Name: "R2", URL: "https://r2.com", Enabled: true})
+	st.InsertExtraction(ctx, &store.Extraction{ID: "ext-r1", SourceID: "src-r1", ContentHash: "h1", ExtractedText: "a", URL: "https://r1.com", ExtractedAt: now})
+
+	router := connectivity.New()
+	svc.RegisterConnectivity(router)
+
+	payload, _ := json.Marshal(map[string]string{"dossier_id": "d1"})
+	resp, err := router.Call(ctx, "veille_stats", payload)
+	if err != nil {
+		t.Fatalf("router call: %v", err)
+	}
+	var stats SpaceStats
+	if err := json.Unmarshal(resp, &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if stats.Sources != 2 {
+		t.Errorf("sources: got %d, want 2", stats.Sources)
+	}
+	if stats.Extractions != 1 {
+		t.Errorf("extractions: got %d, want 1", stats.Extractions)
+	}
+
+	// Same JSON as the HTTP handler, which encodes svc.Stats directly.
+	direct, _ := svc.Stats(ctx, "d1")
+	want, _ := json.Marshal(direct)
+	if string(resp) != string(want) {
+		t.Errorf("shape mismatch:\n router: %s\n http:   %s", resp, want)
+	}
+}
+
+func TestConnectivity_RequiredFields(t *testing.T) {
+	// WHAT: Shared tool requests reject payloads missing a field the MCP schema marks required.
+	// WHY: Connectivity and MCP decode through the same request types; validation must not drift.
+	svc, _ := setupTestService(t)
+
+	cases := []struct {
+		name    string
+		handler func(context.Context, []byte) ([]byte, error)
+		payload string
+	}{
+		{"add_source", svc.handleAddSource, `{"dossier_id":"d1","name":"No URL"}`},
+		{"list_sources", svc.handleListSources, `{}`},
+		{"search", svc.handleSearchConn, `{"dossier_id":"d1"}`},
+		{"stats", svc.handleStats, `{}`},
+		{"run_question", svc.handleRunQuestion, `{"dossier_id":"d1"}`},
+		{"list_extractions", svc.handleListExtractions, `{}`},
+		{"fetch_history", svc.handleFetchHistory, `{"dossier_id":"d1"}`},
+		{"question_results", svc.handleQuestionResults, `{"dossier_id":"d1"}`},
+		{"search_all", svc.handleSearchAll, `{}`},
+	}
+	for _, c := range cases {
+		if _, err := c.handler(context.Background(), []byte(c.payload)); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: got %v, want ErrInvalidInput", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package veille
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestDedupContent_RequiresCatalog(t *testing.T) {
+	// WHAT: DedupContent without a catalog database is rejected at construction.
+	// WHY: The shared blob table lives in the catalog; silently storing inline would hide a misconfiguration.
+	_, err := New(mapPool{}, &Config{DedupContent: true}, nil)
+	if err == nil {
+		t.Fatal("expected error without WithCatalogDB")
+	}
+}
+
+func TestDedupContent_HydrateAndPurge(t *testing.T) {
+	// WHAT: With DedupContent, ListExtractions and Search return the shared body, RebuildFTS keeps it searchable, and purging a source releases its references.
+	// WHY: Readers must not see the dedup, and deletions must not leak blobs.
+	ctx := context.Background()
+	catalogDB := openCatalogDB(t)
+	pool := mapPool{"d1": openShardDB(t), "d2": openShardDB(t)}
+	for _, db := range pool {
+		if err := ApplySchema(db); err != nil {
+			t.Fatalf("apply schema: %v", err)
+		}
+	}
+	svc, err := New(pool, &Config{DedupContent: true, SourceRetention: time.Millisecond}, nil, WithCatalogDB(catalogDB))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	const html = "<p>Shared wire story about data residency.</p>"
+	const text = "Shared wire story about data residency."
+	var ref, textRef string
+	for _, id := range []string{"d1", "d2"} {
+		st := store.NewStore(pool[id])
+		src := &Source{Name: "Wire", URL: "https://wire.example/story", SourceType: "web", Enabled: true}
+		if err := svc.AddSource(ctx, id, src); err != nil {
+			t.Fatalf("%s: add source: %v", id, err)
+		}
+		e := &Extraction{
+			ID: "ext-" + id, SourceID: src.ID, ContentHash: "h", Title: "Story",
+			ExtractedText: text, ExtractedHTML: html,
+			URL: src.URL, ExtractedAt: time.Now().UnixMilli(),
+		}
+		if err := st.InsertExtractionDedup(ctx, svc.content, e); err != nil {
+			t.Fatalf("%s: insert: %v", id, err)
+		}
+		ref, textRef = e.ContentRef, e.TextRef
+
+		exts, err := svc.ListExtractions(ctx, id, src.ID, 10)
+		if err != nil || len(exts) != 1 {
+			t.Fatalf("%s: list: %v (%d)", id, err, len(exts))
+		}
+		if exts[0].ExtractedHTML != html || exts[0].ExtractedText != text {
+			t.Errorf("%s: hydrated: got html=%q text=%q", id, exts[0].ExtractedHTML, exts[0].ExtractedText)
+		}
+		if err := svc.RebuildFTS(ctx, id); err != nil {
+			t.Fatalf("%s: rebuild fts: %v", id, err)
+		}
+		res, err := svc.Search(ctx, id, "residency", 10)
+		if err != nil || len(res) != 1 || res[0].Text != text {
+			t.Errorf("%s: search after rebuild: %+v %v", id, res, err)
+		}
+	}
+	if n, _ := svc.content.RefCount(ctx, ref); n != 2 {
+		t.Fatalf("refcount: got %d, want 2", n)
+	}
+
+	// Soft-delete then purge the source in d1.
+	srcs, _ := svc.ListSources(ctx, "d1")
+	if err := svc.DeleteSource(ctx, "d1", srcs[0].ID); err != nil {
+		t.Fatalf("delete source: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if n, err := svc.PurgeDeletedSources(ctx, "d1"); err != nil || n != 1 {
+		t.Fatalf("purge: n=%d err=%v", n, err)
+	}
+	if n, _ := svc.content.RefCount(ctx, ref); n != 1 {
+		t.Errorf("refcount after purge: got %d, want 1", n)
+	}
+
+	// Dropping d2 releases the last reference and collects the blob.
+	refs, err := svc.DossierContentRefs(ctx, "d2")
+	if err != nil {
+		t.Fatalf("dossier refs: %v", err)
+	}
+	if err := svc.ReleaseContent(ctx, refs); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	for _, r := range []string{ref, textRef} {
+		if body, _ := svc.content.Body(ctx, r); body != "" {
+			t.Error("blob should be collected once unreferenced")
+		}
+	}
+}
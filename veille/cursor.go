@@ -0,0 +1,45 @@
+// CLAUDE:SUMMARY Opaque keyset cursors (base64url of "timestamp:id") shared by the HTTP dossier feed and the paginated MCP/connectivity list tools.
+package veille
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxPageSize bounds one page of a cursor-paginated listing.
+const maxPageSize = 200
+
+// pageLimit returns limit capped at maxPageSize, or 50 when it is unset.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return 50
+	}
+	return min(limit, maxPageSize)
+}
+
+// encodeCursor returns the cursor positioned after the row (at, id). Rows are
+// ordered by (at, id) descending, so rows inserted later with a newer
+// timestamp never shift the pages that follow.
+func encodeCursor(at int64, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(at, 10) + ":" + id))
+}
+
+// decodeCursor parses a cursor from encodeCursor. "" is the first page
+// (at = 0); anything else that does not decode is ErrInvalidInput.
+func decodeCursor(cursor string) (int64, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: bad cursor", ErrInvalidInput)
+	}
+	at, id, ok := strings.Cut(string(raw), ":")
+	n, err := strconv.ParseInt(at, 10, 64)
+	if !ok || err != nil || n <= 0 || id == "" {
+		return 0, "", fmt.Errorf("%w: bad cursor", ErrInvalidInput)
+	}
+	return n, id, nil
+}
@@ -0,0 +1,283 @@
+// CLAUDE:SUMMARY Channel digests: per-(dossier, channel) insertion-sequence watermark, immediate or batched summaries of new extractions pushed through a DigestSender.
+package veille
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// Digest is a channel subscription to a dossier's new extractions.
+type Digest = store.Digest
+
+// DigestMode selects how new extractions are pushed to a channel.
+type DigestMode string
+
+// Digest modes.
+const (
+	// DigestImmediate sends one message per new extraction, at the next digest tick.
+	DigestImmediate DigestMode = "immediate"
+	// DigestBatched sends one summary of all new extractions per interval.
+	DigestBatched DigestMode = "batched"
+)
+
+const (
+	defaultDigestInterval = 24 * time.Hour
+	minDigestInterval     = time.Minute
+	defaultDigestMaxItems = 20
+	maxDigestMaxItems     = 200
+	maxDigestChannelLen   = 128
+	// digestScanLimit bounds the extractions read per digest run; the rest
+	// is picked up by the next run.
+	digestScanLimit = 1000
+)
+
+// DigestOpts configures a digest.
+type DigestOpts struct {
+	Mode     DigestMode    // default DigestBatched
+	Interval time.Duration // batched: minimum time between sends (default 24h, min 1m)
+	MaxItems int           // batched: extractions listed per message (default 20, max 200)
+}
+
+// DigestMessage is one digest delivery: a formatted summary plus the
+// extractions it covers.
+type DigestMessage struct {
+	DossierID   string               `json:"dossier_id"`
+	Channel     string               `json:"channel"`
+	Text        string               `json:"text"`
+	Extractions []*DossierExtraction `json:"extractions"`
+	Total       int                  `json:"total"` // new extractions covered, may exceed len(Extractions)
+}
+
+// DigestSender delivers a digest message to msg.Channel, typically through
+// a channels Dispatcher. An error leaves the watermark unchanged so the
+// extractions are retried on the next tick.
+type DigestSender func(ctx context.Context, msg DigestMessage) error
+
+// WithDigestSender enables channel digests: RegisterDigest subscriptions
+// are delivered through send, and Start runs the digest loop.
+func WithDigestSender(send DigestSender) ServiceOption {
+	return func(svc *Service) { svc.digestSender = send }
+}
+
+// RegisterDigest subscribes channel to the dossier's new extractions, or
+// updates the settings of an existing subscription. A new digest starts
+// after the newest extraction inserted at registration time; an existing
+// one keeps its watermark.
+func (svc *Service) RegisterDigest(ctx context.Context, dossierID, channel string, opts DigestOpts) error {
+	if err := validateDigest(channel, &opts); err != nil {
+		return err
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	lastSeq, err := st.LatestExtractionSeq(ctx)
+	if err != nil {
+		return fmt.Errorf("digest watermark: %w", err)
+	}
+	d := &store.Digest{
+		Channel:    channel,
+		Mode:       string(opts.Mode),
+		IntervalMs: opts.Interval.Milliseconds(),
+		MaxItems:   opts.MaxItems,
+		LastSeq:    lastSeq,
+	}
+	if err := st.UpsertDigest(ctx, d); err != nil {
+		return fmt.Errorf("register digest: %w", err)
+	}
+	svc.auditLog(ctx, "register_digest", fmt.Sprintf(`{"dossier_id":%q,"channel":%q,"mode":%q}`, dossierID, channel, opts.Mode))
+	return nil
+}
+
+// UnregisterDigest removes the digest of channel in a dossier.
+func (svc *Service) UnregisterDigest(ctx context.Context, dossierID, channel string) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	if err := st.DeleteDigest(ctx, channel); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "unregister_digest", fmt.Sprintf(`{"dossier_id":%q,"channel":%q}`, dossierID, channel))
+	return nil
+}
+
+// ListDigests returns the digests registered in a dossier.
+func (svc *Service) ListDigests(ctx context.Context, dossierID string) ([]*Digest, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	return st.ListDigests(ctx)
+}
+
+// RunDigests delivers the due digests of a dossier and returns the number
+// of messages sent. Batched digests are due once their interval has
+// elapsed since the last send; immediate digests are always due.
+// It is a no-op without WithDigestSender.
+func (svc *Service) RunDigests(ctx context.Context, dossierID string) (int, error) {
+	if svc.digestSender == nil {
+		return 0, nil
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return 0, err
+	}
+	digests, err := st.ListDigests(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var sent int
+	for _, d := range digests {
+		n, err := svc.runDigest(ctx, st, dossierID, d, time.Now())
+		sent += n
+		if err != nil {
+			svc.logger.Warn("digest: send failed", "dossier_id", dossierID, "channel", d.Channel, "error", err)
+		}
+	}
+	return sent, nil
+}
+
+// runDigest sends what is due for d and advances its watermark past every
+// extraction delivered.
+func (svc *Service) runDigest(ctx context.Context, st *store.Store, dossierID string, d *store.Digest, now time.Time) (int, error) {
+	if DigestMode(d.Mode) == DigestBatched && d.LastSentAt != nil &&
+		now.UnixMilli()-*d.LastSentAt < d.IntervalMs {
+		return 0, nil
+	}
+	items, err := st.ListExtractionsAfter(ctx, d.LastSeq, digestScanLimit)
+	if err != nil || len(items) == 0 {
+		return 0, err
+	}
+	exts := make([]*Extraction, len(items))
+	for i, item := range items {
+		exts[i] = &item.Extraction
+	}
+	if err := store.HydrateContent(ctx, svc.content, exts); err != nil {
+		return 0, err
+	}
+
+	if DigestMode(d.Mode) == DigestImmediate {
+		var sent int
+		for _, item := range items {
+			e := item.DossierExtraction
+			msg := DigestMessage{
+				DossierID:   dossierID,
+				Channel:     d.Channel,
+				Text:        formatDigest([]*DossierExtraction{e}, 1),
+				Extractions: []*DossierExtraction{e},
+				Total:       1,
+			}
+			if err := svc.digestSender(ctx, msg); err != nil {
+				return sent, err
+			}
+			sent++
+			if err := st.AdvanceDigest(ctx, d.Channel, item.Seq, now.UnixMilli()); err != nil {
+				return sent, fmt.Errorf("advance digest: %w", err)
+			}
+		}
+		return sent, nil
+	}
+
+	listed := make([]*DossierExtraction, 0, len(items))
+	for _, item := range items {
+		if d.MaxItems > 0 && len(listed) == d.MaxItems {
+			break
+		}
+		listed = append(listed, item.DossierExtraction)
+	}
+	msg := DigestMessage{
+		DossierID:   dossierID,
+		Channel:     d.Channel,
+		Text:        formatDigest(listed, len(items)),
+		Extractions: listed,
+		Total:       len(items),
+	}
+	if err := svc.digestSender(ctx, msg); err != nil {
+		return 0, err
+	}
+	if err := st.AdvanceDigest(ctx, d.Channel, items[len(items)-1].Seq, now.UnixMilli()); err != nil {
+		return 1, fmt.Errorf("advance digest: %w", err)
+	}
+	return 1, nil
+}
+
+// formatDigest renders listed extractions as a plain-text summary of total
+// new extractions.
+func formatDigest(listed []*DossierExtraction, total int) string {
+	var b strings.Builder
+	if total == 1 {
+		b.WriteString("1 new extraction\n")
+	} else {
+		fmt.Fprintf(&b, "%d new extractions\n", total)
+	}
+	for _, e := range listed {
+		title := e.Title
+		if title == "" {
+			title = e.URL
+		}
+		fmt.Fprintf(&b, "\n- %s (%s)\n  %s", title, e.SourceName, e.URL)
+	}
+	if more := total - len(listed); more > 0 {
+		fmt.Fprintf(&b, "\n\n… and %d more", more)
+	}
+	return b.String()
+}
+
+// validateDigest checks channel and applies DigestOpts defaults.
+func validateDigest(channel string, opts *DigestOpts) error {
+	if channel == "" {
+		return fmt.Errorf("%w: channel is required", ErrInvalidInput)
+	}
+	if len(channel) > maxDigestChannelLen {
+		return fmt.Errorf("%w: channel exceeds %d characters", ErrInvalidInput, maxDigestChannelLen)
+	}
+	switch opts.Mode {
+	case "":
+		opts.Mode = DigestBatched
+	case DigestBatched, DigestImmediate:
+	default:
+		return fmt.Errorf("%w: unknown digest mode %q", ErrInvalidInput, opts.Mode)
+	}
+	if opts.Interval == 0 {
+		opts.Interval = defaultDigestInterval
+	}
+	if opts.Mode == DigestBatched && opts.Interval < minDigestInterval {
+		return fmt.Errorf("%w: digest interval must be at least %s", ErrInvalidInput, minDigestInterval)
+	}
+	if opts.MaxItems == 0 {
+		opts.MaxItems = defaultDigestMaxItems
+	}
+	if opts.MaxItems < 0 || opts.MaxItems > maxDigestMaxItems {
+		return fmt.Errorf("%w: max_items must be between 1 and %d", ErrInvalidInput, maxDigestMaxItems)
+	}
+	return nil
+}
+
+// runDigester delivers due digests of every active dossier on each
+// Config.DigestInterval tick. Blocks until ctx is cancelled.
+func (svc *Service) runDigester(ctx context.Context) {
+	ticker := time.NewTicker(svc.config.DigestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dossierIDs, err := svc.listActiveShards(ctx)
+			if err != nil {
+				svc.logger.Warn("digest: list shards", "error", err)
+				continue
+			}
+			for _, dossierID := range dossierIDs {
+				if _, err := svc.RunDigests(ctx, dossierID); err != nil {
+					svc.logger.Warn("digest: run", "dossier_id", dossierID, "error", err)
+				}
+			}
+		}
+	}
+}
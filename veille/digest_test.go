@@ -0,0 +1,205 @@
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+
+	_ "modernc.org/sqlite"
+)
+
+// stubChannel records digest messages, failing while fail is set.
+type stubChannel struct {
+	msgs []DigestMessage
+	fail bool
+}
+
+func (c *stubChannel) send(_ context.Context, msg DigestMessage) error {
+	if c.fail {
+		return errors.New("channel down")
+	}
+	c.msgs = append(c.msgs, msg)
+	return nil
+}
+
+func setupDigestService(t *testing.T) (*Service, *sql.DB, *stubChannel) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db.Exec("PRAGMA foreign_keys=ON")
+	if err = store.ApplySchema(db); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ch := &stubChannel{}
+	svc, err := New(&testPool{db: db}, nil, nil, WithDigestSender(ch.send))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	return svc, db, ch
+}
+
+// digestSeq keeps test extraction times strictly increasing across calls.
+var digestSeq int64
+
+func insertDigestExtractions(t *testing.T, db *sql.DB, sourceID string, n int) {
+	t.Helper()
+	st := store.NewStore(db)
+	for range n {
+		digestSeq++
+		id := fmt.Sprintf("ext-%04d", digestSeq)
+		if err := st.InsertExtraction(context.Background(), &store.Extraction{
+			ID: id, SourceID: sourceID, ContentHash: id, Title: "Result " + id,
+			ExtractedText: "text", URL: "https://news.example.com/" + id, ExtractedAt: 1_700_000_000_000 + digestSeq,
+		}); err != nil {
+			t.Fatalf("insert extraction: %v", err)
+		}
+	}
+}
+
+func TestDigest_BatchedSendsNewExtractions(t *testing.T) {
+	// WHAT: New extractions since registration are sent once as a batched summary.
+	// WHY: Channels must learn about new results without re-receiving old ones.
+	svc, db, ch := setupDigestService(t)
+	ctx := context.Background()
+
+	src := &Source{Name: "News", URL: "https://news.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add source: %v", err)
+	}
+	insertDigestExtractions(t, db, src.ID, 2) // before registration: not sent
+
+	if err := svc.RegisterDigest(ctx, "d1", "ops", DigestOpts{Interval: time.Hour, MaxItems: 2}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if n, _ := svc.RunDigests(ctx, "d1"); n != 0 {
+		t.Fatalf("sent %d messages with nothing new", n)
+	}
+
+	insertDigestExtractions(t, db, src.ID, 3)
+	n, err := svc.RunDigests(ctx, "d1")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if n != 1 || len(ch.msgs) != 1 {
+		t.Fatalf("messages: got %d, want 1", len(ch.msgs))
+	}
+	msg := ch.msgs[0]
+	if msg.Channel != "ops" || msg.DossierID != "d1" || msg.Total != 3 || len(msg.Extractions) != 2 {
+		t.Errorf("message: channel=%q total=%d listed=%d", msg.Channel, msg.Total, len(msg.Extractions))
+	}
+	if !strings.HasPrefix(msg.Text, "3 new extractions") || !strings.Contains(msg.Text, "and 1 more") {
+		t.Errorf("text: %q", msg.Text)
+	}
+
+	// Within the interval nothing is sent, even with new extractions.
+	insertDigestExtractions(t, db, src.ID, 1)
+	if n, _ := svc.RunDigests(ctx, "d1"); n != 0 {
+		t.Errorf("sent %d messages before interval elapsed", n)
+	}
+}
+
+func TestDigest_ImmediateOnePerResult(t *testing.T) {
+	// WHAT: Immediate digests send one message per new extraction, retrying after a failure.
+	// WHY: A down channel must not lose results, nor receive duplicates once back.
+	svc, db, ch := setupDigestService(t)
+	ctx := context.Background()
+
+	src := &Source{Name: "News", URL: "https://news.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add source: %v", err)
+	}
+	if err := svc.RegisterDigest(ctx, "d1", "alerts", DigestOpts{Mode: DigestImmediate}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	insertDigestExtractions(t, db, src.ID, 2)
+	ch.fail = true
+	svc.RunDigests(ctx, "d1")
+	ch.fail = false
+
+	if n, _ := svc.RunDigests(ctx, "d1"); n != 2 {
+		t.Fatalf("sent: got %d, want 2", n)
+	}
+	if len(ch.msgs) != 2 || ch.msgs[0].Total != 1 {
+		t.Errorf("messages: got %d", len(ch.msgs))
+	}
+	if n, _ := svc.RunDigests(ctx, "d1"); n != 0 {
+		t.Errorf("resent %d messages", n)
+	}
+}
+
+func TestDigest_LateCommitWithOlderTimestamp(t *testing.T) {
+	// WHAT: An extraction inserted after a send but stamped before the last sent one is still delivered.
+	// WHY: FlushBuffer replays old extracted_at values and parallel workers commit out of order.
+	svc, db, ch := setupDigestService(t)
+	ctx := context.Background()
+
+	src := &Source{Name: "News", URL: "https://news.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add source: %v", err)
+	}
+	if err := svc.RegisterDigest(ctx, "d1", "alerts", DigestOpts{Mode: DigestImmediate}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	insertDigestExtractions(t, db, src.ID, 1)
+	if n, _ := svc.RunDigests(ctx, "d1"); n != 1 {
+		t.Fatalf("first run: sent %d, want 1", n)
+	}
+
+	if err := store.NewStore(db).InsertExtraction(ctx, &store.Extraction{
+		ID: "late", SourceID: src.ID, ContentHash: "late", Title: "Late",
+		ExtractedText: "text", URL: "https://news.example.com/late", ExtractedAt: 1_000,
+	}); err != nil {
+		t.Fatalf("insert late extraction: %v", err)
+	}
+	if n, _ := svc.RunDigests(ctx, "d1"); n != 1 {
+		t.Fatalf("late run: sent %d, want 1", n)
+	}
+	if got := ch.msgs[len(ch.msgs)-1].Extractions[0].ID; got != "late" {
+		t.Errorf("late message: got %q", got)
+	}
+}
+
+func TestRegisterDigest_Validation(t *testing.T) {
+	// WHAT: Bad channel, mode, interval and max_items are rejected.
+	// WHY: A sub-minute batched interval would flood the channel.
+	svc, _, _ := setupDigestService(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		channel string
+		opts    DigestOpts
+	}{
+		{"", DigestOpts{}},
+		{"ops", DigestOpts{Mode: "hourly"}},
+		{"ops", DigestOpts{Interval: time.Second}},
+		{"ops", DigestOpts{MaxItems: 1000}},
+	} {
+		err := svc.RegisterDigest(ctx, "d1", tc.channel, tc.opts)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%q %+v: expected ErrInvalidInput, got %v", tc.channel, tc.opts, err)
+		}
+	}
+
+	if err := svc.RegisterDigest(ctx, "d1", "ops", DigestOpts{}); err != nil {
+		t.Fatalf("register defaults: %v", err)
+	}
+	digests, _ := svc.ListDigests(ctx, "d1")
+	if len(digests) != 1 || digests[0].Mode != "batched" || digests[0].IntervalMs != (24*time.Hour).Milliseconds() {
+		t.Errorf("digests: %+v", digests)
+	}
+	svc.UnregisterDigest(ctx, "d1", "ops")
+	if digests, _ := svc.ListDigests(ctx, "d1"); len(digests) != 0 {
+		t.Errorf("digest still listed after unregister")
+	}
+}
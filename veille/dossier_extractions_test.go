@@ -0,0 +1,60 @@
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestListDossierExtractions_MergedNewestFirst(t *testing.T) {
+	// WHAT: Extractions from two sources come back merged newest-first, annotated with their source, and page by cursor.
+	// WHY: The dossier "latest" view must not require a source ID or a search query.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	st := store.NewStore(db)
+
+	st.InsertSource(ctx, &store.Source{ID: "src-a", Name: "Alpha", URL: "https://a.example", SourceType: "web", Enabled: true})
+	st.InsertSource(ctx, &store.Source{ID: "src-b", Name: "Beta", URL: "https://b.example/feed", SourceType: "rss", Enabled: true})
+	// Interleaved timestamps: a1=1000, b1=2000, a2=3000, b2=4000, a3=5000.
+	for i, src := range []string{"src-a", "src-b", "src-a", "src-b", "src-a"} {
+		st.InsertExtraction(ctx, &store.Extraction{
+			ID: fmt.Sprintf("ext-%d", i), SourceID: src, ContentHash: fmt.Sprintf("h%d", i),
+			ExtractedText: "text", URL: "https://x.example", ExtractedAt: int64(1000 * (i + 1)),
+		})
+	}
+
+	page1, next, err := svc.ListDossierExtractions(ctx, "d1", 3, "")
+	if err != nil {
+		t.Fatalf("page 1: %v", err)
+	}
+	want := []string{"ext-4", "ext-3", "ext-2"}
+	for i, e := range page1 {
+		if e.ID != want[i] {
+			t.Errorf("page 1[%d]: got %s, want %s", i, e.ID, want[i])
+		}
+	}
+	if page1[1].SourceName != "Beta" || page1[1].SourceType != "rss" {
+		t.Errorf("annotation: got %q/%q, want Beta/rss", page1[1].SourceName, page1[1].SourceType)
+	}
+	if next == "" {
+		t.Fatal("full page should return a cursor")
+	}
+
+	page2, next, err := svc.ListDossierExtractions(ctx, "d1", 3, next)
+	if err != nil {
+		t.Fatalf("page 2: %v", err)
+	}
+	if len(page2) != 2 || page2[0].ID != "ext-1" || page2[1].ID != "ext-0" {
+		t.Errorf("page 2: got %d items", len(page2))
+	}
+	if next != "" {
+		t.Errorf("last page cursor: got %q, want empty", next)
+	}
+
+	if _, _, err := svc.ListDossierExtractions(ctx, "d1", 3, "garbage"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("bad cursor: got %v, want ErrInvalidInput", err)
+	}
+}
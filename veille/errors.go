@@ -1,13 +1,39 @@
-// CLAUDE:SUMMARY Sentinel errors for veille service: duplicate source, invalid input, quota exceeded.
+// CLAUDE:SUMMARY Sentinel errors for veille service: duplicate source, source not found, invalid input, quota exceeded, source type mismatch, egress host denied, open circuit, oversized response.
 package veille
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/pipeline"
+)
 
 // ErrDuplicateSource is returned when a source with the same URL already exists.
 var ErrDuplicateSource = errors.New("veille: source with this URL already exists")
 
+// ErrSourceNotFound is returned when a source ID is unknown in the dossier
+// (or, for RestoreSource, not soft-deleted).
+var ErrSourceNotFound = errors.New("veille: source not found")
+
 // ErrInvalidInput is returned when source input fails validation.
 var ErrInvalidInput = errors.New("veille: invalid input")
 
 // ErrQuotaExceeded is returned when a resource limit is reached.
 var ErrQuotaExceeded = errors.New("veille: quota exceeded")
+
+// ErrSourceTypeMismatch is returned by FetchNow when the first response of a
+// source does not match its type and Config.SourceTypeMismatch is
+// SourceTypeReject.
+var ErrSourceTypeMismatch = pipeline.ErrSourceTypeMismatch
+
+// ErrHostDenied is returned when a source URL's host is rejected by the
+// egress host lists (Config.Fetch.AllowHosts / DenyHosts).
+var ErrHostDenied = fetch.ErrHostDenied
+
+// ErrCircuitOpen is returned by FetchNow while the source host's circuit
+// breaker is open after repeated failures.
+var ErrCircuitOpen = fetch.ErrCircuitOpen
+
+// ErrBodyTooLarge is returned by FetchNow when the response exceeds the
+// source type's size limit.
+var ErrBodyTooLarge = fetch.ErrBodyTooLarge
@@ -0,0 +1,63 @@
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestWithExtractor_CustomSourceType(t *testing.T) {
+	// WHAT: WithExtractor under a new source type makes the type valid and extracts its pages.
+	// WHY: Third-party extractors plug in without editing the pipeline package.
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.Exec("PRAGMA foreign_keys=ON")
+	if err := store.ApplySchema(db); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("a,b\n1,2\n"))
+	}))
+	defer srv.Close()
+
+	var gotType string
+	csv := ExtractorFunc(func(_ context.Context, raw []byte, contentType string) (*Extraction, error) {
+		gotType = contentType
+		return &Extraction{ContentHash: "csv-1", Title: "CSV", ExtractedText: "rows: " + string(raw)}, nil
+	})
+	noSSRF := func(string) error { return nil } // httptest listens on loopback
+	cfg := &Config{Fetch: fetch.Config{URLValidator: noSSRF}}
+	svc, err := New(&testPool{db: db}, cfg, nil, WithURLValidator(noSSRF), WithExtractor("csv", csv))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	src := &Source{Name: "CSV", URL: srv.URL, SourceType: "csv", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add csv source: %v", err)
+	}
+	if err := svc.FetchNow(ctx, "d1", src.ID); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	exts, err := svc.ListExtractions(ctx, "d1", src.ID, 10)
+	if err != nil {
+		t.Fatalf("list extractions: %v", err)
+	}
+	if len(exts) != 1 || exts[0].Title != "CSV" {
+		t.Fatalf("extractions: got %+v", exts)
+	}
+	if gotType != "text/csv" {
+		t.Errorf("content type: got %q, want text/csv", gotType)
+	}
+}
@@ -0,0 +1,203 @@
+// CLAUDE:SUMMARY Buffer replay: re-inserts extractions from buffer .md files into their dossier shards (dedup by ID and content hash), skips rows retention pruned, removes consumed files and quarantines unusable ones.
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/buffer"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// ErrBufferDisabled is returned by FlushBuffer without Config.BufferDir.
+var ErrBufferDisabled = errors.New("veille: no buffer directory configured")
+
+// BufferQuarantineDir is the directory, next to Config.BufferDir, receiving
+// buffer files FlushBuffer cannot apply.
+const BufferQuarantineDir = "quarantine"
+
+// BufferFlushReport summarizes a FlushBuffer run.
+type BufferFlushReport struct {
+	Scanned     int      `json:"scanned"`
+	Applied     int      `json:"applied"`     // inserted into their shard, file removed
+	Present     int      `json:"present"`     // already in the shard, file removed
+	Skipped     int      `json:"skipped"`     // pruned by retention or older than its cutoff, file removed
+	Quarantined int      `json:"quarantined"` // moved to the quarantine directory
+	Failed      int      `json:"failed"`      // left in place, retried by the next flush
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// Outcomes of replaying one buffer file.
+const (
+	replayApplied = iota
+	replayPresent
+	replaySkipped
+)
+
+// errQuarantine marks a buffer file that can never be applied.
+type errQuarantine struct{ reason string }
+
+func (e errQuarantine) Error() string { return e.reason }
+
+// FlushBuffer replays the .md files of Config.BufferDir into their dossier
+// shards: a file whose extraction is missing (by ID, then by source and
+// content hash) is re-inserted, unless retention pruned that ID or the
+// extraction is older than the dossier's keep_days cutoff, and every
+// applied, already-present or skipped file is removed. Unparseable files and files whose dossier or source no longer
+// exists are moved to BufferQuarantineDir; files hitting a store error stay
+// for the next flush. The buffer is the RAG hand-off: run it only when no
+// consumer still needs the pending files.
+func (svc *Service) FlushBuffer(ctx context.Context) (*BufferFlushReport, error) {
+	dir := svc.config.BufferDir
+	if dir == "" {
+		return nil, ErrBufferDisabled
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return &BufferFlushReport{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read buffer: %w", err)
+	}
+
+	// Without a catalog every dossier is assumed to exist (pool.Resolve).
+	var active map[string]bool
+	if svc.catalogDB != nil {
+		ids, err := svc.listActiveShards(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list dossiers: %w", err)
+		}
+		active = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			active[id] = true
+		}
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names) // idgen IDs: oldest first
+
+	rep := &BufferFlushReport{}
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return rep, err
+		}
+		rep.Scanned++
+		path := filepath.Join(dir, name)
+		outcome, err := svc.replayBufferFile(ctx, path, active)
+		var q errQuarantine
+		switch {
+		case errors.As(err, &q):
+			if mvErr := quarantineBufferFile(dir, name); mvErr != nil {
+				rep.Failed++
+				rep.Errors = append(rep.Errors, fmt.Sprintf("%s: quarantine: %v", name, mvErr))
+				continue
+			}
+			rep.Quarantined++
+			rep.Errors = append(rep.Errors, fmt.Sprintf("%s: quarantined: %s", name, q.reason))
+			svc.logger.Warn("buffer flush: quarantined", "file", name, "reason", q.reason)
+			continue
+		case err != nil:
+			rep.Failed++
+			rep.Errors = append(rep.Errors, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			rep.Errors = append(rep.Errors, fmt.Sprintf("%s: remove: %v", name, err))
+		}
+		switch outcome {
+		case replayPresent:
+			rep.Present++
+		case replaySkipped:
+			rep.Skipped++
+		default:
+			rep.Applied++
+		}
+	}
+	svc.auditLog(ctx, "flush_buffer", fmt.Sprintf(`{"scanned":%d,"applied":%d,"present":%d,"skipped":%d,"quarantined":%d,"failed":%d}`,
+		rep.Scanned, rep.Applied, rep.Present, rep.Skipped, rep.Quarantined, rep.Failed))
+	return rep, nil
+}
+
+// replayBufferFile inserts the extraction of one buffer file unless already
+// present or deliberately pruned. active restricts the target dossiers when
+// non-nil.
+func (svc *Service) replayBufferFile(ctx context.Context, path string, active map[string]bool) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	meta, text, err := buffer.Parse(data)
+	if err != nil {
+		return 0, errQuarantine{err.Error()}
+	}
+	if meta.ID == "" || meta.DossierID == "" || meta.SourceID == "" || meta.ContentHash == "" {
+		return 0, errQuarantine{"missing id, dossier_id, source_id or content_hash"}
+	}
+	if active != nil && !active[meta.DossierID] {
+		return 0, errQuarantine{"dossier " + meta.DossierID + " no longer exists"}
+	}
+	st, err := svc.resolveStore(ctx, meta.DossierID)
+	if err != nil {
+		return 0, err
+	}
+	src, err := st.GetSource(ctx, meta.SourceID)
+	if err != nil {
+		return 0, err
+	}
+	if src == nil || src.DeletedAt != nil {
+		return 0, errQuarantine{"source " + meta.SourceID + " no longer exists"}
+	}
+
+	if e, err := st.GetExtraction(ctx, meta.ID); err != nil || e != nil {
+		return replayPresent, err
+	}
+	if dup, err := st.ExtractionExists(ctx, meta.SourceID, meta.ContentHash); err != nil || dup {
+		return replayPresent, err
+	}
+	// Retention already decided against this row: do not bring it back.
+	policy, err := st.GetRetentionPolicy(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if policy != nil && policy.KeepDays > 0 &&
+		meta.ExtractedAt.Before(time.Now().AddDate(0, 0, -policy.KeepDays)) {
+		return replaySkipped, nil
+	}
+	if pruned, err := st.IsPruned(ctx, meta.ID); err != nil || pruned {
+		return replaySkipped, err
+	}
+	e := &store.Extraction{
+		ID:            meta.ID,
+		SourceID:      meta.SourceID,
+		ContentHash:   meta.ContentHash,
+		Title:         meta.Title,
+		ExtractedText: text,
+		URL:           meta.SourceURL,
+		ExtractedAt:   meta.ExtractedAt.UnixMilli(),
+	}
+	if err := st.InsertExtractionDedup(ctx, svc.content, e); err != nil {
+		return 0, fmt.Errorf("insert extraction: %w", err)
+	}
+	return replayApplied, nil
+}
+
+// quarantineBufferFile moves name from the buffer dir to BufferQuarantineDir
+// next to it.
+func quarantineBufferFile(dir, name string) error {
+	qdir := filepath.Join(filepath.Dir(dir), BufferQuarantineDir)
+	if err := os.MkdirAll(qdir, 0o755); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(dir, name), filepath.Join(qdir, name))
+}
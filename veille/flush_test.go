@@ -0,0 +1,114 @@
+package veille
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/buffer"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestFlushBuffer_ReplaysAndQuarantines(t *testing.T) {
+	// WHAT: A buffered extraction missing from its shard is inserted and its file removed; an already-stored one is removed; junk and unknown-dossier files are quarantined.
+	// WHY: Replaying the buffer must restore lost rows without duplicating existing ones or looping on files that can never apply.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	catalogDB := openCatalogDB(t)
+	insertShard(t, catalogDB, "d1", "active")
+	svc.catalogDB = catalogDB
+	pending := filepath.Join(t.TempDir(), "buffer", "pending")
+	svc.config.BufferDir = pending
+
+	st := store.NewStore(db)
+	st.InsertSource(ctx, &store.Source{ID: "src-1", Name: "S", URL: "https://s.example", SourceType: "web", Enabled: true})
+	st.InsertExtraction(ctx, &store.Extraction{ID: "ext-kept", SourceID: "src-1", ContentHash: "h-kept",
+		ExtractedText: "already there", URL: "https://s.example/k", ExtractedAt: time.Now().UnixMilli()})
+
+	w := buffer.NewWriter(pending)
+	at := time.Date(2026, 5, 2, 9, 0, 0, 0, time.UTC)
+	w.Write(ctx, buffer.Metadata{ID: "ext-lost", SourceID: "src-1", DossierID: "d1", SourceURL: "https://s.example/l",
+		SourceType: "web", Title: "Lost: found", ContentHash: "h-lost", ExtractedAt: at}, "Recovered body")
+	w.Write(ctx, buffer.Metadata{ID: "ext-dup", SourceID: "src-1", DossierID: "d1", SourceURL: "https://s.example/k",
+		SourceType: "web", ContentHash: "h-kept", ExtractedAt: at}, "already there")
+	w.Write(ctx, buffer.Metadata{ID: "ext-gone", SourceID: "src-1", DossierID: "d-deleted", SourceURL: "https://s.example/g",
+		SourceType: "web", ContentHash: "h-gone", ExtractedAt: at}, "orphan")
+	os.WriteFile(filepath.Join(pending, "junk.md"), []byte("not a buffer file"), 0o644)
+
+	rep, err := svc.FlushBuffer(ctx)
+	if err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if rep.Scanned != 4 || rep.Applied != 1 || rep.Present != 1 || rep.Quarantined != 2 || rep.Failed != 0 {
+		t.Fatalf("report: %+v", rep)
+	}
+
+	e, _ := st.GetExtraction(ctx, "ext-lost")
+	if e == nil || e.Title != "Lost: found" || e.ExtractedText != "Recovered body" || e.ExtractedAt != at.UnixMilli() {
+		t.Fatalf("replayed extraction: %+v", e)
+	}
+	var n int
+	db.QueryRow(`SELECT COUNT(*) FROM extractions WHERE content_hash = 'h-kept'`).Scan(&n)
+	if n != 1 {
+		t.Errorf("duplicate inserted: %d rows for h-kept", n)
+	}
+	if left, _ := os.ReadDir(pending); len(left) != 0 {
+		t.Errorf("pending after flush: %v, want empty", left)
+	}
+	quarantined, _ := os.ReadDir(filepath.Join(filepath.Dir(pending), BufferQuarantineDir))
+	if len(quarantined) != 2 {
+		t.Errorf("quarantine: got %v, want junk.md and ext-gone.md", quarantined)
+	}
+}
+
+func TestFlushBuffer_SkipsPrunedAndExpired(t *testing.T) {
+	// WHAT: Buffered extractions that retention pruned, or that are older than the keep_days cutoff, are not re-inserted; their files are consumed.
+	// WHY: A replay must not undo retention by bringing back rows it deliberately deleted.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	pending := filepath.Join(t.TempDir(), "buffer", "pending")
+	svc.config.BufferDir = pending
+
+	st := store.NewStore(db)
+	st.InsertSource(ctx, &store.Source{ID: "src-1", Name: "S", URL: "https://s.example", SourceType: "web", Enabled: true})
+	now := time.Now()
+	for i, id := range []string{"ext-old", "ext-new"} {
+		st.InsertExtraction(ctx, &store.Extraction{ID: id, SourceID: "src-1", ContentHash: "h-" + id,
+			ExtractedText: id, URL: "https://s.example/" + id, ExtractedAt: now.Add(time.Duration(i-2) * time.Hour).UnixMilli()})
+	}
+	if err := svc.SetRetention(ctx, "d1", 30, 1); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+	if n, err := svc.PruneExtractions(ctx, "d1"); err != nil || n != 1 {
+		t.Fatalf("prune: %d, %v", n, err)
+	}
+
+	w := buffer.NewWriter(pending)
+	w.Write(ctx, buffer.Metadata{ID: "ext-old", SourceID: "src-1", DossierID: "d1", SourceURL: "https://s.example/ext-old",
+		SourceType: "web", ContentHash: "h-ext-old", ExtractedAt: now.Add(-2 * time.Hour)}, "ext-old")
+	w.Write(ctx, buffer.Metadata{ID: "ext-ancient", SourceID: "src-1", DossierID: "d1", SourceURL: "https://s.example/a",
+		SourceType: "web", ContentHash: "h-ancient", ExtractedAt: now.AddDate(0, 0, -60)}, "ancient")
+	w.Write(ctx, buffer.Metadata{ID: "ext-lost", SourceID: "src-1", DossierID: "d1", SourceURL: "https://s.example/l",
+		SourceType: "web", ContentHash: "h-lost", ExtractedAt: now}, "lost")
+
+	rep, err := svc.FlushBuffer(ctx)
+	if err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if rep.Scanned != 3 || rep.Applied != 1 || rep.Skipped != 2 || rep.Failed != 0 {
+		t.Fatalf("report: %+v", rep)
+	}
+	for _, id := range []string{"ext-old", "ext-ancient"} {
+		if e, _ := st.GetExtraction(ctx, id); e != nil {
+			t.Errorf("%s re-inserted", id)
+		}
+	}
+	if e, _ := st.GetExtraction(ctx, "ext-lost"); e == nil {
+		t.Error("ext-lost not replayed")
+	}
+	if left, _ := os.ReadDir(pending); len(left) != 0 {
+		t.Errorf("pending after flush: %v, want empty", left)
+	}
+}
@@ -0,0 +1,73 @@
+// CLAUDE:SUMMARY Per-dossier FTS5 maintenance: integrity check of extractions_fts and rebuild from the extractions table.
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// ErrFTSDrift is returned by CheckFTS when the dossier's search index no
+// longer matches its extractions.
+var ErrFTSDrift = store.ErrFTSDrift
+
+// FTSReport is the outcome of ReindexFTS for one dossier.
+type FTSReport struct {
+	DossierID string `json:"dossier_id"`
+	Drift     bool   `json:"drift"`   // integrity-check failed before the rebuild
+	Rebuilt   bool   `json:"rebuilt"` // rebuild ran and the index checks clean
+	Error     string `json:"error,omitempty"`
+}
+
+// CheckFTS runs the FTS5 integrity check of a dossier. Drift is reported as
+// ErrFTSDrift.
+func (svc *Service) CheckFTS(ctx context.Context, dossierID string) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	return st.CheckFTSIntegrity(ctx)
+}
+
+// RebuildFTS rebuilds the search index of a dossier from its extractions.
+// Searches keep answering from the previous index until the rebuild commits.
+func (svc *Service) RebuildFTS(ctx context.Context, dossierID string) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	if err := st.RebuildFTS(ctx, svc.content); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "rebuild_fts", fmt.Sprintf(`{"dossier_id":%q}`, dossierID))
+	return nil
+}
+
+// ReindexFTS checks a dossier's index and, when rebuild is set, rebuilds it
+// and checks it again. Failures are reported in FTSReport.Error.
+func (svc *Service) ReindexFTS(ctx context.Context, dossierID string, rebuild bool) FTSReport {
+	rep := FTSReport{DossierID: dossierID}
+	err := svc.CheckFTS(ctx, dossierID)
+	switch {
+	case errors.Is(err, ErrFTSDrift):
+		rep.Drift = true
+	case err != nil:
+		rep.Error = err.Error()
+		return rep
+	}
+	if !rebuild {
+		return rep
+	}
+	if err := svc.RebuildFTS(ctx, dossierID); err != nil {
+		rep.Error = err.Error()
+		return rep
+	}
+	if err := svc.CheckFTS(ctx, dossierID); err != nil {
+		rep.Error = err.Error()
+		return rep
+	}
+	rep.Rebuilt = true
+	return rep
+}
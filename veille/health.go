@@ -0,0 +1,270 @@
+// CLAUDE:SUMMARY Source health webhooks: detects ok↔broken transitions after fetches and sweeper probes, debounced per source, and POSTs HMAC-signed notifications to the dossier or global webhook.
+package veille
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// Source health states carried by health notifications.
+const (
+	HealthOK     = "ok"
+	HealthBroken = "broken"
+)
+
+// HealthSignatureHeader carries the HMAC-SHA256 of the notification body as
+// "sha256=<hex>" when the webhook has a secret (same scheme as the domwatch
+// webhook sink).
+const HealthSignatureHeader = "X-Signature-256"
+
+// healthWebhookTimeout bounds one notification POST.
+const healthWebhookTimeout = 10 * time.Second
+
+// HealthNotification is the JSON body POSTed on a source health change.
+type HealthNotification struct {
+	Event          string       `json:"event"` // "source_health"
+	DossierID      string       `json:"dossier_id"`
+	Source         HealthSource `json:"source"`
+	PreviousStatus string       `json:"previous_status"` // HealthOK or HealthBroken
+	Status         string       `json:"status"`          // HealthOK or HealthBroken
+	SourceStatus   string       `json:"source_status"`   // raw last_status (error, broken, ok, pending...)
+	Error          string       `json:"error,omitempty"`
+	At             int64        `json:"at"` // ms
+}
+
+// HealthSource identifies the source of a HealthNotification.
+type HealthSource struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	SourceType string `json:"source_type"`
+}
+
+// SignHealthBody returns the HealthSignatureHeader value of body under
+// secret, for receivers verifying notifications.
+func SignHealthBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// healthState maps a source last_status to its health state.
+func healthState(lastStatus string) string {
+	switch lastStatus {
+	case "error", "extract_error", "broken":
+		return HealthBroken
+	}
+	return HealthOK
+}
+
+// checkHealth compares the health of a source with the last notified one
+// and notifies a change. The first check of a source only records its
+// state. A change is notified at most once per Config.HealthNotifyCooldown
+// per source: a source flapping within the cooldown is notified once, then
+// its state is re-checked after the cooldown. A failed delivery is retried
+// at the next check; without any webhook the change is recorded silently.
+func (svc *Service) checkHealth(ctx context.Context, dossierID string, st *store.Store, sourceID string) {
+	log := svc.logger.With("dossier_id", dossierID, "source_id", sourceID)
+	src, err := st.GetSource(ctx, sourceID)
+	if err != nil || src == nil || src.DeletedAt != nil || src.SourceType == "question" {
+		return
+	}
+	state := healthState(src.LastStatus)
+	now := time.Now().UnixMilli()
+
+	h, err := st.GetSourceHealth(ctx, sourceID)
+	if err != nil {
+		log.Warn("health: get state", "error", err)
+		return
+	}
+	if h == nil {
+		h = &store.SourceHealth{SourceID: sourceID, State: state, NotifiedState: state, ChangedAt: now}
+		if err := st.PutSourceHealth(ctx, h); err != nil {
+			log.Warn("health: put state", "error", err)
+		}
+		return
+	}
+	changed := h.State != state
+	if changed {
+		h.State, h.ChangedAt = state, now
+	}
+	save := func() {
+		if err := st.PutSourceHealth(ctx, h); err != nil {
+			log.Warn("health: put state", "error", err)
+		}
+	}
+	if h.NotifiedState == state {
+		if changed {
+			save()
+		}
+		return
+	}
+	if h.NotifiedAt > 0 && now-h.NotifiedAt < svc.config.HealthNotifyCooldown.Milliseconds() {
+		if changed {
+			save()
+		}
+		log.Debug("health: change within cooldown, deferred", "state", state)
+		return
+	}
+
+	hook, dossierHook, err := svc.healthWebhook(ctx, st)
+	if err != nil {
+		log.Warn("health: webhook lookup", "error", err)
+		return
+	}
+	if hook != nil {
+		n := &HealthNotification{
+			Event:     "source_health",
+			DossierID: dossierID,
+			Source: HealthSource{
+				ID: src.ID, Name: src.Name, URL: src.URL, SourceType: src.SourceType,
+			},
+			PreviousStatus: h.NotifiedState,
+			Status:         state,
+			SourceStatus:   src.LastStatus,
+			Error:          src.LastError,
+			At:             now,
+		}
+		if err := svc.postHealth(ctx, hook, dossierHook, n); err != nil {
+			log.Warn("health: notify failed", "state", state, "error", err)
+			save()
+			return
+		}
+		log.Info("health: notified", "previous", h.NotifiedState, "state", state)
+	}
+	h.NotifiedState, h.NotifiedAt = state, now
+	save()
+}
+
+// healthWebhook returns the dossier's webhook, else the global one from
+// Config, else nil. dossier reports a user-set (dossier) webhook.
+func (svc *Service) healthWebhook(ctx context.Context, st *store.Store) (hook *HealthWebhook, dossier bool, err error) {
+	hook, err = st.GetHealthWebhook(ctx)
+	if err != nil || hook != nil {
+		return hook, hook != nil, err
+	}
+	if svc.config.HealthWebhookURL == "" {
+		return nil, false, nil
+	}
+	return &HealthWebhook{URL: svc.config.HealthWebhookURL, Secret: svc.config.HealthWebhookSecret}, false, nil
+}
+
+// postHealth POSTs n to hook, signed when hook has a secret. Redirects are
+// never followed (a 3xx is a failure). A dossier webhook is user input: its
+// URL is re-checked against the egress lists and the SSRF validator at send
+// time, and the dialed address is validated after DNS resolution so a
+// rebinding between SetHealthWebhook and the POST cannot reach an internal
+// host. The operator's global webhook is trusted.
+func (svc *Service) postHealth(ctx context.Context, hook *HealthWebhook, dossier bool, n *HealthNotification) error {
+	client := &http.Client{CheckRedirect: noRedirect}
+	if dossier {
+		if err := svc.config.Fetch.CheckEgress(hook.URL); err != nil {
+			return err
+		}
+		if err := svc.urlValidator(hook.URL); err != nil {
+			return fmt.Errorf("webhook url: %w", err)
+		}
+		client.Transport = svc.guardedTransport()
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		req.Header.Set(HealthSignatureHeader, SignHealthBody([]byte(hook.Secret), body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// noRedirect makes an http.Client return the 3xx response as is.
+func noRedirect(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+// guardedTransport dials only addresses the URL validator accepts, checked on
+// the resolved ip:port. No proxy, no keep-alive: notifications are rare.
+func (svc *Service) guardedTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: healthWebhookTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			if err := svc.urlValidator("http://" + address); err != nil {
+				return fmt.Errorf("webhook address %s: %w", address, err)
+			}
+			return nil
+		},
+	}
+	return &http.Transport{
+		DialContext:       dialer.DialContext,
+		DisableKeepAlives: true,
+	}
+}
+
+// SetHealthWebhook sets the dossier's health webhook, used instead of
+// Config.HealthWebhookURL for its sources. secret may be empty (unsigned).
+func (svc *Service) SetHealthWebhook(ctx context.Context, dossierID, rawURL, secret string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook url must be http(s)", ErrInvalidInput)
+	}
+	if err := svc.urlValidator(rawURL); err != nil {
+		return fmt.Errorf("%w: webhook url: %v", ErrInvalidInput, err)
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	if err := st.SetHealthWebhook(ctx, rawURL, secret); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "set_health_webhook", fmt.Sprintf(`{"dossier_id":%q,"url":%q}`, dossierID, rawURL))
+	return nil
+}
+
+// GetHealthWebhook returns the dossier's own health webhook (secret not
+// serialized), or nil if it uses the global one.
+func (svc *Service) GetHealthWebhook(ctx context.Context, dossierID string) (*HealthWebhook, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	return st.GetHealthWebhook(ctx)
+}
+
+// DeleteHealthWebhook removes the dossier's health webhook; the global one
+// applies again.
+func (svc *Service) DeleteHealthWebhook(ctx context.Context, dossierID string) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	if err := st.DeleteHealthWebhook(ctx); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "delete_health_webhook", fmt.Sprintf(`{"dossier_id":%q}`, dossierID))
+	return nil
+}
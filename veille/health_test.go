@@ -0,0 +1,183 @@
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestHealthWebhook_NotifiesOkToErrorOnce(t *testing.T) {
+	// WHAT: A source going ok → error fires one signed notification; repeated errors and a quick recovery do not.
+	// WHY: Operators must hear about a broken source once, not on every failed fetch or flap.
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.Exec("PRAGMA foreign_keys=ON")
+	if err := store.ApplySchema(db); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+
+	var mu sync.Mutex
+	var got []HealthNotification
+	var badSig atomic.Bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(HealthSignatureHeader) != SignHealthBody([]byte("s3cret"), body) {
+			badSig.Store(true)
+		}
+		var n HealthNotification
+		json.Unmarshal(body, &n)
+		mu.Lock()
+		got = append(got, n)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	var failing atomic.Bool
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`<html><head><title>Up</title></head><body><main><p>The site is up and serving its regular content today.</p></main></body></html>`))
+	}))
+	defer site.Close()
+
+	noSSRF := func(string) error { return nil } // httptest listens on loopback
+	cfg := &Config{
+		Fetch:               fetch.Config{URLValidator: noSSRF},
+		HealthWebhookURL:    receiver.URL,
+		HealthWebhookSecret: "s3cret",
+	}
+	svc, err := New(&testPool{db: db}, cfg, nil, WithURLValidator(noSSRF))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	src := &Source{Name: "Site", URL: site.URL, SourceType: "web", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add source: %v", err)
+	}
+
+	if err := svc.FetchNow(ctx, "d1", src.ID); err != nil {
+		t.Fatalf("healthy fetch: %v", err)
+	}
+	failing.Store(true)
+	for range 2 {
+		svc.FetchNow(ctx, "d1", src.ID) // error expected
+	}
+	failing.Store(false)
+	svc.FetchNow(ctx, "d1", src.ID) // recovery within the cooldown: deferred
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 {
+		t.Fatalf("notifications: got %d, want 1: %+v", len(got), got)
+	}
+	n := got[0]
+	if n.PreviousStatus != HealthOK || n.Status != HealthBroken || n.DossierID != "d1" || n.Source.ID != src.ID || n.Error == "" {
+		t.Errorf("notification: got %+v", n)
+	}
+	if badSig.Load() {
+		t.Error("bad or missing signature")
+	}
+}
+
+func TestSetHealthWebhook_ValidatesURL(t *testing.T) {
+	// WHAT: A per-dossier webhook must be an http(s) URL; it is stored and removable.
+	// WHY: The URL is user input sent POSTs by the server.
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	if err := svc.SetHealthWebhook(ctx, "d1", "ftp://hooks.example.com", ""); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("ftp url: got %v, want ErrInvalidInput", err)
+	}
+	if err := svc.SetHealthWebhook(ctx, "d1", "https://hooks.example.com/veille", "k"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	hook, err := svc.GetHealthWebhook(ctx, "d1")
+	if err != nil || hook == nil || hook.URL != "https://hooks.example.com/veille" || hook.Secret != "k" {
+		t.Fatalf("get: %+v, %v", hook, err)
+	}
+	if err := svc.DeleteHealthWebhook(ctx, "d1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if hook, _ := svc.GetHealthWebhook(ctx, "d1"); hook != nil {
+		t.Errorf("after delete: got %+v", hook)
+	}
+}
+
+func TestPostHealth_NoRedirect(t *testing.T) {
+	// WHAT: A webhook answering 3xx is a failed notification; the redirect target is never contacted.
+	// WHY: Following redirects would let a webhook bounce the server's POST to an internal host.
+	var hit atomic.Bool
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hit.Store(true) }))
+	defer internal.Close()
+	hook := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+	defer hook.Close()
+
+	svc, _ := setupTestService(t)
+	svc.urlValidator = func(string) error { return nil }
+	n := &HealthNotification{Event: "source_health", DossierID: "d1"}
+	for _, dossier := range []bool{false, true} {
+		if err := svc.postHealth(context.Background(), &HealthWebhook{URL: hook.URL}, dossier, n); err == nil {
+			t.Errorf("dossier=%v: redirect accepted", dossier)
+		}
+	}
+	if hit.Load() {
+		t.Error("redirect target contacted")
+	}
+}
+
+func TestPostHealth_DossierRevalidatedAtSend(t *testing.T) {
+	// WHAT: A dossier webhook is re-validated at send time, on its URL and on the resolved address dialed.
+	// WHY: A URL accepted by SetHealthWebhook can later resolve (DNS rebinding) or be reclassified to an internal host.
+	var hit atomic.Bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hit.Store(true) }))
+	defer receiver.Close()
+	_, port, _ := net.SplitHostPort(receiver.Listener.Addr().String())
+
+	svc, _ := setupTestService(t)
+	// Rejects IP-literal loopback only, so "localhost" passes the URL check
+	// and is caught once resolved.
+	svc.urlValidator = func(raw string) error {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(u.Hostname()); ip != nil && ip.IsLoopback() {
+			return errors.New("loopback")
+		}
+		return nil
+	}
+	n := &HealthNotification{Event: "source_health", DossierID: "d1"}
+	ctx := context.Background()
+
+	if err := svc.postHealth(ctx, &HealthWebhook{URL: receiver.URL}, true, n); err == nil {
+		t.Error("loopback URL: accepted at send time")
+	}
+	if err := svc.postHealth(ctx, &HealthWebhook{URL: "http://localhost:" + port}, true, n); err == nil {
+		t.Error("hostname resolving to loopback: dialed")
+	}
+	if hit.Load() {
+		t.Fatal("dossier webhook reached a blocked address")
+	}
+	// The operator's global webhook is trusted.
+	if err := svc.postHealth(ctx, &HealthWebhook{URL: receiver.URL}, false, n); err != nil || !hit.Load() {
+		t.Errorf("global webhook: %v, hit=%v", err, hit.Load())
+	}
+}
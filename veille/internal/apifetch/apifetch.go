@@ -1,8 +1,10 @@
-// CLAUDE:SUMMARY JSON API fetcher with dot-notation result walker, field mapping, and env var expansion.
+// CLAUDE:SUMMARY JSON API fetcher with dot-notation or JSONPath result mapping, and env var expansion.
 // Package apifetch fetches and extracts structured results from JSON APIs.
 //
 // It supports configurable HTTP method, headers (with ${ENV_VAR} expansion),
-// dot-notation path walking for nested results, and field mapping.
+// dot-notation path walking for nested results, and field mapping. When
+// item_path is set, items and their fields are selected with JSONPath
+// expressions instead (see Path).
 package apifetch
 
 import (
@@ -22,6 +24,24 @@ type Config struct {
 	ResultPath  string            `json:"result_path"`   // dot-notation: "data.results"
 	Fields      map[string]string `json:"fields"`        // {"title":"name","text":"body","url":"link"}
 	RateLimitMs int64             `json:"rate_limit_ms"` // minimum ms between requests
+
+	// JSONPath mapping. When ItemPath is set, ResultPath and Fields are
+	// ignored: ItemPath selects the items from the response root and the
+	// other paths are evaluated relative to each item.
+	ItemPath  string `json:"item_path,omitempty"`  // "$.data.items[*]"
+	TitlePath string `json:"title_path,omitempty"` // "$.name"
+	URLPath   string `json:"url_path,omitempty"`   // "$.links.html"
+	BodyPath  string `json:"body_path,omitempty"`  // "$.summary"
+
+	// OnSkip, if set, is called for each item dropped because one of its
+	// mapped paths did not resolve. index is the item position in ItemPath.
+	OnSkip func(index int, err error) `json:"-"`
+}
+
+// JSONPathMode reports whether the config maps items with JSONPath
+// (item_path) rather than result_path and fields.
+func (c Config) JSONPathMode() bool {
+	return c.ItemPath != ""
 }
 
 // Result is one extracted item from an API response.
@@ -71,6 +91,35 @@ func Fetch(ctx context.Context, client *http.Client, baseURL string, cfg Config)
 		return nil, fmt.Errorf("apifetch: json decode: %w", err)
 	}
 
+	return Extract(raw, cfg)
+}
+
+// Validate checks that the JSONPath expressions in c compile. Mapping
+// paths without item_path are rejected since they would be ignored.
+func (c Config) Validate() error {
+	if !c.JSONPathMode() {
+		if c.TitlePath != "" || c.URLPath != "" || c.BodyPath != "" {
+			return fmt.Errorf("apifetch: title_path, url_path and body_path require item_path")
+		}
+		return nil
+	}
+	for name, expr := range map[string]string{
+		"item_path": c.ItemPath, "title_path": c.TitlePath,
+		"url_path": c.URLPath, "body_path": c.BodyPath,
+	} {
+		if _, err := CompilePath(expr); err != nil {
+			return fmt.Errorf("apifetch: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Extract maps a decoded JSON response into Results according to cfg.
+func Extract(raw any, cfg Config) ([]Result, error) {
+	if cfg.JSONPathMode() {
+		return extractJSONPath(raw, cfg)
+	}
+
 	// Walk result_path to find the array of items.
 	items, err := walkPath(raw, cfg.ResultPath)
 	if err != nil {
@@ -90,6 +139,86 @@ func Fetch(ctx context.Context, client *http.Client, baseURL string, cfg Config)
 	return results, nil
 }
 
+// extractJSONPath selects items with cfg.ItemPath and maps each one
+// through the title, URL and body paths. An item whose mapped path does not
+// resolve is skipped and reported to cfg.OnSkip; an unset path leaves the
+// field empty.
+func extractJSONPath(raw any, cfg Config) ([]Result, error) {
+	itemPath, err := CompilePath(cfg.ItemPath)
+	if err != nil {
+		return nil, fmt.Errorf("apifetch: item_path: %w", err)
+	}
+	fields := []struct {
+		name string
+		expr string
+		dst  func(*Result) *string
+	}{
+		{"title_path", cfg.TitlePath, func(r *Result) *string { return &r.Title }},
+		{"url_path", cfg.URLPath, func(r *Result) *string { return &r.URL }},
+		{"body_path", cfg.BodyPath, func(r *Result) *string { return &r.Text }},
+	}
+	paths := make([]*Path, len(fields))
+	for i, f := range fields {
+		if f.expr == "" {
+			continue
+		}
+		if paths[i], err = CompilePath(f.expr); err != nil {
+			return nil, fmt.Errorf("apifetch: %s: %w", f.name, err)
+		}
+	}
+
+	items, err := itemPath.Eval(raw)
+	if err != nil {
+		return nil, fmt.Errorf("apifetch: item_path: %w", err)
+	}
+	// "$.data.items" without a wildcard names the array itself.
+	if !itemPath.Wildcard() && len(items) == 1 {
+		if arr, ok := items[0].([]any); ok {
+			items = arr
+		}
+	}
+
+	results := make([]Result, 0, len(items))
+items:
+	for i, item := range items {
+		var r Result
+		for j, path := range paths {
+			if path == nil {
+				continue
+			}
+			vals, err := path.Eval(item)
+			if err != nil {
+				if cfg.OnSkip != nil {
+					cfg.OnSkip(i, fmt.Errorf("%s: %w", fields[j].name, err))
+				}
+				continue items
+			}
+			*fields[j].dst(&r) = joinValues(vals)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// joinValues renders the values selected by a path as text, one per line.
+// Objects and arrays are kept as JSON.
+func joinValues(vals []any) string {
+	parts := make([]string, 0, len(vals))
+	for _, v := range vals {
+		s := asString(v)
+		switch v.(type) {
+		case map[string]any, []any:
+			if b, err := json.Marshal(v); err == nil {
+				s = string(b)
+			}
+		}
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
 // walkPath walks a dot-notation path into a JSON value, returning the items
 // found at that path. If the path is empty, the root must be an array.
 func walkPath(v any, path string) ([]any, error) {
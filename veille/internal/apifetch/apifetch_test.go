@@ -152,3 +152,66 @@ func TestWalkPath_Deep(t *testing.T) {
 		t.Errorf("items: got %d", len(items))
 	}
 }
+
+func TestFetch_JSONPath(t *testing.T) {
+	// WHAT: item_path maps each element of data.items[*] through the field paths.
+	// WHY: API sources declare their mapping in config_json instead of custom code.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {
+				"items": [
+					{"meta": {"title": "First"}, "links": {"html": "https://example.com/1"}, "content": {"body": "Body one"}},
+					{"meta": {"title": "Second"}, "links": {"html": "https://example.com/2"}, "content": {"body": "Body two"}},
+					{"meta": {"title": "No body"}, "links": {"html": "https://example.com/3"}},
+					{"meta": {"title": "Paragraphs"}, "links": {"html": "https://example.com/4"}, "content": {"body": ["p1", "p2"]}}
+				]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	var skipped []int
+	cfg := Config{
+		ItemPath:  "$.data.items[*]",
+		TitlePath: "$.meta.title",
+		URLPath:   "$.links.html",
+		BodyPath:  "$.content.body",
+		OnSkip:    func(i int, err error) { skipped = append(skipped, i) },
+	}
+	results, err := Fetch(context.Background(), srv.Client(), srv.URL, cfg)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("results: got %d, want 3", len(results))
+	}
+	if results[1].Title != "Second" || results[1].URL != "https://example.com/2" || results[1].Text != "Body two" {
+		t.Errorf("result[1]: got %+v", results[1])
+	}
+	if results[2].Text != `["p1","p2"]` {
+		t.Errorf("array body: got %q", results[2].Text)
+	}
+	if len(skipped) != 1 || skipped[0] != 2 {
+		t.Errorf("skipped: got %v, want [2]", skipped)
+	}
+}
+
+func TestExtract_JSONPathArrayWithoutWildcard(t *testing.T) {
+	// WHAT: An item_path naming an array without [*] yields its elements.
+	// WHY: "data.items" and "data.items[*]" should mean the same thing for items.
+	raw := map[string]any{"items": []any{
+		map[string]any{"t": "a"},
+		map[string]any{"t": "b"},
+	}}
+	results, err := Extract(raw, Config{ItemPath: "items", TitlePath: "t"})
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if len(results) != 2 || results[1].Title != "b" {
+		t.Errorf("results: got %+v", results)
+	}
+
+	if _, err := Extract(raw, Config{ItemPath: "$.missing[*]"}); err == nil {
+		t.Error("expected error for missing item_path")
+	}
+}
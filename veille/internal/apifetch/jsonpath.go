@@ -0,0 +1,156 @@
+// CLAUDE:SUMMARY Minimal JSONPath evaluator: $, dot keys, ['quoted keys'], [n] indexes and [*] wildcards.
+package apifetch
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrPathNotFound is returned by Path.Eval when the path does not resolve.
+var ErrPathNotFound = errors.New("path not found")
+
+// Path is a compiled JSONPath expression.
+//
+// Supported syntax is the subset useful for API payloads: an optional
+// leading "$" (or "@"), dot-separated keys, ['quoted keys'], [n] array
+// indexes (negative counts from the end) and [*] / .* wildcards.
+// Filters, slices and recursive descent are not supported.
+type Path struct {
+	raw   string
+	steps []pathStep
+}
+
+// pathStep is one segment of a Path. A wildcard step has wildcard set;
+// otherwise exactly one of key (object member) or index (array element,
+// isIndex set) applies.
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// CompilePath parses a JSONPath expression. The empty string and "$"
+// both denote the root value.
+func CompilePath(expr string) (*Path, error) {
+	p := &Path{raw: expr}
+	s := strings.TrimSpace(expr)
+	if strings.HasPrefix(s, "$") || strings.HasPrefix(s, "@") {
+		s = s[1:]
+	}
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+			end := i
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+			name := s[i:end]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("jsonpath %q: empty key at offset %d", expr, i)
+			case "*":
+				p.steps = append(p.steps, pathStep{wildcard: true})
+			default:
+				p.steps = append(p.steps, pathStep{key: name})
+			}
+			i = end
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath %q: unclosed '['", expr)
+			}
+			inner := strings.TrimSpace(s[i+1 : i+end])
+			i += end + 1
+			switch {
+			case inner == "*":
+				p.steps = append(p.steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.steps = append(p.steps, pathStep{key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("jsonpath %q: invalid index %q", expr, inner)
+				}
+				p.steps = append(p.steps, pathStep{index: n, isIndex: true})
+			}
+		default:
+			// A bare leading key: "data.items" is read as "$.data.items".
+			if i != 0 {
+				return nil, fmt.Errorf("jsonpath %q: unexpected %q at offset %d", expr, s[i], i)
+			}
+			s = "." + s
+		}
+	}
+	return p, nil
+}
+
+// String returns the expression the path was compiled from.
+func (p *Path) String() string { return p.raw }
+
+// Wildcard reports whether the path contains a [*] step and may therefore
+// yield several values.
+func (p *Path) Wildcard() bool {
+	for _, st := range p.steps {
+		if st.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// Eval returns the values the path selects in v. A key or index that does
+// not exist yields ErrPathNotFound; a wildcard over an empty array yields
+// no values and no error.
+func (p *Path) Eval(v any) ([]any, error) {
+	current := []any{v}
+	for _, st := range p.steps {
+		var next []any
+		for _, c := range current {
+			switch {
+			case st.wildcard:
+				switch t := c.(type) {
+				case []any:
+					next = append(next, t...)
+				case map[string]any:
+					// Key order keeps the result deterministic.
+					for _, k := range slices.Sorted(maps.Keys(t)) {
+						next = append(next, t[k])
+					}
+				default:
+					return nil, fmt.Errorf("%w: %q: wildcard on %T", ErrPathNotFound, p.raw, c)
+				}
+			case st.isIndex:
+				arr, ok := c.([]any)
+				if !ok {
+					return nil, fmt.Errorf("%w: %q: index on %T", ErrPathNotFound, p.raw, c)
+				}
+				idx := st.index
+				if idx < 0 {
+					idx += len(arr)
+				}
+				if idx < 0 || idx >= len(arr) {
+					return nil, fmt.Errorf("%w: %q: index %d out of range", ErrPathNotFound, p.raw, st.index)
+				}
+				next = append(next, arr[idx])
+			default:
+				obj, ok := c.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("%w: %q: key %q on %T", ErrPathNotFound, p.raw, st.key, c)
+				}
+				val, ok := obj[st.key]
+				if !ok {
+					return nil, fmt.Errorf("%w: %q: key %q", ErrPathNotFound, p.raw, st.key)
+				}
+				next = append(next, val)
+			}
+		}
+		current = next
+	}
+	return current, nil
+}
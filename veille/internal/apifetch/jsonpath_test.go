@@ -0,0 +1,88 @@
+package apifetch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func mustDecode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return v
+}
+
+func TestPath_Eval(t *testing.T) {
+	// WHAT: Supported JSONPath forms select the expected values.
+	// WHY: API mappings rely on keys, indexes and wildcards over nested payloads.
+	doc := mustDecode(t, `{
+		"data": {
+			"items": [
+				{"name": "a", "tags": ["x", "y"]},
+				{"name": "b", "tags": []}
+			],
+			"odd key": 7
+		}
+	}`)
+
+	cases := []struct {
+		expr string
+		want int
+	}{
+		{"$", 1},
+		{"", 1},
+		{"$.data.items", 1},
+		{"data.items", 1},
+		{"$.data.items[*]", 2},
+		{"$.data.items.*", 2},
+		{"$.data.items[*].name", 2},
+		{"$.data.items[0].tags[*]", 2},
+		{"$.data.items[1].tags[*]", 0},
+		{"$.data.items[-1].name", 1},
+		{"$.data['odd key']", 1},
+		{"$['data'][\"items\"][0]", 1},
+	}
+	for _, c := range cases {
+		p, err := CompilePath(c.expr)
+		if err != nil {
+			t.Errorf("compile %q: %v", c.expr, err)
+			continue
+		}
+		got, err := p.Eval(doc)
+		if err != nil {
+			t.Errorf("eval %q: %v", c.expr, err)
+			continue
+		}
+		if len(got) != c.want {
+			t.Errorf("eval %q: got %d values, want %d", c.expr, len(got), c.want)
+		}
+	}
+}
+
+func TestPath_Missing(t *testing.T) {
+	// WHAT: Unresolvable keys and indexes return ErrPathNotFound.
+	// WHY: Callers skip items on a missing path instead of mapping empty fields.
+	doc := mustDecode(t, `{"data": {"items": [{"name": "a"}]}}`)
+	for _, expr := range []string{"$.data.nope", "$.data.items[3]", "$.data.items[0].name.first", "$.data.items.name"} {
+		p, err := CompilePath(expr)
+		if err != nil {
+			t.Fatalf("compile %q: %v", expr, err)
+		}
+		if _, err := p.Eval(doc); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("eval %q: got %v, want ErrPathNotFound", expr, err)
+		}
+	}
+}
+
+func TestCompilePath_Invalid(t *testing.T) {
+	// WHAT: Malformed expressions are rejected at compile time.
+	// WHY: A bad selector is a config error and must surface before fetching.
+	for _, expr := range []string{"$.data[", "$.items[abc]", "$..items", "$.a.[0]"} {
+		if _, err := CompilePath(expr); err == nil {
+			t.Errorf("compile %q: expected error", expr)
+		}
+	}
+}
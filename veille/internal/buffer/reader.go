@@ -0,0 +1,79 @@
+// CLAUDE:SUMMARY Parses buffer .md files back into Metadata + text (inverse of the writer's frontmatter format), for buffer replay.
+package buffer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrMalformed is returned by Parse for content that is not a buffer file.
+var ErrMalformed = errors.New("buffer: malformed file")
+
+// Parse splits a file written by Writer into its Metadata and text body.
+func Parse(data []byte) (Metadata, string, error) {
+	var m Metadata
+	content := string(data)
+	if !strings.HasPrefix(content, "---\n") {
+		return m, "", fmt.Errorf("%w: no frontmatter", ErrMalformed)
+	}
+	fm, body, ok := strings.Cut(content[len("---\n"):], "\n---\n")
+	if !ok {
+		return m, "", fmt.Errorf("%w: unterminated frontmatter", ErrMalformed)
+	}
+	for _, line := range strings.Split(fm, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			key, value = strings.TrimSuffix(line, ":"), ""
+		}
+		switch key {
+		case "id":
+			m.ID = value
+		case "source_id":
+			m.SourceID = value
+		case "dossier_id":
+			m.DossierID = value
+		case "source_url":
+			m.SourceURL = value
+		case "source_type":
+			m.SourceType = value
+		case "title":
+			title, err := yamlUnescape(value)
+			if err != nil {
+				return m, "", err
+			}
+			m.Title = title
+		case "extracted_at":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return m, "", fmt.Errorf("%w: extracted_at: %v", ErrMalformed, err)
+			}
+			m.ExtractedAt = t
+		case "content_hash":
+			m.ContentHash = value
+		default:
+			return m, "", fmt.Errorf("%w: unexpected line %q", ErrMalformed, line)
+		}
+	}
+	return m, strings.TrimPrefix(body, "\n"), nil
+}
+
+// yamlUnescape reverses yamlEscape.
+func yamlUnescape(s string) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return s, nil
+	}
+	if len(s) < 2 || !strings.HasSuffix(s, `"`) {
+		return "", fmt.Errorf("%w: unterminated quoted title", ErrMalformed)
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String(), nil
+}
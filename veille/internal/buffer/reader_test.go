@@ -0,0 +1,42 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	// WHAT: Parse returns the Metadata and text that Write serialized, quoted titles included; junk is ErrMalformed.
+	// WHY: Buffer replay re-inserts extractions from these files and must not mangle them.
+	w := NewWriter(t.TempDir())
+	meta := Metadata{
+		ID: "rt-001", SourceID: "src-1", DossierID: "u_d", SourceURL: "https://example.com/a",
+		SourceType: "rss", Title: `Say "hi": a \ test`, ContentHash: "abc",
+		ExtractedAt: time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC),
+	}
+	path, err := w.Write(context.Background(), meta, "Line one\n\n---\nLine two")
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+
+	got, text, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got != meta {
+		t.Errorf("metadata: got %+v, want %+v", got, meta)
+	}
+	if text != "Line one\n\n---\nLine two" {
+		t.Errorf("text: got %q", text)
+	}
+
+	for _, junk := range []string{"no frontmatter", "---\nid: x\n", "---\nid: x\nextracted_at: yesterday\n---\n\nt"} {
+		if _, _, err := Parse([]byte(junk)); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Parse(%q): got %v, want ErrMalformed", junk, err)
+		}
+	}
+}
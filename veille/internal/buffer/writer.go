@@ -9,9 +9,12 @@ package buffer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hazyhaar/pkg/idgen"
@@ -29,10 +32,16 @@ type Metadata struct {
 	ExtractedAt time.Time
 }
 
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("buffer: writer closed")
+
 // Writer deposits .md files into the pending directory.
 type Writer struct {
 	dir   string // buffer/pending/
 	newID func() string
+
+	mu     sync.RWMutex // Write holds it shared, Close exclusively
+	closed bool
 }
 
 // NewWriter creates a Writer targeting the given pending directory.
@@ -47,6 +56,11 @@ func NewWriter(pendingDir string) *Writer {
 // Write creates a .md file with YAML frontmatter + text body.
 // Returns the path of the written file.
 func (w *Writer) Write(_ context.Context, meta Metadata, text string) (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return "", ErrClosed
+	}
 	if err := os.MkdirAll(w.dir, 0o755); err != nil {
 		return "", fmt.Errorf("buffer: mkdir %s: %w", w.dir, err)
 	}
@@ -73,6 +87,40 @@ func (w *Writer) Write(_ context.Context, meta Metadata, text string) (string, e
 	return target, nil
 }
 
+// Close waits for in-progress writes, rejects new ones, removes .tmp files
+// left by interrupted writes and syncs the pending directory so renamed
+// files are durable. Closing twice is a no-op.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	entries, err := os.ReadDir(w.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("buffer: read %s: %w", w.dir, err)
+	}
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			_ = os.Remove(filepath.Join(w.dir, e.Name()))
+		}
+	}
+	d, err := os.Open(w.dir)
+	if err != nil {
+		return fmt.Errorf("buffer: open %s: %w", w.dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("buffer: sync %s: %w", w.dir, err)
+	}
+	return nil
+}
+
 // formatFrontmatter builds a YAML frontmatter block.
 func formatFrontmatter(m Metadata) string {
 	return "---\n" +
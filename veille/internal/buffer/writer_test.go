@@ -2,6 +2,7 @@ package buffer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -214,3 +215,32 @@ func TestWrite_GeneratesIDIfEmpty(t *testing.T) {
 
 // Ensure fmt is used.
 var _ = fmt.Sprintf
+
+func TestClose_RemovesTmpAndRejectsWrites(t *testing.T) {
+	// WHAT: Close removes stale .tmp files, keeps finished files, and later writes fail with ErrClosed.
+	// WHY: Shutdown must leave only complete files for the RAG consumer and no write may start after it.
+	dir := t.TempDir()
+	w := NewWriter(dir)
+	path, err := w.Write(context.Background(), Metadata{ID: "done-001", ExtractedAt: time.Now()}, "content")
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	stale := filepath.Join(dir, "crashed-001.md.tmp")
+	os.WriteFile(stale, []byte("partial"), 0o644)
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale .tmp should be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("finished file should be kept: %v", err)
+	}
+	if _, err := w.Write(context.Background(), Metadata{ID: "late-001"}, "late"); !errors.Is(err, ErrClosed) {
+		t.Errorf("write after close: got %v, want ErrClosed", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second close: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+// CLAUDE:SUMMARY Bounded link following: outbound link extraction from HTML and a breadth-first Walker with visited set, depth, page and per-host caps.
+// Package crawl follows the outbound links of fetched pages, breadth-first,
+// under hard limits so that one source cannot trigger a crawl explosion.
+//
+// Fetching, SSRF validation and storage are the caller's job (Visit); the
+// package only decides which URLs to visit, in which order, and when to stop.
+package crawl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// MaxDepth is the upper bound for a follow depth (web source config_json
+// follow_depth, tracked question follow_depth).
+const MaxDepth = 3
+
+// MaxLinksPerPage caps the links kept for one page.
+const MaxLinksPerPage = 200
+
+// Limits caps the pages visited by one Walker, i.e. one fetch job.
+type Limits struct {
+	MaxPages   int // pages visited, roots excluded. Default: 20.
+	MaxPerHost int // pages visited per host. Default: 5.
+}
+
+// Visit fetches and stores the page at u, reached at depth (1 = linked from
+// a root), and returns its outbound links. An error skips the page: its
+// links are not followed.
+type Visit func(ctx context.Context, u string, depth int) ([]string, error)
+
+// Walker follows links for one fetch job. The visited set and the caps span
+// every Follow call of the Walker. Not safe for concurrent use.
+type Walker struct {
+	limits  Limits
+	visit   Visit
+	visited map[string]bool
+	perHost map[string]int
+	pages   int
+}
+
+// NewWalker returns a Walker calling visit for each followed page.
+func NewWalker(limits Limits, visit Visit) *Walker {
+	if limits.MaxPages <= 0 {
+		limits.MaxPages = 20
+	}
+	if limits.MaxPerHost <= 0 {
+		limits.MaxPerHost = 5
+	}
+	return &Walker{
+		limits:  limits,
+		visit:   visit,
+		visited: make(map[string]bool),
+		perHost: make(map[string]int),
+	}
+}
+
+// Follow visits, breadth-first, the pages reachable from root through links
+// (root's outbound links) in at most depth hops, depth capped by MaxDepth.
+// root itself is marked visited, not visited again. Each URL (fragment
+// ignored) is visited at most once per Walker; a host at MaxPerHost is
+// skipped and the walk stops at MaxPages or when ctx is done. Returns the
+// number of pages visited by this call.
+func (w *Walker) Follow(ctx context.Context, root string, links []string, depth int) int {
+	depth = min(depth, MaxDepth)
+	if k, _ := key(root); k != "" {
+		w.visited[k] = true
+	}
+	var visited int
+	frontier := links
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var next []string
+		for _, link := range frontier {
+			if ctx.Err() != nil || w.pages >= w.limits.MaxPages {
+				return visited
+			}
+			k, host := key(link)
+			if k == "" || w.visited[k] {
+				continue
+			}
+			w.visited[k] = true
+			if w.perHost[host] >= w.limits.MaxPerHost {
+				continue
+			}
+			w.perHost[host]++
+			w.pages++
+			visited++
+			out, err := w.visit(ctx, k, d)
+			if err == nil && d < depth {
+				next = append(next, out...)
+			}
+		}
+		frontier = next
+	}
+	return visited
+}
+
+// Pages returns the number of pages visited so far by the Walker.
+func (w *Walker) Pages() int {
+	return w.pages
+}
+
+// key returns the visited-set key of an absolute http(s) URL (fragment
+// removed) and its lower-cased host, or "" if u is not one.
+func key(u string) (string, string) {
+	ref, err := url.Parse(u)
+	if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") || ref.Host == "" {
+		return "", ""
+	}
+	ref.Fragment = ""
+	return ref.String(), strings.ToLower(ref.Hostname())
+}
+
+// Links returns the distinct absolute http(s) links of the HTML fragment,
+// resolved against base, fragments removed, in document order, up to max
+// (max <= 0: no cap).
+func Links(fragment, base string, max int) []string {
+	baseURL, err := url.Parse(base)
+	if err != nil || fragment == "" {
+		return nil
+	}
+	var links []string
+	seen := make(map[string]bool)
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for max <= 0 || len(links) < max {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		if string(name) != "a" || !hasAttr {
+			continue
+		}
+		for {
+			k, val, more := z.TagAttr()
+			if string(k) == "href" {
+				if ref, err := baseURL.Parse(strings.TrimSpace(string(val))); err == nil &&
+					(ref.Scheme == "http" || ref.Scheme == "https") {
+					ref.Fragment = ""
+					if s := ref.String(); !seen[s] {
+						seen[s] = true
+						links = append(links, s)
+					}
+				}
+			}
+			if !more {
+				break
+			}
+		}
+	}
+	return links
+}
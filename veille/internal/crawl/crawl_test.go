@@ -0,0 +1,89 @@
+package crawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// graph is a static link graph: page URL -> outbound links.
+type graph map[string][]string
+
+func (g graph) visitor(seen *[]string) Visit {
+	return func(_ context.Context, u string, _ int) ([]string, error) {
+		*seen = append(*seen, u)
+		links, ok := g[u]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return links, nil
+	}
+}
+
+func TestFollow_DepthAndCycles(t *testing.T) {
+	// WHAT: Depth 1 visits only the root's links; depth 2 adds their links once, even with cycles back to the root and between pages.
+	// WHY: follow_depth must bound the crawl and a cycle must not loop or refetch.
+	g := graph{
+		"https://a.test/p1":   {"https://a.test/", "https://a.test/p2", "https://a.test/deep"},
+		"https://a.test/p2":   {"https://a.test/p1#top", "https://a.test/deep"},
+		"https://a.test/deep": {"https://a.test/deeper"},
+	}
+	root := []string{"https://a.test/p1", "https://a.test/p2"}
+
+	var seen []string
+	if n := NewWalker(Limits{}, g.visitor(&seen)).Follow(context.Background(), "https://a.test/", root, 1); n != 2 {
+		t.Errorf("depth 1: visited %d, want 2", n)
+	}
+	if !slices.Equal(seen, root) {
+		t.Errorf("depth 1: visited %v", seen)
+	}
+
+	seen = nil
+	NewWalker(Limits{}, g.visitor(&seen)).Follow(context.Background(), "https://a.test/", root, 2)
+	want := []string{"https://a.test/p1", "https://a.test/p2", "https://a.test/deep"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("depth 2: visited %v, want %v", seen, want)
+	}
+}
+
+func TestFollow_Caps(t *testing.T) {
+	// WHAT: MaxPerHost skips a host's extra pages, MaxPages stops the walk, and the caps span several Follow calls of one Walker.
+	// WHY: A page full of links must not turn one fetch job into a crawl.
+	var links []string
+	for i := range 10 {
+		links = append(links, fmt.Sprintf("https://big.test/%d", i), fmt.Sprintf("https://h%d.test/", i))
+	}
+	var seen []string
+	w := NewWalker(Limits{MaxPages: 6, MaxPerHost: 2}, graph{}.visitor(&seen))
+	w.Follow(context.Background(), "https://root.test/", links, 1)
+	var big int
+	for _, u := range seen {
+		if strings.HasPrefix(u, "https://big.test/") {
+			big++
+		}
+	}
+	if big != 2 || len(seen) != 6 {
+		t.Errorf("visited %v: %d on big.test (want 2), %d total (want 6)", seen, big, len(seen))
+	}
+	if n := w.Follow(context.Background(), "https://root2.test/", []string{"https://other.test/"}, 1); n != 0 || w.Pages() != 6 {
+		t.Errorf("second Follow visited %d (pages %d), want 0 once MaxPages is reached", n, w.Pages())
+	}
+}
+
+func TestLinks_ResolvesAndDedups(t *testing.T) {
+	// WHAT: Links resolves relative hrefs, drops fragments, non-http schemes and duplicates, and honours max.
+	// WHY: Stored links and followed URLs must be absolute and unique.
+	frag := `<p><a href="/a">A</a> <a href="b#x">B</a> <a href="/a#y">A again</a>
+		<a href="mailto:x@y.z">mail</a> <a href="javascript:void(0)">js</a> <a href="https://o.test/c">C</a></p>`
+	got := Links(frag, "https://s.test/dir/page", 0)
+	want := []string{"https://s.test/a", "https://s.test/dir/b", "https://o.test/c"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Links: got %v, want %v", got, want)
+	}
+	if got := Links(frag, "https://s.test/dir/page", 2); len(got) != 2 {
+		t.Errorf("max 2: got %v", got)
+	}
+}
@@ -0,0 +1,136 @@
+// CLAUDE:SUMMARY Per-host circuit breaker shared by all sources fetched through one Fetcher.
+package fetch
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a host's circuit is open and the fetch was
+// short-circuited without a network request.
+var ErrCircuitOpen = errors.New("circuit open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// hostCircuit tracks consecutive failures for one host.
+type hostCircuit struct {
+	state        circuitState
+	failures     int
+	firstFailure time.Time // start of the current failure streak
+	openedAt     time.Time
+	probing      bool // half-open probe in flight
+}
+
+// breaker is a set of per-host circuits.
+//
+// closed → open after threshold consecutive failures within window;
+// open → half-open once cooldown has elapsed, letting a single probe through;
+// half-open → closed on probe success, back to open on probe failure.
+type breaker struct {
+	mu        sync.Mutex
+	hosts     map[string]*hostCircuit
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+func newBreaker(threshold int, window, cooldown time.Duration) *breaker {
+	return &breaker{
+		hosts:     make(map[string]*hostCircuit),
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a request to host may proceed.
+func (b *breaker) allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.hosts[host]
+	if !ok {
+		return true
+	}
+	switch c.state {
+	case circuitOpen:
+		if b.now().Sub(c.openedAt) < b.cooldown {
+			return false
+		}
+		c.state = circuitHalfOpen
+		c.probing = true
+		return true
+	case circuitHalfOpen:
+		if c.probing {
+			return false
+		}
+		c.probing = true
+		return true
+	}
+	return true
+}
+
+// success closes the host's circuit and clears its failure streak.
+func (b *breaker) success(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.hosts, host)
+}
+
+// release frees a half-open probe slot without judging the host, e.g. when
+// the caller's context was cancelled mid-request.
+func (b *breaker) release(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.hosts[host]; ok && c.state == circuitHalfOpen {
+		c.probing = false
+	}
+}
+
+// failure records a failure for host, opening the circuit when the threshold is reached.
+func (b *breaker) failure(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &hostCircuit{}
+		b.hosts[host] = c
+	}
+	switch c.state {
+	case circuitHalfOpen:
+		c.state = circuitOpen
+		c.openedAt = now
+		c.probing = false
+		return
+	case circuitOpen:
+		return
+	}
+	if c.failures == 0 || now.Sub(c.firstFailure) > b.window {
+		c.failures = 0
+		c.firstFailure = now
+	}
+	c.failures++
+	if c.failures >= b.threshold {
+		c.state = circuitOpen
+		c.openedAt = now
+	}
+}
+
+// state returns the current circuit state for host (for tests and diagnostics).
+func (b *breaker) state(host string) circuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.hosts[host]; ok {
+		return c.state
+	}
+	return circuitClosed
+}
@@ -0,0 +1,141 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced time source.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(threshold int, window, cooldown time.Duration) (*breaker, *fakeClock) {
+	clk := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	b := newBreaker(threshold, window, cooldown)
+	b.now = clk.now
+	return b, clk
+}
+
+func TestBreaker_OpenHalfOpenClosed(t *testing.T) {
+	// WHAT: Threshold failures open the circuit; after cooldown one probe is let through; success closes it.
+	// WHY: A flaky host must stop consuming fetch slots, then recover automatically.
+	b, clk := newTestBreaker(3, time.Minute, 30*time.Second)
+	const host = "flaky.example.com"
+
+	for i := 0; i < 3; i++ {
+		if !b.allow(host) {
+			t.Fatalf("attempt %d should be allowed while closed", i)
+		}
+		b.failure(host)
+	}
+	if b.state(host) != circuitOpen {
+		t.Fatalf("state: got %v, want open", b.state(host))
+	}
+	if b.allow(host) {
+		t.Error("open circuit should reject")
+	}
+
+	clk.advance(31 * time.Second)
+	if !b.allow(host) {
+		t.Fatal("after cooldown, one probe should be allowed")
+	}
+	if b.state(host) != circuitHalfOpen {
+		t.Fatalf("state: got %v, want half-open", b.state(host))
+	}
+	if b.allow(host) {
+		t.Error("half-open should allow only one probe in flight")
+	}
+
+	b.success(host)
+	if b.state(host) != circuitClosed {
+		t.Fatalf("state: got %v, want closed", b.state(host))
+	}
+	if !b.allow(host) {
+		t.Error("closed circuit should allow")
+	}
+}
+
+func TestBreaker_HalfOpenFailureReopens(t *testing.T) {
+	// WHAT: A failed half-open probe reopens the circuit for a full cooldown.
+	// WHY: The host is still down; probing must not turn into hammering.
+	b, clk := newTestBreaker(2, time.Minute, 30*time.Second)
+	const host = "down.example.com"
+
+	b.failure(host)
+	b.failure(host)
+	clk.advance(30 * time.Second)
+	if !b.allow(host) {
+		t.Fatal("probe should be allowed")
+	}
+	b.failure(host)
+	if b.state(host) != circuitOpen {
+		t.Fatalf("state: got %v, want open", b.state(host))
+	}
+	clk.advance(10 * time.Second)
+	if b.allow(host) {
+		t.Error("reopened circuit should reject until cooldown elapses again")
+	}
+}
+
+func TestBreaker_WindowResetsStreak(t *testing.T) {
+	// WHAT: Failures spread beyond the window do not open the circuit.
+	// WHY: Occasional errors over hours are per-source noise, not a host outage.
+	b, clk := newTestBreaker(3, time.Minute, 30*time.Second)
+	const host = "slow.example.com"
+
+	b.failure(host)
+	b.failure(host)
+	clk.advance(2 * time.Minute)
+	b.failure(host)
+	if b.state(host) != circuitClosed {
+		t.Errorf("state: got %v, want closed", b.state(host))
+	}
+}
+
+func TestFetch_CircuitSharedAcrossURLs(t *testing.T) {
+	// WHAT: 5xx responses on one URL open the circuit for every URL on that host.
+	// WHY: Sources pointing at the same host share the breaker.
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f := New(Config{URLValidator: noopValidator, BreakerThreshold: 2})
+	ctx := context.Background()
+	f.Fetch(ctx, srv.URL+"/a", "", "", "")
+	f.Fetch(ctx, srv.URL+"/b", "", "", "")
+
+	_, err := f.Fetch(ctx, srv.URL+"/c", "", "", "")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("server hits: got %d, want 2 (third fetch short-circuited)", n)
+	}
+}
+
+func TestFetch_BreakerDisabled(t *testing.T) {
+	// WHAT: A negative threshold disables the breaker.
+	// WHY: Callers that manage their own retries must be able to opt out.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	f := New(Config{URLValidator: noopValidator, BreakerThreshold: -1})
+	for i := 0; i < 10; i++ {
+		_, err := f.Fetch(context.Background(), srv.URL, "", "", "")
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("attempt %d: breaker should be disabled", i)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// CLAUDE:SUMMARY Global egress policy: host allowlist/denylist patterns checked before the SSRF validator, with a typed ErrHostDenied.
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrHostDenied is returned when a URL's host is on Config.DenyHosts, or not
+// on Config.AllowHosts when that list is set. It is distinct from SSRF
+// rejections so fetch logs and callers can tell policy from safety.
+var ErrHostDenied = errors.New("host_denied")
+
+// CheckEgress applies the egress host lists to rawURL. The denylist wins
+// over the allowlist; an empty allowlist allows every host.
+func (c *Config) CheckEgress(rawURL string) error {
+	if len(c.AllowHosts) == 0 && len(c.DenyHosts) == 0 {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if matchesHost(host, c.DenyHosts) {
+		return fmt.Errorf("%w: %s is denylisted", ErrHostDenied, host)
+	}
+	if len(c.AllowHosts) > 0 && !matchesHost(host, c.AllowHosts) {
+		return fmt.Errorf("%w: %s is not allowlisted", ErrHostDenied, host)
+	}
+	return nil
+}
+
+// matchesHost reports whether host matches one of patterns. A pattern is a
+// host name ("example.com", that host only) or "*.example.com" (any
+// subdomain of example.com, not example.com itself). Case is ignored.
+func matchesHost(host string, patterns []string) bool {
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if suffix, ok := strings.CutPrefix(p, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if p != "" && host == p {
+			return true
+		}
+	}
+	return false
+}
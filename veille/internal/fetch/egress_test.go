@@ -0,0 +1,83 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetch_EgressLists(t *testing.T) {
+	// WHAT: An allowlisted host is fetched; a host off the allowlist or on the denylist is rejected with ErrHostDenied before any request.
+	// WHY: Deployments restrict fetching to approved domains; policy rejections must be told apart from SSRF blocks.
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	allowed := New(Config{URLValidator: noopValidator, AllowHosts: []string{"127.0.0.1"}})
+	if _, err := allowed.Fetch(context.Background(), srv.URL, "", "", ""); err != nil {
+		t.Fatalf("allowlisted host: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cfg  Config
+	}{
+		{"not allowlisted", Config{URLValidator: noopValidator, AllowHosts: []string{"approved.example", "*.approved.example"}}},
+		{"denylisted", Config{URLValidator: noopValidator, DenyHosts: []string{"127.0.0.1"}}},
+		{"denylisted and allowlisted", Config{URLValidator: noopValidator, AllowHosts: []string{"127.0.0.1"}, DenyHosts: []string{"127.0.0.1"}}},
+	}
+	for _, c := range cases {
+		_, err := New(c.cfg).Fetch(context.Background(), srv.URL, "", "", "")
+		if !errors.Is(err, ErrHostDenied) {
+			t.Errorf("%s: got %v, want ErrHostDenied", c.name, err)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("requests: got %d, want 1 (denied hosts must not be contacted)", n)
+	}
+}
+
+func TestCheckEgress_Patterns(t *testing.T) {
+	// WHAT: "host" matches that host only, "*.host" any subdomain but not the apex; case and trailing dots are ignored.
+	// WHY: Pattern semantics decide what an allowlist really lets through.
+	cfg := Config{AllowHosts: []string{"Example.com", "*.news.example"}}
+	for url, ok := range map[string]bool{
+		"https://example.com/a":        true,
+		"https://EXAMPLE.com.:443/a":   true,
+		"https://www.example.com/":     false,
+		"https://a.news.example/":      true,
+		"https://a.b.news.example/":    true,
+		"https://news.example/":        false,
+		"https://evilnews.example/":    false,
+		"https://example.com.evil.io/": false,
+	} {
+		if err := cfg.CheckEgress(url); (err == nil) != ok {
+			t.Errorf("%s: got %v, want allowed=%v", url, err, ok)
+		}
+	}
+}
+
+func TestFetch_UserAgent(t *testing.T) {
+	// WHAT: Requests carry Config.UserAgent, or chrc-veille/1.0 by default.
+	// WHY: Site operators identify (and allow) the fetcher by its User-Agent.
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+	}))
+	defer srv.Close()
+
+	New(Config{URLValidator: noopValidator}).Fetch(context.Background(), srv.URL, "", "", "")
+	if got != "chrc-veille/1.0" {
+		t.Errorf("default user agent: got %q", got)
+	}
+	New(Config{URLValidator: noopValidator, UserAgent: "acme-watch/2.0"}).Fetch(context.Background(), srv.URL, "", "", "")
+	if got != "acme-watch/2.0" {
+		t.Errorf("custom user agent: got %q", got)
+	}
+}
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY HTTP conditional GET fetcher with ETag, If-Modified-Since, and content-hash dedup.
+// CLAUDE:SUMMARY HTTP conditional GET fetcher with ETag, If-Modified-Since, and normalized content-hash dedup.
 // Package fetch implements HTTP content fetching with conditional GET support.
 //
 // Supports ETag, If-Modified-Since, and content-hash-based change detection.
@@ -6,42 +6,83 @@ package fetch
 
 import (
 	"context"
-	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/hazyhaar/pkg/horosafe"
 )
 
+// ErrBodyTooLarge is returned when a response body exceeds the size limit
+// of the source type (Config.MaxResponseBytes by default). The fetch is
+// aborted without reading the rest.
+var ErrBodyTooLarge = errors.New("oversized_response")
+
 // Result contains the outcome of a fetch.
 type Result struct {
-	Body       []byte
-	StatusCode int
-	Hash       string // SHA-256 of body
-	ETag       string // from response header
-	LastMod    string // from response header
-	Changed    bool   // true if content is new/different
+	Body        []byte
+	StatusCode  int
+	Hash        string // SHA-256 of the normalized body (see Config.Normalizer)
+	ETag        string // from response header
+	LastMod     string // from response header
+	ContentType string // Content-Type response header, as sent
+	Changed     bool   // true if content is new/different
 }
 
 // Config configures the fetcher.
 type Config struct {
-	Timeout  time.Duration // HTTP timeout. Default: 30s.
-	MaxBytes int64         // Max response body size. Default: 10MB.
+	Timeout time.Duration // HTTP timeout, body read included. Default: 30s.
+	// MaxResponseBytes caps the response body. Larger responses are aborted
+	// with ErrBodyTooLarge instead of being buffered. Default: 10MB.
+	MaxResponseBytes int64
+	// TypeTimeouts and TypeMaxBytes override Timeout and MaxResponseBytes
+	// for requests whose RequestOptions.SourceType matches a key ("rss",
+	// "web", ...). Missing or non-positive entries use the defaults.
+	TypeTimeouts map[string]time.Duration
+	TypeMaxBytes map[string]int64
 	// UserAgent sent with requests.
 	UserAgent string
 	// URLValidator validates URLs before fetch (SSRF prevention).
 	// Default: horosafe.ValidateURL.
 	URLValidator func(string) error
+	// AllowHosts and DenyHosts restrict egress by host pattern
+	// ("example.com" or "*.example.com"), checked before URLValidator on
+	// every request and redirect. A non-empty AllowHosts rejects any other
+	// host; DenyHosts wins over AllowHosts. Rejections wrap ErrHostDenied.
+	AllowHosts []string
+	DenyHosts  []string
+	// Proxy is the default outbound proxy ("http://", "https://",
+	// "socks5://" or "socks5h://", optional user:pass@), overridden per
+	// request by RequestOptions.Proxy. Empty uses the environment
+	// (HTTP_PROXY, HTTPS_PROXY, NO_PROXY).
+	Proxy string
+	// AllowPrivateProxy lets proxies on private or loopback addresses
+	// through; otherwise the proxy address must pass URLValidator.
+	AllowPrivateProxy bool
+	// Normalizer rewrites bodies before they are hashed for change
+	// detection. Default: DefaultNormalizer. RawBody hashes bodies as is.
+	Normalizer Normalizer
+
+	// BreakerThreshold is the number of consecutive failures (network error,
+	// 5xx, 429) for one host within BreakerWindow that opens its circuit.
+	// Default: 5. Negative disables the breaker.
+	BreakerThreshold int
+	// BreakerWindow bounds the failure streak. Default: 5 minutes.
+	BreakerWindow time.Duration
+	// BreakerCooldown is how long an open circuit rejects fetches before
+	// letting one probe through. Default: 2 minutes.
+	BreakerCooldown time.Duration
 }
 
 func (c *Config) defaults() {
 	if c.Timeout <= 0 {
 		c.Timeout = 30 * time.Second
 	}
-	if c.MaxBytes <= 0 {
-		c.MaxBytes = 10 * 1024 * 1024 // 10MB
+	if c.MaxResponseBytes <= 0 {
+		c.MaxResponseBytes = 10 * 1024 * 1024 // 10MB
 	}
 	if c.UserAgent == "" {
 		c.UserAgent = "chrc-veille/1.0"
@@ -49,49 +90,167 @@ func (c *Config) defaults() {
 	if c.URLValidator == nil {
 		c.URLValidator = horosafe.ValidateURL
 	}
+	if c.Normalizer == nil {
+		c.Normalizer = DefaultNormalizer
+	}
+	if c.BreakerThreshold == 0 {
+		c.BreakerThreshold = 5
+	}
+	if c.BreakerWindow <= 0 {
+		c.BreakerWindow = 5 * time.Minute
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = 2 * time.Minute
+	}
 }
 
+// RequestOptions customizes one request, e.g. with per-source credentials.
+type RequestOptions struct {
+	// Headers are set on the request, overriding the defaults (User-Agent
+	// included). Reserved headers (Host, Content-Length, Connection,
+	// Transfer-Encoding) and the conditional-GET headers are ignored.
+	// They are dropped when a redirect leaves the original host.
+	Headers map[string]string
+	// Username and Password are sent as HTTP basic auth when Username is set.
+	Username string
+	Password string
+	// SourceType selects the Config.TypeTimeouts / TypeMaxBytes limits.
+	SourceType string
+	// Proxy overrides Config.Proxy for this request.
+	Proxy string
+	// HashIgnore patterns are removed from the normalized body before
+	// hashing (see CompileHashIgnore).
+	HashIgnore []*regexp.Regexp
+}
+
+// reservedHeaders are never taken from RequestOptions.Headers.
+var reservedHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Transfer-Encoding": true,
+	"If-None-Match":     true,
+	"If-Modified-Since": true,
+}
+
+// IsReservedHeader reports whether name is ignored in RequestOptions.Headers.
+func IsReservedHeader(name string) bool {
+	return reservedHeaders[http.CanonicalHeaderKey(name)]
+}
+
+// customHeadersKey carries the names of RequestOptions headers on the
+// request context, so redirects to another host can strip them.
+type customHeadersKey struct{}
+
 // Fetcher performs HTTP requests with conditional GET.
 type Fetcher struct {
-	client *http.Client
-	config Config
+	client  *http.Client
+	config  Config
+	breaker *breaker // nil when disabled
 }
 
 // New creates a Fetcher with SSRF protection on redirects.
 func New(cfg Config) *Fetcher {
 	cfg.defaults()
 	validate := cfg.URLValidator
+	egress := cfg.CheckEgress
+	var br *breaker
+	if cfg.BreakerThreshold > 0 {
+		br = newBreaker(cfg.BreakerThreshold, cfg.BreakerWindow, cfg.BreakerCooldown)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFromContext
 	return &Fetcher{
+		// No client-wide timeout: Fetch bounds each request by its source
+		// type's timeout.
 		client: &http.Client{
-			Timeout: cfg.Timeout,
+			Transport: transport,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				if len(via) >= 5 {
 					return fmt.Errorf("too many redirects (%d)", len(via))
 				}
+				if err := egress(req.URL.String()); err != nil {
+					return fmt.Errorf("redirect blocked: %w", err)
+				}
 				if err := validate(req.URL.String()); err != nil {
 					return fmt.Errorf("redirect blocked (SSRF): %w", err)
 				}
+				if req.URL.Host != via[0].URL.Host {
+					// Per-source credentials are for the configured host only.
+					// net/http already drops Authorization across hosts.
+					names, _ := req.Context().Value(customHeadersKey{}).([]string)
+					for _, name := range names {
+						req.Header.Del(name)
+					}
+				}
 				return nil
 			},
 		},
-		config: cfg,
+		config:  cfg,
+		breaker: br,
+	}
+}
+
+// ValidateURL applies the egress host lists, then the configured URL
+// validator (SSRF prevention). Fetch calls it too; handlers use it to vet
+// URLs discovered in fetched content before queuing them.
+func (f *Fetcher) ValidateURL(url string) error {
+	if err := f.config.CheckEgress(url); err != nil {
+		return err
 	}
+	return f.config.URLValidator(url)
+}
+
+// CheckEgress applies the egress host lists only, for requests made outside
+// the Fetcher (JSON API sources).
+func (f *Fetcher) CheckEgress(url string) error {
+	return f.config.CheckEgress(url)
 }
 
 // Fetch retrieves a URL. If etag or lastMod are provided, sends conditional headers.
+// opts, if given, adds per-request headers and basic auth.
 // Returns Changed=false on 304 Not Modified.
-// If prevHash is provided and body hash matches, also returns Changed=false.
-func (f *Fetcher) Fetch(ctx context.Context, url, etag, lastMod, prevHash string) (*Result, error) {
+// If prevHash is provided and the normalized body hash matches, also returns
+// Changed=false.
+// Returns an error wrapping ErrHostDenied if the egress host lists reject the
+// URL, ErrCircuitOpen if the host's circuit is open, or ErrBodyTooLarge if
+// the body exceeds the source type's size limit.
+func (f *Fetcher) Fetch(ctx context.Context, url, etag, lastMod, prevHash string, opts ...RequestOptions) (*Result, error) {
+	if err := f.config.CheckEgress(url); err != nil {
+		return nil, err
+	}
 	// SSRF: validate URL before request.
 	if err := f.config.URLValidator(url); err != nil {
 		return nil, fmt.Errorf("URL blocked (SSRF): %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var sourceType, proxy string
+	var hashIgnore []*regexp.Regexp
+	if len(opts) > 0 {
+		sourceType, proxy, hashIgnore = opts[0].SourceType, opts[0].Proxy, opts[0].HashIgnore
+	}
+	proxyURL, err := f.proxyFor(proxy)
+	if err != nil {
+		return nil, err
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, f.timeout(sourceType))
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("new request: %w", err)
 	}
 	req.Header.Set("User-Agent", f.config.UserAgent)
+	if len(opts) > 0 {
+		req = applyRequestOptions(req, opts[0])
+	}
+	if proxyURL != nil {
+		req = withProxy(req, proxyURL)
+	}
+
+	host := req.URL.Host
+	if f.breaker != nil && !f.breaker.allow(host) {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	}
 
 	if etag != "" {
 		req.Header.Set("If-None-Match", etag)
@@ -102,9 +261,15 @@ func (f *Fetcher) Fetch(ctx context.Context, url, etag, lastMod, prevHash string
 
 	resp, err := f.client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			f.releaseHost(host)
+		} else {
+			f.recordHost(host, false)
+		}
 		return nil, fmt.Errorf("http get: %w", err)
 	}
 	defer resp.Body.Close()
+	f.recordHost(host, resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests)
 
 	if resp.StatusCode == http.StatusNotModified {
 		return &Result{
@@ -119,21 +284,84 @@ func (f *Fetcher) Fetch(ctx context.Context, url, etag, lastMod, prevHash string
 		return &Result{StatusCode: resp.StatusCode}, fmt.Errorf("http %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBytes))
+	limit := f.maxBytes(sourceType)
+	if resp.ContentLength > limit {
+		return &Result{StatusCode: resp.StatusCode},
+			fmt.Errorf("%w: content-length %d exceeds %d bytes", ErrBodyTooLarge, resp.ContentLength, limit)
+	}
+	// Read one byte past the limit to tell "exactly at the limit" from "over it".
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
 	if err != nil {
 		return nil, fmt.Errorf("read body: %w", err)
 	}
+	if int64(len(body)) > limit {
+		return &Result{StatusCode: resp.StatusCode},
+			fmt.Errorf("%w: body exceeds %d bytes", ErrBodyTooLarge, limit)
+	}
 
-	h := sha256.Sum256(body)
-	hash := fmt.Sprintf("%x", h)
-
+	hash := f.contentHash(body, hashIgnore)
 	changed := prevHash == "" || hash != prevHash
 	return &Result{
-		Body:       body,
-		StatusCode: resp.StatusCode,
-		Hash:       hash,
-		ETag:       resp.Header.Get("ETag"),
-		LastMod:    resp.Header.Get("Last-Modified"),
-		Changed:    changed,
+		Body:        body,
+		StatusCode:  resp.StatusCode,
+		Hash:        hash,
+		ETag:        resp.Header.Get("ETag"),
+		LastMod:     resp.Header.Get("Last-Modified"),
+		ContentType: resp.Header.Get("Content-Type"),
+		Changed:     changed,
 	}, nil
 }
+
+// timeout returns the request timeout for sourceType.
+func (f *Fetcher) timeout(sourceType string) time.Duration {
+	if d := f.config.TypeTimeouts[sourceType]; d > 0 {
+		return d
+	}
+	return f.config.Timeout
+}
+
+// maxBytes returns the response body limit for sourceType.
+func (f *Fetcher) maxBytes(sourceType string) int64 {
+	if n := f.config.TypeMaxBytes[sourceType]; n > 0 {
+		return n
+	}
+	return f.config.MaxResponseBytes
+}
+
+// applyRequestOptions sets o's headers and basic auth on req.
+func applyRequestOptions(req *http.Request, o RequestOptions) *http.Request {
+	var names []string
+	for name, value := range o.Headers {
+		if IsReservedHeader(name) {
+			continue
+		}
+		req.Header.Set(name, value)
+		names = append(names, name)
+	}
+	if o.Username != "" {
+		req.SetBasicAuth(o.Username, o.Password)
+	}
+	if len(names) == 0 {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), customHeadersKey{}, names))
+}
+
+// recordHost reports a request outcome to the host circuit breaker.
+func (f *Fetcher) recordHost(host string, ok bool) {
+	if f.breaker == nil {
+		return
+	}
+	if ok {
+		f.breaker.success(host)
+	} else {
+		f.breaker.failure(host)
+	}
+}
+
+// releaseHost frees a half-open probe slot without recording an outcome.
+func (f *Fetcher) releaseHost(host string) {
+	if f.breaker != nil {
+		f.breaker.release(host)
+	}
+}
@@ -3,6 +3,7 @@ package fetch
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -112,9 +113,9 @@ func TestFetch_Timeout(t *testing.T) {
 	}
 }
 
-func TestFetch_MaxBody(t *testing.T) {
-	// WHAT: Body is truncated to MaxBytes.
-	// WHY: Prevents memory exhaustion from large responses.
+func TestFetch_OversizedResponse(t *testing.T) {
+	// WHAT: A body larger than MaxResponseBytes aborts the fetch with ErrBodyTooLarge.
+	// WHY: A misbehaving source must not OOM the fetcher or be indexed truncated.
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		for i := 0; i < 1000; i++ {
 			w.Write([]byte("x"))
@@ -122,13 +123,156 @@ func TestFetch_MaxBody(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	f := New(Config{MaxBytes: 100, URLValidator: noopValidator})
+	f := New(Config{MaxResponseBytes: 100, URLValidator: noopValidator})
+	result, err := f.Fetch(context.Background(), srv.URL, "", "", "")
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("got %v, want ErrBodyTooLarge", err)
+	}
+	if result == nil || result.Body != nil {
+		t.Errorf("aborted fetch must not return a body")
+	}
+}
+
+func TestFetch_OversizedContentLength(t *testing.T) {
+	// WHAT: A declared Content-Length above the limit is rejected before reading the body.
+	// WHY: No need to stream a multi-GB body just to discard it.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "5000")
+		w.Write(make([]byte, 5000))
+	}))
+	defer srv.Close()
+
+	f := New(Config{MaxResponseBytes: 100, URLValidator: noopValidator})
+	if _, err := f.Fetch(context.Background(), srv.URL, "", "", ""); !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("got %v, want ErrBodyTooLarge", err)
+	}
+}
+
+func TestFetch_TypeTimeout(t *testing.T) {
+	// WHAT: A slow server trips the rss timeout while a web fetch of the same URL succeeds.
+	// WHY: Feeds answer fast; a stalled feed must not hold a worker for the full page budget.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+			w.Write([]byte("slow"))
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	f := New(Config{
+		URLValidator: noopValidator,
+		TypeTimeouts: map[string]time.Duration{"rss": 50 * time.Millisecond},
+	})
+	if _, err := f.Fetch(context.Background(), srv.URL, "", "", "", RequestOptions{SourceType: "rss"}); err == nil {
+		t.Fatal("rss fetch: expected timeout error")
+	}
+	if _, err := f.Fetch(context.Background(), srv.URL, "", "", "", RequestOptions{SourceType: "web"}); err != nil {
+		t.Fatalf("web fetch: %v", err)
+	}
+}
+
+func TestFetch_TypeMaxBytes(t *testing.T) {
+	// WHAT: A body over the rss size cap fails with ErrBodyTooLarge; the same body passes as web.
+	// WHY: A malicious feed must not stream more than its type's budget.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 1000))
+	}))
+	defer srv.Close()
+
+	f := New(Config{
+		URLValidator: noopValidator,
+		TypeMaxBytes: map[string]int64{"rss": 500},
+	})
+	if _, err := f.Fetch(context.Background(), srv.URL, "", "", "", RequestOptions{SourceType: "rss"}); !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("rss fetch: got %v, want ErrBodyTooLarge", err)
+	}
+	result, err := f.Fetch(context.Background(), srv.URL, "", "", "", RequestOptions{SourceType: "web"})
+	if err != nil {
+		t.Fatalf("web fetch: %v", err)
+	}
+	if len(result.Body) != 1000 {
+		t.Errorf("web body: got %d bytes, want 1000", len(result.Body))
+	}
+}
+
+func TestFetch_BodyAtLimit(t *testing.T) {
+	// WHAT: A body of exactly MaxResponseBytes is accepted in full.
+	// WHY: The limit is inclusive; the extra byte read is only a probe.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 100))
+	}))
+	defer srv.Close()
+
+	f := New(Config{MaxResponseBytes: 100, URLValidator: noopValidator})
 	result, err := f.Fetch(context.Background(), srv.URL, "", "", "")
 	if err != nil {
 		t.Fatalf("fetch: %v", err)
 	}
-	if len(result.Body) > 100 {
-		t.Errorf("body too large: %d bytes, max 100", len(result.Body))
+	if len(result.Body) != 100 {
+		t.Errorf("body: got %d bytes, want 100", len(result.Body))
+	}
+}
+
+func TestFetch_RequestOptions(t *testing.T) {
+	// WHAT: Per-request headers and basic auth reach the server; reserved headers are ignored.
+	// WHY: Sources behind API keys or basic auth need their credentials on every fetch.
+	var got http.Header
+	var host string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		host = r.Host
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	f := New(Config{URLValidator: noopValidator})
+	_, err := f.Fetch(context.Background(), srv.URL, "", "", "", RequestOptions{
+		Headers:  map[string]string{"X-Api-Key": "k-123", "User-Agent": "custom-ua", "Host": "evil.example"},
+		Username: "alice",
+		Password: "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if got.Get("X-Api-Key") != "k-123" {
+		t.Errorf("X-Api-Key: got %q", got.Get("X-Api-Key"))
+	}
+	if got.Get("User-Agent") != "custom-ua" {
+		t.Errorf("User-Agent: got %q", got.Get("User-Agent"))
+	}
+	if !strings.HasPrefix(got.Get("Authorization"), "Basic ") {
+		t.Errorf("Authorization: got %q", got.Get("Authorization"))
+	}
+	if host == "evil.example" {
+		t.Error("reserved Host header must be ignored")
+	}
+}
+
+func TestFetch_RequestOptions_DroppedOnCrossHostRedirect(t *testing.T) {
+	// WHAT: Custom headers are not forwarded when a redirect leaves the original host.
+	// WHY: A source's API key must not leak to whatever host it redirects to.
+	var leaked string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("X-Api-Key")
+		w.Write([]byte("ok"))
+	}))
+	defer other.Close()
+	// Same server, reached through another host name.
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, otherURL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	f := New(Config{URLValidator: noopValidator})
+	if _, err := f.Fetch(context.Background(), origin.URL, "", "", "", RequestOptions{
+		Headers: map[string]string{"X-Api-Key": "k-123"},
+	}); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if leaked != "" {
+		t.Errorf("X-Api-Key forwarded to another host: %q", leaked)
 	}
 }
 
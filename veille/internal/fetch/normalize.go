@@ -0,0 +1,66 @@
+// CLAUDE:SUMMARY Change-detection hashing: bodies are normalized (volatile timestamps, nonces, CSRF tokens, HTML comments and whitespace stripped, plus per-source hash_ignore patterns) before SHA-256, so cosmetic changes do not count as new content.
+package fetch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+)
+
+// Normalizer rewrites a response body before it is hashed for change
+// detection. It must not modify body in place.
+type Normalizer func(body []byte) []byte
+
+// volatilePatterns match content that changes on every request without the
+// page changing: HTML comments (build stamps, render times), CSP nonces,
+// CSRF tokens and ISO-8601 timestamps.
+var volatilePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?s)<!--.*?-->`),
+	regexp.MustCompile(`(?i)\bnonce\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`),
+	regexp.MustCompile(`(?i)<(meta|input)\b[^>]*(csrf|xsrf|authenticity)[_-]?token[^>]*>`),
+	regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?\b`),
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// DefaultNormalizer strips volatilePatterns and collapses whitespace.
+func DefaultNormalizer(body []byte) []byte {
+	for _, re := range volatilePatterns {
+		body = re.ReplaceAll(body, nil)
+	}
+	return bytes.TrimSpace(whitespace.ReplaceAll(body, []byte(" ")))
+}
+
+// RawBody is a Normalizer that hashes the body as received.
+func RawBody(body []byte) []byte { return body }
+
+// MaxHashIgnore bounds the per-source hash_ignore patterns.
+const MaxHashIgnore = 20
+
+// CompileHashIgnore compiles per-source hash_ignore patterns (Go regexp
+// syntax); matches are removed from the body before hashing.
+func CompileHashIgnore(patterns []string) ([]*regexp.Regexp, error) {
+	if len(patterns) > MaxHashIgnore {
+		return nil, fmt.Errorf("at most %d hash_ignore patterns", MaxHashIgnore)
+	}
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("hash_ignore %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// contentHash returns the hex SHA-256 of body after the configured
+// normalizer and the request's hash_ignore patterns.
+func (f *Fetcher) contentHash(body []byte, ignore []*regexp.Regexp) string {
+	norm := f.config.Normalizer(body)
+	for _, re := range ignore {
+		norm = re.ReplaceAll(norm, nil)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(norm))
+}
@@ -0,0 +1,52 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetch_NormalizedHash(t *testing.T) {
+	// WHAT: Bodies differing only in comments, nonces, CSRF tokens, timestamps or whitespace hash the same; real text changes and RawBody do not.
+	// WHY: The hash drives the unchanged short-circuit; volatile markup must not defeat it nor hide real edits.
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := n.Add(1)
+		text := "Price: 10"
+		if r.URL.Path == "/edited" {
+			text = "Price: 12"
+		}
+		fmt.Fprintf(w, `<html><!-- rendered in %dms --><meta name="csrf-token" content="tok%d">
+<script nonce='abc%d'></script>  <p>%s</p><time>2026-10-17T10:0%d:00+02:00</time></html>`, i, i, i, text, i)
+	}))
+	defer srv.Close()
+
+	f := New(Config{URLValidator: noopValidator})
+	first, err := f.Fetch(context.Background(), srv.URL, "", "", "")
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	second, err := f.Fetch(context.Background(), srv.URL, "", "", first.Hash)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if second.Changed {
+		t.Error("volatile-only change reported as changed")
+	}
+	edited, _ := f.Fetch(context.Background(), srv.URL+"/edited", "", "", first.Hash)
+	if !edited.Changed {
+		t.Error("text change reported as unchanged")
+	}
+
+	raw := New(Config{URLValidator: noopValidator, Normalizer: RawBody})
+	if res, _ := raw.Fetch(context.Background(), srv.URL, "", "", first.Hash); !res.Changed {
+		t.Error("RawBody: volatile change reported as unchanged")
+	}
+
+	if _, err := CompileHashIgnore([]string{"("}); err == nil {
+		t.Error("invalid hash_ignore pattern accepted")
+	}
+}
@@ -0,0 +1,102 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubProxy is a forward HTTP proxy that answers every request itself and
+// records the absolute URI and credentials it was asked for.
+func stubProxy(t *testing.T) (*httptest.Server, func() (uris, auths []string)) {
+	t.Helper()
+	var mu sync.Mutex
+	var uris, auths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		uris = append(uris, r.RequestURI)
+		auths = append(auths, r.Header.Get("Proxy-Authorization"))
+		mu.Unlock()
+		w.Write([]byte("via proxy"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() ([]string, []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), uris...), append([]string(nil), auths...)
+	}
+}
+
+func TestFetch_Proxy(t *testing.T) {
+	// WHAT: With a global proxy the request goes to the proxy as an absolute URI; a per-request proxy overrides it and sends its credentials.
+	// WHY: Sources behind egress proxies must be fetched through them, not directly.
+	global, globalSeen := stubProxy(t)
+	perSource, perSourceSeen := stubProxy(t)
+	f := New(Config{URLValidator: noopValidator, Proxy: global.URL, AllowPrivateProxy: true})
+
+	res, err := f.Fetch(context.Background(), "http://target.example/page", "", "", "")
+	if err != nil {
+		t.Fatalf("fetch via global proxy: %v", err)
+	}
+	if string(res.Body) != "via proxy" {
+		t.Errorf("body: got %q", res.Body)
+	}
+	if uris, _ := globalSeen(); len(uris) != 1 || uris[0] != "http://target.example/page" {
+		t.Fatalf("global proxy saw %v, want the target URL", uris)
+	}
+
+	authed := strings.Replace(perSource.URL, "http://", "http://user:secret@", 1)
+	if _, err := f.Fetch(context.Background(), "http://target.example/other", "", "", "", RequestOptions{Proxy: authed}); err != nil {
+		t.Fatalf("fetch via per-source proxy: %v", err)
+	}
+	uris, auths := perSourceSeen()
+	if len(uris) != 1 || uris[0] != "http://target.example/other" {
+		t.Fatalf("per-source proxy saw %v", uris)
+	}
+	if auths[0] != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("Proxy-Authorization: got %q", auths[0])
+	}
+	if uris, _ := globalSeen(); len(uris) != 1 {
+		t.Errorf("global proxy used despite per-source override: %v", uris)
+	}
+}
+
+func TestFetch_ProxyValidated(t *testing.T) {
+	// WHAT: A private proxy is refused unless AllowPrivateProxy; the target URL is still validated; malformed proxy URLs are rejected.
+	// WHY: A source must not reach internal services by naming them as its proxy, nor bypass SSRF checks on the target.
+	proxy, seen := stubProxy(t)
+	blockLoopback := func(u string) error {
+		if strings.Contains(u, "127.0.0.1") {
+			return errors.New("private")
+		}
+		return nil
+	}
+
+	f := New(Config{URLValidator: blockLoopback, Proxy: proxy.URL})
+	if _, err := f.Fetch(context.Background(), "http://target.example/", "", "", ""); err == nil || !strings.Contains(err.Error(), "proxy blocked") {
+		t.Errorf("private proxy: got %v, want proxy blocked", err)
+	}
+
+	f = New(Config{URLValidator: blockLoopback, Proxy: proxy.URL, AllowPrivateProxy: true})
+	if _, err := f.Fetch(context.Background(), "http://127.0.0.1/admin", "", "", ""); err == nil {
+		t.Error("private target fetched through a proxy")
+	}
+	if uris, _ := seen(); len(uris) != 0 {
+		t.Errorf("proxy contacted for rejected requests: %v", uris)
+	}
+
+	for _, raw := range []string{"ftp://proxy:21", "socks4://proxy:1080", "http://", "http://proxy:3128/path"} {
+		if _, err := ParseProxyURL(raw); err == nil {
+			t.Errorf("%s: accepted", raw)
+		}
+	}
+	for _, raw := range []string{"http://proxy:3128", "https://u:p@proxy", "socks5://u:p@proxy:1080", "socks5h://proxy:1080"} {
+		if _, err := ParseProxyURL(raw); err != nil {
+			t.Errorf("%s: %v", raw, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// CLAUDE:SUMMARY Content sniffing: classifies a response as HTML, RSS/Atom feed, sitemap or JSON from its body prefix, then its Content-Type.
+package fetch
+
+import (
+	"bytes"
+	"mime"
+	"strings"
+)
+
+// Content kinds returned by Sniff.
+const (
+	KindUnknown = ""
+	KindHTML    = "html"
+	KindFeed    = "feed" // RSS, Atom or RDF
+	KindSitemap = "sitemap"
+	KindJSON    = "json"
+)
+
+// sniffLen bounds how much of the body Sniff inspects.
+const sniffLen = 2048
+
+// xmlRoots maps XML root element names to content kinds.
+var xmlRoots = map[string]string{
+	"rss":          KindFeed,
+	"feed":         KindFeed,
+	"rdf:rdf":      KindFeed,
+	"urlset":       KindSitemap,
+	"sitemapindex": KindSitemap,
+	"html":         KindHTML,
+}
+
+// mediaKinds maps Content-Type media types to content kinds, used when the
+// body prefix is not conclusive.
+var mediaKinds = map[string]string{
+	"text/html":             KindHTML,
+	"application/xhtml+xml": KindHTML,
+	"application/rss+xml":   KindFeed,
+	"application/atom+xml":  KindFeed,
+	"application/rdf+xml":   KindFeed,
+	"application/json":      KindJSON,
+}
+
+// Sniff classifies a response from the start of its body and, when the body
+// is not conclusive, its Content-Type. The body wins: feeds are often served
+// as text/html or text/xml. Returns KindUnknown for anything else.
+func Sniff(contentType string, body []byte) string {
+	if kind := sniffBody(body); kind != KindUnknown {
+		return kind
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return KindUnknown
+	}
+	if kind, ok := mediaKinds[mt]; ok {
+		return kind
+	}
+	if strings.HasSuffix(mt, "+json") {
+		return KindJSON
+	}
+	return KindUnknown
+}
+
+// sniffBody inspects the first element of body, skipping a BOM, whitespace,
+// the XML declaration, processing instructions and comments.
+func sniffBody(body []byte) string {
+	b := body[:min(len(body), sniffLen)]
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	for {
+		b = bytes.TrimLeft(b, " \t\r\n")
+		switch {
+		case len(b) == 0:
+			return KindUnknown
+		case b[0] == '{' || b[0] == '[':
+			return KindJSON
+		case b[0] != '<':
+			return KindUnknown
+		case bytes.HasPrefix(b, []byte("<?")):
+			b = skipPast(b, "?>")
+		case bytes.HasPrefix(b, []byte("<!--")):
+			b = skipPast(b, "-->")
+		default:
+			return rootKind(b[1:])
+		}
+	}
+}
+
+// rootKind classifies the element whose name starts at b.
+func rootKind(b []byte) string {
+	end := bytes.IndexAny(b, " \t\r\n>/")
+	if end < 0 {
+		return KindUnknown
+	}
+	name := strings.ToLower(string(b[:end]))
+	if name == "!doctype" {
+		rest := strings.ToLower(string(bytes.TrimLeft(b[end:], " \t\r\n")))
+		if strings.HasPrefix(rest, "html") {
+			return KindHTML
+		}
+		return KindUnknown
+	}
+	return xmlRoots[name]
+}
+
+// skipPast returns b after the first occurrence of marker, or nil.
+func skipPast(b []byte, marker string) []byte {
+	i := bytes.Index(b, []byte(marker))
+	if i < 0 {
+		return nil
+	}
+	return b[i+len(marker):]
+}
@@ -0,0 +1,27 @@
+package fetch
+
+import "testing"
+
+func TestSniff(t *testing.T) {
+	// WHAT: Sniff classifies feeds, sitemaps, HTML and JSON from the body first, then the Content-Type.
+	// WHY: Source type correction relies on it; feeds are often served as text/html or text/xml.
+	cases := []struct {
+		name, contentType, body, want string
+	}{
+		{"rss as html", "text/html", `<?xml version="1.0"?><rss version="2.0"><channel/></rss>`, KindFeed},
+		{"atom after comment", "text/xml", "\ufeff<?xml version=\"1.0\"?>\n<!-- gen -->\n<feed xmlns=\"http://www.w3.org/2005/Atom\">", KindFeed},
+		{"rdf", "", `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">`, KindFeed},
+		{"sitemap index", "application/xml", `<?xml version="1.0"?><sitemapindex xmlns="x">`, KindSitemap},
+		{"doctype html", "", "<!DOCTYPE html>\n<html><body>hi</body></html>", KindHTML},
+		{"html fragment by type", "text/html; charset=utf-8", "<div>hi</div>", KindHTML},
+		{"json body", "text/plain", `  {"items": []}`, KindJSON},
+		{"json by type", "application/vnd.api+json", "", KindJSON},
+		{"feed by type", "application/atom+xml", "", KindFeed},
+		{"plain text", "text/plain", "hello", KindUnknown},
+	}
+	for _, c := range cases {
+		if got := Sniff(c.contentType, []byte(c.body)); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
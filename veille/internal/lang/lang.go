@@ -0,0 +1,211 @@
+// CLAUDE:SUMMARY Lightweight trigram language detector (fr, en, es, de, it) returning ISO 639-1 codes or "und".
+// Package lang guesses the language of extracted text.
+//
+// Detection compares the text's character-trigram ranking with reference
+// rankings built at init from short samples of each supported language
+// (Cavnar & Trenkle "out-of-place" distance). It needs no model files and
+// is meant for coarse filtering, not for short or mixed-language snippets.
+package lang
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Undetermined is returned when the text is too short or too ambiguous.
+const Undetermined = "und"
+
+const (
+	// profileSize is the number of ranked trigrams kept per profile.
+	profileSize = 300
+	// minLetters is the minimum letter count below which Detect gives up.
+	minLetters = 40
+	// maxRunes bounds the text examined; the first few KB are plenty.
+	maxRunes = 4096
+	// minMargin is the minimum relative distance gap between the best and
+	// second-best language for a confident answer.
+	minMargin = 0.03
+)
+
+// samples are reference texts; their trigram rankings are the language profiles.
+var samples = map[string]string{
+	"fr": `Le gouvernement a présenté mercredi un projet de loi qui doit permettre de renforcer la sécurité des données personnelles.
+Selon le ministre, les entreprises seront tenues de signaler toute fuite dans un délai de quarante-huit heures, et les sanctions
+pourront atteindre plusieurs millions d'euros. Les associations de défense des libertés estiment que le texte ne va pas assez loin,
+mais elles reconnaissent une avancée. Il faudra encore que les députés l'adoptent avant la fin de l'année, ce qui reste incertain
+dans le contexte actuel. Pour les collectivités, la mise en œuvre sera progressive et accompagnée par l'État. C'est une question
+de confiance entre les citoyens et les services publics, qui sont de plus en plus numériques. Nous avons demandé aux responsables
+quelles seraient les conséquences pour les petites entreprises : ils répondent que des aides sont prévues et que les délais
+seront adaptés à leur situation.`,
+	"en": `The government on Wednesday introduced a bill that would strengthen the protection of personal data.
+According to the minister, companies will be required to report any breach within forty-eight hours, and penalties could reach
+several million dollars. Civil liberties groups say the text does not go far enough, but they acknowledge that it is a step
+forward. Lawmakers still have to pass it before the end of the year, which remains uncertain in the current climate. For local
+authorities, the rollout will be gradual and supported by the state. It is a question of trust between citizens and public
+services, which are more and more digital. We asked the officials what the consequences would be for small businesses: they said
+that funding has been planned and that the deadlines will be adapted to their situation, with the help of the agencies that
+already work with them.`,
+	"es": `El gobierno presentó el miércoles un proyecto de ley que debe reforzar la protección de los datos personales.
+Según el ministro, las empresas estarán obligadas a comunicar cualquier filtración en un plazo de cuarenta y ocho horas, y las
+sanciones podrán alcanzar varios millones de euros. Las asociaciones de defensa de las libertades consideran que el texto no va
+lo bastante lejos, pero reconocen un avance. Los diputados todavía tienen que aprobarlo antes de que termine el año, lo que sigue
+siendo incierto en el contexto actual. Para los ayuntamientos, la aplicación será progresiva y contará con el apoyo del Estado.
+Es una cuestión de confianza entre los ciudadanos y los servicios públicos, que son cada vez más digitales. Preguntamos a los
+responsables cuáles serían las consecuencias para las pequeñas empresas: respondieron que hay ayudas previstas y que los plazos
+se adaptarán a su situación.`,
+	"de": `Die Regierung hat am Mittwoch einen Gesetzentwurf vorgestellt, der den Schutz personenbezogener Daten stärken soll.
+Nach Angaben des Ministers müssen Unternehmen jedes Datenleck innerhalb von achtundvierzig Stunden melden, und die Strafen
+können mehrere Millionen Euro erreichen. Bürgerrechtsverbände halten den Text für nicht weitgehend genug, erkennen aber einen
+Fortschritt an. Die Abgeordneten müssen ihn noch vor Ende des Jahres verabschieden, was in der aktuellen Lage unsicher bleibt.
+Für die Kommunen wird die Umsetzung schrittweise erfolgen und vom Staat begleitet werden. Es geht um das Vertrauen zwischen den
+Bürgern und den öffentlichen Diensten, die immer mehr digital sind. Wir haben die Verantwortlichen gefragt, welche Folgen das
+für kleine Unternehmen hätte: Sie antworteten, dass Hilfen vorgesehen sind und dass die Fristen an ihre Lage angepasst werden.`,
+	"it": `Il governo ha presentato mercoledì un disegno di legge che dovrebbe rafforzare la protezione dei dati personali.
+Secondo il ministro, le aziende saranno tenute a segnalare ogni violazione entro quarantotto ore, e le sanzioni potranno
+raggiungere diversi milioni di euro. Le associazioni per la difesa delle libertà ritengono che il testo non vada abbastanza
+lontano, ma riconoscono un passo avanti. I deputati devono ancora approvarlo prima della fine dell'anno, il che resta incerto
+nel contesto attuale. Per i comuni, l'attuazione sarà graduale e accompagnata dallo Stato. Si tratta di una questione di fiducia
+tra i cittadini e i servizi pubblici, che sono sempre più digitali. Abbiamo chiesto ai responsabili quali sarebbero le
+conseguenze per le piccole imprese: hanno risposto che sono previsti aiuti e che le scadenze saranno adattate alla loro
+situazione.`,
+}
+
+// profiles maps a language code to its trigram ranks (0 = most frequent).
+var profiles = buildProfiles()
+
+func buildProfiles() map[string]map[string]int {
+	out := make(map[string]map[string]int, len(samples))
+	for code, text := range samples {
+		ranked := rankTrigrams(text)
+		ranks := make(map[string]int, len(ranked))
+		for i, tg := range ranked {
+			ranks[tg] = i
+		}
+		out[code] = ranks
+	}
+	return out
+}
+
+// Detect returns the ISO 639-1 code of the most likely language of text
+// among the supported ones, or Undetermined when text has fewer than 40
+// letters or no language clearly wins. It never fails.
+func Detect(text string) string {
+	if letterCount(text) < minLetters {
+		return Undetermined
+	}
+	ranked := rankTrigrams(text)
+	if len(ranked) == 0 {
+		return Undetermined
+	}
+
+	best, second := Undetermined, -1
+	bestDist := -1
+	for code, ranks := range profiles {
+		d := distance(ranked, ranks)
+		switch {
+		case bestDist < 0 || d < bestDist || (d == bestDist && code < best):
+			second = bestDist
+			best, bestDist = code, d
+		case second < 0 || d < second:
+			second = d
+		}
+	}
+	if second >= 0 && float64(second-bestDist) < minMargin*float64(second) {
+		return Undetermined
+	}
+	return best
+}
+
+// Supported returns the language codes Detect can return, besides Undetermined.
+func Supported() []string {
+	codes := make([]string, 0, len(profiles))
+	for code := range profiles {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// distance is the out-of-place measure between a text ranking and a
+// language profile: the sum of rank differences, with a maximal penalty
+// for trigrams missing from the profile.
+func distance(ranked []string, profile map[string]int) int {
+	var d int
+	for i, tg := range ranked {
+		r, ok := profile[tg]
+		if !ok {
+			d += profileSize
+			continue
+		}
+		if r > i {
+			d += r - i
+		} else {
+			d += i - r
+		}
+	}
+	return d
+}
+
+// rankTrigrams returns the profileSize most frequent trigrams of text,
+// most frequent first. Words are lower-cased and padded with spaces so
+// that word starts and ends count; non-letters separate words.
+func rankTrigrams(text string) []string {
+	counts := make(map[string]int)
+	var word []rune
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		padded := append(append([]rune{' '}, word...), ' ')
+		for i := 0; i+3 <= len(padded); i++ {
+			counts[string(padded[i:i+3])]++
+		}
+		word = word[:0]
+	}
+	n := 0
+	for _, r := range text {
+		if n >= maxRunes {
+			break
+		}
+		n++
+		if unicode.IsLetter(r) {
+			word = append(word, unicode.ToLower(r))
+			continue
+		}
+		if r == '\'' || r == '’' {
+			// Elisions ("l'État") split like spaces.
+			flush()
+			continue
+		}
+		flush()
+	}
+	flush()
+
+	ranked := make([]string, 0, len(counts))
+	for tg := range counts {
+		ranked = append(ranked, tg)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if counts[ranked[i]] != counts[ranked[j]] {
+			return counts[ranked[i]] > counts[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > profileSize {
+		ranked = ranked[:profileSize]
+	}
+	return ranked
+}
+
+func letterCount(s string) int {
+	var n int
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			n++
+			if n >= minLetters {
+				return n
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,34 @@
+package lang
+
+import "testing"
+
+func TestDetect_KnownSamples(t *testing.T) {
+	// WHAT: French and English news paragraphs unseen by the profiles are detected.
+	// WHY: Multilingual dossiers are filtered on this value.
+	cases := []struct {
+		want, text string
+	}{
+		{"fr", "La ville a annoncé lundi la fermeture de la piscine municipale pour des travaux de rénovation qui devraient durer jusqu'à l'été prochain. Les habitants sont invités à se rendre dans les communes voisines."},
+		{"fr", "Une nouvelle version du logiciel est disponible : elle corrige plusieurs failles de sécurité et améliore les performances sur les anciens ordinateurs."},
+		{"en", "The city announced on Monday that the municipal swimming pool will close for renovation work expected to last until next summer. Residents are encouraged to use the facilities in neighbouring towns."},
+		{"en", "A new version of the software is available: it fixes several security vulnerabilities and improves performance on older computers."},
+		{"es", "El ayuntamiento anunció el lunes el cierre de la piscina municipal por unas obras de renovación que deberían durar hasta el próximo verano."},
+		{"de", "Die Stadt hat am Montag angekündigt, dass das städtische Schwimmbad wegen Renovierungsarbeiten bis zum nächsten Sommer geschlossen bleibt."},
+		{"it", "Il comune ha annunciato lunedì la chiusura della piscina comunale per lavori di ristrutturazione che dovrebbero durare fino alla prossima estate."},
+	}
+	for _, c := range cases {
+		if got := Detect(c.text); got != c.want {
+			t.Errorf("Detect(%.40q...): got %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestDetect_Undetermined(t *testing.T) {
+	// WHAT: Short, empty or letterless text is "und".
+	// WHY: A guess on a title-sized string would mislabel more than it helps.
+	for _, text := range []string{"", "Go 1.24", "Breaking news", "12345 67890 !!! ??? 2026-02-24 10:00:00 +0000 ---- ####"} {
+		if got := Detect(text); got != Undetermined {
+			t.Errorf("Detect(%q): got %q, want und", text, got)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// CLAUDE:SUMMARY Metrics interface, metric names and no-op default shared by pipeline and scheduler.
+// Package metrics defines the instrumentation hook used by the veille
+// pipeline and scheduler.
+//
+// Metric names are fixed constants. Label values are limited to
+// source_type and status so cardinality stays bounded — never label by
+// dossier ID, source ID or URL.
+package metrics
+
+// Metric names emitted by veille.
+const (
+	// FetchTotal counts pipeline jobs. Labels: source_type, status ("ok", "error").
+	FetchTotal = "veille_fetch_total"
+	// FetchDuration observes job duration in seconds. Labels: source_type.
+	FetchDuration = "veille_fetch_duration_seconds"
+	// ExtractionsTotal counts stored extractions. Labels: source_type.
+	ExtractionsTotal = "veille_extractions_total"
+	// SchedulerTick observes one scheduler pass in seconds. No labels.
+	SchedulerTick = "veille_scheduler_tick_seconds"
+)
+
+// Metrics records counters and histograms.
+// Labels are passed as values, in the order declared for each metric name.
+type Metrics interface {
+	Add(name string, delta float64, labels ...string)
+	Observe(name string, value float64, labels ...string)
+}
+
+// Nop discards all metrics.
+type Nop struct{}
+
+// Add implements Metrics.
+func (Nop) Add(string, float64, ...string) {}
+
+// Observe implements Metrics.
+func (Nop) Observe(string, float64, ...string) {}
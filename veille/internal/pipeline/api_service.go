@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -36,6 +37,9 @@ func NewAPIService() connectivity.Handler {
 				return nil, fmt.Errorf("api_fetch: config parse: %w", err)
 			}
 		}
+		cfg.OnSkip = func(index int, err error) {
+			slog.Warn("api_fetch: item skipped", "source_id", req.SourceID, "index", index, "error", err)
+		}
 
 		results, err := apifetch.Fetch(ctx, client, req.URL, cfg)
 		if err != nil {
@@ -52,7 +56,7 @@ func NewAPIService() connectivity.Handler {
 				Title:       r.Title,
 				Content:     r.Text,
 				URL:         url,
-				ContentHash: hashString(r.URL + "|" + r.Title),
+				ContentHash: apiContentHash(cfg, r),
 			})
 		}
 
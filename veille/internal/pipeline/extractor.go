@@ -0,0 +1,81 @@
+// CLAUDE:SUMMARY Extractor interface and registry: fetched bodies are extracted by the extractor registered for the source type, else for the response content type, else the built-in HTML extractor.
+package pipeline
+
+import (
+	"context"
+	"mime"
+	"strings"
+
+	"github.com/hazyhaar/chrc/extract"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// Extractor turns a fetched body into extracted content. The returned
+// extraction carries ContentHash, Title, ExtractedText (cleaned) and
+// optionally ExtractedHTML and MetadataJSON; the handler sets ID, source,
+// URL and timestamps. Empty ExtractedText means nothing to store.
+type Extractor interface {
+	Extract(ctx context.Context, raw []byte, contentType string) (*store.Extraction, error)
+}
+
+// ExtractorFunc adapts a plain function to Extractor.
+type ExtractorFunc func(ctx context.Context, raw []byte, contentType string) (*store.Extraction, error)
+
+// Extract implements Extractor.
+func (f ExtractorFunc) Extract(ctx context.Context, raw []byte, contentType string) (*store.Extraction, error) {
+	return f(ctx, raw, contentType)
+}
+
+// HTMLExtractor is the built-in extractor for web pages, including the pages
+// followed by rss and sitemap sources. The zero value uses extract mode "auto".
+type HTMLExtractor struct {
+	Options extract.Options
+}
+
+// Extract implements Extractor.
+func (x HTMLExtractor) Extract(_ context.Context, raw []byte, _ string) (*store.Extraction, error) {
+	res, err := extract.Extract(raw, x.Options)
+	if err != nil {
+		return nil, err
+	}
+	return &store.Extraction{
+		ContentHash:   res.Hash,
+		Title:         res.Title,
+		ExtractedText: extract.CleanText(res.Text),
+		ExtractedHTML: res.HTML,
+	}, nil
+}
+
+// RegisterExtractor registers x under key, a source type ("web", "rss") or a
+// media type ("application/pdf"). Registering an existing key replaces it.
+func (p *Pipeline) RegisterExtractor(key string, x Extractor) {
+	p.extractors[strings.ToLower(key)] = x
+}
+
+// extractorFor selects the extractor for a body fetched for sourceType with
+// the given Content-Type header: source type first, then media type, then
+// HTMLExtractor.
+func (p *Pipeline) extractorFor(sourceType, contentType string) Extractor {
+	if x, ok := p.extractors[strings.ToLower(sourceType)]; ok {
+		return x
+	}
+	if mt := mediaType(contentType); mt != "" {
+		if x, ok := p.extractors[mt]; ok {
+			return x
+		}
+	}
+	return HTMLExtractor{}
+}
+
+// mediaType returns the lowercased media type of a Content-Type header,
+// without parameters.
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt, _, _ = strings.Cut(contentType, ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mt))
+}
@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// fakeExtractor records its calls and returns a fixed text.
+type fakeExtractor struct {
+	text        string
+	calls       atomic.Int32
+	contentType atomic.Value
+}
+
+func (f *fakeExtractor) Extract(_ context.Context, raw []byte, contentType string) (*store.Extraction, error) {
+	f.calls.Add(1)
+	f.contentType.Store(contentType)
+	return &store.Extraction{ContentHash: hashString(string(raw)), Title: "Fake", ExtractedText: f.text}, nil
+}
+
+func TestExtractor_SelectedByContentType(t *testing.T) {
+	// WHAT: An extractor registered for a media type handles web pages served with that Content-Type.
+	// WHY: Third parties add formats (PDF, ...) without editing the pipeline.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.7 not really a pdf"))
+	}))
+	defer srv.Close()
+	s.InsertSource(ctx, &store.Source{ID: "src-pdf", Name: "PDF", URL: srv.URL, Enabled: true})
+
+	pdf := &fakeExtractor{text: "text extracted from the pdf document"}
+	p := New(fetch.New(fetch.Config{}), nil)
+	p.RegisterExtractor("Application/PDF", pdf)
+	if err := p.HandleJob(ctx, s, &Job{SourceID: "src-pdf", URL: srv.URL}); err != nil {
+		t.Fatalf("handle job: %v", err)
+	}
+
+	if n := pdf.calls.Load(); n != 1 {
+		t.Fatalf("extractor calls: got %d, want 1", n)
+	}
+	if ct, _ := pdf.contentType.Load().(string); ct != "application/pdf" {
+		t.Errorf("content type: got %q", ct)
+	}
+	exts, _ := s.ListExtractions(ctx, "src-pdf", 10)
+	if len(exts) != 1 || exts[0].ExtractedText != pdf.text || exts[0].Title != "Fake" {
+		t.Fatalf("extractions: got %+v", exts)
+	}
+}
+
+func TestExtractor_Selection(t *testing.T) {
+	// WHAT: Source type wins over media type; unmatched pages use HTMLExtractor.
+	// WHY: The lookup order is the contract documented for WithExtractor.
+	p := New(fetch.New(fetch.Config{}), nil)
+	byType := &fakeExtractor{}
+	byMedia := &fakeExtractor{}
+	p.RegisterExtractor("custom", byType)
+	p.RegisterExtractor("application/pdf", byMedia)
+
+	cases := []struct {
+		sourceType, contentType string
+		want                    *fakeExtractor // nil = HTMLExtractor
+	}{
+		{"custom", "application/pdf", byType},
+		{"web", "application/pdf; charset=binary", byMedia},
+		{"web", "text/html; charset=utf-8", nil},
+		{"web", "", nil},
+	}
+	for _, c := range cases {
+		got := p.extractorFor(c.sourceType, c.contentType)
+		if c.want == nil {
+			if _, ok := got.(HTMLExtractor); !ok {
+				t.Errorf("extractorFor(%q, %q) = %T, want HTMLExtractor", c.sourceType, c.contentType, got)
+			}
+			continue
+		}
+		if got != Extractor(c.want) {
+			t.Errorf("extractorFor(%q, %q) = %T, want the registered extractor", c.sourceType, c.contentType, got)
+		}
+	}
+}
@@ -281,7 +281,7 @@ func TestGitHubBridge_Pipeline(t *testing.T) {
 	p := New(f, nil)
 
 	bridge := NewConnectivityBridge(router, "github_fetch", "github")
-	p.currentJob = &Job{DossierID: "u1_s1", SourceID: "src-ghb", URL: src.URL}
+	ctx = withJob(ctx, &Job{DossierID: "u1_s1", SourceID: "src-ghb", URL: src.URL})
 
 	err := bridge.Handle(ctx, s, src, p)
 	if err != nil {
@@ -332,7 +332,7 @@ func TestGitHubBridge_Dedup(t *testing.T) {
 	p := New(f, nil)
 
 	bridge := NewConnectivityBridge(router, "github_fetch", "github")
-	p.currentJob = &Job{DossierID: "u1_s1", SourceID: "src-ghd"}
+	ctx = withJob(ctx, &Job{DossierID: "u1_s1", SourceID: "src-ghd"})
 
 	// First call.
 	bridge.Handle(ctx, s, src, p)
@@ -387,7 +387,7 @@ func TestGitHubBridge_Buffer(t *testing.T) {
 	p.SetBuffer(buffer.NewWriter(bufDir))
 
 	bridge := NewConnectivityBridge(router, "github_fetch", "github")
-	p.currentJob = &Job{DossierID: "u1_s1", SourceID: "src-ghbuf", URL: src.URL}
+	ctx = withJob(ctx, &Job{DossierID: "u1_s1", SourceID: "src-ghbuf", URL: src.URL})
 
 	err := bridge.Handle(ctx, s, src, p)
 	if err != nil {
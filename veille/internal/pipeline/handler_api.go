@@ -38,6 +38,9 @@ func (h *APIHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 			return fmt.Errorf("api config: %w", err)
 		}
 	}
+	cfg.OnSkip = func(index int, err error) {
+		log.Warn("api: item skipped", "index", index, "error", err)
+	}
 
 	logEntry := &store.FetchLogEntry{
 		ID:         p.newID(),
@@ -46,13 +49,17 @@ func (h *APIHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 		FetchedAt:  time.Now().UnixMilli(),
 	}
 
-	// Fetch from API.
-	results, err := apifetch.Fetch(ctx, h.client, src.URL, cfg)
+	// Fetch from API. The Fetcher's egress host lists apply here too.
+	var results []apifetch.Result
+	err := p.fetcher.CheckEgress(src.URL)
+	if err == nil {
+		results, err = apifetch.Fetch(ctx, h.client, src.URL, cfg)
+	}
 	duration := time.Since(start).Milliseconds()
 	logEntry.DurationMs = duration
 
 	if err != nil {
-		logEntry.Status = "error"
+		logEntry.Status = fetchErrorStatus(err)
 		logEntry.ErrorMessage = err.Error()
 		_ = s.InsertFetchLog(ctx, logEntry)
 		_ = s.RecordFetchError(ctx, src.ID, err.Error())
@@ -68,7 +75,7 @@ func (h *APIHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 			continue
 		}
 
-		contentHash := hashString(r.URL + "|" + r.Title)
+		contentHash := apiContentHash(cfg, r)
 
 		// Dedup check.
 		exists, err := s.ExtractionExists(ctx, src.ID, contentHash)
@@ -97,17 +104,19 @@ func (h *APIHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 			URL:           url,
 			ExtractedAt:   now,
 		}
-		if err := s.InsertExtraction(ctx, extraction); err != nil {
+		p.PostProcess(ctx, extraction)
+		if err := s.InsertExtractionDedup(ctx, p.content, extraction); err != nil {
 			log.Warn("api: insert extraction failed", "error", err)
 			continue
 		}
+		p.extractionStored(ctx, s, src, extraction)
 
 		// Write to buffer.
-		if p.buffer != nil && p.currentJob != nil {
+		if p.buffer != nil && jobFrom(ctx) != nil {
 			meta := buffer.Metadata{
 				ID:          extractionID,
 				SourceID:    src.ID,
-				DossierID:   p.currentJob.DossierID,
+				DossierID:   jobFrom(ctx).DossierID,
 				SourceURL:   url,
 				SourceType:  "api",
 				Title:       r.Title,
@@ -130,3 +139,14 @@ func (h *APIHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 
 	return nil
 }
+
+// apiContentHash derives the dedup hash of an API result. JSONPath-mapped
+// sources hash every mapped field so that an item whose body changes under
+// the same URL and title is stored again; legacy result_path sources keep
+// the URL|title hash so existing extractions still dedup.
+func apiContentHash(cfg apifetch.Config, r apifetch.Result) string {
+	if cfg.JSONPathMode() {
+		return hashString(r.URL + "|" + r.Title + "|" + r.Text)
+	}
+	return hashString(r.URL + "|" + r.Title)
+}
@@ -124,3 +124,59 @@ func TestAPI_WritesBuffer(t *testing.T) {
 		t.Errorf("buffer .md files: got %d, want 1", mdCount)
 	}
 }
+
+func TestAPI_JSONPathMapping(t *testing.T) {
+	// WHAT: item_path/title_path/url_path/body_path map data.items[*] to extractions.
+	// WHY: API sources declare their field mapping instead of needing custom code.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	body := "First summary, long enough to keep"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {
+				"items": [
+					{"attributes": {"headline": "First"}, "links": {"self": "https://example.com/1"}, "summary": "` + body + `"},
+					{"attributes": {"headline": "Second"}, "links": {"self": "https://example.com/2"}, "summary": "Second summary text"},
+					{"attributes": {"headline": "Broken"}, "summary": "No link on this one"}
+				]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	s.InsertSource(ctx, &store.Source{
+		ID: "src-jp", Name: "API JSONPath", URL: srv.URL,
+		SourceType: "api", Enabled: true,
+		ConfigJSON: `{"item_path":"$.data.items[*]","title_path":"$.attributes.headline","url_path":"$.links.self","body_path":"$.summary"}`,
+	})
+
+	f := fetch.New(fetch.Config{})
+	p := New(f, nil)
+	p.RegisterHandler("api", NewAPIHandler())
+	job := &Job{DossierID: "u_sp", SourceID: "src-jp", URL: srv.URL}
+
+	if err := p.HandleJob(ctx, s, job); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	exts, _ := s.ListExtractions(ctx, "src-jp", 10)
+	if len(exts) != 2 {
+		t.Fatalf("extractions: got %d, want 2 (item without url skipped)", len(exts))
+	}
+
+	// Same payload again: the mapped-field hash dedups.
+	p.HandleJob(ctx, s, job)
+	exts, _ = s.ListExtractions(ctx, "src-jp", 10)
+	if len(exts) != 2 {
+		t.Errorf("extractions after refetch: got %d, want 2", len(exts))
+	}
+
+	// A changed body under the same URL and title is a new extraction.
+	body = "First summary, now revised"
+	p.HandleJob(ctx, s, job)
+	exts, _ = s.ListExtractions(ctx, "src-jp", 10)
+	if len(exts) != 3 {
+		t.Errorf("extractions after body change: got %d, want 3", len(exts))
+	}
+}
@@ -143,17 +143,19 @@ func (b *ConnectivityBridge) Handle(ctx context.Context, s *store.Store, src *st
 			URL:           url,
 			ExtractedAt:   now,
 		}
-		if err := s.InsertExtraction(ctx, extraction); err != nil {
+		p.PostProcess(ctx, extraction)
+		if err := s.InsertExtractionDedup(ctx, p.content, extraction); err != nil {
 			log.Warn("connectivity: insert extraction failed", "error", err)
 			continue
 		}
+		p.extractionStored(ctx, s, src, extraction)
 
 		// Buffer write.
-		if p.buffer != nil && p.currentJob != nil {
+		if p.buffer != nil && jobFrom(ctx) != nil {
 			meta := buffer.Metadata{
 				ID:          extractionID,
 				SourceID:    src.ID,
-				DossierID:   p.currentJob.DossierID,
+				DossierID:   jobFrom(ctx).DossierID,
 				SourceURL:   url,
 				SourceType:  b.sourceType,
 				Title:       ext.Title,
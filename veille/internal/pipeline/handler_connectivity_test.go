@@ -40,7 +40,7 @@ func TestConnectivityBridge_CallsRouter(t *testing.T) {
 	p := New(f, nil)
 
 	bridge := NewConnectivityBridge(router, "socmed_fetch", "socmed")
-	p.currentJob = &Job{DossierID: "u1_s1", SourceID: "src-socmed", URL: src.URL}
+	ctx = withJob(ctx, &Job{DossierID: "u1_s1", SourceID: "src-socmed", URL: src.URL})
 
 	err := bridge.Handle(ctx, s, src, p)
 	if err != nil {
@@ -94,7 +94,7 @@ func TestConnectivityBridge_Dedup(t *testing.T) {
 	p := New(f, nil)
 
 	bridge := NewConnectivityBridge(router, "test_fetch", "test")
-	p.currentJob = &Job{DossierID: "u1_s1", SourceID: "src-dedup"}
+	ctx = withJob(ctx, &Job{DossierID: "u1_s1", SourceID: "src-dedup"})
 
 	// First call.
 	bridge.Handle(ctx, s, src, p)
@@ -93,16 +93,18 @@ func (h *DocumentHandler) Handle(ctx context.Context, s *store.Store, src *store
 		URL:           src.URL,
 		ExtractedAt:   now,
 	}
-	if err := s.InsertExtraction(ctx, extraction); err != nil {
+	p.PostProcess(ctx, extraction)
+	if err := s.InsertExtractionDedup(ctx, p.content, extraction); err != nil {
 		return fmt.Errorf("store extraction: %w", err)
 	}
+	p.extractionStored(ctx, s, src, extraction)
 
 	// Write to buffer.
-	if p.buffer != nil && p.currentJob != nil {
+	if p.buffer != nil && jobFrom(ctx) != nil {
 		meta := buffer.Metadata{
 			ID:          extractionID,
 			SourceID:    src.ID,
-			DossierID:   p.currentJob.DossierID,
+			DossierID:   jobFrom(ctx).DossierID,
 			SourceURL:   src.URL,
 			SourceType:  "document",
 			Title:       doc.Title,
@@ -54,8 +54,8 @@ func (h *QuestionHandler) Handle(ctx context.Context, s *store.Store, src *store
 
 	// Determine dossier from current job.
 	var dossierID string
-	if p.currentJob != nil {
-		dossierID = p.currentJob.DossierID
+	if job := jobFrom(ctx); job != nil {
+		dossierID = job.DossierID
 	}
 
 	// Run the question.
@@ -81,6 +81,7 @@ func (h *QuestionHandler) Handle(ctx context.Context, s *store.Store, src *store
 	logEntry.Status = "ok"
 	_ = s.InsertFetchLog(ctx, logEntry)
 	_ = s.RecordFetchSuccess(ctx, src.ID, "")
+	p.countExtractions(src, newCount)
 
 	log.Info("question: handler complete", "new", newCount, "duration_ms", duration)
 	return nil
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hazyhaar/chrc/extract"
@@ -16,8 +17,36 @@ import (
 
 // RSSConfig is parsed from source.config_json for RSS sources.
 type RSSConfig struct {
-	MaxEntries  int  `json:"max_entries"`
-	FollowLinks bool `json:"follow_links"`
+	MaxEntries  int    `json:"max_entries"`
+	FollowLinks bool   `json:"follow_links"`
+	TitleField  string `json:"title_field"` // entry field indexed as title (default: "title")
+}
+
+// rssTitleFields maps title_field values to the feed entry field they read.
+var rssTitleFields = map[string]func(feed.Entry) string{
+	"title":       func(e feed.Entry) string { return e.Title },
+	"description": func(e feed.Entry) string { return e.Description },
+	"author":      func(e feed.Entry) string { return e.Author },
+	"link":        func(e feed.Entry) string { return e.Link },
+}
+
+// ValidRSSTitleField reports whether name is an accepted title_field for RSS sources.
+func ValidRSSTitleField(name string) bool {
+	_, ok := rssTitleFields[name]
+	return ok
+}
+
+// entryTitle returns the entry field selected by field, HTML-stripped.
+// Falls back to the entry title when the field is unknown or empty.
+func entryTitle(e feed.Entry, field string) string {
+	get, ok := rssTitleFields[field]
+	if !ok {
+		return e.Title
+	}
+	if v := strings.TrimSpace(stripAllHTML(get(e))); v != "" {
+		return v
+	}
+	return e.Title
 }
 
 // RSSHandler handles RSS/Atom feed sources.
@@ -38,7 +67,7 @@ func (h *RSSHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 	}
 
 	// Fetch the feed XML.
-	result, err := p.fetcher.Fetch(ctx, src.URL, "", "", "")
+	result, err := p.fetcher.Fetch(ctx, src.URL, "", "", src.LastHash, requestOptions(src))
 	duration := time.Since(start).Milliseconds()
 
 	logEntry := &store.FetchLogEntry{
@@ -49,7 +78,7 @@ func (h *RSSHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 	}
 
 	if err != nil {
-		logEntry.Status = "error"
+		logEntry.Status = fetchErrorStatus(err)
 		logEntry.ErrorMessage = err.Error()
 		if result != nil {
 			logEntry.StatusCode = result.StatusCode
@@ -63,6 +92,18 @@ func (h *RSSHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 	logEntry.StatusCode = result.StatusCode
 	logEntry.ContentHash = result.Hash
 
+	if !result.Changed {
+		logEntry.Status = "unchanged"
+		_ = s.InsertFetchLog(ctx, logEntry)
+		_ = s.RecordFetchUnchanged(ctx, src.ID)
+		log.Debug("rss: feed unchanged", "duration_ms", duration)
+		return nil
+	}
+
+	if handled, err := p.checkSourceType(ctx, s, src, result, logEntry); handled {
+		return err
+	}
+
 	// Parse the feed.
 	f, err := feed.Parse(result.Body)
 	if err != nil {
@@ -111,10 +152,10 @@ func (h *RSSHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 		if cfg.FollowLinks && entry.Link != "" {
 			pageResult, fetchErr := p.fetcher.Fetch(ctx, entry.Link, "", "", "")
 			if fetchErr == nil && pageResult.Changed {
-				extractResult, extractErr := extract.Extract(pageResult.Body, extract.Options{Mode: "auto"})
-				if extractErr == nil && extractResult.Text != "" {
-					text = extract.CleanText(extractResult.Text)
-					extractedHTML = extractResult.HTML
+				extracted, extractErr := p.extractorFor(src.SourceType, pageResult.ContentType).Extract(ctx, pageResult.Body, pageResult.ContentType)
+				if extractErr == nil && extracted.ExtractedText != "" {
+					text = extracted.ExtractedText
+					extractedHTML = extracted.ExtractedHTML
 					followedURL = entry.Link
 				}
 			}
@@ -128,7 +169,7 @@ func (h *RSSHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 		now := time.Now().UnixMilli()
 		extractionID := p.newID()
 
-		title := entry.Title
+		title := entryTitle(entry, cfg.TitleField)
 		url := entry.Link
 		if url == "" {
 			url = src.URL
@@ -144,13 +185,15 @@ func (h *RSSHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 			URL:           url,
 			ExtractedAt:   now,
 		}
-		if err := s.InsertExtraction(ctx, extraction); err != nil {
+		p.PostProcess(ctx, extraction)
+		if err := s.InsertExtractionDedup(ctx, p.content, extraction); err != nil {
 			log.Warn("rss: insert extraction failed", "error", err, "guid", entry.GUID)
 			continue
 		}
+		p.extractionStored(ctx, s, src, extraction)
 
 		// Write to buffer (markdown if HTML available, plain text fallback).
-		if p.buffer != nil && p.currentJob != nil {
+		if p.buffer != nil && jobFrom(ctx) != nil {
 			var bufferText string
 			if extractedHTML != "" {
 				bufferText = p.htmlToMarkdown(extractedHTML, followedURL, text)
@@ -165,7 +208,7 @@ func (h *RSSHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 			meta := buffer.Metadata{
 				ID:          extractionID,
 				SourceID:    src.ID,
-				DossierID:   p.currentJob.DossierID,
+				DossierID:   jobFrom(ctx).DossierID,
 				SourceURL:   url,
 				SourceType:  "rss",
 				Title:       title,
@@ -196,3 +196,65 @@ func TestRSS_WritesBuffer(t *testing.T) {
 		t.Errorf("buffer .md files: got %d, want 2", mdCount)
 	}
 }
+
+const testRSSIssues = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Issues</title>
+    <link>https://issues.example.com</link>
+    <item>
+      <guid>issue-42</guid>
+      <title>Issue #42</title>
+      <link>https://issues.example.com/42</link>
+      <description>Kubernetes scheduler starvation</description>
+      <content:encoded>A long discussion thread covering many unrelated topics such as logging, release notes, packaging, documentation fixes, flaky tests and finally a short mention of kubernetes near the end of the body.</content:encoded>
+    </item>
+  </channel>
+</rss>`
+
+func TestRSS_TitleFieldImprovesRanking(t *testing.T) {
+	// WHAT: A source mapping description to title ranks above the same entry indexed with its generic title.
+	// WHY: Some feeds carry the meaningful label outside <title>; indexing it as title improves relevance.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(testRSSIssues))
+	}))
+	defer srv.Close()
+
+	s.InsertSource(ctx, &store.Source{
+		ID: "src-plain", Name: "Plain", URL: srv.URL,
+		SourceType: "rss", Enabled: true,
+	})
+	s.InsertSource(ctx, &store.Source{
+		ID: "src-mapped", Name: "Mapped", URL: srv.URL + "/mapped",
+		SourceType: "rss", Enabled: true, ConfigJSON: `{"title_field":"description"}`,
+	})
+
+	f := fetch.New(fetch.Config{URLValidator: func(string) error { return nil }})
+	p := New(f, nil)
+	for _, id := range []string{"src-plain", "src-mapped"} {
+		if err := p.HandleJob(ctx, s, &Job{DossierID: "u_sp", SourceID: id}); err != nil {
+			t.Fatalf("handle %s: %v", id, err)
+		}
+	}
+
+	exts, _ := s.ListExtractions(ctx, "src-mapped", 10)
+	if len(exts) != 1 || exts[0].Title != "Kubernetes scheduler starvation" {
+		t.Fatalf("mapped title: got %+v", exts)
+	}
+
+	results, err := s.Search(ctx, "kubernetes", 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results: got %d, want 2", len(results))
+	}
+	if results[0].SourceID != "src-mapped" {
+		t.Errorf("top result: got %s, want src-mapped", results[0].SourceID)
+	}
+}
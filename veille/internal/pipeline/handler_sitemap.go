@@ -0,0 +1,263 @@
+// CLAUDE:SUMMARY Pipeline handler for sitemap source type: walks sitemap(index).xml, fetches each <loc>, lastmod dedup, extract, store.
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/buffer"
+	"github.com/hazyhaar/chrc/veille/internal/sitemap"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// MaxSitemapURLs is the upper bound for SitemapConfig.MaxURLs.
+const MaxSitemapURLs = 1000
+
+const (
+	defaultSitemapMaxURLs = 100
+	// maxSitemapDepth bounds index nesting: the source sitemap is depth 0.
+	maxSitemapDepth = 2
+)
+
+// SitemapConfig is parsed from source.config_json for sitemap sources.
+type SitemapConfig struct {
+	MaxURLs int `json:"max_urls"` // page URLs considered per fetch (default 100, max 1000)
+}
+
+// SitemapHandler handles sitemap.xml sources: every page listed in the
+// sitemap (or in the child sitemaps of a sitemap index) is fetched and
+// stored as an extraction of the source.
+type SitemapHandler struct{}
+
+// Handle fetches the sitemap, expands indexes, and extracts each listed page.
+// Pages whose <lastmod> is unchanged since the last extraction are not
+// refetched; pages without <lastmod> are refetched and deduped on their body.
+func (h *SitemapHandler) Handle(ctx context.Context, s *store.Store, src *store.Source, p *Pipeline) error {
+	log := p.logger.With("source_id", src.ID, "url", src.URL, "handler", "sitemap")
+	start := time.Now()
+
+	cfg := SitemapConfig{MaxURLs: defaultSitemapMaxURLs}
+	if src.ConfigJSON != "" && src.ConfigJSON != "{}" {
+		_ = json.Unmarshal([]byte(src.ConfigJSON), &cfg)
+	}
+	if cfg.MaxURLs <= 0 {
+		cfg.MaxURLs = defaultSitemapMaxURLs
+	}
+	cfg.MaxURLs = min(cfg.MaxURLs, MaxSitemapURLs)
+
+	// Fetch the root sitemap.
+	result, err := p.fetcher.Fetch(ctx, src.URL, "", "", "", requestOptions(src))
+	duration := time.Since(start).Milliseconds()
+
+	logEntry := &store.FetchLogEntry{
+		ID:         p.newID(),
+		SourceID:   src.ID,
+		DurationMs: duration,
+		FetchedAt:  time.Now().UnixMilli(),
+	}
+
+	if err != nil {
+		logEntry.Status = fetchErrorStatus(err)
+		logEntry.ErrorMessage = err.Error()
+		if result != nil {
+			logEntry.StatusCode = result.StatusCode
+		}
+		_ = s.InsertFetchLog(ctx, logEntry)
+		_ = s.RecordFetchError(ctx, src.ID, err.Error())
+		log.Warn("sitemap: fetch failed", "error", err)
+		return fmt.Errorf("sitemap fetch: %w", err)
+	}
+
+	logEntry.StatusCode = result.StatusCode
+	logEntry.ContentHash = result.Hash
+
+	if handled, err := p.checkSourceType(ctx, s, src, result, logEntry); handled {
+		return err
+	}
+
+	root, err := sitemap.Parse(result.Body)
+	if err != nil {
+		logEntry.Status = "extract_error"
+		logEntry.ErrorMessage = err.Error()
+		_ = s.InsertFetchLog(ctx, logEntry)
+		_ = s.RecordFetchError(ctx, src.ID, "parse: "+err.Error())
+		log.Warn("sitemap: parse failed", "error", err)
+		return fmt.Errorf("sitemap parse: %w", err)
+	}
+
+	pages := h.collect(ctx, p, src, root, cfg.MaxURLs, log)
+
+	var newCount int
+	for _, page := range pages {
+		if ctx.Err() != nil {
+			break
+		}
+		if h.handlePage(ctx, s, src, p, page, log) {
+			newCount++
+		}
+	}
+
+	logEntry.Status = "ok"
+	_ = s.InsertFetchLog(ctx, logEntry)
+	_ = s.RecordFetchSuccess(ctx, src.ID, result.Hash)
+
+	log.Info("sitemap: processed", "urls", len(pages), "new", newCount,
+		"duration_ms", time.Since(start).Milliseconds())
+
+	return nil
+}
+
+// collect gathers up to limit page entries from root. Child sitemaps of an
+// index are fetched breadth-first, and only while the limit is not reached.
+// URLs that fail SSRF validation and child sitemaps that cannot be fetched
+// or parsed are skipped and logged.
+func (h *SitemapHandler) collect(ctx context.Context, p *Pipeline, src *store.Source, root *sitemap.Sitemap, limit int, log *slog.Logger) []sitemap.Entry {
+	var pages []sitemap.Entry
+	seen := map[string]bool{src.URL: true}
+
+	type child struct {
+		loc   string
+		depth int
+	}
+	var queue []child
+
+	add := func(sm *sitemap.Sitemap, depth int) {
+		for _, e := range sm.URLs {
+			if len(pages) >= limit {
+				log.Info("sitemap: max_urls reached", "max_urls", limit)
+				return
+			}
+			if seen[e.Loc] {
+				continue
+			}
+			seen[e.Loc] = true
+			if err := p.fetcher.ValidateURL(e.Loc); err != nil {
+				log.Warn("sitemap: url blocked", "loc", e.Loc, "error", err)
+				continue
+			}
+			pages = append(pages, e)
+		}
+		for _, e := range sm.Sitemaps {
+			if depth+1 > maxSitemapDepth {
+				log.Warn("sitemap: index nested too deep, skipping", "loc", e.Loc)
+				continue
+			}
+			if seen[e.Loc] {
+				continue
+			}
+			seen[e.Loc] = true
+			if err := p.fetcher.ValidateURL(e.Loc); err != nil {
+				log.Warn("sitemap: child sitemap blocked", "loc", e.Loc, "error", err)
+				continue
+			}
+			queue = append(queue, child{e.Loc, depth + 1})
+		}
+	}
+
+	add(root, 0)
+	for len(queue) > 0 && len(pages) < limit && ctx.Err() == nil {
+		c := queue[0]
+		queue = queue[1:]
+		res, err := p.fetcher.Fetch(ctx, c.loc, "", "", "", requestOptions(src))
+		if err != nil {
+			log.Warn("sitemap: child sitemap fetch failed", "loc", c.loc, "error", err)
+			continue
+		}
+		sm, err := sitemap.Parse(res.Body)
+		if err != nil {
+			log.Warn("sitemap: child sitemap parse failed", "loc", c.loc, "error", err)
+			continue
+		}
+		add(sm, c.depth)
+	}
+	return pages
+}
+
+// handlePage fetches, extracts and stores one sitemap page. It reports
+// whether a new extraction was stored.
+func (h *SitemapHandler) handlePage(ctx context.Context, s *store.Store, src *store.Source, p *Pipeline, page sitemap.Entry, log *slog.Logger) bool {
+	log = log.With("loc", page.Loc)
+
+	// With <lastmod>, loc+lastmod identifies the page version: skip the
+	// fetch entirely when it was already extracted.
+	var contentHash string
+	if page.LastMod != "" {
+		contentHash = hashString(page.Loc + "|" + page.LastMod)
+		exists, err := s.ExtractionExists(ctx, src.ID, contentHash)
+		if err != nil {
+			log.Warn("sitemap: dedup check failed", "error", err)
+			return false
+		}
+		if exists {
+			return false
+		}
+	}
+
+	res, err := p.fetcher.Fetch(ctx, page.Loc, "", "", "", requestOptions(src))
+	if err != nil {
+		log.Warn("sitemap: page fetch failed", "error", err)
+		return false
+	}
+
+	if contentHash == "" {
+		contentHash = hashString(page.Loc + "|" + res.Hash)
+		exists, err := s.ExtractionExists(ctx, src.ID, contentHash)
+		if err != nil {
+			log.Warn("sitemap: dedup check failed", "error", err)
+			return false
+		}
+		if exists {
+			return false
+		}
+	}
+
+	extracted, err := p.extractorFor(src.SourceType, res.ContentType).Extract(ctx, res.Body, res.ContentType)
+	if err != nil {
+		log.Warn("sitemap: extraction failed", "error", err)
+		return false
+	}
+	text := extracted.ExtractedText
+	if text == "" {
+		return false
+	}
+
+	extractionID := p.newID()
+	extraction := &store.Extraction{
+		ID:            extractionID,
+		SourceID:      src.ID,
+		ContentHash:   contentHash,
+		Title:         extracted.Title,
+		ExtractedText: text,
+		ExtractedHTML: extracted.ExtractedHTML,
+		MetadataJSON:  extracted.MetadataJSON,
+		URL:           page.Loc,
+		ExtractedAt:   time.Now().UnixMilli(),
+	}
+	p.PostProcess(ctx, extraction)
+	if err := s.InsertExtractionDedup(ctx, p.content, extraction); err != nil {
+		log.Warn("sitemap: insert extraction failed", "error", err)
+		return false
+	}
+	p.extractionStored(ctx, s, src, extraction)
+
+	if p.buffer != nil && jobFrom(ctx) != nil {
+		meta := buffer.Metadata{
+			ID:          extractionID,
+			SourceID:    src.ID,
+			DossierID:   jobFrom(ctx).DossierID,
+			SourceURL:   page.Loc,
+			SourceType:  "sitemap",
+			Title:       extracted.Title,
+			ContentHash: contentHash,
+			ExtractedAt: time.Now().UTC(),
+		}
+		bufferText := p.htmlToMarkdown(extracted.ExtractedHTML, page.Loc, text)
+		if _, err := p.buffer.Write(ctx, meta, bufferText); err != nil {
+			log.Warn("sitemap: buffer write failed", "error", err)
+		}
+	}
+	return true
+}
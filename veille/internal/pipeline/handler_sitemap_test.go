@@ -0,0 +1,143 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// newSitemapServer serves a sitemap index at / pointing to two child
+// sitemaps, one of which lists a URL blocked by the test validator.
+// Page fetches are counted per path.
+func newSitemapServer(t *testing.T) (*httptest.Server, func(string) int) {
+	t.Helper()
+	var mu sync.Mutex
+	hits := map[string]int{}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		base := srv.URL
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/sitemap-posts.xml</loc></sitemap>
+  <sitemap><loc>%[1]s/sitemap-pages.xml</loc></sitemap>
+</sitemapindex>`, base)
+		case "/sitemap-posts.xml":
+			fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/posts/1</loc><lastmod>2026-02-01</lastmod></url>
+  <url><loc>%[1]s/posts/2</loc><lastmod>2026-02-02</lastmod></url>
+</urlset>`, base)
+		case "/sitemap-pages.xml":
+			fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/about</loc></url>
+  <url><loc>http://blocked.internal/admin</loc></url>
+</urlset>`, base)
+		default:
+			fmt.Fprintf(w, `<html><head><title>Page %[1]s</title></head><body><article>
+<p>Long enough article body for %[1]s so the extractor keeps it as real content.</p>
+</article></body></html>`, r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits[path]
+	}
+}
+
+func sitemapTestFetcher() *fetch.Fetcher {
+	return fetch.New(fetch.Config{URLValidator: func(u string) error {
+		if strings.Contains(u, "blocked.internal") {
+			return fmt.Errorf("private host")
+		}
+		return nil
+	}})
+}
+
+func TestSitemap_NestedIndexExtractsChildURLs(t *testing.T) {
+	// WHAT: A sitemap index is expanded and each child <loc> becomes an extraction.
+	// WHY: Users monitor a whole site by pointing at its sitemap.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	srv, hits := newSitemapServer(t)
+	s.InsertSource(ctx, &store.Source{
+		ID: "src-sm", Name: "Sitemap", URL: srv.URL + "/",
+		SourceType: "sitemap", Enabled: true,
+	})
+
+	p := New(sitemapTestFetcher(), nil)
+	job := &Job{DossierID: "u_sp", SourceID: "src-sm", URL: srv.URL + "/"}
+	if err := p.HandleJob(ctx, s, job); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	exts, _ := s.ListExtractions(ctx, "src-sm", 10)
+	if len(exts) != 3 {
+		t.Fatalf("extractions: got %d, want 3 (blocked url skipped)", len(exts))
+	}
+	urls := map[string]bool{}
+	for _, e := range exts {
+		urls[e.URL] = true
+	}
+	for _, path := range []string{"/posts/1", "/posts/2", "/about"} {
+		if !urls[srv.URL+path] {
+			t.Errorf("missing extraction for %s", path)
+		}
+	}
+
+	// Second run: pages with an unchanged <lastmod> are not refetched,
+	// the page without one is refetched but deduped on its body.
+	if err := p.HandleJob(ctx, s, job); err != nil {
+		t.Fatalf("handle again: %v", err)
+	}
+	if n := hits("/posts/1"); n != 1 {
+		t.Errorf("/posts/1 fetched %d times, want 1 (lastmod unchanged)", n)
+	}
+	if n := hits("/about"); n != 2 {
+		t.Errorf("/about fetched %d times, want 2", n)
+	}
+	exts, _ = s.ListExtractions(ctx, "src-sm", 10)
+	if len(exts) != 3 {
+		t.Errorf("extractions after refetch: got %d, want 3", len(exts))
+	}
+}
+
+func TestSitemap_MaxURLs(t *testing.T) {
+	// WHAT: max_urls caps the pages fetched per run.
+	// WHY: A large sitemap must not expand into thousands of fetches.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	srv, hits := newSitemapServer(t)
+	s.InsertSource(ctx, &store.Source{
+		ID: "src-smcap", Name: "Sitemap capped", URL: srv.URL + "/",
+		SourceType: "sitemap", Enabled: true, ConfigJSON: `{"max_urls":1}`,
+	})
+
+	p := New(sitemapTestFetcher(), nil)
+	if err := p.HandleJob(ctx, s, &Job{DossierID: "u_sp", SourceID: "src-smcap"}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	exts, _ := s.ListExtractions(ctx, "src-smcap", 10)
+	if len(exts) != 1 {
+		t.Errorf("extractions: got %d, want 1", len(exts))
+	}
+	if n := hits("/sitemap-pages.xml"); n != 0 {
+		t.Errorf("second child sitemap fetched %d times after cap reached", n)
+	}
+}
@@ -1,16 +1,29 @@
-// CLAUDE:SUMMARY Pipeline handler for web source type: HTTP fetch, HTML extract, dedup, store.
+// CLAUDE:SUMMARY Pipeline handler for web source type: HTTP fetch, extract via the extractor registry, dedup, store, outbound links and bounded link following (follow_depth).
 package pipeline
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
 	"time"
 
-	"github.com/hazyhaar/chrc/extract"
 	"github.com/hazyhaar/chrc/veille/internal/buffer"
+	"github.com/hazyhaar/chrc/veille/internal/crawl"
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
 	"github.com/hazyhaar/chrc/veille/internal/store"
 )
 
+// WebConfig is parsed from source.config_json for web sources.
+type WebConfig struct {
+	// FollowDepth is how many link hops are followed from the page
+	// (0 = the page only, at most crawl.MaxDepth). Followed pages are stored
+	// as extractions of the same source.
+	FollowDepth int `json:"follow_depth"`
+}
+
 // WebHandler handles web (HTTP GET) sources.
 type WebHandler struct{}
 
@@ -20,7 +33,7 @@ func (h *WebHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 	start := time.Now()
 
 	// Fetch with conditional GET.
-	result, err := p.fetcher.Fetch(ctx, src.URL, "", "", src.LastHash)
+	result, err := p.fetcher.Fetch(ctx, src.URL, "", "", src.LastHash, requestOptions(src))
 	duration := time.Since(start).Milliseconds()
 
 	logEntry := &store.FetchLogEntry{
@@ -31,7 +44,7 @@ func (h *WebHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 	}
 
 	if err != nil {
-		logEntry.Status = "error"
+		logEntry.Status = fetchErrorStatus(err)
 		logEntry.ErrorMessage = err.Error()
 		if result != nil {
 			logEntry.StatusCode = result.StatusCode
@@ -53,8 +66,12 @@ func (h *WebHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 		return nil
 	}
 
-	// Extract content.
-	extractResult, err := extract.Extract(result.Body, extract.Options{Mode: "auto"})
+	if handled, err := p.checkSourceType(ctx, s, src, result, logEntry); handled {
+		return err
+	}
+
+	// Extract content with the extractor registered for the source or content type.
+	extracted, err := p.extractorFor(src.SourceType, result.ContentType).Extract(ctx, result.Body, result.ContentType)
 	if err != nil {
 		logEntry.Status = "extract_error"
 		logEntry.ErrorMessage = err.Error()
@@ -64,7 +81,7 @@ func (h *WebHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 		return fmt.Errorf("extract: %w", err)
 	}
 
-	cleanText := extract.CleanText(extractResult.Text)
+	cleanText := extracted.ExtractedText
 	if cleanText == "" {
 		logEntry.Status = "empty"
 		_ = s.InsertFetchLog(ctx, logEntry)
@@ -80,40 +97,125 @@ func (h *WebHandler) Handle(ctx context.Context, s *store.Store, src *store.Sour
 	extraction := &store.Extraction{
 		ID:            extractionID,
 		SourceID:      src.ID,
-		ContentHash:   extractResult.Hash,
-		Title:         extractResult.Title,
+		ContentHash:   extracted.ContentHash,
+		Title:         extracted.Title,
 		ExtractedText: cleanText,
-		ExtractedHTML: extractResult.HTML,
+		ExtractedHTML: extracted.ExtractedHTML,
+		MetadataJSON:  extracted.MetadataJSON,
 		URL:           src.URL,
 		ExtractedAt:   now,
 	}
-	if err := s.InsertExtraction(ctx, extraction); err != nil {
+	p.PostProcess(ctx, extraction)
+	if err := s.InsertExtractionDedup(ctx, p.content, extraction); err != nil {
 		return fmt.Errorf("store extraction: %w", err)
 	}
-
-	// Write to buffer if configured.
-	if p.buffer != nil {
-		meta := buffer.Metadata{
-			ID:          extractionID,
-			SourceID:    src.ID,
-			DossierID:   p.currentJob.DossierID,
-			SourceURL:   src.URL,
-			SourceType:  src.SourceType,
-			Title:       extractResult.Title,
-			ContentHash: extractResult.Hash,
-			ExtractedAt: time.Now().UTC(),
-		}
-		bufferText := p.htmlToMarkdown(extractResult.HTML, src.URL, cleanText)
-		if _, err := p.buffer.Write(ctx, meta, bufferText); err != nil {
-			log.Warn("web: buffer write failed", "error", err)
-		}
+	p.extractionStored(ctx, s, src, extraction)
+	p.keepRawBody(ctx, s, extractionID, src.ID, result.ContentType, result.Body)
+	links := crawl.Links(extracted.ExtractedHTML, src.URL, crawl.MaxLinksPerPage)
+	if err := s.InsertLinks(ctx, extractionID, links); err != nil {
+		log.Warn("web: store links failed", "error", err)
 	}
+	p.writeWebBuffer(ctx, log, src, extraction, extracted.ExtractedHTML)
 
 	logEntry.Status = "ok"
 	_ = s.InsertFetchLog(ctx, logEntry)
 	_ = s.RecordFetchSuccess(ctx, src.ID, result.Hash)
 
-	log.Info("web: processed", "text_len", len(cleanText), "duration_ms", duration)
+	var followed int
+	if depth := webFollowDepth(src); depth > 0 && len(links) > 0 {
+		w := crawl.NewWalker(p.follow, func(ctx context.Context, u string, _ int) ([]string, error) {
+			return h.followPage(ctx, s, src, p, u)
+		})
+		followed = w.Follow(ctx, src.URL, links, depth)
+	}
+
+	log.Info("web: processed", "text_len", len(cleanText), "links", len(links), "followed", followed, "duration_ms", duration)
 
 	return nil
 }
+
+// webFollowDepth returns the follow_depth of src's config_json, capped by
+// crawl.MaxDepth.
+func webFollowDepth(src *store.Source) int {
+	var cfg WebConfig
+	if src.ConfigJSON == "" || json.Unmarshal([]byte(src.ConfigJSON), &cfg) != nil {
+		return 0
+	}
+	return min(max(cfg.FollowDepth, 0), crawl.MaxDepth)
+}
+
+// followPage fetches and extracts a page linked from src and stores it as an
+// extraction of src unless its content is already there. Returns the page's
+// outbound links. Source headers and basic auth are only sent to src's host.
+func (h *WebHandler) followPage(ctx context.Context, s *store.Store, src *store.Source, p *Pipeline, pageURL string) ([]string, error) {
+	log := p.logger.With("source_id", src.ID, "url", pageURL, "handler", "web")
+	opts := fetch.RequestOptions{SourceType: src.SourceType}
+	if sameHost(pageURL, src.URL) {
+		opts = requestOptions(src)
+	}
+	result, err := p.fetcher.Fetch(ctx, pageURL, "", "", "", opts)
+	if err != nil {
+		log.Debug("web: follow fetch failed", "error", err)
+		return nil, err
+	}
+	extracted, err := p.extractorFor(src.SourceType, result.ContentType).Extract(ctx, result.Body, result.ContentType)
+	if err != nil {
+		log.Debug("web: follow extraction failed", "error", err)
+		return nil, err
+	}
+	links := crawl.Links(extracted.ExtractedHTML, pageURL, crawl.MaxLinksPerPage)
+	if extracted.ExtractedText == "" {
+		return links, nil
+	}
+	if dup, err := s.ExtractionExists(ctx, src.ID, extracted.ContentHash); err != nil || dup {
+		return links, err
+	}
+	extraction := &store.Extraction{
+		ID:            p.newID(),
+		SourceID:      src.ID,
+		ContentHash:   extracted.ContentHash,
+		Title:         extracted.Title,
+		ExtractedText: extracted.ExtractedText,
+		ExtractedHTML: extracted.ExtractedHTML,
+		MetadataJSON:  extracted.MetadataJSON,
+		URL:           pageURL,
+		ExtractedAt:   time.Now().UnixMilli(),
+	}
+	p.PostProcess(ctx, extraction)
+	if err := s.InsertExtractionDedup(ctx, p.content, extraction); err != nil {
+		return nil, fmt.Errorf("store followed extraction: %w", err)
+	}
+	p.extractionStored(ctx, s, src, extraction)
+	if err := s.InsertLinks(ctx, extraction.ID, links); err != nil {
+		log.Warn("web: store links failed", "error", err)
+	}
+	p.writeWebBuffer(ctx, log, src, extraction, extracted.ExtractedHTML)
+	return links, nil
+}
+
+// writeWebBuffer writes e to the buffer, if configured, as markdown of html.
+func (p *Pipeline) writeWebBuffer(ctx context.Context, log *slog.Logger, src *store.Source, e *store.Extraction, html string) {
+	if p.buffer == nil {
+		return
+	}
+	meta := buffer.Metadata{
+		ID:          e.ID,
+		SourceID:    src.ID,
+		DossierID:   jobFrom(ctx).DossierID,
+		SourceURL:   e.URL,
+		SourceType:  src.SourceType,
+		Title:       e.Title,
+		ContentHash: e.ContentHash,
+		ExtractedAt: time.Now().UTC(),
+	}
+	if _, err := p.buffer.Write(ctx, meta, p.htmlToMarkdown(html, e.URL, e.ExtractedText)); err != nil {
+		log.Warn("web: buffer write failed", "error", err)
+	}
+}
+
+// sameHost reports whether a and b have the same host (case-insensitive).
+func sameHost(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	return errA == nil && errB == nil && strings.EqualFold(ua.Host, ub.Host)
+}
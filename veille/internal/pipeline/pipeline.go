@@ -1,15 +1,20 @@
 // CLAUDE:SUMMARY Pipeline orchestrator dispatching fetch jobs to source-type-specific handlers.
 // Package pipeline orchestrates the fetch → extract → store workflow.
 //
-// It dispatches to source-type-specific handlers (web, rss, api, document).
+// It dispatches to source-type-specific handlers (web, rss, sitemap, api, document).
 // The web handler is the default fallback for unknown source types.
+// Fetched pages are turned into extractions by pluggable Extractors.
 package pipeline
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"strings"
+	"time"
 
 	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
@@ -18,7 +23,9 @@ import (
 	"github.com/microcosm-cc/bluemonday"
 
 	"github.com/hazyhaar/chrc/veille/internal/buffer"
+	"github.com/hazyhaar/chrc/veille/internal/crawl"
 	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/metrics"
 	"github.com/hazyhaar/chrc/veille/internal/store"
 	"github.com/hazyhaar/pkg/idgen"
 )
@@ -30,16 +37,38 @@ type Job struct {
 	URL       string
 }
 
+// jobKey is the context key of the Job being handled.
+type jobKey struct{}
+
+// withJob returns ctx carrying job.
+func withJob(ctx context.Context, job *Job) context.Context {
+	return context.WithValue(ctx, jobKey{}, job)
+}
+
+// jobFrom returns the Job being handled in ctx, or nil outside HandleJob.
+func jobFrom(ctx context.Context) *Job {
+	job, _ := ctx.Value(jobKey{}).(*Job)
+	return job
+}
+
 // Pipeline processes fetch jobs, dispatching to type-specific handlers.
 type Pipeline struct {
-	fetcher       *fetch.Fetcher
-	logger        *slog.Logger
-	newID         func() string
-	buffer        *buffer.Writer
-	handlers      map[string]SourceHandler
-	currentJob    *Job // set during HandleJob for handlers to access
-	mdConverter   *converter.Converter
-	htmlSanitizer *bluemonday.Policy
+	fetcher        *fetch.Fetcher
+	logger         *slog.Logger
+	newID          func() string
+	buffer         *buffer.Writer
+	content        *store.ContentStore // optional — shared body dedup
+	metrics        metrics.Metrics
+	handlers       map[string]SourceHandler
+	postProcessors []namedPostProcessor
+	postTimeout    time.Duration
+	extractors     map[string]Extractor // by source type or media type, see extractorFor
+	rawKeep        int                  // raw bodies kept per source; 0 = none
+	typeMismatch   TypeMismatchPolicy   // first-fetch source type check
+	indexer        Indexer              // optional — called for each stored extraction
+	follow         crawl.Limits         // link following caps per job (web follow_depth)
+	mdConverter    *converter.Converter
+	htmlSanitizer  *bluemonday.Policy
 }
 
 // New creates a Pipeline.
@@ -59,12 +88,17 @@ func New(fetcher *fetch.Fetcher, logger *slog.Logger) *Pipeline {
 			),
 		),
 		htmlSanitizer: newHTMLSanitizer(),
+		metrics:       metrics.Nop{},
 		handlers:      make(map[string]SourceHandler),
+		postTimeout:   DefaultPostProcessTimeout,
+		extractors:    make(map[string]Extractor),
+		typeMismatch:  TypeMismatchWarn,
 	}
 	// Register built-in handlers.
 	// "api" is now a connectivity service (api_fetch), auto-discovered by DiscoverHandlers.
 	p.handlers["web"] = &WebHandler{}
 	p.handlers["rss"] = &RSSHandler{}
+	p.handlers["sitemap"] = &SitemapHandler{}
 	p.handlers["document"] = NewDocumentHandler()
 	return p
 }
@@ -84,13 +118,33 @@ func (p *Pipeline) SetBuffer(w *buffer.Writer) {
 	p.buffer = w
 }
 
+// SetContentStore enables cross-dossier body dedup: extraction HTML is stored
+// once in cs and referenced from the shard. A nil cs stores bodies inline.
+func (p *Pipeline) SetContentStore(cs *store.ContentStore) {
+	p.content = cs
+}
+
+// SetMetrics configures the instrumentation sink. A nil m restores the no-op default.
+func (p *Pipeline) SetMetrics(m metrics.Metrics) {
+	if m == nil {
+		m = metrics.Nop{}
+	}
+	p.metrics = m
+}
+
+// SetFollowLimits sets the caps on the pages followed by one web job
+// (follow_depth). Zero fields keep the crawl package defaults.
+func (p *Pipeline) SetFollowLimits(l crawl.Limits) {
+	p.follow = l
+}
+
 // RegisterHandler registers a handler for a source type.
 func (p *Pipeline) RegisterHandler(sourceType string, h SourceHandler) {
 	p.handlers[sourceType] = h
 }
 
 // HandleJob processes a single fetch job against a resolved shard store.
-// Returns nil if the source is disabled or content is unchanged.
+// Returns nil if the source is disabled, soft-deleted, or content is unchanged.
 func (p *Pipeline) HandleJob(ctx context.Context, s *store.Store, job *Job) error {
 	log := p.logger.With("source_id", job.SourceID, "url", job.URL)
 
@@ -106,10 +160,14 @@ func (p *Pipeline) HandleJob(ctx context.Context, s *store.Store, job *Job) erro
 		log.Debug("pipeline: source disabled, skipping")
 		return nil
 	}
+	if src.DeletedAt != nil {
+		log.Debug("pipeline: source deleted, skipping")
+		return nil
+	}
 
-	// Set current job for handlers to access dossier context.
-	p.currentJob = job
-	defer func() { p.currentJob = nil }()
+	// Carry the job in ctx for handlers to access dossier context; HandleJob
+	// may run concurrently for several jobs.
+	ctx = withJob(ctx, job)
 
 	// Dispatch to source-type-specific handler.
 	handler, ok := p.handlers[src.SourceType]
@@ -120,7 +178,100 @@ func (p *Pipeline) HandleJob(ctx context.Context, s *store.Store, job *Job) erro
 			"source_type", src.SourceType)
 	}
 
-	return handler.Handle(ctx, s, src, p)
+	start := time.Now()
+	err = handler.Handle(ctx, s, src, p)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	p.metrics.Add(metrics.FetchTotal, 1, src.SourceType, status)
+	p.metrics.Observe(metrics.FetchDuration, time.Since(start).Seconds(), src.SourceType)
+	return err
+}
+
+// countExtractions records n newly stored extractions for src.
+func (p *Pipeline) countExtractions(src *store.Source, n int) {
+	if n > 0 {
+		p.metrics.Add(metrics.ExtractionsTotal, float64(n), src.SourceType)
+	}
+}
+
+// Indexer is called after an extraction is stored in shard s of dossierID.
+// An error is logged; the extraction stays stored.
+type Indexer func(ctx context.Context, s *store.Store, dossierID string, e *store.Extraction) error
+
+// SetIndexer sets the hook called for each extraction stored by a source
+// handler. A nil fn disables it.
+func (p *Pipeline) SetIndexer(fn Indexer) {
+	p.indexer = fn
+}
+
+// extractionStored counts the newly stored extraction e of src and passes it
+// to the indexer, if any.
+func (p *Pipeline) extractionStored(ctx context.Context, s *store.Store, src *store.Source, e *store.Extraction) {
+	p.countExtractions(src, 1)
+	if p.indexer == nil {
+		return
+	}
+	var dossierID string
+	if job := jobFrom(ctx); job != nil {
+		dossierID = job.DossierID
+	}
+	if err := p.indexer(ctx, s, dossierID, e); err != nil {
+		p.logger.Warn("pipeline: index extraction failed", "extraction_id", e.ID, "error", err)
+	}
+}
+
+// requestOptions returns the per-source fetch options: source type (fetch
+// limits), configured headers and basic auth, plus the user_agent (auto-repair
+// rotates it), proxy and hash_ignore patterns set in config_json. Invalid
+// hash_ignore patterns (rejected at input) are ignored.
+func requestOptions(src *store.Source) fetch.RequestOptions {
+	opts := fetch.RequestOptions{Headers: src.Headers, SourceType: src.SourceType}
+	if src.BasicAuth != nil {
+		opts.Username, opts.Password = src.BasicAuth.Username, src.BasicAuth.Password
+	}
+	var cfg struct {
+		UserAgent  string   `json:"user_agent"`
+		Proxy      string   `json:"proxy"`
+		HashIgnore []string `json:"hash_ignore"`
+	}
+	if json.Unmarshal([]byte(src.ConfigJSON), &cfg) != nil {
+		return opts
+	}
+	opts.Proxy = cfg.Proxy
+	opts.HashIgnore, _ = fetch.CompileHashIgnore(cfg.HashIgnore)
+	if cfg.UserAgent != "" {
+		if _, ok := headerValue(src.Headers, "User-Agent"); !ok {
+			opts.Headers = maps.Clone(src.Headers)
+			if opts.Headers == nil {
+				opts.Headers = map[string]string{}
+			}
+			opts.Headers["User-Agent"] = cfg.UserAgent
+		}
+	}
+	return opts
+}
+
+// headerValue looks up name in h case-insensitively.
+func headerValue(h map[string]string, name string) (string, bool) {
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// fetchErrorStatus is the fetch_log status recorded for a failed fetch.
+func fetchErrorStatus(err error) string {
+	if errors.Is(err, fetch.ErrBodyTooLarge) {
+		return "oversized_response"
+	}
+	if errors.Is(err, fetch.ErrHostDenied) {
+		return "host_denied"
+	}
+	return "error"
 }
 
 // htmlToMarkdown converts HTML to structured markdown.
@@ -3,11 +3,14 @@ package pipeline
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hazyhaar/chrc/veille/internal/buffer"
@@ -119,6 +122,49 @@ func TestHandleJob_Unchanged(t *testing.T) {
 	}
 }
 
+func TestHandleJob_UnchangedVolatile(t *testing.T) {
+	// WHAT: A page whose only changes are a timestamp, a CSP nonce and a hash_ignore'd counter is logged unchanged on the second fetch, with no new extraction.
+	// WHY: Frequently polled pages re-render volatile bits on every request; they must not cost an extraction and FTS write each time.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var n int
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n++
+		i := n
+		mu.Unlock()
+		fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>Status</title><script nonce="n%d"></script></head>
+	<body><main><p>Service status page, content long enough to be extracted. Rendered at 2026-10-1%dT08:00:0%dZ.</p>
+	<p>Visitors: %d</p></main></body></html>`, i, i, i, 1000+i)
+	}))
+	defer srv.Close()
+
+	s.InsertSource(ctx, &store.Source{ID: "src-v", Name: "V", URL: srv.URL, Enabled: true,
+		ConfigJSON: `{"hash_ignore":["Visitors: \\d+"]}`})
+
+	p := New(fetch.New(fetch.Config{}), nil)
+	for range 2 {
+		if err := p.HandleJob(ctx, s, &Job{SourceID: "src-v", URL: srv.URL}); err != nil {
+			t.Fatalf("handle job: %v", err)
+		}
+	}
+
+	exts, _ := s.ListExtractions(ctx, "src-v", 10)
+	if len(exts) != 1 {
+		t.Errorf("extractions: got %d, want 1", len(exts))
+	}
+	history, _ := s.FetchHistory(ctx, "src-v", 10)
+	if len(history) != 2 || history[0].Status != "unchanged" || history[1].Status != "ok" {
+		t.Fatalf("fetch log: got %+v, want unchanged then ok", history)
+	}
+	if src, _ := s.GetSource(ctx, "src-v"); src.LastStatus != "unchanged" {
+		t.Errorf("last_status: got %q, want unchanged", src.LastStatus)
+	}
+}
+
 func TestHandleJob_FetchError(t *testing.T) {
 	// WHAT: HTTP errors are recorded and fail_count incremented.
 	// WHY: Error handling feeds into scheduler backoff.
@@ -150,6 +196,35 @@ func TestHandleJob_FetchError(t *testing.T) {
 	}
 }
 
+func TestHandleJob_OversizedResponse(t *testing.T) {
+	// WHAT: A body over MaxResponseBytes aborts the job and logs an oversized_response fetch.
+	// WHY: Oversized sources must be visible in the fetch history, not silently truncated.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("<p>big</p>", 100)))
+	}))
+	defer srv.Close()
+
+	s.InsertSource(ctx, &store.Source{ID: "src-big", Name: "Big", URL: srv.URL, Enabled: true})
+
+	f := fetch.New(fetch.Config{MaxResponseBytes: 256, URLValidator: func(string) error { return nil }})
+	p := New(f, nil)
+
+	if err := p.HandleJob(ctx, s, &Job{SourceID: "src-big", URL: srv.URL}); !errors.Is(err, fetch.ErrBodyTooLarge) {
+		t.Fatalf("got %v, want ErrBodyTooLarge", err)
+	}
+	hist, _ := s.FetchHistory(ctx, "src-big", 1)
+	if len(hist) != 1 || hist[0].Status != "oversized_response" {
+		t.Fatalf("fetch log: got %+v", hist)
+	}
+	if exts, _ := s.ListExtractions(ctx, "src-big", 10); len(exts) != 0 {
+		t.Errorf("oversized body must not be stored, got %d extractions", len(exts))
+	}
+}
+
 func TestHandleJob_DisabledSource(t *testing.T) {
 	// WHAT: Disabled sources are skipped silently.
 	// WHY: User can disable without deleting.
@@ -347,3 +422,96 @@ func TestDispatch_UnknownFallsBackToWeb(t *testing.T) {
 		t.Fatal("unknown type should fallback to web and create extractions")
 	}
 }
+
+func TestIndexer_CalledPerStoredExtraction(t *testing.T) {
+	// WHAT: The indexer sees each stored extraction with the job's dossier; its error does not fail the job.
+	// WHY: Related extractions rely on every stored extraction being embedded under its dossier.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<!DOCTYPE html><html><head><title>Indexed</title></head>
+	<body><main><p>Content long enough to be extracted and handed to the indexer hook.</p></main></body></html>`))
+	}))
+	defer srv.Close()
+	s.InsertSource(ctx, &store.Source{ID: "src-idx", Name: "Idx", URL: srv.URL, Enabled: true})
+
+	var dossiers, ids []string
+	p := New(fetch.New(fetch.Config{}), nil)
+	p.SetIndexer(func(_ context.Context, _ *store.Store, dossierID string, e *store.Extraction) error {
+		dossiers = append(dossiers, dossierID)
+		ids = append(ids, e.ID)
+		return errors.New("embedder down")
+	})
+	if err := p.HandleJob(ctx, s, &Job{DossierID: "d1", SourceID: "src-idx", URL: srv.URL}); err != nil {
+		t.Fatalf("handle job: %v", err)
+	}
+
+	exts, _ := s.ListExtractions(ctx, "src-idx", 10)
+	if len(exts) != 1 {
+		t.Fatalf("extractions: got %d, want 1", len(exts))
+	}
+	if len(ids) != 1 || ids[0] != exts[0].ID || dossiers[0] != "d1" {
+		t.Errorf("indexer calls: ids %v dossiers %v", ids, dossiers)
+	}
+}
+
+func TestHandleJob_FollowDepth(t *testing.T) {
+	// WHAT: With follow_depth 1, the two pages linked from the source page are fetched and stored with their links; the pages they link to (depth 2) are not fetched.
+	// WHY: Link following must stop at the configured depth, and the links table must record each page's outbound links.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	page := func(title, links string) string {
+		return `<!DOCTYPE html><html><head><title>` + title + `</title></head><body><main><article>
+		<h1>` + title + `</h1><p>This is the page called ` + title + `. It carries enough distinct text
+		to be extracted on its own by the pipeline, with a few links to other pages.</p>` + links +
+			`</article></main></body></html>`
+	}
+	var mu sync.Mutex
+	hits := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(page("Root", `<p><a href="/a">A</a> <a href="/b">B</a></p>`)))
+		case "/a":
+			w.Write([]byte(page("Alpha", `<p><a href="/">home</a> <a href="/deep-a">deeper</a></p>`)))
+		case "/b":
+			w.Write([]byte(page("Bravo", `<p><a href="/a">A</a> <a href="/deep-b">deeper</a></p>`)))
+		default:
+			w.Write([]byte(page("Deep", "")))
+		}
+	}))
+	defer srv.Close()
+
+	s.InsertSource(ctx, &store.Source{ID: "src-f", Name: "Follow", URL: srv.URL + "/", SourceType: "web",
+		Enabled: true, ConfigJSON: `{"follow_depth": 1}`})
+	p := New(fetch.New(fetch.Config{}), nil)
+	if err := p.HandleJob(ctx, s, &Job{SourceID: "src-f", URL: srv.URL + "/"}); err != nil {
+		t.Fatalf("handle job: %v", err)
+	}
+
+	if hits["/"] != 1 || hits["/a"] != 1 || hits["/b"] != 1 {
+		t.Errorf("hits: got %v, want /, /a and /b once each", hits)
+	}
+	if hits["/deep-a"]+hits["/deep-b"] != 0 {
+		t.Errorf("depth-2 pages fetched: %v", hits)
+	}
+	exts, _ := s.ListExtractions(ctx, "src-f", 10)
+	urls := map[string]string{}
+	for _, e := range exts {
+		urls[strings.TrimPrefix(e.URL, srv.URL)] = e.ID
+	}
+	if len(exts) != 3 || urls["/"] == "" || urls["/a"] == "" || urls["/b"] == "" {
+		t.Fatalf("extractions: got %v, want /, /a and /b", urls)
+	}
+	links, _ := s.ListLinks(ctx, urls["/a"])
+	if len(links) != 2 || links[0] != srv.URL+"/" || links[1] != srv.URL+"/deep-a" {
+		t.Errorf("links of /a: got %v", links)
+	}
+}
@@ -0,0 +1,132 @@
+// CLAUDE:SUMMARY Language detection plus PostProcessor extension point: time-bounded enrichment of extractions (tags, entities, sentiment) merged into metadata_json before storage.
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/lang"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// DefaultPostProcessTimeout bounds a single PostProcessor call.
+const DefaultPostProcessTimeout = 5 * time.Second
+
+// PostProcessor enriches an extraction before it is stored.
+// The returned fields are merged into the extraction's metadata_json;
+// keys already set by the source handler are kept.
+// Returning an error or exceeding the timeout skips this processor only.
+type PostProcessor interface {
+	Process(ctx context.Context, e *store.Extraction) (map[string]any, error)
+}
+
+// PostProcessorFunc adapts a plain function to PostProcessor.
+type PostProcessorFunc func(ctx context.Context, e *store.Extraction) (map[string]any, error)
+
+// Process implements PostProcessor.
+func (f PostProcessorFunc) Process(ctx context.Context, e *store.Extraction) (map[string]any, error) {
+	return f(ctx, e)
+}
+
+type namedPostProcessor struct {
+	name string
+	pp   PostProcessor
+}
+
+// RegisterPostProcessor adds a post-processor. Processors run in registration
+// order; registering an existing name replaces it in place.
+func (p *Pipeline) RegisterPostProcessor(name string, pp PostProcessor) {
+	for i := range p.postProcessors {
+		if p.postProcessors[i].name == name {
+			p.postProcessors[i].pp = pp
+			return
+		}
+	}
+	p.postProcessors = append(p.postProcessors, namedPostProcessor{name: name, pp: pp})
+}
+
+// SetPostProcessTimeout bounds each post-processor call. Non-positive d restores the default.
+func (p *Pipeline) SetPostProcessTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultPostProcessTimeout
+	}
+	p.postTimeout = d
+}
+
+// PostProcess detects the language of e when unset, then runs the registered
+// post-processors on e and merges their fields into e.MetadataJSON.
+// Failures are logged and never block storage.
+func (p *Pipeline) PostProcess(ctx context.Context, e *store.Extraction) {
+	if e.Lang == "" {
+		e.Lang = lang.Detect(e.Title + "\n" + e.ExtractedText)
+	}
+	if len(p.postProcessors) == 0 {
+		return
+	}
+	meta := make(map[string]any)
+	if e.MetadataJSON != "" {
+		if err := json.Unmarshal([]byte(e.MetadataJSON), &meta); err != nil {
+			p.logger.Warn("pipeline: unreadable metadata, skipping post-processors",
+				"extraction_id", e.ID, "error", err)
+			return
+		}
+	}
+	changed := false
+	for _, np := range p.postProcessors {
+		fields, err := p.runPostProcessor(ctx, np.pp, e)
+		if err != nil {
+			p.logger.Warn("pipeline: post-processor failed",
+				"processor", np.name, "extraction_id", e.ID, "error", err)
+			continue
+		}
+		for k, v := range fields {
+			if _, exists := meta[k]; exists {
+				continue
+			}
+			meta[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	b, err := json.Marshal(meta)
+	if err != nil {
+		p.logger.Warn("pipeline: marshal metadata", "extraction_id", e.ID, "error", err)
+		return
+	}
+	e.MetadataJSON = string(b)
+}
+
+// runPostProcessor calls pp under the configured timeout. A processor that
+// ignores its context is abandoned when the deadline passes.
+func (p *Pipeline) runPostProcessor(ctx context.Context, pp PostProcessor, e *store.Extraction) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.postTimeout)
+	defer cancel()
+
+	// The processor gets a copy so an abandoned call cannot race with storage.
+	cp := *e
+	type result struct {
+		fields map[string]any
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		fields, err := pp.Process(ctx, &cp)
+		done <- result{fields, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.fields, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestPostProcess_TagsStoredInMetadata(t *testing.T) {
+	// WHAT: A registered post-processor's fields end up in the stored extraction's metadata_json.
+	// WHY: Enrichment (tags, entities, sentiment) must be persisted with the extraction.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	txtPath := filepath.Join(t.TempDir(), "note.txt")
+	os.WriteFile(txtPath, []byte("The GDPR ruling affects every sovereign cloud provider in Europe."), 0o644)
+	s.InsertSource(ctx, &store.Source{
+		ID: "src-doc", Name: "Doc", URL: txtPath,
+		SourceType: "document", Enabled: true,
+	})
+
+	p := New(fetch.New(fetch.Config{}), nil)
+	p.RegisterPostProcessor("tagger", PostProcessorFunc(func(_ context.Context, e *store.Extraction) (map[string]any, error) {
+		var tags []string
+		if strings.Contains(e.ExtractedText, "GDPR") {
+			tags = append(tags, "regulation")
+		}
+		return map[string]any{"tags": tags}, nil
+	}))
+	p.RegisterPostProcessor("broken", PostProcessorFunc(func(context.Context, *store.Extraction) (map[string]any, error) {
+		return nil, errors.New("model unavailable")
+	}))
+
+	if err := p.HandleJob(ctx, s, &Job{DossierID: "u_sp", SourceID: "src-doc", URL: txtPath}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	exts, _ := s.ListExtractions(ctx, "src-doc", 10)
+	if len(exts) != 1 {
+		t.Fatalf("extractions: got %d, want 1", len(exts))
+	}
+	var meta struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(exts[0].MetadataJSON), &meta); err != nil {
+		t.Fatalf("metadata: %v (%s)", err, exts[0].MetadataJSON)
+	}
+	if len(meta.Tags) != 1 || meta.Tags[0] != "regulation" {
+		t.Errorf("tags: got %v, want [regulation]", meta.Tags)
+	}
+}
+
+func TestPostProcess_TimeoutAndExistingKeys(t *testing.T) {
+	// WHAT: A processor that blocks past the timeout is skipped; handler-set keys are not overwritten.
+	// WHY: A slow enricher must not stall the pipeline nor clobber source metadata.
+	p := New(fetch.New(fetch.Config{}), nil)
+	p.SetPostProcessTimeout(20 * time.Millisecond)
+
+	block := make(chan struct{})
+	defer close(block)
+	p.RegisterPostProcessor("slow", PostProcessorFunc(func(context.Context, *store.Extraction) (map[string]any, error) {
+		<-block // ignores ctx on purpose
+		return map[string]any{"slow": true}, nil
+	}))
+	p.RegisterPostProcessor("fast", PostProcessorFunc(func(context.Context, *store.Extraction) (map[string]any, error) {
+		return map[string]any{"question_id": "overwritten", "sentiment": "neutral"}, nil
+	}))
+
+	e := &store.Extraction{ID: "e1", MetadataJSON: `{"question_id":"q1"}`}
+	start := time.Now()
+	p.PostProcess(context.Background(), e)
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("post-process took %v, timeout not enforced", d)
+	}
+
+	var meta map[string]any
+	json.Unmarshal([]byte(e.MetadataJSON), &meta)
+	if meta["question_id"] != "q1" {
+		t.Errorf("question_id: got %v, want q1", meta["question_id"])
+	}
+	if meta["sentiment"] != "neutral" {
+		t.Errorf("sentiment: got %v, want neutral", meta["sentiment"])
+	}
+	if _, ok := meta["slow"]; ok {
+		t.Error("timed-out processor fields should be dropped")
+	}
+}
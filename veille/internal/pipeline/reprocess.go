@@ -0,0 +1,82 @@
+// CLAUDE:SUMMARY Raw body retention for web extractions and Reprocess: re-runs the registered extractor on the kept body and updates the extraction in place.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// ErrExtractionNotFound is returned by Reprocess for an unknown extraction.
+var ErrExtractionNotFound = errors.New("extraction not found")
+
+// ErrNoRawBody is returned by Reprocess when no raw body was kept for the
+// extraction (retention disabled, non-web source, or trimmed by the cap).
+var ErrNoRawBody = errors.New("no raw body kept for extraction")
+
+// SetRawBodyRetention keeps the raw response body of the newest keep web
+// extractions of each source, for Reprocess. keep <= 0 disables retention.
+func (p *Pipeline) SetRawBodyRetention(keep int) {
+	p.rawKeep = max(keep, 0)
+}
+
+// keepRawBody stores body and its Content-Type for extractionID when
+// retention is enabled. Failures are logged: the extraction itself is
+// already stored.
+func (p *Pipeline) keepRawBody(ctx context.Context, s *store.Store, extractionID, sourceID, contentType string, body []byte) {
+	if p.rawKeep == 0 {
+		return
+	}
+	if err := s.PutRawBody(ctx, extractionID, sourceID, contentType, body, p.rawKeep); err != nil {
+		p.logger.Warn("pipeline: keep raw body failed", "extraction_id", extractionID, "error", err)
+	}
+}
+
+// Reprocess re-runs extraction and post-processing on the raw body kept for
+// extractionID and updates the extraction in place: same ID, source, URL
+// and extracted_at; new hash, title, text, HTML and lang. The extractor is
+// selected as at fetch time, from the source type and the kept Content-Type.
+// Metadata set by post-processors is kept. The buffer is not rewritten.
+func (p *Pipeline) Reprocess(ctx context.Context, s *store.Store, extractionID string) (*store.Extraction, error) {
+	old, err := s.GetExtraction(ctx, extractionID)
+	if err != nil {
+		return nil, err
+	}
+	if old == nil {
+		return nil, fmt.Errorf("%w: %s", ErrExtractionNotFound, extractionID)
+	}
+	body, contentType, err := s.RawBody(ctx, extractionID)
+	if err != nil {
+		return nil, fmt.Errorf("raw body: %w", err)
+	}
+	if body == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRawBody, extractionID)
+	}
+	var sourceType string
+	if src, err := s.GetSource(ctx, old.SourceID); err == nil && src != nil {
+		sourceType = src.SourceType
+	}
+
+	res, err := p.extractorFor(sourceType, contentType).Extract(ctx, body, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("extract: %w", err)
+	}
+	if res.ExtractedText == "" {
+		return nil, fmt.Errorf("extract: empty text for extraction %s", extractionID)
+	}
+
+	e := *old
+	e.ContentHash = res.ContentHash
+	e.Title = res.Title
+	e.ExtractedText = res.ExtractedText
+	e.ExtractedHTML = res.ExtractedHTML
+	e.ContentRef, e.TextRef = "", ""
+	e.Lang = ""
+	p.PostProcess(ctx, &e)
+	if err := s.UpdateExtractionDedup(ctx, p.content, &e, old.ContentRef, old.TextRef); err != nil {
+		return nil, fmt.Errorf("update extraction: %w", err)
+	}
+	return &e, nil
+}
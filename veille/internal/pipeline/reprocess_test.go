@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hazyhaar/chrc/extract"
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+const reprocessPage = `<!DOCTYPE html><html><head><title>Page</title></head><body>
+<nav class="menu">Navigation links that a better extractor should keep out of the text body.</nav>
+<article class="story"><p>The article body carries the actual story and is long enough to be
+picked up by the automatic extraction mode without any trouble at all.</p></article>
+</body></html>`
+
+func TestReprocess_RerunsExtractorOnRawBody(t *testing.T) {
+	// WHAT: With raw bodies kept, Reprocess re-extracts with the new extractor and updates the row in place.
+	// WHY: Extraction improvements must apply to past content without re-fetching the source.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write([]byte(reprocessPage))
+	}))
+	defer srv.Close()
+	s.InsertSource(ctx, &store.Source{ID: "src-rp", Name: "RP", URL: srv.URL, Enabled: true})
+
+	p := New(fetch.New(fetch.Config{}), nil)
+	p.SetRawBodyRetention(2)
+	p.RegisterExtractor("web", HTMLExtractor{Options: extract.Options{Mode: "css", Selectors: []string{"nav.menu"}}})
+	if err := p.HandleJob(ctx, s, &Job{SourceID: "src-rp", URL: srv.URL}); err != nil {
+		t.Fatalf("handle job: %v", err)
+	}
+	exts, _ := s.ListExtractions(ctx, "src-rp", 10)
+	if len(exts) != 1 {
+		t.Fatalf("got %d extractions, want 1", len(exts))
+	}
+	before := exts[0]
+
+	p.RegisterExtractor("web", HTMLExtractor{Options: extract.Options{Mode: "css", Selectors: []string{"article.story"}}})
+	got, err := p.Reprocess(ctx, s, before.ID)
+	if err != nil {
+		t.Fatalf("reprocess: %v", err)
+	}
+	if got.ID != before.ID || got.ExtractedAt != before.ExtractedAt {
+		t.Errorf("identity changed: %s@%d, want %s@%d", got.ID, got.ExtractedAt, before.ID, before.ExtractedAt)
+	}
+	if got.ExtractedText == before.ExtractedText || !strings.Contains(got.ExtractedText, "actual story") {
+		t.Errorf("text not re-extracted: before %q, after %q", before.ExtractedText, got.ExtractedText)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("reprocess must not re-fetch, got %d fetches", n)
+	}
+
+	stored, _ := s.GetExtraction(ctx, before.ID)
+	if stored.ExtractedText != got.ExtractedText {
+		t.Errorf("stored text not updated: %q", stored.ExtractedText)
+	}
+	if res, _ := s.Search(ctx, "story", 10); len(res) != 1 {
+		t.Errorf("search on new text: got %d results, want 1", len(res))
+	}
+}
+
+func TestReprocess_NoRawBody(t *testing.T) {
+	// WHAT: Without retention, Reprocess fails with ErrNoRawBody; unknown IDs with ErrExtractionNotFound.
+	// WHY: Callers map these to distinct HTTP statuses.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(reprocessPage))
+	}))
+	defer srv.Close()
+	s.InsertSource(ctx, &store.Source{ID: "src-nr", Name: "NR", URL: srv.URL, Enabled: true})
+
+	p := New(fetch.New(fetch.Config{}), nil)
+	if err := p.HandleJob(ctx, s, &Job{SourceID: "src-nr", URL: srv.URL}); err != nil {
+		t.Fatalf("handle job: %v", err)
+	}
+	exts, _ := s.ListExtractions(ctx, "src-nr", 10)
+	if len(exts) != 1 {
+		t.Fatalf("got %d extractions, want 1", len(exts))
+	}
+	if _, err := p.Reprocess(ctx, s, exts[0].ID); !errors.Is(err, ErrNoRawBody) {
+		t.Errorf("got %v, want ErrNoRawBody", err)
+	}
+	if _, err := p.Reprocess(ctx, s, "missing"); !errors.Is(err, ErrExtractionNotFound) {
+		t.Errorf("got %v, want ErrExtractionNotFound", err)
+	}
+}
+
+func TestPutRawBody_KeepsNewest(t *testing.T) {
+	// WHAT: Only the newest keep raw bodies of a source survive.
+	// WHY: Retention is capped per source so shards do not grow without bound.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.InsertSource(ctx, &store.Source{ID: "src-cap", Name: "Cap", URL: "https://cap.example", Enabled: true})
+	for i := 1; i <= 3; i++ {
+		id := fmt.Sprintf("ext-%d", i)
+		s.InsertExtraction(ctx, &store.Extraction{ID: id, SourceID: "src-cap", ContentHash: id, ExtractedText: id, URL: "https://cap.example", ExtractedAt: int64(i)})
+		if err := s.PutRawBody(ctx, id, "src-cap", "text/html", []byte(id), 2); err != nil {
+			t.Fatalf("put %s: %v", id, err)
+		}
+	}
+	if body, _, _ := s.RawBody(ctx, "ext-1"); body != nil {
+		t.Error("oldest raw body should be trimmed")
+	}
+	for _, id := range []string{"ext-2", "ext-3"} {
+		if body, _, _ := s.RawBody(ctx, id); string(body) != id {
+			t.Errorf("%s: got %q", id, body)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// CLAUDE:SUMMARY Source type check on first fetch: sniffs the response and warns, corrects the stored source_type and re-dispatches, or rejects, per TypeMismatchPolicy.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// TypeMismatchPolicy selects what happens when the first response of a
+// source does not match its declared source type.
+type TypeMismatchPolicy string
+
+// Type mismatch policies.
+const (
+	TypeMismatchWarn    TypeMismatchPolicy = "warn"    // log and process as declared (default)
+	TypeMismatchCorrect TypeMismatchPolicy = "correct" // store the sniffed type and process as such
+	TypeMismatchReject  TypeMismatchPolicy = "reject"  // record a fetch error, store nothing
+)
+
+// ErrSourceTypeMismatch is returned by HandleJob for a source rejected by
+// TypeMismatchReject.
+var ErrSourceTypeMismatch = errors.New("source type mismatch")
+
+// kindSourceTypes maps sniffed content kinds to the source type handling them.
+var kindSourceTypes = map[string]string{
+	fetch.KindHTML:    "web",
+	fetch.KindFeed:    "rss",
+	fetch.KindSitemap: "sitemap",
+	fetch.KindJSON:    "api",
+}
+
+// sniffedTypes are the declared source types whose content is checked.
+var sniffedTypes = map[string]bool{"web": true, "rss": true, "sitemap": true}
+
+// typeCheckedKey marks the context of a handler re-dispatched after a correction.
+type typeCheckedKey struct{}
+
+// SetTypeMismatchPolicy sets the source type check policy. An empty policy
+// restores TypeMismatchWarn.
+func (p *Pipeline) SetTypeMismatchPolicy(policy TypeMismatchPolicy) {
+	if policy == "" {
+		policy = TypeMismatchWarn
+	}
+	p.typeMismatch = policy
+}
+
+// checkSourceType sniffs res, the first successful response of src (no
+// stored hash yet). On a mismatch it applies the policy and reports whether
+// it handled the job, in which case the handler returns err as is.
+// Correction only applies when a handler is registered for the sniffed type;
+// otherwise the mismatch is only logged.
+func (p *Pipeline) checkSourceType(ctx context.Context, s *store.Store, src *store.Source, res *fetch.Result, logEntry *store.FetchLogEntry) (handled bool, err error) {
+	if src.LastHash != "" || !sniffedTypes[src.SourceType] || ctx.Value(typeCheckedKey{}) != nil {
+		return false, nil
+	}
+	sniffed := kindSourceTypes[fetch.Sniff(res.ContentType, res.Body)]
+	if sniffed == "" || sniffed == src.SourceType {
+		return false, nil
+	}
+	log := p.logger.With("source_id", src.ID, "url", src.URL,
+		"declared", src.SourceType, "sniffed", sniffed, "content_type", res.ContentType)
+
+	switch p.typeMismatch {
+	case TypeMismatchCorrect:
+		h, ok := p.handlers[sniffed]
+		if !ok {
+			break
+		}
+		if err := s.UpdateSourceType(ctx, src.ID, sniffed); err != nil {
+			log.Warn("pipeline: source type correction failed", "error", err)
+			return false, nil
+		}
+		log.Info("pipeline: source type corrected")
+		corrected := *src
+		corrected.SourceType = sniffed
+		return true, h.Handle(context.WithValue(ctx, typeCheckedKey{}, true), s, &corrected, p)
+
+	case TypeMismatchReject:
+		msg := fmt.Sprintf("declared %s, content is %s", src.SourceType, sniffed)
+		logEntry.Status = "type_mismatch"
+		logEntry.ErrorMessage = msg
+		_ = s.InsertFetchLog(ctx, logEntry)
+		_ = s.RecordFetchError(ctx, src.ID, "source type mismatch: "+msg)
+		log.Warn("pipeline: source type mismatch, rejected")
+		return true, fmt.Errorf("%w: %s", ErrSourceTypeMismatch, msg)
+	}
+	log.Warn("pipeline: source type mismatch")
+	return false, nil
+}
@@ -0,0 +1,79 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func feedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(testRSS))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSourceType_WebFeedCorrectedToRSS(t *testing.T) {
+	// WHAT: With TypeMismatchCorrect, a web source serving an RSS feed is stored as rss and its entries extracted.
+	// WHY: Feeds added as web produce empty or useless extractions.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+	srv := feedServer(t)
+	s.InsertSource(ctx, &store.Source{ID: "src-mis", Name: "Feed", URL: srv.URL, SourceType: "web", Enabled: true})
+
+	p := New(fetch.New(fetch.Config{}), nil)
+	p.SetTypeMismatchPolicy(TypeMismatchCorrect)
+	if err := p.HandleJob(ctx, s, &Job{SourceID: "src-mis", URL: srv.URL}); err != nil {
+		t.Fatalf("handle job: %v", err)
+	}
+
+	src, _ := s.GetSource(ctx, "src-mis")
+	if src.SourceType != "rss" {
+		t.Errorf("source type: got %q, want rss", src.SourceType)
+	}
+	exts, _ := s.ListExtractions(ctx, "src-mis", 10)
+	if len(exts) != 2 {
+		t.Fatalf("extractions: got %d, want 2 feed entries", len(exts))
+	}
+}
+
+func TestSourceType_RejectAndWarn(t *testing.T) {
+	// WHAT: TypeMismatchReject fails the first fetch with ErrSourceTypeMismatch; the default only warns.
+	// WHY: Correction is opt-in; the default must keep the declared type.
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	ctx := context.Background()
+	srv := feedServer(t)
+	s.InsertSource(ctx, &store.Source{ID: "src-rej", Name: "Rej", URL: srv.URL, SourceType: "web", Enabled: true})
+
+	p := New(fetch.New(fetch.Config{}), nil)
+	p.SetTypeMismatchPolicy(TypeMismatchReject)
+	err := p.HandleJob(ctx, s, &Job{SourceID: "src-rej", URL: srv.URL})
+	if !errors.Is(err, ErrSourceTypeMismatch) {
+		t.Fatalf("reject: got %v, want ErrSourceTypeMismatch", err)
+	}
+	logs, _ := s.FetchHistory(ctx, "src-rej", 10)
+	if len(logs) != 1 || logs[0].Status != "type_mismatch" {
+		t.Errorf("fetch log: got %+v", logs)
+	}
+	if exts, _ := s.ListExtractions(ctx, "src-rej", 10); len(exts) != 0 {
+		t.Errorf("reject stored %d extractions", len(exts))
+	}
+
+	p.SetTypeMismatchPolicy("")
+	if err := p.HandleJob(ctx, s, &Job{SourceID: "src-rej", URL: srv.URL}); err != nil {
+		t.Fatalf("warn: %v", err)
+	}
+	if src, _ := s.GetSource(ctx, "src-rej"); src.SourceType != "web" {
+		t.Errorf("warn changed source type to %q", src.SourceType)
+	}
+}
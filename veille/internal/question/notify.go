@@ -0,0 +1,43 @@
+// CLAUDE:SUMMARY Per-question notification rules (min new results, optional regex) evaluated after each run.
+package question
+
+import (
+	"regexp"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// Notification describes a question run that satisfied its notification rules.
+type Notification struct {
+	DossierID  string              `json:"dossier_id"`
+	QuestionID string              `json:"question_id"`
+	Text       string              `json:"text"`
+	NewResults []*store.Extraction `json:"new_results"`
+}
+
+// ShouldNotify reports whether a run that produced newResults satisfies q's
+// notification rules: at least NotifyMinResults new results (default 1) and,
+// if NotifyPattern is set, at least one result whose title, text or URL matches it.
+// An invalid pattern never matches.
+func ShouldNotify(q *store.TrackedQuestion, newResults []*store.Extraction) bool {
+	minResults := q.NotifyMinResults
+	if minResults <= 0 {
+		minResults = 1
+	}
+	if len(newResults) < minResults {
+		return false
+	}
+	if q.NotifyPattern == "" {
+		return true
+	}
+	re, err := regexp.Compile(q.NotifyPattern)
+	if err != nil {
+		return false
+	}
+	for _, e := range newResults {
+		if re.MatchString(e.Title) || re.MatchString(e.ExtractedText) || re.MatchString(e.URL) {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/hazyhaar/chrc/extract"
 	"github.com/hazyhaar/chrc/veille/internal/buffer"
+	"github.com/hazyhaar/chrc/veille/internal/crawl"
 	"github.com/hazyhaar/chrc/veille/internal/fetch"
 	"github.com/hazyhaar/chrc/veille/internal/search"
 	"github.com/hazyhaar/chrc/veille/internal/store"
@@ -26,6 +27,10 @@ type Runner struct {
 	searcher func(ctx context.Context, engine *search.Engine, query string) ([]search.Result, error)
 	fetcher  *fetch.Fetcher
 	buffer   *buffer.Writer
+	notify   func(ctx context.Context, n Notification)
+	enrich   func(ctx context.Context, e *store.Extraction)
+	content  *store.ContentStore
+	follow   crawl.Limits
 	logger   *slog.Logger
 	newID    func() string
 }
@@ -44,6 +49,21 @@ type Config struct {
 	// Buffer for .md output (optional).
 	Buffer *buffer.Writer
 
+	// Notify is called after a run whose new results satisfy the question's
+	// notification rules (see ShouldNotify). Optional.
+	Notify func(ctx context.Context, n Notification)
+
+	// Enrich is called on each new extraction before it is stored, e.g. to
+	// run the pipeline's post-processors. Optional.
+	Enrich func(ctx context.Context, e *store.Extraction)
+
+	// Content is the shared body store when content dedup is enabled (optional).
+	Content *store.ContentStore
+
+	// Follow caps the pages followed per run from result pages
+	// (TrackedQuestion.FollowDepth). Zero fields keep the crawl defaults.
+	Follow crawl.Limits
+
 	Logger *slog.Logger
 	NewID  func() string
 }
@@ -55,6 +75,10 @@ func NewRunner(cfg Config) *Runner {
 		searcher: cfg.Searcher,
 		fetcher:  cfg.Fetcher,
 		buffer:   cfg.Buffer,
+		notify:   cfg.Notify,
+		enrich:   cfg.Enrich,
+		content:  cfg.Content,
+		follow:   cfg.Follow,
 		logger:   cfg.Logger,
 		newID:    cfg.NewID,
 	}
@@ -70,7 +94,9 @@ func NewRunner(cfg Config) *Runner {
 }
 
 // Run executes a tracked question: searches each channel, deduplicates results,
-// optionally follows links, stores extractions and chunks. Returns new result count.
+// optionally fetches result pages and follows their links up to
+// q.FollowDepth, stores extractions and chunks, then fires the notification
+// hook if the question's rules are met. Returns new result count.
 func (r *Runner) Run(ctx context.Context, s *store.Store, q *store.TrackedQuestion, dossierID string) (int, error) {
 	log := r.logger.With("question_id", q.ID, "text", q.Text)
 
@@ -126,8 +152,21 @@ func (r *Runner) Run(ctx context.Context, s *store.Store, q *store.TrackedQuesti
 		allResults = allResults[:q.MaxResults]
 	}
 
-	// Process each result.
+	// Process each result. One walker per run: followed pages are visited
+	// once and the follow caps apply to the whole run.
 	var newCount int
+	var fresh []*store.Extraction
+	var walker *crawl.Walker
+	if q.FollowLinks && q.FollowDepth > 0 && r.fetcher != nil {
+		walker = crawl.NewWalker(r.follow, func(ctx context.Context, u string, _ int) ([]string, error) {
+			e, links, err := r.followPage(ctx, s, q, dossierID, query, u)
+			if e != nil {
+				fresh = append(fresh, e)
+				newCount++
+			}
+			return links, err
+		})
+	}
 	for _, tr := range allResults {
 		res := tr.result
 		contentHash := hashString(res.URL)
@@ -144,12 +183,14 @@ func (r *Runner) Run(ctx context.Context, s *store.Store, q *store.TrackedQuesti
 
 		// Get text content.
 		var text string
+		var links []string
 		if q.FollowLinks && res.URL != "" && r.fetcher != nil {
 			fetchResult, fetchErr := r.fetcher.Fetch(ctx, res.URL, "", "", "")
 			if fetchErr == nil && fetchResult.Changed {
 				extractResult, extractErr := extract.Extract(fetchResult.Body, extract.Options{Mode: "auto"})
 				if extractErr == nil && extractResult.Text != "" {
 					text = extract.CleanText(extractResult.Text)
+					links = crawl.Links(extractResult.HTML, res.URL, crawl.MaxLinksPerPage)
 				}
 			}
 		}
@@ -179,29 +220,17 @@ func (r *Runner) Run(ctx context.Context, s *store.Store, q *store.TrackedQuesti
 			ExtractedAt:   now,
 			MetadataJSON:  string(metaJSON),
 		}
-		if err := s.InsertExtraction(ctx, extraction); err != nil {
+		if err := r.storeResult(ctx, s, dossierID, extraction, links); err != nil {
 			log.Warn("question: insert extraction failed", "error", err, "url", res.URL)
 			continue
 		}
 
-		// Buffer write.
-		if r.buffer != nil {
-			meta := buffer.Metadata{
-				ID:          extractionID,
-				SourceID:    q.ID,
-				DossierID:   dossierID,
-				SourceURL:   res.URL,
-				SourceType:  "question",
-				Title:       res.Title,
-				ContentHash: contentHash,
-				ExtractedAt: time.Now().UTC(),
-			}
-			if _, err := r.buffer.Write(ctx, meta, text); err != nil {
-				log.Warn("question: buffer write failed", "error", err)
-			}
-		}
-
+		fresh = append(fresh, extraction)
 		newCount++
+
+		if walker != nil {
+			walker.Follow(ctx, res.URL, links, q.FollowDepth)
+		}
 	}
 
 	// Record run stats.
@@ -209,10 +238,91 @@ func (r *Runner) Run(ctx context.Context, s *store.Store, q *store.TrackedQuesti
 		log.Warn("question: record run failed", "error", err)
 	}
 
+	if r.notify != nil && ShouldNotify(q, fresh) {
+		r.notify(ctx, Notification{
+			DossierID:  dossierID,
+			QuestionID: q.ID,
+			Text:       q.Text,
+			NewResults: fresh,
+		})
+	}
+
 	log.Info("question: run complete", "new", newCount, "total_searched", len(allResults))
 	return newCount, nil
 }
 
+// storeResult enriches and inserts a new result extraction e of question
+// e.SourceID with its outbound links, and writes it to the buffer.
+func (r *Runner) storeResult(ctx context.Context, s *store.Store, dossierID string, e *store.Extraction, links []string) error {
+	if r.enrich != nil {
+		r.enrich(ctx, e)
+	}
+	if err := s.InsertExtractionDedup(ctx, r.content, e); err != nil {
+		return err
+	}
+	if err := s.InsertLinks(ctx, e.ID, links); err != nil {
+		r.logger.Warn("question: store links failed", "extraction_id", e.ID, "error", err)
+	}
+	if r.buffer != nil {
+		meta := buffer.Metadata{
+			ID:          e.ID,
+			SourceID:    e.SourceID,
+			DossierID:   dossierID,
+			SourceURL:   e.URL,
+			SourceType:  "question",
+			Title:       e.Title,
+			ContentHash: e.ContentHash,
+			ExtractedAt: time.Now().UTC(),
+		}
+		if _, err := r.buffer.Write(ctx, meta, e.ExtractedText); err != nil {
+			r.logger.Warn("question: buffer write failed", "extraction_id", e.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// followPage fetches a page linked from a result page and stores it as a
+// result of q unless its URL is already one. Returns the new extraction (nil
+// if none) and the page's outbound links.
+func (r *Runner) followPage(ctx context.Context, s *store.Store, q *store.TrackedQuestion, dossierID, query, pageURL string) (*store.Extraction, []string, error) {
+	result, err := r.fetcher.Fetch(ctx, pageURL, "", "", "")
+	if err != nil {
+		return nil, nil, err
+	}
+	extracted, err := extract.Extract(result.Body, extract.Options{Mode: "auto"})
+	if err != nil {
+		return nil, nil, err
+	}
+	links := crawl.Links(extracted.HTML, pageURL, crawl.MaxLinksPerPage)
+	text := extract.CleanText(extracted.Text)
+	contentHash := hashString(pageURL)
+	if text == "" {
+		return nil, links, nil
+	}
+	if exists, err := s.ExtractionExists(ctx, q.ID, contentHash); err != nil || exists {
+		return nil, links, err
+	}
+	metaJSON, _ := json.Marshal(map[string]string{
+		"question_id": q.ID,
+		"channel":     "follow",
+		"query":       query,
+	})
+	e := &store.Extraction{
+		ID:            r.newID(),
+		SourceID:      q.ID,
+		ContentHash:   contentHash,
+		Title:         extracted.Title,
+		ExtractedText: text,
+		URL:           pageURL,
+		ExtractedAt:   time.Now().UnixMilli(),
+		MetadataJSON:  string(metaJSON),
+	}
+	if err := r.storeResult(ctx, s, dossierID, e, links); err != nil {
+		return nil, links, err
+	}
+	return e, links, nil
+}
+
 func hashString(s string) string {
 	h := sha256.Sum256([]byte(s))
 	return fmt.Sprintf("%x", h)
@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hazyhaar/chrc/veille/internal/buffer"
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
 	"github.com/hazyhaar/chrc/veille/internal/search"
 	"github.com/hazyhaar/chrc/veille/internal/store"
 
@@ -324,3 +328,132 @@ func TestRun_WritesBuffer(t *testing.T) {
 		t.Error("frontmatter missing source_id")
 	}
 }
+
+func TestRun_NotifyThreshold(t *testing.T) {
+	// WHAT: The notification fires only when a run yields at least notify_min_results new results.
+	// WHY: A question returning a handful of daily results must not notify every run.
+	s := openTestDB(t)
+	ctx := context.Background()
+	idCounter = 0
+
+	s.InsertSource(ctx, &store.Source{ID: "q-n", Name: "Q: N", URL: "question://q-n", SourceType: "question", Enabled: true})
+	q := &store.TrackedQuestion{
+		ID: "q-n", Text: "sovereign cloud", Channels: `["brave"]`, Enabled: true,
+		NotifyMinResults: 3,
+	}
+	s.InsertQuestion(ctx, q)
+	stored, _ := s.GetQuestion(ctx, "q-n")
+	if stored.NotifyMinResults != 3 {
+		t.Fatalf("stored notify_min_results: got %d, want 3", stored.NotifyMinResults)
+	}
+
+	var notified []Notification
+	results := []search.Result{
+		{Title: "A", URL: "https://a.example/1", Snippet: "first result"},
+		{Title: "B", URL: "https://a.example/2", Snippet: "second result"},
+	}
+	runner := NewRunner(Config{
+		Engines:  func(_ context.Context, id string) (*search.Engine, error) { return mockEngine(id), nil },
+		Searcher: func(context.Context, *search.Engine, string) ([]search.Result, error) { return results, nil },
+		Notify:   func(_ context.Context, n Notification) { notified = append(notified, n) },
+		NewID:    testID,
+	})
+
+	// Run 1: 2 new results < 3 → no notification.
+	if _, err := runner.Run(ctx, s, stored, "d1"); err != nil {
+		t.Fatalf("run 1: %v", err)
+	}
+	if len(notified) != 0 {
+		t.Fatalf("run 1 should not notify, got %d", len(notified))
+	}
+
+	// Run 2: 3 new results (first two deduped) → notification.
+	results = append(results,
+		search.Result{Title: "C", URL: "https://a.example/3", Snippet: "third result"},
+		search.Result{Title: "D", URL: "https://a.example/4", Snippet: "fourth result"},
+		search.Result{Title: "E", URL: "https://a.example/5", Snippet: "fifth result"},
+	)
+	if _, err := runner.Run(ctx, s, stored, "d1"); err != nil {
+		t.Fatalf("run 2: %v", err)
+	}
+	if len(notified) != 1 {
+		t.Fatalf("run 2 should notify once, got %d", len(notified))
+	}
+	if n := notified[0]; n.QuestionID != "q-n" || n.DossierID != "d1" || len(n.NewResults) != 3 {
+		t.Errorf("notification: got question=%s dossier=%s results=%d", n.QuestionID, n.DossierID, len(n.NewResults))
+	}
+}
+
+func TestShouldNotify_Pattern(t *testing.T) {
+	// WHAT: With notify_pattern set, only runs with a matching new result notify.
+	// WHY: Users want alerts only when a keyword appears.
+	q := &store.TrackedQuestion{NotifyPattern: `(?i)\bgdpr\b`}
+	plain := []*store.Extraction{{Title: "Cloud pricing", ExtractedText: "new tiers announced"}}
+	match := []*store.Extraction{{Title: "Cloud pricing", ExtractedText: "new tiers"}, {Title: "EU ruling", ExtractedText: "GDPR fine for transfers"}}
+
+	if ShouldNotify(q, plain) {
+		t.Error("non-matching results should not notify")
+	}
+	if !ShouldNotify(q, match) {
+		t.Error("matching result should notify")
+	}
+	if ShouldNotify(&store.TrackedQuestion{}, nil) {
+		t.Error("zero new results should never notify")
+	}
+	if !ShouldNotify(&store.TrackedQuestion{}, plain) {
+		t.Error("default rules should notify on any new result")
+	}
+}
+
+func TestRun_FollowDepth(t *testing.T) {
+	// WHAT: With follow_depth 1, the pages linked from a fetched result page become results of the question; their own links (depth 2) are not fetched.
+	// WHY: Questions follow links up to their depth, bounded like web sources.
+	var mu sync.Mutex
+	hits := map[string]int{}
+	page := func(title, links string) string {
+		return `<html><head><title>` + title + `</title></head><body><article><h1>` + title + `</h1>
+		<p>This page is called ` + title + ` and has enough readable text for the extractor to keep it
+		as the main content of the document, along with its outbound links.</p>` + links + `</article></body></html>`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/result":
+			w.Write([]byte(page("Result", `<p><a href="/a">Alpha</a> and <a href="/b">Bravo</a></p>`)))
+		case "/a":
+			w.Write([]byte(page("Alpha", `<p><a href="/deep">Deeper</a></p>`)))
+		default:
+			w.Write([]byte(page("Leaf "+r.URL.Path, "")))
+		}
+	}))
+	defer srv.Close()
+
+	s := openTestDB(t)
+	ctx := context.Background()
+	s.InsertSource(ctx, &store.Source{ID: "q-f", Name: "Q: follow", URL: "question://q-f", SourceType: "question", Enabled: true})
+	q := &store.TrackedQuestion{ID: "q-f", Text: "follow", Channels: `["brave"]`, MaxResults: 10,
+		FollowLinks: true, FollowDepth: 1, Enabled: true}
+	s.InsertQuestion(ctx, q)
+
+	runner := NewRunner(Config{
+		Engines:  func(_ context.Context, id string) (*search.Engine, error) { return mockEngine(id), nil },
+		Searcher: mockSearcher([]search.Result{{Title: "Result", URL: srv.URL + "/result", Snippet: "result"}}),
+		Fetcher:  fetch.New(fetch.Config{}),
+		NewID:    testID,
+	})
+	count, err := runner.Run(ctx, s, q, "d1")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("new count: got %d, want 3 (result + 2 linked pages)", count)
+	}
+	if hits["/a"] != 1 || hits["/b"] != 1 || hits["/deep"] != 0 {
+		t.Errorf("hits: got %v, want /a and /b once, /deep never", hits)
+	}
+	if got, _ := s.GetQuestion(ctx, "q-f"); got.FollowDepth != 1 {
+		t.Errorf("follow_depth round-trip: got %d", got.FollowDepth)
+	}
+}
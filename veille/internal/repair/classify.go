@@ -53,7 +53,7 @@ func Classify(sourceType string, statusCode int, errMsg string) (ErrorClass, Act
 
 	// Forbidden — web sources can try rotating UA.
 	if statusCode == 403 {
-		if sourceType == "web" || sourceType == "rss" {
+		if sourceType == "web" || sourceType == "rss" || sourceType == "sitemap" {
 			return ClassForbidden, ActionRotateUA
 		}
 		return ClassForbidden, ActionMarkBroken
@@ -1,6 +1,6 @@
-// CLAUDE:SUMMARY Periodic sweeper that probes broken/error sources and resets those that recover.
+// CLAUDE:SUMMARY Periodic sweeper that probes broken/error sources with per-source exponential backoff and resets those that recover.
 // CLAUDE:DEPENDS repair, store
-// CLAUDE:EXPORTS Sweeper, SweepResult
+// CLAUDE:EXPORTS Sweeper, SweepResult, ProbeHook
 package repair
 
 import (
@@ -30,15 +30,30 @@ type PoolResolver interface {
 // ShardLister returns active dossier IDs.
 type ShardLister func(ctx context.Context) ([]string, error)
 
+// probeSlack lets a sweep probe sources due shortly after it starts, so a
+// tick landing just before next_probe_at does not defer the probe a full
+// interval.
+const probeSlack = time.Minute
+
 // Sweeper periodically probes broken sources and resets those that recover.
+// A source whose probe fails waits base * 2^failures (capped at max) before
+// the next one; failures counts the consecutive failed probes before this one.
 type Sweeper struct {
-	pool     PoolResolver
-	list     ShardLister
-	logger   *slog.Logger
-	interval time.Duration
-	timeout  time.Duration // per-probe timeout
+	pool      PoolResolver
+	list      ShardLister
+	logger    *slog.Logger
+	interval  time.Duration
+	timeout   time.Duration // per-probe timeout
+	probeBase time.Duration
+	probeMax  time.Duration
+	now       func() time.Time
+	onProbe   ProbeHook // optional — called after each probe
 }
 
+// ProbeHook is called after the sweeper probed a source of dossierID in
+// shard st and recorded the outcome (reset or backoff).
+type ProbeHook func(ctx context.Context, dossierID string, st *store.Store, r SweepResult)
+
 // NewSweeper creates a Sweeper.
 func NewSweeper(pool PoolResolver, list ShardLister, logger *slog.Logger, interval time.Duration) *Sweeper {
 	if logger == nil {
@@ -53,7 +68,37 @@ func NewSweeper(pool PoolResolver, list ShardLister, logger *slog.Logger, interv
 		logger:   logger,
 		interval: interval,
 		timeout:  10 * time.Second,
+		// Defaults: first retry at the next sweep, at most weekly.
+		probeBase: interval,
+		probeMax:  7 * 24 * time.Hour,
+		now:       time.Now,
+	}
+}
+
+// SetProbeBackoff sets the base delay and the cap of the per-source probe
+// backoff. Non-positive values keep the current setting.
+func (sw *Sweeper) SetProbeBackoff(base, max time.Duration) {
+	if base > 0 {
+		sw.probeBase = base
+	}
+	if max > 0 {
+		sw.probeMax = max
+	}
+}
+
+// SetProbeHook sets the hook called after each probe. A nil fn disables it.
+func (sw *Sweeper) SetProbeHook(fn ProbeHook) {
+	sw.onProbe = fn
+}
+
+// probeDelay returns the wait before the next probe after failures
+// consecutive failed probes (not counting the one just made).
+func (sw *Sweeper) probeDelay(failures int) time.Duration {
+	d := sw.probeBase
+	for i := 0; i < failures && d < sw.probeMax; i++ {
+		d *= 2
 	}
+	return min(d, sw.probeMax)
 }
 
 // Run launches the periodic sweep. Blocks until ctx.Done().
@@ -82,8 +127,8 @@ func (sw *Sweeper) Run(ctx context.Context) {
 	}
 }
 
-// SweepOnce probes all broken/error sources across all shards.
-// Returns results for sources that were probed.
+// SweepOnce probes the broken/error sources due for a probe across all
+// shards. Returns results for sources that were probed.
 func (sw *Sweeper) SweepOnce(ctx context.Context) []SweepResult {
 	dossierIDs, err := sw.list(ctx)
 	if err != nil {
@@ -91,15 +136,16 @@ func (sw *Sweeper) SweepOnce(ctx context.Context) []SweepResult {
 		return nil
 	}
 
+	now := sw.now()
 	var results []SweepResult
 	for _, dossierID := range dossierIDs {
-		shardResults := sw.sweepShard(ctx, dossierID)
+		shardResults := sw.sweepShard(ctx, dossierID, now)
 		results = append(results, shardResults...)
 	}
 	return results
 }
 
-func (sw *Sweeper) sweepShard(ctx context.Context, dossierID string) []SweepResult {
+func (sw *Sweeper) sweepShard(ctx context.Context, dossierID string, now time.Time) []SweepResult {
 	db, err := sw.pool.Resolve(ctx, dossierID)
 	if err != nil {
 		sw.logger.Warn("sweeper: resolve shard", "dossier_id", dossierID, "error", err)
@@ -107,7 +153,7 @@ func (sw *Sweeper) sweepShard(ctx context.Context, dossierID string) []SweepResu
 	}
 	st := store.NewStore(db)
 
-	broken, err := st.ListBrokenSources(ctx)
+	broken, err := st.ListProbeDueSources(ctx, now.Add(probeSlack).UnixMilli())
 	if err != nil {
 		sw.logger.Warn("sweeper: list broken", "dossier_id", dossierID, "error", err)
 		return nil
@@ -121,11 +167,30 @@ func (sw *Sweeper) sweepShard(ctx context.Context, dossierID string) []SweepResu
 		}
 
 		r := sw.probeSource(ctx, st, src)
+		if !r.Recovered {
+			sw.backoff(ctx, st, src.ID, now)
+		}
+		if sw.onProbe != nil {
+			sw.onProbe(ctx, dossierID, st, r)
+		}
 		results = append(results, r)
 	}
 	return results
 }
 
+// backoff records a failed probe and schedules the next one.
+func (sw *Sweeper) backoff(ctx context.Context, st *store.Store, sourceID string, now time.Time) {
+	failures, err := st.ProbeFailures(ctx, sourceID)
+	if err != nil {
+		sw.logger.Warn("sweeper: probe failures", "source_id", sourceID, "error", err)
+		return
+	}
+	next := now.Add(sw.probeDelay(failures))
+	if err := st.RecordProbeFailure(ctx, sourceID, failures+1, next.UnixMilli()); err != nil {
+		sw.logger.Warn("sweeper: record probe failure", "source_id", sourceID, "error", err)
+	}
+}
+
 func (sw *Sweeper) probeSource(ctx context.Context, st *store.Store, src *store.Source) SweepResult {
 	result := SweepResult{
 		SourceID:   src.ID,
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hazyhaar/chrc/veille/internal/store"
 	_ "modernc.org/sqlite"
@@ -153,3 +155,76 @@ func TestSweepOnce_NoShards(t *testing.T) {
 		t.Errorf("should return 0 results, got %d", len(results))
 	}
 }
+
+func TestSweepOnce_ProbeBackoff(t *testing.T) {
+	// WHAT: The wait before re-probing a failing source doubles per failure (capped) and resets on recovery.
+	// WHY: A permanently dead source must not be probed at full frequency forever.
+	db := openTestDB(t)
+	st := store.NewStore(db)
+	ctx := context.Background()
+
+	var up atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if up.Load() {
+			w.WriteHeader(200)
+			return
+		}
+		w.WriteHeader(503)
+	}))
+	defer ts.Close()
+
+	st.InsertSource(ctx, &store.Source{
+		ID: "src-backoff", Name: "Dead", URL: ts.URL,
+		SourceType: "web", Enabled: true, FailCount: 10, LastStatus: "broken",
+	})
+	st.SetSourceStatus(ctx, "src-backoff", "broken")
+
+	pool := &mockPool{dbs: map[string]*sql.DB{"d1": db}}
+	sw := NewSweeper(pool, func(context.Context) ([]string, error) { return []string{"d1"}, nil }, nil, time.Hour)
+	sw.SetProbeBackoff(time.Hour, 4*time.Hour)
+	clock := time.Unix(1_700_000_000, 0)
+	sw.now = func() time.Time { return clock }
+
+	// probedAfter advances the clock by d and reports whether the sweep probed the source.
+	probedAfter := func(d time.Duration) bool {
+		clock = clock.Add(d)
+		return len(sw.SweepOnce(ctx)) == 1
+	}
+
+	if !probedAfter(0) {
+		t.Fatal("first sweep should probe")
+	}
+	// Waits after failures 1..4: 1h, 2h, 4h, then capped at 4h.
+	for i, wait := range []time.Duration{time.Hour, 2 * time.Hour, 4 * time.Hour, 4 * time.Hour} {
+		if probedAfter(wait - 10*time.Minute) {
+			t.Fatalf("failure %d: probed before the %v backoff elapsed", i+1, wait)
+		}
+		if !probedAfter(10 * time.Minute) {
+			t.Fatalf("failure %d: not probed after %v", i+1, wait)
+		}
+	}
+
+	up.Store(true)
+	clock = clock.Add(4 * time.Hour)
+	results := sw.SweepOnce(ctx)
+	if len(results) != 1 || !results[0].Recovered {
+		t.Fatalf("recovery sweep: got %+v", results)
+	}
+	if n, _ := st.ProbeFailures(ctx, "src-backoff"); n != 0 {
+		t.Errorf("probe_failures after recovery: got %d, want 0", n)
+	}
+	got, _ := st.GetSource(ctx, "src-backoff")
+	if got.FailCount != 0 || got.LastStatus != "pending" {
+		t.Errorf("error state not cleared: fail_count=%d status=%q", got.FailCount, got.LastStatus)
+	}
+
+	// Broken again: the backoff starts over at the base delay.
+	up.Store(false)
+	st.RecordFetchError(ctx, "src-backoff", "down again")
+	if !probedAfter(0) {
+		t.Fatal("first probe after relapse should run")
+	}
+	if !probedAfter(time.Hour) {
+		t.Error("backoff should restart at 1h after recovery")
+	}
+}
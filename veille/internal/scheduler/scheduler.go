@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Polls for due sources across shards and enqueues pipeline fetch jobs.
+// CLAUDE:SUMMARY Polls for due sources across shards and runs pipeline fetch jobs on a worker pool, fair across dossiers with per-dossier in-flight caps.
 // Package scheduler polls for due sources and enqueues fetch jobs.
 package scheduler
 
@@ -6,8 +6,12 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/hazyhaar/chrc/veille/internal/metrics"
 	"github.com/hazyhaar/chrc/veille/internal/store"
 )
 
@@ -18,12 +22,34 @@ type Job struct {
 	URL       string `json:"url"`
 }
 
+// Policy selects how a tick's capacity is shared between dossiers.
+type Policy string
+
+const (
+	// PolicyFair takes due sources round-robin across dossiers, so a dossier
+	// with many due sources cannot starve the others.
+	PolicyFair Policy = "fair"
+	// PolicyFIFO takes the longest-waiting due sources first, regardless of dossier.
+	PolicyFIFO Policy = "fifo"
+)
+
 // Config configures the scheduler.
 type Config struct {
 	// CheckInterval is how often to poll for due sources. Default: 1 minute.
 	CheckInterval time.Duration
 	// MaxFailCount is the maximum failure count before a source is skipped.
 	MaxFailCount int
+	// MaxJobsPerTick caps the jobs enqueued per tick. Sources left over stay
+	// due and are picked up on a later tick. Default: 0 (unlimited).
+	MaxJobsPerTick int
+	// Policy selects due sources when MaxJobsPerTick is reached. Default: PolicyFair.
+	Policy Policy
+	// Workers is the number of jobs run concurrently. Default: 1 (sequential).
+	Workers int
+	// MaxPerDossier caps the jobs of one dossier running at once, so a
+	// dossier with many slow sources cannot hold every worker. Default: 0
+	// (Workers, no cap).
+	MaxPerDossier int
 }
 
 func (c *Config) defaults() {
@@ -33,6 +59,15 @@ func (c *Config) defaults() {
 	if c.MaxFailCount <= 0 {
 		c.MaxFailCount = 10
 	}
+	if c.Policy == "" {
+		c.Policy = PolicyFair
+	}
+	if c.Workers <= 0 {
+		c.Workers = 1
+	}
+	if c.MaxPerDossier <= 0 || c.MaxPerDossier > c.Workers {
+		c.MaxPerDossier = c.Workers
+	}
 }
 
 // ShardResolver returns a *sql.DB for a given dossierID.
@@ -51,6 +86,26 @@ type Scheduler struct {
 	sink    JobSink
 	config  Config
 	logger  *slog.Logger
+	metrics metrics.Metrics
+	rrStart int // rotates the first dossier served under PolicyFair
+
+	mu       sync.Mutex
+	done     *sync.Cond     // signalled when a job finishes
+	inFlight map[string]int // running jobs per dossier
+	lastTick time.Time
+	lastJobs int
+}
+
+// Status is a snapshot of the scheduler's effective settings and load.
+type Status struct {
+	Policy            Policy         `json:"policy"`
+	Workers           int            `json:"workers"`
+	MaxPerDossier     int            `json:"max_per_dossier"`
+	MaxJobsPerTick    int            `json:"max_jobs_per_tick"` // 0 = unlimited
+	InFlight          int            `json:"in_flight"`
+	InFlightByDossier map[string]int `json:"in_flight_by_dossier"`
+	LastTickAt        int64          `json:"last_tick_at"` // ms, 0 before the first tick
+	LastTickJobs      int            `json:"last_tick_jobs"`
 }
 
 // New creates a Scheduler.
@@ -59,13 +114,47 @@ func New(resolve ShardResolver, list ShardLister, sink JobSink, cfg Config, logg
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Scheduler{
-		resolve: resolve,
-		list:    list,
-		sink:    sink,
-		config:  cfg,
-		logger:  logger,
+	s := &Scheduler{
+		resolve:  resolve,
+		list:     list,
+		sink:     sink,
+		config:   cfg,
+		logger:   logger,
+		metrics:  metrics.Nop{},
+		inFlight: make(map[string]int),
+	}
+	s.done = sync.NewCond(&s.mu)
+	return s
+}
+
+// Status returns the effective concurrency settings and the jobs running now.
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Status{
+		Policy:            s.config.Policy,
+		Workers:           s.config.Workers,
+		MaxPerDossier:     s.config.MaxPerDossier,
+		MaxJobsPerTick:    s.config.MaxJobsPerTick,
+		InFlightByDossier: make(map[string]int, len(s.inFlight)),
+		LastTickJobs:      s.lastJobs,
+	}
+	for d, n := range s.inFlight {
+		st.InFlightByDossier[d] = n
+		st.InFlight += n
+	}
+	if !s.lastTick.IsZero() {
+		st.LastTickAt = s.lastTick.UnixMilli()
 	}
+	return st
+}
+
+// SetMetrics configures the instrumentation sink. A nil m restores the no-op default.
+func (s *Scheduler) SetMetrics(m metrics.Metrics) {
+	if m == nil {
+		m = metrics.Nop{}
+	}
+	s.metrics = m
 }
 
 // Run polls for due sources on a ticker. Blocks until ctx is cancelled.
@@ -86,14 +175,26 @@ func (s *Scheduler) Run(ctx context.Context) {
 	}
 }
 
-// enqueueDueSources iterates all active shards and enqueues due sources.
+// dueBatch holds the due sources of one dossier for the current tick.
+type dueBatch struct {
+	dossierID string
+	sources   []*store.Source
+}
+
+// enqueueDueSources collects due sources from all active shards, selects up
+// to MaxJobsPerTick of them according to Policy, and runs them. Returns when
+// the tick's jobs are done.
 func (s *Scheduler) enqueueDueSources(ctx context.Context) {
+	start := time.Now()
+	defer func() { s.metrics.Observe(metrics.SchedulerTick, time.Since(start).Seconds()) }()
+
 	shards, err := s.list(ctx)
 	if err != nil {
 		s.logger.Error("scheduler: list shards", "error", err)
 		return
 	}
 
+	var batches []dueBatch
 	for _, dossierID := range shards {
 		db, err := s.resolve(ctx, dossierID)
 		if err != nil {
@@ -107,20 +208,134 @@ func (s *Scheduler) enqueueDueSources(ctx context.Context) {
 			s.logger.Warn("scheduler: due sources", "dossier", dossierID, "error", err)
 			continue
 		}
+		if len(due) > 0 {
+			batches = append(batches, dueBatch{dossierID: dossierID, sources: due})
+		}
+	}
 
-		for _, src := range due {
-			job := &Job{
-				DossierID: dossierID,
-				SourceID:  src.ID,
-				URL:       src.URL,
-			}
+	var jobs []*Job
+	if s.config.Policy == PolicyFIFO {
+		jobs = s.selectFIFO(batches)
+	} else {
+		jobs = s.selectFair(batches)
+	}
+
+	s.mu.Lock()
+	s.lastTick, s.lastJobs = start, len(jobs)
+	s.mu.Unlock()
+
+	enqueued := make(map[string]int, len(batches))
+	for _, job := range jobs {
+		enqueued[job.DossierID]++
+	}
+	for dossierID, n := range enqueued {
+		s.logger.Debug("scheduler: enqueued", "dossier", dossierID, "jobs", n)
+	}
+	s.dispatch(ctx, jobs)
+}
+
+// dispatch runs jobs on up to Workers goroutines, taking them in order but
+// skipping past jobs whose dossier already has MaxPerDossier running. Jobs
+// not started when ctx is cancelled are dropped. Returns when all started
+// jobs are done.
+func (s *Scheduler) dispatch(ctx context.Context, jobs []*Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	running := 0
+	for len(jobs) > 0 || running > 0 {
+		if ctx.Err() != nil {
+			jobs = nil
+		}
+		next := -1
+		if running < s.config.Workers {
+			next = slices.IndexFunc(jobs, func(j *Job) bool {
+				return s.inFlight[j.DossierID] < s.config.MaxPerDossier
+			})
+		}
+		if next < 0 {
+			s.done.Wait()
+			continue
+		}
+		job := jobs[next]
+		jobs = slices.Delete(jobs, next, next+1)
+		running++
+		s.inFlight[job.DossierID]++
+		go func() {
 			if err := s.sink(ctx, job); err != nil {
-				s.logger.Warn("scheduler: enqueue job", "source_id", src.ID, "error", err)
+				s.logger.Warn("scheduler: enqueue job", "source_id", job.SourceID, "error", err)
+			}
+			s.mu.Lock()
+			running--
+			if s.inFlight[job.DossierID]--; s.inFlight[job.DossierID] == 0 {
+				delete(s.inFlight, job.DossierID)
+			}
+			s.done.Broadcast()
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// capacity returns the number of jobs allowed this tick given total due sources.
+func (s *Scheduler) capacity(total int) int {
+	if s.config.MaxJobsPerTick > 0 && s.config.MaxJobsPerTick < total {
+		return s.config.MaxJobsPerTick
+	}
+	return total
+}
+
+// selectFIFO takes the longest-waiting sources first across all dossiers.
+// Never-fetched sources come first; ties keep shard order.
+func (s *Scheduler) selectFIFO(batches []dueBatch) []*Job {
+	type candidate struct {
+		job  *Job
+		last int64 // last_fetched_at, -1 if never fetched
+	}
+	var cands []candidate
+	for _, b := range batches {
+		for _, src := range b.sources {
+			c := candidate{job: &Job{DossierID: b.dossierID, SourceID: src.ID, URL: src.URL}, last: -1}
+			if src.LastFetchedAt != nil {
+				c.last = *src.LastFetchedAt
 			}
+			cands = append(cands, c)
 		}
+	}
+	sort.SliceStable(cands, func(a, b int) bool { return cands[a].last < cands[b].last })
 
-		if len(due) > 0 {
-			s.logger.Debug("scheduler: enqueued", "dossier", dossierID, "jobs", len(due))
+	jobs := make([]*Job, s.capacity(len(cands)))
+	for i := range jobs {
+		jobs[i] = cands[i].job
+	}
+	return jobs
+}
+
+// selectFair takes one source per dossier in turn until capacity is reached.
+// The starting dossier rotates every tick so a tight capacity still reaches
+// every dossier over successive ticks.
+func (s *Scheduler) selectFair(batches []dueBatch) []*Job {
+	total := 0
+	for _, b := range batches {
+		total += len(b.sources)
+	}
+	n := s.capacity(total)
+	jobs := make([]*Job, 0, n)
+	if len(batches) == 0 {
+		return jobs
+	}
+	offset := s.rrStart % len(batches)
+	s.rrStart++
+	for round := 0; len(jobs) < n; round++ {
+		for i := range batches {
+			b := batches[(offset+i)%len(batches)]
+			if round >= len(b.sources) {
+				continue
+			}
+			src := b.sources[round]
+			jobs = append(jobs, &Job{DossierID: b.dossierID, SourceID: src.ID, URL: src.URL})
+			if len(jobs) == n {
+				break
+			}
 		}
 	}
+	return jobs
 }
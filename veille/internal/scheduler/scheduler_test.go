@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -97,3 +98,130 @@ func TestSkipHighFailCount(t *testing.T) {
 		t.Errorf("jobs: got %d, want 0 (high fail count should be skipped)", len(jobs))
 	}
 }
+
+// seedDossiers creates a "huge" dossier with 50 long-overdue sources and a
+// "small" one with 6 sources that became due more recently.
+func seedDossiers(t *testing.T) map[string]*sql.DB {
+	t.Helper()
+	ctx := context.Background()
+	dbs := map[string]*sql.DB{"huge": openTestDB(t), "small": openTestDB(t)}
+	t.Cleanup(func() {
+		for _, db := range dbs {
+			db.Close()
+		}
+	})
+	old := time.Now().UnixMilli() - 48*3600000
+	recent := time.Now().UnixMilli() - 2*3600000
+	huge := store.NewStore(dbs["huge"])
+	for i := 0; i < 50; i++ {
+		id := fmt.Sprintf("huge-%02d", i)
+		huge.InsertSource(ctx, &store.Source{ID: id, Name: id, URL: "https://big.example/" + id, Enabled: true, FetchInterval: 3600000, LastFetchedAt: &old})
+	}
+	small := store.NewStore(dbs["small"])
+	for i := 0; i < 6; i++ {
+		id := fmt.Sprintf("small-%d", i)
+		small.InsertSource(ctx, &store.Source{ID: id, Name: id, URL: "https://small.example/" + id, Enabled: true, FetchInterval: 3600000, LastFetchedAt: &recent})
+	}
+	return dbs
+}
+
+// runTicks runs n ticks with the given policy and returns jobs per dossier per tick.
+// The sink marks each job's source as fetched, as the pipeline would.
+func runTicks(t *testing.T, dbs map[string]*sql.DB, policy Policy, n int) []map[string]int {
+	t.Helper()
+	resolve := func(ctx context.Context, dossierID string) (*sql.DB, error) { return dbs[dossierID], nil }
+	list := func(ctx context.Context) ([]string, error) { return []string{"huge", "small"}, nil }
+	var tick map[string]int
+	sink := func(ctx context.Context, job *Job) error {
+		tick[job.DossierID]++
+		return store.NewStore(dbs[job.DossierID]).RecordFetchSuccess(ctx, job.SourceID, "h")
+	}
+
+	sched := New(resolve, list, sink, Config{MaxFailCount: 5, MaxJobsPerTick: 4, Policy: policy}, nil)
+	var ticks []map[string]int
+	for i := 0; i < n; i++ {
+		tick = map[string]int{}
+		sched.enqueueDueSources(context.Background())
+		ticks = append(ticks, tick)
+	}
+	return ticks
+}
+
+func TestFairPolicy_SmallDossierProgressesEachTick(t *testing.T) {
+	// WHAT: Under PolicyFair with a tight capacity, the small dossier gets jobs every tick.
+	// WHY: A high-volume dossier must not monopolize fetch slots.
+	ticks := runTicks(t, seedDossiers(t), PolicyFair, 3)
+	for i, tick := range ticks {
+		if tick["huge"]+tick["small"] != 4 {
+			t.Errorf("tick %d: got %d jobs, want capacity 4", i, tick["huge"]+tick["small"])
+		}
+		if tick["small"] == 0 {
+			t.Errorf("tick %d: small dossier starved (%v)", i, tick)
+		}
+	}
+}
+
+func TestFIFOPolicy_OldestFirst(t *testing.T) {
+	// WHAT: Under PolicyFIFO the longest-waiting sources win regardless of dossier.
+	// WHY: FIFO stays available for deployments that prefer strict age ordering.
+	ticks := runTicks(t, seedDossiers(t), PolicyFIFO, 2)
+	for i, tick := range ticks {
+		if tick["huge"] != 4 || tick["small"] != 0 {
+			t.Errorf("tick %d: got %v, want all 4 jobs from huge", i, tick)
+		}
+	}
+}
+
+func TestWorkers_PerDossierCapLetsSmallDossierThrough(t *testing.T) {
+	// WHAT: With 4 workers and MaxPerDossier 2, the huge dossier never holds more than 2 workers and the small dossier starts within the first tick, even last in FIFO order.
+	// WHY: Slow sources of one dossier must not occupy every fetch slot while another dossier waits.
+	dbs := seedDossiers(t)
+	resolve := func(ctx context.Context, dossierID string) (*sql.DB, error) { return dbs[dossierID], nil }
+	list := func(ctx context.Context) ([]string, error) { return []string{"huge", "small"}, nil }
+
+	var mu sync.Mutex
+	running := map[string]int{}
+	maxRunning := map[string]int{}
+	maxTotal, started, firstSmall := 0, 0, -1
+	sink := func(ctx context.Context, job *Job) error {
+		mu.Lock()
+		started++
+		if job.DossierID == "small" && firstSmall < 0 {
+			firstSmall = started
+		}
+		running[job.DossierID]++
+		maxRunning[job.DossierID] = max(maxRunning[job.DossierID], running[job.DossierID])
+		maxTotal = max(maxTotal, running["huge"]+running["small"])
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond) // slow fetch
+
+		mu.Lock()
+		running[job.DossierID]--
+		mu.Unlock()
+		return store.NewStore(dbs[job.DossierID]).RecordFetchSuccess(ctx, job.SourceID, "h")
+	}
+
+	sched := New(resolve, list, sink, Config{MaxFailCount: 5, Policy: PolicyFIFO, Workers: 4, MaxPerDossier: 2}, nil)
+	sched.enqueueDueSources(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxRunning["huge"] > 2 || maxTotal > 4 {
+		t.Errorf("concurrency: huge peaked at %d (cap 2), total at %d (4 workers)", maxRunning["huge"], maxTotal)
+	}
+	if maxTotal < 3 {
+		t.Errorf("concurrency: total peaked at %d, want the pool used", maxTotal)
+	}
+	if firstSmall < 0 || firstSmall > 3 {
+		t.Errorf("small dossier first started as job %d, want within the first 3", firstSmall)
+	}
+	if started != 56 {
+		t.Errorf("jobs run: got %d, want 56", started)
+	}
+
+	st := sched.Status()
+	if st.Workers != 4 || st.MaxPerDossier != 2 || st.InFlight != 0 || st.LastTickJobs != 56 || st.LastTickAt == 0 {
+		t.Errorf("status: %+v", st)
+	}
+}
@@ -0,0 +1,92 @@
+// CLAUDE:SUMMARY sitemaps.org parser for <urlset> and <sitemapindex> documents.
+// Package sitemap parses sitemap.xml files using encoding/xml.
+//
+// Auto-detects the document kind from the XML root element:
+//   - <urlset ...> → page URLs
+//   - <sitemapindex ...> → child sitemap URLs
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Entry is one <url> or <sitemap> element.
+type Entry struct {
+	Loc     string `json:"loc"`
+	LastMod string `json:"lastmod"` // W3C datetime as published, may be empty
+}
+
+// Sitemap is a parsed sitemap document. For an index, Sitemaps lists the
+// child sitemaps and URLs is empty; otherwise the reverse.
+type Sitemap struct {
+	Index    bool    `json:"index"`
+	URLs     []Entry `json:"urls"`
+	Sitemaps []Entry `json:"sitemaps"`
+}
+
+type xmlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type xmlURLSet struct {
+	URLs []xmlEntry `xml:"url"`
+}
+
+type xmlIndex struct {
+	Sitemaps []xmlEntry `xml:"sitemap"`
+}
+
+// Parse auto-detects and parses a <urlset> or <sitemapindex> document.
+// Entries without a <loc> are dropped.
+func Parse(data []byte) (*Sitemap, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("sitemap: empty data")
+	}
+
+	switch rootElement(trimmed) {
+	case "urlset":
+		var set xmlURLSet
+		if err := xml.Unmarshal(trimmed, &set); err != nil {
+			return nil, fmt.Errorf("sitemap: parse urlset: %w", err)
+		}
+		return &Sitemap{URLs: entries(set.URLs)}, nil
+	case "sitemapindex":
+		var idx xmlIndex
+		if err := xml.Unmarshal(trimmed, &idx); err != nil {
+			return nil, fmt.Errorf("sitemap: parse index: %w", err)
+		}
+		return &Sitemap{Index: true, Sitemaps: entries(idx.Sitemaps)}, nil
+	default:
+		return nil, fmt.Errorf("sitemap: unknown format (expected <urlset> or <sitemapindex>)")
+	}
+}
+
+func rootElement(data []byte) string {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return ""
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			return strings.ToLower(se.Name.Local)
+		}
+	}
+}
+
+func entries(raw []xmlEntry) []Entry {
+	out := make([]Entry, 0, len(raw))
+	for _, e := range raw {
+		loc := strings.TrimSpace(e.Loc)
+		if loc == "" {
+			continue
+		}
+		out = append(out, Entry{Loc: loc, LastMod: strings.TrimSpace(e.LastMod)})
+	}
+	return out
+}
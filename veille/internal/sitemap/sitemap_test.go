@@ -0,0 +1,74 @@
+package sitemap
+
+import "testing"
+
+const urlsetSample = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/a</loc>
+    <lastmod>2026-02-24</lastmod>
+  </url>
+  <url>
+    <loc> https://example.com/b </loc>
+  </url>
+  <url>
+    <lastmod>2026-02-24</lastmod>
+  </url>
+</urlset>`
+
+const indexSample = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://example.com/sitemap-posts.xml</loc>
+    <lastmod>2026-02-20T10:00:00Z</lastmod>
+  </sitemap>
+  <sitemap>
+    <loc>https://example.com/sitemap-pages.xml</loc>
+  </sitemap>
+</sitemapindex>`
+
+func TestParse_URLSet(t *testing.T) {
+	// WHAT: Parse a <urlset> sitemap.
+	// WHY: Page locs and lastmod drive what the sitemap source fetches.
+	sm, err := Parse([]byte(urlsetSample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if sm.Index {
+		t.Error("urlset reported as index")
+	}
+	if len(sm.URLs) != 2 {
+		t.Fatalf("urls: got %d, want 2 (entry without loc dropped)", len(sm.URLs))
+	}
+	if sm.URLs[0].LastMod != "2026-02-24" {
+		t.Errorf("lastmod: got %q", sm.URLs[0].LastMod)
+	}
+	if sm.URLs[1].Loc != "https://example.com/b" {
+		t.Errorf("loc not trimmed: %q", sm.URLs[1].Loc)
+	}
+}
+
+func TestParse_Index(t *testing.T) {
+	// WHAT: Parse a <sitemapindex> document.
+	// WHY: Large sites split their sitemap into child files behind an index.
+	sm, err := Parse([]byte(indexSample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !sm.Index {
+		t.Fatal("index not detected")
+	}
+	if len(sm.Sitemaps) != 2 || sm.Sitemaps[1].Loc != "https://example.com/sitemap-pages.xml" {
+		t.Errorf("sitemaps: got %+v", sm.Sitemaps)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	// WHAT: Non-sitemap XML and empty input are rejected.
+	// WHY: A misconfigured source must surface a parse error, not zero URLs.
+	for _, in := range []string{"", "<rss><channel/></rss>", "not xml"} {
+		if _, err := Parse([]byte(in)); err == nil {
+			t.Errorf("parse %q: expected error", in)
+		}
+	}
+}
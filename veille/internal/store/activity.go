@@ -0,0 +1,88 @@
+// CLAUDE:SUMMARY Dossier activity feed: activity_log (source mutations, question runs, fetches and extractions copied by trigger) as typed events paged by insertion sequence.
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Activity event types.
+const (
+	ActivitySourceAdded    = "source_added"
+	ActivitySourceUpdated  = "source_updated"
+	ActivitySourceDeleted  = "source_deleted"
+	ActivitySourceRestored = "source_restored"
+	ActivitySourcePurged   = "source_purged"
+	ActivityQuestionRun    = "question_run"
+	ActivityFetch          = "fetch"
+	ActivityExtraction     = "extraction"
+)
+
+// ActivityEvent is one entry of a dossier's activity feed. Type discriminates
+// the event; SubjectID is the source (or question) it concerns. Detail is the
+// source name, question text, extraction title or fetch error; Status is set
+// on fetch events, Count (new results) on question runs. Seq is the event's
+// position in the feed's insertion sequence.
+type ActivityEvent struct {
+	Seq       int64  `json:"-"`
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	At        int64  `json:"at"`
+	SubjectID string `json:"subject_id"`
+	Detail    string `json:"detail,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Count     int    `json:"count,omitempty"`
+}
+
+// logSourceActivity records a mutation of source id, with its current name.
+// Best effort: the mutation itself has already succeeded.
+func (s *Store) logSourceActivity(ctx context.Context, typ, id string) {
+	_, _ = s.DB.ExecContext(ctx,
+		`INSERT INTO activity_log (type, subject_id, detail, count, at)
+		SELECT ?, id, name, 0, ? FROM sources WHERE id = ?`,
+		typ, time.Now().UnixMilli(), id)
+}
+
+// ListActivity returns up to limit events in insertion order. With
+// afterSeq <= 0 they are the most recent ones; otherwise the first ones
+// inserted after the event of that Seq. The sequence follows commit order, so
+// polling with the last Seq seen never misses an event committed later with
+// an older timestamp (At), nor repeats one.
+func (s *Store) ListActivity(ctx context.Context, limit int, afterSeq int64) ([]*ActivityEvent, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `SELECT id, type, CASE WHEN ref_id != '' THEN ref_id ELSE CAST(id AS TEXT) END,
+		at, subject_id, detail, status, count FROM activity_log`
+	args := []any{}
+	if afterSeq > 0 {
+		query += ` WHERE id > ? ORDER BY id ASC LIMIT ?`
+		args = append(args, afterSeq, limit)
+	} else {
+		query += ` ORDER BY id DESC LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []*ActivityEvent
+	for rows.Next() {
+		var e ActivityEvent
+		if err := rows.Scan(&e.Seq, &e.Type, &e.ID, &e.At, &e.SubjectID, &e.Detail, &e.Status, &e.Count); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if afterSeq <= 0 {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,283 @@
+// CLAUDE:SUMMARY Shared content-addressed blob store (catalog DB) with refcounting, used to dedup extraction HTML and text across dossiers.
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContentSchema is the shared blob table, created in the catalog database
+// when content dedup is enabled.
+const ContentSchema = `
+CREATE TABLE IF NOT EXISTS content_blobs (
+    hash       TEXT PRIMARY KEY,
+    body       TEXT NOT NULL,
+    size       INTEGER NOT NULL,
+    refcount   INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL
+);
+`
+
+// ContentStore is a content-addressed, refcounted blob table shared by all shards.
+// Like Store, it holds no connection of its own.
+type ContentStore struct {
+	DB *sql.DB
+}
+
+// NewContentStore wraps the catalog database.
+func NewContentStore(db *sql.DB) *ContentStore {
+	return &ContentStore{DB: db}
+}
+
+// ApplyContentSchema creates the content_blobs table.
+func ApplyContentSchema(db *sql.DB) error {
+	_, err := db.Exec(ContentSchema)
+	return err
+}
+
+// ContentHash returns the blob key for body (hex SHA-256).
+func ContentHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// Acquire stores body if absent and takes one reference on it.
+// Returns the blob hash to record on the referencing row.
+func (c *ContentStore) Acquire(ctx context.Context, body string) (string, error) {
+	hash := ContentHash(body)
+	_, err := c.DB.ExecContext(ctx,
+		`INSERT INTO content_blobs (hash, body, size, refcount, created_at)
+		VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT(hash) DO UPDATE SET refcount = refcount + 1`,
+		hash, body, len(body), time.Now().UnixMilli())
+	if err != nil {
+		return "", fmt.Errorf("acquire blob: %w", err)
+	}
+	return hash, nil
+}
+
+// Release drops one reference per hash (repeat a hash to drop several) and
+// deletes blobs no longer referenced.
+func (c *ContentStore) Release(ctx context.Context, hashes ...string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, h := range hashes {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE content_blobs SET refcount = refcount - 1 WHERE hash = ?`, h); err != nil {
+			return fmt.Errorf("release blob: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM content_blobs WHERE refcount <= 0`); err != nil {
+		return fmt.Errorf("gc blobs: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Body returns the blob for hash, or "" if it does not exist.
+func (c *ContentStore) Body(ctx context.Context, hash string) (string, error) {
+	var body string
+	err := c.DB.QueryRowContext(ctx, `SELECT body FROM content_blobs WHERE hash = ?`, hash).Scan(&body)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return body, err
+}
+
+// RefCount returns the reference count of hash (0 if absent).
+func (c *ContentStore) RefCount(ctx context.Context, hash string) (int, error) {
+	var n int
+	err := c.DB.QueryRowContext(ctx, `SELECT refcount FROM content_blobs WHERE hash = ?`, hash).Scan(&n)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return n, err
+}
+
+// InsertExtractionDedup stores e with its HTML and text moved to the
+// shared content store. The text is indexed in extractions_fts in the same
+// transaction as the row, so search still finds it; readers restore both
+// with HydrateContent. With a nil cs or an empty body it behaves like
+// InsertExtraction.
+func (s *Store) InsertExtractionDedup(ctx context.Context, cs *ContentStore, e *Extraction) error {
+	if cs == nil || (e.ExtractedHTML == "" && e.ExtractedText == "") {
+		return s.InsertExtraction(ctx, e)
+	}
+	row, acquired, err := dedupRow(ctx, cs, e)
+	if err != nil {
+		return err
+	}
+	if err := s.writeDedupRow(ctx, row, e.ExtractedText, insertExtraction); err != nil {
+		_ = cs.Release(ctx, acquired...)
+		return err
+	}
+	e.ContentRef, e.TextRef = row.ContentRef, row.TextRef
+	return nil
+}
+
+// dedupRow returns the shard row of e: non-empty HTML and text are acquired
+// in cs and replaced by their refs. acquired lists the refs to release if
+// the row is not written.
+func dedupRow(ctx context.Context, cs *ContentStore, e *Extraction) (*Extraction, []string, error) {
+	row := *e
+	row.ContentRef, row.TextRef = "", ""
+	var acquired []string
+	for _, f := range []struct{ body, ref *string }{
+		{&row.ExtractedHTML, &row.ContentRef},
+		{&row.ExtractedText, &row.TextRef},
+	} {
+		if *f.body == "" {
+			continue
+		}
+		hash, err := cs.Acquire(ctx, *f.body)
+		if err != nil {
+			_ = cs.Release(ctx, acquired...)
+			return nil, nil, err
+		}
+		acquired = append(acquired, hash)
+		*f.body, *f.ref = "", hash
+	}
+	return &row, acquired, nil
+}
+
+// writeDedupRow writes row with write (insert or update) and, when its text
+// was moved out, indexes text for the row in extractions_fts — the FTS
+// triggers skip rows with a text_ref. Both happen in one transaction.
+func (s *Store) writeDedupRow(ctx context.Context, row *Extraction, text string, write func(context.Context, execer, *Extraction) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := write(ctx, tx, row); err != nil {
+		return err
+	}
+	if row.TextRef != "" {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO extractions_fts(rowid, title, extracted_text)
+			SELECT rowid, title, ? FROM extractions WHERE id = ?`, text, row.ID); err != nil {
+			return fmt.Errorf("index text: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// nonEmpty returns refs without the empty ones.
+func nonEmpty(refs []string) []string {
+	var out []string
+	for _, r := range refs {
+		if r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// HydrateContent fills ExtractedHTML and ExtractedText from the shared store
+// for extractions whose bodies were deduplicated.
+func HydrateContent(ctx context.Context, cs *ContentStore, exts []*Extraction) error {
+	for _, e := range exts {
+		if err := cs.hydrate(ctx, e.ContentRef, &e.ExtractedHTML); err != nil {
+			return fmt.Errorf("hydrate %s: %w", e.ID, err)
+		}
+		if err := cs.hydrate(ctx, e.TextRef, &e.ExtractedText); err != nil {
+			return fmt.Errorf("hydrate %s: %w", e.ID, err)
+		}
+	}
+	return nil
+}
+
+// HydrateSearchResults fills the Text of search results whose extraction
+// text was deduplicated.
+func HydrateSearchResults(ctx context.Context, cs *ContentStore, results []*SearchResult) error {
+	for _, r := range results {
+		if err := cs.hydrate(ctx, r.TextRef, &r.Text); err != nil {
+			return fmt.Errorf("hydrate %s: %w", r.ExtractionID, err)
+		}
+	}
+	return nil
+}
+
+// HydrateExtractionTexts fills the Text of deduplicated extraction texts,
+// cut to maxRunes characters like RecentExtractionTexts.
+func HydrateExtractionTexts(ctx context.Context, cs *ContentStore, texts []*ExtractionText, maxRunes int) error {
+	for _, t := range texts {
+		if err := cs.hydrate(ctx, t.TextRef, &t.Text); err != nil {
+			return fmt.Errorf("hydrate %s: %w", t.ID, err)
+		}
+		if r := []rune(t.Text); len(r) > maxRunes {
+			t.Text = string(r[:maxRunes])
+		}
+	}
+	return nil
+}
+
+// hydrate sets *dst to the blob of ref when ref is set and *dst is empty.
+func (c *ContentStore) hydrate(ctx context.Context, ref string, dst *string) error {
+	if ref == "" || *dst != "" || c == nil {
+		return nil
+	}
+	body, err := c.Body(ctx, ref)
+	if err != nil {
+		return err
+	}
+	*dst = body
+	return nil
+}
+
+// ContentRefs returns one entry per reference to the shared store (HTML and
+// text of each extraction), restricted to the given sources, or all extractions if none are given.
+// Callers release them after deleting the rows.
+func (s *Store) ContentRefs(ctx context.Context, sourceIDs ...string) ([]string, error) {
+	query := `SELECT content_ref, text_ref FROM extractions WHERE (content_ref != '' OR text_ref != '')`
+	var args []any
+	if len(sourceIDs) > 0 {
+		query += ` AND source_id IN (?` + strings.Repeat(", ?", len(sourceIDs)-1) + `)`
+		for _, id := range sourceIDs {
+			args = append(args, id)
+		}
+	}
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRefs(rows)
+}
+
+// PurgeableContentRefs returns the content refs (HTML and text) of extractions belonging to
+// sources soft-deleted at or before cutoff (ms), i.e. those
+// PurgeDeletedSources would remove.
+func (s *Store) PurgeableContentRefs(ctx context.Context, cutoff int64) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT e.content_ref, e.text_ref FROM extractions e JOIN sources s ON s.id = e.source_id
+		WHERE (e.content_ref != '' OR e.text_ref != '') AND s.deleted_at IS NOT NULL AND s.deleted_at <= ?`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRefs(rows)
+}
+
+// scanRefs collects the non-empty (content_ref, text_ref) pairs of rows.
+func scanRefs(rows *sql.Rows) ([]string, error) {
+	var refs []string
+	for rows.Next() {
+		var html, text string
+		if err := rows.Scan(&html, &text); err != nil {
+			return nil, err
+		}
+		refs = append(refs, nonEmpty([]string{html, text})...)
+	}
+	return refs, rows.Err()
+}
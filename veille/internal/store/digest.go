@@ -0,0 +1,131 @@
+// CLAUDE:SUMMARY Digest subscriptions per channel: upsert keeping the watermark, list, delete, advance; extractions inserted after an activity_log sequence.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// UpsertDigest registers a digest for d.Channel or updates its settings.
+// A new digest starts its watermark at d.LastSeq; an existing digest keeps
+// its watermark and last send time.
+func (s *Store) UpsertDigest(ctx context.Context, d *Digest) error {
+	now := time.Now().UnixMilli()
+	if d.CreatedAt == 0 {
+		d.CreatedAt = now
+	}
+	d.UpdatedAt = now
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO digests (channel, mode, interval_ms, max_items, last_seq,
+		created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(channel) DO UPDATE SET
+			mode = excluded.mode, interval_ms = excluded.interval_ms,
+			max_items = excluded.max_items, updated_at = excluded.updated_at`,
+		d.Channel, d.Mode, d.IntervalMs, d.MaxItems, d.LastSeq,
+		d.CreatedAt, d.UpdatedAt,
+	)
+	return err
+}
+
+// GetDigest returns the digest for channel, or nil if none is registered.
+func (s *Store) GetDigest(ctx context.Context, channel string) (*Digest, error) {
+	row := s.DB.QueryRowContext(ctx,
+		`SELECT channel, mode, interval_ms, max_items, last_seq, last_sent_at,
+		created_at, updated_at
+		FROM digests WHERE channel = ?`, channel)
+	var d Digest
+	err := row.Scan(&d.Channel, &d.Mode, &d.IntervalMs, &d.MaxItems, &d.LastSeq,
+		&d.LastSentAt, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan digest: %w", err)
+	}
+	return &d, nil
+}
+
+// ListDigests returns every digest of the shard, ordered by channel.
+func (s *Store) ListDigests(ctx context.Context) ([]*Digest, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT channel, mode, interval_ms, max_items, last_seq, last_sent_at,
+		created_at, updated_at
+		FROM digests ORDER BY channel`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Digest
+	for rows.Next() {
+		var d Digest
+		if err := rows.Scan(&d.Channel, &d.Mode, &d.IntervalMs, &d.MaxItems, &d.LastSeq,
+			&d.LastSentAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan digest: %w", err)
+		}
+		result = append(result, &d)
+	}
+	return result, rows.Err()
+}
+
+// DeleteDigest removes the digest for channel.
+func (s *Store) DeleteDigest(ctx context.Context, channel string) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM digests WHERE channel = ?`, channel)
+	return err
+}
+
+// AdvanceDigest moves the watermark of channel to lastSeq and records
+// sentAt as the last send time.
+func (s *Store) AdvanceDigest(ctx context.Context, channel string, lastSeq, sentAt int64) error {
+	_, err := s.DB.ExecContext(ctx,
+		`UPDATE digests SET last_seq = ?, last_sent_at = ?, updated_at = ?
+		WHERE channel = ?`, lastSeq, sentAt, sentAt, channel)
+	return err
+}
+
+// ListExtractionsAfter returns extractions of live sources inserted after
+// the activity_log sequence afterSeq, in insertion order, annotated with
+// their source and sequence. Extractions deleted since are skipped.
+func (s *Store) ListExtractionsAfter(ctx context.Context, afterSeq int64, limit int) ([]*DigestItem, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT a.id, e.id, e.source_id, e.content_hash, e.title, e.extracted_text, e.extracted_html,
+		e.url, e.extracted_at, e.metadata_json, e.content_ref, e.text_ref, e.lang, s.name, s.source_type
+		FROM activity_log a
+		JOIN extractions e ON e.id = a.ref_id
+		JOIN sources s ON s.id = e.source_id
+		WHERE a.id > ? AND a.type = 'extraction' AND s.deleted_at IS NULL
+		ORDER BY a.id ASC LIMIT ?`,
+		afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*DigestItem
+	for rows.Next() {
+		item := &DigestItem{DossierExtraction: &DossierExtraction{}}
+		e := &item.Extraction
+		if err := rows.Scan(&item.Seq, &e.ID, &e.SourceID, &e.ContentHash, &e.Title, &e.ExtractedText,
+			&e.ExtractedHTML, &e.URL, &e.ExtractedAt, &e.MetadataJSON, &e.ContentRef, &e.TextRef, &e.Lang,
+			&item.SourceName, &item.SourceType); err != nil {
+			return nil, fmt.Errorf("scan extraction: %w", err)
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
+
+// LatestExtractionSeq returns the activity_log sequence of the newest
+// extraction insert in the shard, or 0 if there is none.
+func (s *Store) LatestExtractionSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(id), 0) FROM activity_log WHERE type = 'extraction'`).Scan(&seq)
+	return seq, err
+}
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Extraction CRUD: insert with FTS5 sync, list by source, existence check for dedup.
+// CLAUDE:SUMMARY Extraction CRUD: insert with FTS5 sync, list by source (optionally by language), pin flag, existence check for dedup.
 package store
 
 import (
@@ -9,15 +9,28 @@ import (
 
 // InsertExtraction stores a new extraction.
 func (s *Store) InsertExtraction(ctx context.Context, e *Extraction) error {
+	return insertExtraction(ctx, s.DB, e)
+}
+
+// execer is implemented by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertExtraction is InsertExtraction on db or a transaction.
+func insertExtraction(ctx context.Context, db execer, e *Extraction) error {
 	if e.MetadataJSON == "" {
 		e.MetadataJSON = "{}"
 	}
-	_, err := s.DB.ExecContext(ctx,
+	if e.Lang == "" {
+		e.Lang = LangUndetermined
+	}
+	_, err := db.ExecContext(ctx,
 		`INSERT INTO extractions (id, source_id, content_hash, title, extracted_text,
-		extracted_html, url, extracted_at, metadata_json)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		extracted_html, url, extracted_at, metadata_json, content_ref, text_ref, lang)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		e.ID, e.SourceID, e.ContentHash, e.Title, e.ExtractedText,
-		e.ExtractedHTML, e.URL, e.ExtractedAt, e.MetadataJSON,
+		e.ExtractedHTML, e.URL, e.ExtractedAt, e.MetadataJSON, e.ContentRef, e.TextRef, e.Lang,
 	)
 	return err
 }
@@ -26,12 +39,12 @@ func (s *Store) InsertExtraction(ctx context.Context, e *Extraction) error {
 func (s *Store) GetExtraction(ctx context.Context, id string) (*Extraction, error) {
 	row := s.DB.QueryRowContext(ctx,
 		`SELECT id, source_id, content_hash, title, extracted_text, extracted_html,
-		url, extracted_at, metadata_json
+		url, extracted_at, metadata_json, content_ref, text_ref, lang, pinned
 		FROM extractions WHERE id = ?`, id)
 
 	var e Extraction
 	err := row.Scan(&e.ID, &e.SourceID, &e.ContentHash, &e.Title, &e.ExtractedText,
-		&e.ExtractedHTML, &e.URL, &e.ExtractedAt, &e.MetadataJSON)
+		&e.ExtractedHTML, &e.URL, &e.ExtractedAt, &e.MetadataJSON, &e.ContentRef, &e.TextRef, &e.Lang, &e.Pinned)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -43,14 +56,28 @@ func (s *Store) GetExtraction(ctx context.Context, id string) (*Extraction, erro
 
 // ListExtractions returns extractions for a source, newest first.
 func (s *Store) ListExtractions(ctx context.Context, sourceID string, limit int) ([]*Extraction, error) {
+	return s.ListExtractionsLang(ctx, sourceID, "", limit)
+}
+
+// ListExtractionsLang is ListExtractions restricted to extractions detected
+// as lang. An empty lang does not filter.
+func (s *Store) ListExtractionsLang(ctx context.Context, sourceID, lang string, limit int) ([]*Extraction, error) {
+	return s.ListExtractionsPage(ctx, sourceID, lang, limit, 0, "")
+}
+
+// ListExtractionsPage is ListExtractionsLang with keyset pagination: pass the
+// (extracted_at, id) of the last row seen, or beforeAt <= 0 to start.
+func (s *Store) ListExtractionsPage(ctx context.Context, sourceID, lang string, limit int, beforeAt int64, beforeID string) ([]*Extraction, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 	rows, err := s.DB.QueryContext(ctx,
 		`SELECT id, source_id, content_hash, title, extracted_text, extracted_html,
-		url, extracted_at, metadata_json
-		FROM extractions WHERE source_id = ?
-		ORDER BY extracted_at DESC LIMIT ?`, sourceID, limit)
+		url, extracted_at, metadata_json, content_ref, text_ref, lang, pinned
+		FROM extractions WHERE source_id = ? AND (? = '' OR lang = ?)
+		AND (? <= 0 OR extracted_at < ? OR (extracted_at = ? AND id < ?))
+		ORDER BY extracted_at DESC, id DESC LIMIT ?`,
+		sourceID, lang, lang, beforeAt, beforeAt, beforeAt, beforeID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +87,7 @@ func (s *Store) ListExtractions(ctx context.Context, sourceID string, limit int)
 	for rows.Next() {
 		var e Extraction
 		if err := rows.Scan(&e.ID, &e.SourceID, &e.ContentHash, &e.Title, &e.ExtractedText,
-			&e.ExtractedHTML, &e.URL, &e.ExtractedAt, &e.MetadataJSON); err != nil {
+			&e.ExtractedHTML, &e.URL, &e.ExtractedAt, &e.MetadataJSON, &e.ContentRef, &e.TextRef, &e.Lang, &e.Pinned); err != nil {
 			return nil, fmt.Errorf("scan extraction: %w", err)
 		}
 		result = append(result, &e)
@@ -68,6 +95,65 @@ func (s *Store) ListExtractions(ctx context.Context, sourceID string, limit int)
 	return result, rows.Err()
 }
 
+// ListDossierExtractions returns extractions across all live sources of the
+// shard, newest first, annotated with their source. Pagination is keyset:
+// pass the (extracted_at, id) of the last row seen, or beforeAt <= 0 to start.
+// A non-empty lang keeps only extractions detected as that language;
+// pinnedOnly keeps only pinned extractions.
+func (s *Store) ListDossierExtractions(ctx context.Context, limit int, beforeAt int64, beforeID, lang string, pinnedOnly bool) ([]*DossierExtraction, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `SELECT e.id, e.source_id, e.content_hash, e.title, e.extracted_text, e.extracted_html,
+		e.url, e.extracted_at, e.metadata_json, e.content_ref, e.text_ref, e.lang, e.pinned, s.name, s.source_type
+		FROM extractions e JOIN sources s ON s.id = e.source_id
+		WHERE s.deleted_at IS NULL`
+	args := []any{}
+	if lang != "" {
+		query += ` AND e.lang = ?`
+		args = append(args, lang)
+	}
+	if pinnedOnly {
+		query += ` AND e.pinned = 1`
+	}
+	if beforeAt > 0 {
+		query += ` AND (e.extracted_at < ? OR (e.extracted_at = ? AND e.id < ?))`
+		args = append(args, beforeAt, beforeAt, beforeID)
+	}
+	query += ` ORDER BY e.extracted_at DESC, e.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*DossierExtraction
+	for rows.Next() {
+		var d DossierExtraction
+		e := &d.Extraction
+		if err := rows.Scan(&e.ID, &e.SourceID, &e.ContentHash, &e.Title, &e.ExtractedText,
+			&e.ExtractedHTML, &e.URL, &e.ExtractedAt, &e.MetadataJSON, &e.ContentRef, &e.TextRef, &e.Lang, &e.Pinned,
+			&d.SourceName, &d.SourceType); err != nil {
+			return nil, fmt.Errorf("scan extraction: %w", err)
+		}
+		result = append(result, &d)
+	}
+	return result, rows.Err()
+}
+
+// SetExtractionPinned sets the pin flag of an extraction. Returns false if
+// the extraction does not exist.
+func (s *Store) SetExtractionPinned(ctx context.Context, id string, pinned bool) (bool, error) {
+	res, err := s.DB.ExecContext(ctx, `UPDATE extractions SET pinned = ? WHERE id = ?`, pinned, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 // ExtractionExists checks if an extraction with the given source and content hash exists.
 // Used for deduplication in RSS/API pipelines to avoid re-processing identical content.
 func (s *Store) ExtractionExists(ctx context.Context, sourceID, contentHash string) (bool, error) {
@@ -20,6 +20,12 @@ func (s *Store) InsertFetchLog(ctx context.Context, entry *FetchLogEntry) error
 
 // FetchHistory returns fetch log entries for a source, newest first.
 func (s *Store) FetchHistory(ctx context.Context, sourceID string, limit int) ([]*FetchLogEntry, error) {
+	return s.FetchHistoryPage(ctx, sourceID, limit, 0, "")
+}
+
+// FetchHistoryPage is FetchHistory with keyset pagination: pass the
+// (fetched_at, id) of the last entry seen, or beforeAt <= 0 to start.
+func (s *Store) FetchHistoryPage(ctx context.Context, sourceID string, limit int, beforeAt int64, beforeID string) ([]*FetchLogEntry, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -27,7 +33,9 @@ func (s *Store) FetchHistory(ctx context.Context, sourceID string, limit int) ([
 		`SELECT id, source_id, status, status_code, content_hash,
 		error_message, duration_ms, fetched_at
 		FROM fetch_log WHERE source_id = ?
-		ORDER BY fetched_at DESC LIMIT ?`, sourceID, limit)
+		AND (? <= 0 OR fetched_at < ? OR (fetched_at = ? AND id < ?))
+		ORDER BY fetched_at DESC, id DESC LIMIT ?`,
+		sourceID, beforeAt, beforeAt, beforeAt, beforeID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,111 @@
+// CLAUDE:SUMMARY FTS5 maintenance on the contentless extractions_fts: integrity and rowid check against the extractions table, full rebuild including deduplicated texts.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFTSDrift is returned by CheckFTSIntegrity when extractions_fts no longer
+// matches the extractions table.
+var ErrFTSDrift = errors.New("store: FTS index out of sync with extractions")
+
+// CheckFTSIntegrity runs the FTS5 integrity-check on extractions_fts, then
+// compares the indexed rowids with the extractions rows. The index is
+// contentless, so the tokens of a row are not compared with its text: a row
+// edited behind the triggers goes unnoticed, a row inserted or deleted
+// behind them does not. Drift is reported as ErrFTSDrift; other errors are
+// returned as is.
+func (s *Store) CheckFTSIntegrity(ctx context.Context) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO extractions_fts(extractions_fts) VALUES('integrity-check')`)
+	if err != nil {
+		// SQLITE_CORRUPT_VTAB: "database disk image is malformed".
+		if strings.Contains(err.Error(), "malformed") {
+			return fmt.Errorf("%w: %v", ErrFTSDrift, err)
+		}
+		return fmt.Errorf("fts integrity-check: %w", err)
+	}
+	var missing, orphans int
+	if err := s.DB.QueryRowContext(ctx,
+		`SELECT
+			(SELECT COUNT(*) FROM extractions e WHERE NOT EXISTS
+				(SELECT 1 FROM extractions_fts_docsize d WHERE d.id = e.rowid)),
+			(SELECT COUNT(*) FROM extractions_fts_docsize d WHERE NOT EXISTS
+				(SELECT 1 FROM extractions e WHERE e.rowid = d.id))`).Scan(&missing, &orphans); err != nil {
+		return fmt.Errorf("fts rowid check: %w", err)
+	}
+	if missing > 0 || orphans > 0 {
+		return fmt.Errorf("%w: %d extractions not indexed, %d index rows without extraction", ErrFTSDrift, missing, orphans)
+	}
+	return nil
+}
+
+// RebuildFTS rebuilds extractions_fts from the extractions table, reading
+// deduplicated texts from cs, then merges the index segments. Each step is
+// a single write transaction: shards run in WAL mode, so readers keep using
+// the previous index until commit and only writers wait. Deduplicated rows
+// with a nil cs fail the rebuild rather than lose their text.
+func (s *Store) RebuildFTS(ctx context.Context, cs *ContentStore) error {
+	type dedupText struct {
+		rowid      int64
+		title, ref string
+	}
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT rowid, title, text_ref FROM extractions WHERE text_ref != ''`)
+	if err != nil {
+		return fmt.Errorf("fts rebuild: %w", err)
+	}
+	var dedup []dedupText
+	for rows.Next() {
+		var d dedupText
+		if err := rows.Scan(&d.rowid, &d.title, &d.ref); err != nil {
+			rows.Close()
+			return fmt.Errorf("fts rebuild: %w", err)
+		}
+		dedup = append(dedup, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("fts rebuild: %w", err)
+	}
+	if len(dedup) > 0 && cs == nil {
+		return fmt.Errorf("fts rebuild: %d deduplicated texts and no content store", len(dedup))
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO extractions_fts(extractions_fts) VALUES('delete-all')`); err != nil {
+		return fmt.Errorf("fts rebuild: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO extractions_fts(rowid, title, extracted_text)
+		SELECT rowid, title, extracted_text FROM extractions WHERE text_ref = ''`); err != nil {
+		return fmt.Errorf("fts rebuild: %w", err)
+	}
+	for _, d := range dedup {
+		text, err := cs.Body(ctx, d.ref)
+		if err != nil {
+			return fmt.Errorf("fts rebuild: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO extractions_fts(rowid, title, extracted_text) VALUES (?, ?, ?)`,
+			d.rowid, d.title, text); err != nil {
+			return fmt.Errorf("fts rebuild: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("fts rebuild: %w", err)
+	}
+	if _, err := s.DB.ExecContext(ctx,
+		`INSERT INTO extractions_fts(extractions_fts) VALUES('optimize')`); err != nil {
+		return fmt.Errorf("fts optimize: %w", err)
+	}
+	return nil
+}
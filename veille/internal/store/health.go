@@ -0,0 +1,79 @@
+// CLAUDE:SUMMARY Source health tracking for webhooks: current vs last notified state per source, and the per-dossier health webhook.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SourceHealth is the health of a source as last seen and as last notified.
+type SourceHealth struct {
+	SourceID      string
+	State         string
+	NotifiedState string
+	ChangedAt     int64 // ms, when State last changed
+	NotifiedAt    int64 // ms, 0 = never notified
+}
+
+// GetSourceHealth returns the health row of a source, or nil if none.
+func (s *Store) GetSourceHealth(ctx context.Context, sourceID string) (*SourceHealth, error) {
+	var h SourceHealth
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT source_id, state, notified_state, changed_at, notified_at
+		FROM source_health WHERE source_id = ?`, sourceID).
+		Scan(&h.SourceID, &h.State, &h.NotifiedState, &h.ChangedAt, &h.NotifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// PutSourceHealth inserts or replaces the health row of a source.
+func (s *Store) PutSourceHealth(ctx context.Context, h *SourceHealth) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT OR REPLACE INTO source_health (source_id, state, notified_state, changed_at, notified_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		h.SourceID, h.State, h.NotifiedState, h.ChangedAt, h.NotifiedAt)
+	return err
+}
+
+// HealthWebhook is the per-dossier health webhook.
+type HealthWebhook struct {
+	URL       string `json:"url"`
+	Secret    string `json:"-"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// GetHealthWebhook returns the dossier's health webhook, or nil if none.
+func (s *Store) GetHealthWebhook(ctx context.Context) (*HealthWebhook, error) {
+	var w HealthWebhook
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT url, secret, updated_at FROM health_webhook WHERE id = 1`).
+		Scan(&w.URL, &w.Secret, &w.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// SetHealthWebhook sets the dossier's health webhook.
+func (s *Store) SetHealthWebhook(ctx context.Context, url, secret string) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT OR REPLACE INTO health_webhook (id, url, secret, updated_at) VALUES (1, ?, ?, ?)`,
+		url, secret, time.Now().UnixMilli())
+	return err
+}
+
+// DeleteHealthWebhook removes the dossier's health webhook.
+func (s *Store) DeleteHealthWebhook(ctx context.Context) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM health_webhook WHERE id = 1`)
+	return err
+}
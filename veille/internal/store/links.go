@@ -0,0 +1,50 @@
+// CLAUDE:SUMMARY Outbound links of extractions, stored in page order for link following and the links API.
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// InsertLinks stores the outbound links of an extraction, in page order.
+func (s *Store) InsertLinks(ctx context.Context, extractionID string, links []string) error {
+	if len(links) == 0 {
+		return nil
+	}
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT OR IGNORE INTO links (extraction_id, position, url) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for i, u := range links {
+		if _, err := stmt.ExecContext(ctx, extractionID, i, u); err != nil {
+			return fmt.Errorf("insert link: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListLinks returns the outbound links of an extraction, in page order.
+func (s *Store) ListLinks(ctx context.Context, extractionID string) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT url FROM links WHERE extraction_id = ? ORDER BY position`, extractionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var links []string
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			return nil, err
+		}
+		links = append(links, u)
+	}
+	return links, rows.Err()
+}
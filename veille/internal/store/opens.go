@@ -0,0 +1,49 @@
+// CLAUDE:SUMMARY Result open (click-through) log: one row per extraction opened from a result list, with the originating query, and most-opened ranking.
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// StatsMostOpened is the number of most-opened extractions reported by Stats.
+const StatsMostOpened = 10
+
+// LogResultOpen records that extractionID was opened from the results of
+// query. It is a single INSERT guarded by the extraction's existence;
+// ok is false when the extraction is not in this shard.
+func (s *Store) LogResultOpen(ctx context.Context, extractionID, query string) (ok bool, err error) {
+	res, err := s.DB.ExecContext(ctx,
+		`INSERT INTO result_opens (extraction_id, query, opened_at)
+		SELECT id, ?, ? FROM extractions WHERE id = ?`,
+		query, time.Now().UnixMilli(), extractionID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MostOpened returns the limit extractions with the most opens, most
+// recently opened first on ties.
+func (s *Store) MostOpened(ctx context.Context, limit int) ([]*OpenedExtraction, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT o.extraction_id, e.title, e.url, COUNT(*) AS opens, MAX(o.opened_at) AS last
+		FROM result_opens o JOIN extractions e ON e.id = o.extraction_id
+		GROUP BY o.extraction_id
+		ORDER BY opens DESC, last DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*OpenedExtraction
+	for rows.Next() {
+		var o OpenedExtraction
+		if err := rows.Scan(&o.ExtractionID, &o.Title, &o.URL, &o.Opens, &o.LastOpenedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, &o)
+	}
+	return out, rows.Err()
+}
@@ -26,15 +26,19 @@ func (s *Store) InsertQuestion(ctx context.Context, q *TrackedQuestion) error {
 	if q.MaxResults == 0 {
 		q.MaxResults = 20
 	}
+	if q.NotifyMinResults <= 0 {
+		q.NotifyMinResults = 1
+	}
 
 	_, err := s.DB.ExecContext(ctx,
 		`INSERT INTO tracked_questions (id, text, keywords, channels, schedule_ms,
-		max_results, follow_links, enabled, last_run_at, last_result_count,
-		total_results, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		max_results, follow_links, follow_depth, enabled, last_run_at, last_result_count,
+		total_results, notify_min_results, notify_pattern, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		q.ID, q.Text, q.Keywords, q.Channels, q.ScheduleMs,
-		q.MaxResults, q.FollowLinks, q.Enabled, q.LastRunAt,
-		q.LastResultCount, q.TotalResults, q.CreatedAt, q.UpdatedAt,
+		q.MaxResults, q.FollowLinks, q.FollowDepth, q.Enabled, q.LastRunAt,
+		q.LastResultCount, q.TotalResults, q.NotifyMinResults, q.NotifyPattern,
+		q.CreatedAt, q.UpdatedAt,
 	)
 	return err
 }
@@ -43,8 +47,8 @@ func (s *Store) InsertQuestion(ctx context.Context, q *TrackedQuestion) error {
 func (s *Store) GetQuestion(ctx context.Context, id string) (*TrackedQuestion, error) {
 	row := s.DB.QueryRowContext(ctx,
 		`SELECT id, text, keywords, channels, schedule_ms, max_results,
-		follow_links, enabled, last_run_at, last_result_count, total_results,
-		created_at, updated_at
+		follow_links, follow_depth, enabled, last_run_at, last_result_count, total_results,
+		notify_min_results, notify_pattern, created_at, updated_at
 		FROM tracked_questions WHERE id = ?`, id)
 	return scanQuestion(row)
 }
@@ -53,8 +57,8 @@ func (s *Store) GetQuestion(ctx context.Context, id string) (*TrackedQuestion, e
 func (s *Store) ListQuestions(ctx context.Context) ([]*TrackedQuestion, error) {
 	rows, err := s.DB.QueryContext(ctx,
 		`SELECT id, text, keywords, channels, schedule_ms, max_results,
-		follow_links, enabled, last_run_at, last_result_count, total_results,
-		created_at, updated_at
+		follow_links, follow_depth, enabled, last_run_at, last_result_count, total_results,
+		notify_min_results, notify_pattern, created_at, updated_at
 		FROM tracked_questions ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, err
@@ -75,16 +79,55 @@ func (s *Store) ListQuestions(ctx context.Context) ([]*TrackedQuestion, error) {
 // UpdateQuestion updates a tracked question's mutable fields.
 func (s *Store) UpdateQuestion(ctx context.Context, q *TrackedQuestion) error {
 	q.UpdatedAt = time.Now().UnixMilli()
+	if q.NotifyMinResults <= 0 {
+		q.NotifyMinResults = 1
+	}
 	_, err := s.DB.ExecContext(ctx,
 		`UPDATE tracked_questions SET text=?, keywords=?, channels=?,
-		schedule_ms=?, max_results=?, follow_links=?, enabled=?, updated_at=?
+		schedule_ms=?, max_results=?, follow_links=?, follow_depth=?, enabled=?,
+		notify_min_results=?, notify_pattern=?, updated_at=?
 		WHERE id=?`,
 		q.Text, q.Keywords, q.Channels, q.ScheduleMs,
-		q.MaxResults, q.FollowLinks, q.Enabled, q.UpdatedAt, q.ID,
+		q.MaxResults, q.FollowLinks, q.FollowDepth, q.Enabled,
+		q.NotifyMinResults, q.NotifyPattern, q.UpdatedAt, q.ID,
 	)
 	return err
 }
 
+// SetQuestionsEnabled sets the enabled flag of the questions among ids, and
+// of their auto-sources, in one transaction. It returns how many questions
+// matched and the IDs matching none.
+func (s *Store) SetQuestionsEnabled(ctx context.Context, ids []string, enabled bool) (int, []string, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+	now := time.Now().UnixMilli()
+	updated, missing := 0, []string{}
+	for _, id := range ids {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE tracked_questions SET enabled=?, updated_at=? WHERE id=?`, enabled, now, id)
+		if err != nil {
+			return 0, nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			missing = append(missing, id)
+			continue
+		}
+		updated++
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE sources SET enabled=?, updated_at=? WHERE id=? AND source_type='question'`,
+			enabled, now, id); err != nil {
+			return 0, nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return updated, missing, nil
+}
+
 // DeleteQuestion removes a tracked question by ID.
 func (s *Store) DeleteQuestion(ctx context.Context, id string) error {
 	_, err := s.DB.ExecContext(ctx, `DELETE FROM tracked_questions WHERE id = ?`, id)
@@ -98,8 +141,8 @@ func (s *Store) DueQuestions(ctx context.Context) ([]*TrackedQuestion, error) {
 	now := time.Now().UnixMilli()
 	rows, err := s.DB.QueryContext(ctx,
 		`SELECT id, text, keywords, channels, schedule_ms, max_results,
-		follow_links, enabled, last_run_at, last_result_count, total_results,
-		created_at, updated_at
+		follow_links, follow_depth, enabled, last_run_at, last_result_count, total_results,
+		notify_min_results, notify_pattern, created_at, updated_at
 		FROM tracked_questions
 		WHERE enabled = 1
 		  AND (last_run_at IS NULL OR last_run_at + schedule_ms <= ?)
@@ -127,7 +170,14 @@ func (s *Store) RecordQuestionRun(ctx context.Context, id string, newCount int)
 		`UPDATE tracked_questions SET last_run_at=?, last_result_count=?,
 		total_results=total_results+?, updated_at=?
 		WHERE id=?`, now, newCount, newCount, now, id)
-	return err
+	if err != nil {
+		return err
+	}
+	_, _ = s.DB.ExecContext(ctx,
+		`INSERT INTO activity_log (type, subject_id, detail, count, at)
+		SELECT ?, id, text, ?, ? FROM tracked_questions WHERE id = ?`,
+		ActivityQuestionRun, newCount, now, id)
+	return nil
 }
 
 func scanQuestion(row *sql.Row) (*TrackedQuestion, error) {
@@ -135,8 +185,9 @@ func scanQuestion(row *sql.Row) (*TrackedQuestion, error) {
 	var enabled, followLinks int
 	err := row.Scan(
 		&q.ID, &q.Text, &q.Keywords, &q.Channels, &q.ScheduleMs,
-		&q.MaxResults, &followLinks, &enabled, &q.LastRunAt,
-		&q.LastResultCount, &q.TotalResults, &q.CreatedAt, &q.UpdatedAt,
+		&q.MaxResults, &followLinks, &q.FollowDepth, &enabled, &q.LastRunAt,
+		&q.LastResultCount, &q.TotalResults, &q.NotifyMinResults, &q.NotifyPattern,
+		&q.CreatedAt, &q.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -154,8 +205,9 @@ func scanQuestionRows(rows *sql.Rows) (*TrackedQuestion, error) {
 	var enabled, followLinks int
 	err := rows.Scan(
 		&q.ID, &q.Text, &q.Keywords, &q.Channels, &q.ScheduleMs,
-		&q.MaxResults, &followLinks, &enabled, &q.LastRunAt,
-		&q.LastResultCount, &q.TotalResults, &q.CreatedAt, &q.UpdatedAt,
+		&q.MaxResults, &followLinks, &q.FollowDepth, &enabled, &q.LastRunAt,
+		&q.LastResultCount, &q.TotalResults, &q.NotifyMinResults, &q.NotifyPattern,
+		&q.CreatedAt, &q.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("scan question: %w", err)
@@ -0,0 +1,63 @@
+// CLAUDE:SUMMARY Per-dossier quota overrides (catalog DB): max_sources replacing the default source limit of a dossier.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// QuotaSchema is the quota override table, created in the catalog database.
+const QuotaSchema = `
+CREATE TABLE IF NOT EXISTS dossier_quotas (
+    dossier_id  TEXT PRIMARY KEY,
+    max_sources INTEGER NOT NULL,
+    updated_at  INTEGER NOT NULL
+);
+`
+
+// ApplyQuotaSchema creates the dossier_quotas table.
+func ApplyQuotaSchema(db *sql.DB) error {
+	_, err := db.Exec(QuotaSchema)
+	return err
+}
+
+// QuotaStore reads and writes quota overrides in the catalog database.
+// Like Store, it holds no connection of its own.
+type QuotaStore struct {
+	DB *sql.DB
+}
+
+// NewQuotaStore wraps the catalog database.
+func NewQuotaStore(db *sql.DB) *QuotaStore {
+	return &QuotaStore{DB: db}
+}
+
+// MaxSources returns the max_sources override of dossierID; ok is false
+// when the dossier has none.
+func (q *QuotaStore) MaxSources(ctx context.Context, dossierID string) (n int, ok bool, err error) {
+	err = q.DB.QueryRowContext(ctx,
+		`SELECT max_sources FROM dossier_quotas WHERE dossier_id = ?`, dossierID).Scan(&n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return n, true, nil
+}
+
+// SetMaxSources sets the max_sources override of dossierID.
+func (q *QuotaStore) SetMaxSources(ctx context.Context, dossierID string, n int) error {
+	_, err := q.DB.ExecContext(ctx,
+		`INSERT OR REPLACE INTO dossier_quotas (dossier_id, max_sources, updated_at) VALUES (?, ?, ?)`,
+		dossierID, n, time.Now().UnixMilli())
+	return err
+}
+
+// DeleteMaxSources removes the override of dossierID.
+func (q *QuotaStore) DeleteMaxSources(ctx context.Context, dossierID string) error {
+	_, err := q.DB.ExecContext(ctx, `DELETE FROM dossier_quotas WHERE dossier_id = ?`, dossierID)
+	return err
+}
@@ -0,0 +1,89 @@
+// CLAUDE:SUMMARY Raw response body retention (newest N per source) and in-place extraction updates for reprocessing.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PutRawBody keeps body and its Content-Type header as the raw response
+// behind extractionID, then drops the source's older raw bodies beyond the
+// newest keep.
+func (s *Store) PutRawBody(ctx context.Context, extractionID, sourceID, contentType string, body []byte, keep int) error {
+	if _, err := s.DB.ExecContext(ctx,
+		`INSERT OR REPLACE INTO raw_bodies (extraction_id, source_id, body, content_type, stored_at) VALUES (?, ?, ?, ?, ?)`,
+		extractionID, sourceID, body, contentType, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+	_, err := s.DB.ExecContext(ctx,
+		`DELETE FROM raw_bodies WHERE source_id = ? AND extraction_id NOT IN (
+			SELECT extraction_id FROM raw_bodies WHERE source_id = ?
+			ORDER BY stored_at DESC, rowid DESC LIMIT ?)`,
+		sourceID, sourceID, keep)
+	return err
+}
+
+// RawBody returns the raw response kept for an extraction and its
+// Content-Type, or a nil body if none.
+func (s *Store) RawBody(ctx context.Context, extractionID string) ([]byte, string, error) {
+	var body []byte
+	var contentType string
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT body, content_type FROM raw_bodies WHERE extraction_id = ?`, extractionID).Scan(&body, &contentType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", nil
+	}
+	return body, contentType, err
+}
+
+// UpdateExtractionContent rewrites the extracted fields of e in place
+// (hash, title, text, HTML, metadata, lang, content_ref, text_ref); ID,
+// source, URL and extracted_at are kept. The FTS5 update trigger reindexes
+// the row.
+func (s *Store) UpdateExtractionContent(ctx context.Context, e *Extraction) error {
+	return updateExtractionContent(ctx, s.DB, e)
+}
+
+// updateExtractionContent is UpdateExtractionContent on db or a transaction.
+func updateExtractionContent(ctx context.Context, db execer, e *Extraction) error {
+	if e.MetadataJSON == "" {
+		e.MetadataJSON = "{}"
+	}
+	if e.Lang == "" {
+		e.Lang = LangUndetermined
+	}
+	res, err := db.ExecContext(ctx,
+		`UPDATE extractions SET content_hash = ?, title = ?, extracted_text = ?,
+		extracted_html = ?, metadata_json = ?, content_ref = ?, text_ref = ?, lang = ?
+		WHERE id = ?`,
+		e.ContentHash, e.Title, e.ExtractedText, e.ExtractedHTML, e.MetadataJSON,
+		e.ContentRef, e.TextRef, e.Lang, e.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateExtractionDedup is UpdateExtractionContent with the HTML and text
+// moved to cs, like InsertExtractionDedup. The blobs previously referenced
+// by the row (oldRefs) are released once the update succeeds.
+func (s *Store) UpdateExtractionDedup(ctx context.Context, cs *ContentStore, e *Extraction, oldRefs ...string) error {
+	if cs == nil {
+		return s.UpdateExtractionContent(ctx, e)
+	}
+	row, acquired, err := dedupRow(ctx, cs, e)
+	if err != nil {
+		return err
+	}
+	if err := s.writeDedupRow(ctx, row, e.ExtractedText, updateExtractionContent); err != nil {
+		_ = cs.Release(ctx, acquired...)
+		return err
+	}
+	e.ContentRef, e.TextRef = row.ContentRef, row.TextRef
+	return cs.Release(ctx, nonEmpty(oldRefs)...)
+}
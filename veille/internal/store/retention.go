@@ -0,0 +1,155 @@
+// CLAUDE:SUMMARY Per-dossier extraction retention policy (keep_days / max_rows) and batched pruning of the unpinned extractions outside it.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// RetentionPolicy bounds the extractions kept in a dossier. A zero field
+// means no limit on that axis.
+type RetentionPolicy struct {
+	KeepDays  int   `json:"keep_days"`
+	MaxRows   int   `json:"max_rows"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// GetRetentionPolicy returns the dossier's retention policy, or nil if none.
+func (s *Store) GetRetentionPolicy(ctx context.Context) (*RetentionPolicy, error) {
+	var p RetentionPolicy
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT keep_days, max_rows, updated_at FROM retention_policy WHERE id = 1`).
+		Scan(&p.KeepDays, &p.MaxRows, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SetRetentionPolicy sets the dossier's retention policy.
+func (s *Store) SetRetentionPolicy(ctx context.Context, keepDays, maxRows int) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT OR REPLACE INTO retention_policy (id, keep_days, max_rows, updated_at) VALUES (1, ?, ?, ?)`,
+		keepDays, maxRows, time.Now().UnixMilli())
+	return err
+}
+
+// DeleteRetentionPolicy removes the dossier's retention policy.
+func (s *Store) DeleteRetentionPolicy(ctx context.Context) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM retention_policy WHERE id = 1`)
+	return err
+}
+
+// PruneExtractions deletes, oldest first, the unpinned extractions extracted
+// before cutoff (ms, 0 = no age limit) and those beyond the newest maxRows
+// unpinned ones (0 = no count limit); pinned extractions are never deleted
+// and do not count toward maxRows. Rows go in batches of batch, one statement each, so no
+// write lock is held for the whole prune. FTS rows follow via trigger and
+// dependent rows via cascade. Each deleted ID is recorded in
+// pruned_extractions (see IsPruned); records older than cutoff are dropped,
+// cutoff itself keeps such IDs out of a replay. Returns the deleted count
+// and the content refs to release.
+func (s *Store) PruneExtractions(ctx context.Context, cutoff int64, maxRows, batch int) (int64, []string, error) {
+	var total int64
+	var refs []string
+	prune := func(query string, args ...any) error {
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			n, batchRefs, err := s.deleteExtractionBatch(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+			total += n
+			refs = append(refs, batchRefs...)
+			if n < int64(batch) {
+				return nil
+			}
+		}
+	}
+	if cutoff > 0 {
+		if err := prune(`SELECT id, content_ref, text_ref, extracted_at FROM extractions
+			WHERE pinned = 0 AND extracted_at < ? ORDER BY extracted_at, id LIMIT ?`, cutoff, batch); err != nil {
+			return total, refs, err
+		}
+	}
+	if maxRows > 0 {
+		if err := prune(`SELECT id, content_ref, text_ref, extracted_at FROM extractions WHERE pinned = 0
+			ORDER BY extracted_at DESC, id DESC LIMIT ? OFFSET ?`, batch, maxRows); err != nil {
+			return total, refs, err
+		}
+	}
+	if cutoff > 0 {
+		if _, err := s.DB.ExecContext(ctx,
+			`DELETE FROM pruned_extractions WHERE extracted_at < ?`, cutoff); err != nil {
+			return total, refs, err
+		}
+	}
+	return total, refs, nil
+}
+
+// IsPruned reports whether extraction id was deleted by retention pruning.
+func (s *Store) IsPruned(ctx context.Context, id string) (bool, error) {
+	var n int
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pruned_extractions WHERE id = ?`, id).Scan(&n)
+	return n > 0, err
+}
+
+// deleteExtractionBatch deletes the extractions selected by query (id,
+// content_ref, text_ref, extracted_at), records them in pruned_extractions
+// and returns how many went and their non-empty content refs.
+func (s *Store) deleteExtractionBatch(ctx context.Context, query string, args ...any) (int64, []string, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	var ids, tombstones []any
+	var refs []string
+	now := time.Now().UnixMilli()
+	for rows.Next() {
+		var id, htmlRef, textRef string
+		var at int64
+		if err := rows.Scan(&id, &htmlRef, &textRef, &at); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		ids = append(ids, id)
+		tombstones = append(tombstones, id, at, now)
+		refs = append(refs, nonEmpty([]string{htmlRef, textRef})...)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR REPLACE INTO pruned_extractions (id, extracted_at, pruned_at) VALUES (?, ?, ?)`+
+			strings.Repeat(", (?, ?, ?)", len(ids)-1), tombstones...); err != nil {
+		return 0, nil, err
+	}
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM extractions WHERE id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, ids...)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	n, _ := res.RowsAffected()
+	return n, refs, nil
+}
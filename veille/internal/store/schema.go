@@ -1,7 +1,11 @@
-// CLAUDE:SUMMARY Applies the complete veille SQL schema including FTS5 indexes and triggers.
+// CLAUDE:SUMMARY Applies the complete veille SQL schema including the contentless FTS5 index and its triggers.
 package store
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
 
 // Schema is the complete veille schema applied to each user×space shard.
 const Schema = `
@@ -39,24 +43,6 @@ CREATE TABLE IF NOT EXISTS extractions (
 CREATE INDEX IF NOT EXISTS idx_extractions_source ON extractions(source_id);
 CREATE INDEX IF NOT EXISTS idx_extractions_time ON extractions(extracted_at DESC);
 
--- FTS5 on extractions (title + text)
-CREATE VIRTUAL TABLE IF NOT EXISTS extractions_fts USING fts5(
-    title, extracted_text, content='extractions', content_rowid='rowid',
-    tokenize='unicode61 remove_diacritics 2'
-);
-
--- Triggers to keep FTS5 in sync
-CREATE TRIGGER IF NOT EXISTS extractions_ai AFTER INSERT ON extractions BEGIN
-    INSERT INTO extractions_fts(rowid, title, extracted_text) VALUES (new.rowid, new.title, new.extracted_text);
-END;
-CREATE TRIGGER IF NOT EXISTS extractions_ad AFTER DELETE ON extractions BEGIN
-    INSERT INTO extractions_fts(extractions_fts, rowid, title, extracted_text) VALUES('delete', old.rowid, old.title, old.extracted_text);
-END;
-CREATE TRIGGER IF NOT EXISTS extractions_au AFTER UPDATE ON extractions BEGIN
-    INSERT INTO extractions_fts(extractions_fts, rowid, title, extracted_text) VALUES('delete', old.rowid, old.title, old.extracted_text);
-    INSERT INTO extractions_fts(rowid, title, extracted_text) VALUES (new.rowid, new.title, new.extracted_text);
-END;
-
 -- Fetch log (observability)
 CREATE TABLE IF NOT EXISTS fetch_log (
     id              TEXT PRIMARY KEY,
@@ -112,6 +98,102 @@ CREATE TABLE IF NOT EXISTS search_log (
     searched_at  INTEGER NOT NULL
 );
 CREATE INDEX IF NOT EXISTS idx_search_log_time ON search_log(searched_at DESC);
+
+-- Digests: new-extraction summaries pushed to a channel, with a watermark
+CREATE TABLE IF NOT EXISTS digests (
+    channel      TEXT PRIMARY KEY,
+    mode         TEXT NOT NULL DEFAULT 'batched',
+    interval_ms  INTEGER NOT NULL DEFAULT 86400000,
+    max_items    INTEGER NOT NULL DEFAULT 20,
+    last_seq     INTEGER NOT NULL DEFAULT 0,
+    last_sent_at INTEGER,
+    created_at   INTEGER NOT NULL,
+    updated_at   INTEGER NOT NULL
+);
+
+-- Raw response bodies kept for reprocessing (opt-in, newest N per source)
+CREATE TABLE IF NOT EXISTS raw_bodies (
+    extraction_id TEXT PRIMARY KEY REFERENCES extractions(id) ON DELETE CASCADE,
+    source_id     TEXT NOT NULL,
+    body          BLOB NOT NULL,
+    stored_at     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_raw_bodies_source ON raw_bodies(source_id, stored_at DESC);
+
+-- Vector index IDs of embedded extractions (set when an embedder is configured)
+CREATE TABLE IF NOT EXISTS extraction_vectors (
+    extraction_id TEXT PRIMARY KEY REFERENCES extractions(id) ON DELETE CASCADE,
+    vector_id     TEXT NOT NULL UNIQUE,
+    model         TEXT NOT NULL DEFAULT '',
+    embedded_at   INTEGER NOT NULL
+);
+
+-- Source health as last seen and as last notified (health webhooks)
+CREATE TABLE IF NOT EXISTS source_health (
+    source_id      TEXT PRIMARY KEY REFERENCES sources(id) ON DELETE CASCADE,
+    state          TEXT NOT NULL,
+    notified_state TEXT NOT NULL,
+    changed_at     INTEGER NOT NULL,
+    notified_at    INTEGER NOT NULL DEFAULT 0
+);
+
+-- Per-dossier health webhook, overriding the global one (single row)
+CREATE TABLE IF NOT EXISTS health_webhook (
+    id         INTEGER PRIMARY KEY CHECK (id = 1),
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL DEFAULT '',
+    updated_at INTEGER NOT NULL
+);
+
+-- Extractions opened from results (click-through log for ranking)
+CREATE TABLE IF NOT EXISTS result_opens (
+    id            INTEGER PRIMARY KEY,
+    extraction_id TEXT NOT NULL REFERENCES extractions(id) ON DELETE CASCADE,
+    query         TEXT NOT NULL DEFAULT '',
+    opened_at     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_result_opens_extraction ON result_opens(extraction_id);
+
+-- Outbound links of extractions, in page order
+CREATE TABLE IF NOT EXISTS links (
+    extraction_id TEXT NOT NULL REFERENCES extractions(id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    url           TEXT NOT NULL,
+    PRIMARY KEY (extraction_id, position)
+);
+CREATE INDEX IF NOT EXISTS idx_links_url ON links(url);
+
+-- Per-dossier extraction retention policy (single row); 0 = no limit
+CREATE TABLE IF NOT EXISTS retention_policy (
+    id         INTEGER PRIMARY KEY CHECK (id = 1),
+    keep_days  INTEGER NOT NULL DEFAULT 0,
+    max_rows   INTEGER NOT NULL DEFAULT 0,
+    updated_at INTEGER NOT NULL
+);
+
+-- Activity feed: source mutations and question runs, plus fetches and
+-- extractions copied by trigger (MigrationActivityTriggers). id is the
+-- insertion sequence polled by the feed; rows are never deleted.
+CREATE TABLE IF NOT EXISTS activity_log (
+    id         INTEGER PRIMARY KEY,
+    type       TEXT NOT NULL,
+    subject_id TEXT NOT NULL,
+    detail     TEXT NOT NULL DEFAULT '',
+    count      INTEGER NOT NULL DEFAULT 0,
+    at         INTEGER NOT NULL,
+    ref_id     TEXT NOT NULL DEFAULT '',
+    status     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_activity_log_at ON activity_log(at);
+
+-- IDs of extractions deleted by retention pruning, so a buffer replay does
+-- not bring them back. Dropped once older than the keep_days cutoff.
+CREATE TABLE IF NOT EXISTS pruned_extractions (
+    id           TEXT PRIMARY KEY,
+    extracted_at INTEGER NOT NULL,
+    pruned_at    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_pruned_extractions_at ON pruned_extractions(extracted_at);
 `
 
 // Migration adds the UNIQUE index on sources(url) for dedup.
@@ -126,6 +208,176 @@ const Migration002OriginalFetchInterval = `
 ALTER TABLE sources ADD COLUMN original_fetch_interval INTEGER;
 `
 
+// Migration003SoftDelete adds deleted_at for soft-deleted sources.
+// NULL = live source, non-NULL = deletion time (ms), purged after the retention window.
+const Migration003SoftDelete = `
+ALTER TABLE sources ADD COLUMN deleted_at INTEGER;
+`
+
+// Migration004NotifyMinResults adds the per-question notification threshold.
+// A run notifies only if it yields at least this many new results.
+const Migration004NotifyMinResults = `
+ALTER TABLE tracked_questions ADD COLUMN notify_min_results INTEGER NOT NULL DEFAULT 1;
+`
+
+// Migration005NotifyPattern adds the optional per-question notification regex.
+// Empty = no filter; otherwise at least one new result must match.
+const Migration005NotifyPattern = `
+ALTER TABLE tracked_questions ADD COLUMN notify_pattern TEXT NOT NULL DEFAULT '';
+`
+
+// Migration006ContentRef adds the shared blob reference to extractions.
+// Empty = body stored inline; otherwise extracted_html lives in content_blobs.
+const Migration006ContentRef = `
+ALTER TABLE extractions ADD COLUMN content_ref TEXT NOT NULL DEFAULT '';
+`
+
+// Migration007SourceTags adds the source tags (JSON array of strings).
+const Migration007SourceTags = `
+ALTER TABLE sources ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+`
+
+// Migration008ExtractionLang adds the detected language of extractions.
+// "und" = undetermined, including extractions stored before detection.
+const Migration008ExtractionLang = `
+ALTER TABLE extractions ADD COLUMN lang TEXT NOT NULL DEFAULT 'und';
+`
+
+// Migration009ProbeFailures adds the count of consecutive failed sweeper probes.
+const Migration009ProbeFailures = `
+ALTER TABLE sources ADD COLUMN probe_failures INTEGER NOT NULL DEFAULT 0;
+`
+
+// Migration010NextProbeAt adds the earliest time (ms) of the next sweeper probe.
+// 0 = due now.
+const Migration010NextProbeAt = `
+ALTER TABLE sources ADD COLUMN next_probe_at INTEGER NOT NULL DEFAULT 0;
+`
+
+// Migration011RawBodyContentType adds the Content-Type of kept raw bodies,
+// used to select the extractor on reprocess. Empty = unknown.
+const Migration011RawBodyContentType = `
+ALTER TABLE raw_bodies ADD COLUMN content_type TEXT NOT NULL DEFAULT '';
+`
+
+// Migration012ExtractionPinned adds the pin flag of extractions.
+// 1 = pinned: kept by retention pruning.
+const Migration012ExtractionPinned = `
+ALTER TABLE extractions ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;
+`
+
+// Migration013QuestionFollowDepth adds how many link hops a question follows
+// from each fetched result page. 0 = result pages only.
+const Migration013QuestionFollowDepth = `
+ALTER TABLE tracked_questions ADD COLUMN follow_depth INTEGER NOT NULL DEFAULT 0;
+`
+
+// Migration014ActivityRefID adds the fetch_log / extractions id of copied
+// activity events.
+const Migration014ActivityRefID = `
+ALTER TABLE activity_log ADD COLUMN ref_id TEXT NOT NULL DEFAULT '';
+`
+
+// Migration015ActivityStatus adds the fetch status of copied activity events.
+const Migration015ActivityStatus = `
+ALTER TABLE activity_log ADD COLUMN status TEXT NOT NULL DEFAULT '';
+`
+
+// Migration016DigestLastSeq replaces the (last_at, last_id) digest watermark
+// by an activity_log sequence. Existing digests start after the newest
+// event: nothing already logged is resent.
+const Migration016DigestLastSeq = `
+ALTER TABLE digests ADD COLUMN last_seq INTEGER NOT NULL DEFAULT 0;
+UPDATE digests SET last_seq = (SELECT COALESCE(MAX(id), 0) FROM activity_log);
+`
+
+// Migration017TextRef adds the shared blob reference of the extraction text.
+// Empty = text stored inline; otherwise extracted_text lives in content_blobs.
+const Migration017TextRef = `
+ALTER TABLE extractions ADD COLUMN text_ref TEXT NOT NULL DEFAULT '';
+`
+
+// MigrationContentlessFTS (re)creates extractions_fts as a contentless FTS5
+// index (title + text): the text of a deduplicated extraction is not in the
+// shard, so the index cannot read it back from extractions. Triggers index
+// inline rows; InsertExtractionDedup and UpdateExtractionDedup index the
+// text they move to content_blobs. Replaces the former external-content
+// index and reindexes the inline rows. Applied after 017_text_ref.
+const MigrationContentlessFTS = `
+DROP TRIGGER IF EXISTS extractions_ai;
+DROP TRIGGER IF EXISTS extractions_ad;
+DROP TRIGGER IF EXISTS extractions_au;
+DROP TABLE IF EXISTS extractions_fts;
+CREATE VIRTUAL TABLE extractions_fts USING fts5(
+    title, extracted_text, content='', contentless_delete=1,
+    tokenize='unicode61 remove_diacritics 2'
+);
+CREATE TRIGGER extractions_ai AFTER INSERT ON extractions WHEN new.text_ref = '' BEGIN
+    INSERT INTO extractions_fts(rowid, title, extracted_text) VALUES (new.rowid, new.title, new.extracted_text);
+END;
+CREATE TRIGGER extractions_ad AFTER DELETE ON extractions BEGIN
+    DELETE FROM extractions_fts WHERE rowid = old.rowid;
+END;
+CREATE TRIGGER extractions_au AFTER UPDATE OF title, extracted_text, text_ref ON extractions BEGIN
+    DELETE FROM extractions_fts WHERE rowid = old.rowid;
+    INSERT INTO extractions_fts(rowid, title, extracted_text)
+        SELECT new.rowid, new.title, new.extracted_text WHERE new.text_ref = '';
+END;
+INSERT INTO extractions_fts(rowid, title, extracted_text)
+    SELECT rowid, title, extracted_text FROM extractions WHERE text_ref = '';
+`
+
+// MigrationActivityTriggers copies each new fetch_log and extractions row
+// into activity_log, so the feed follows one insertion sequence whatever the
+// event timestamps. Applied after the column migrations it depends on;
+// earlier fetches and extractions are not backfilled.
+const MigrationActivityTriggers = `
+CREATE TRIGGER IF NOT EXISTS activity_fetch_log AFTER INSERT ON fetch_log BEGIN
+    INSERT INTO activity_log (type, ref_id, subject_id, detail, status, count, at)
+    VALUES ('fetch', new.id, new.source_id, new.error_message, new.status, 0, new.fetched_at);
+END;
+CREATE TRIGGER IF NOT EXISTS activity_extractions AFTER INSERT ON extractions BEGIN
+    INSERT INTO activity_log (type, ref_id, subject_id, detail, status, count, at)
+    VALUES ('extraction', new.id, new.source_id, new.title, '', 0, new.extracted_at);
+END;
+`
+
+// columnMigration adds column to table with ddl if the column is missing.
+type columnMigration struct {
+	name, table, column, ddl string
+}
+
+// columnMigrations lists the column-adding migrations in order.
+// ApplySchema applies them; CheckMigrations reports the missing ones.
+var columnMigrations = []columnMigration{
+	{"002_original_fetch_interval", "sources", "original_fetch_interval", Migration002OriginalFetchInterval},
+	{"003_soft_delete", "sources", "deleted_at", Migration003SoftDelete},
+	{"004_notify_min_results", "tracked_questions", "notify_min_results", Migration004NotifyMinResults},
+	{"005_notify_pattern", "tracked_questions", "notify_pattern", Migration005NotifyPattern},
+	{"006_content_ref", "extractions", "content_ref", Migration006ContentRef},
+	{"007_source_tags", "sources", "tags", Migration007SourceTags},
+	{"008_extraction_lang", "extractions", "lang", Migration008ExtractionLang},
+	{"009_probe_failures", "sources", "probe_failures", Migration009ProbeFailures},
+	{"010_next_probe_at", "sources", "next_probe_at", Migration010NextProbeAt},
+	{"011_raw_body_content_type", "raw_bodies", "content_type", Migration011RawBodyContentType},
+	{"012_extraction_pinned", "extractions", "pinned", Migration012ExtractionPinned},
+	{"013_question_follow_depth", "tracked_questions", "follow_depth", Migration013QuestionFollowDepth},
+	{"014_activity_ref_id", "activity_log", "ref_id", Migration014ActivityRefID},
+	{"015_activity_status", "activity_log", "status", Migration015ActivityStatus},
+	{"016_digest_last_seq", "digests", "last_seq", Migration016DigestLastSeq},
+	{"017_text_ref", "extractions", "text_ref", Migration017TextRef},
+}
+
+// schemaTables are the tables created by Schema. extractions_fts is created
+// by MigrationContentlessFTS.
+var schemaTables = []string{
+	"sources", "extractions", "fetch_log",
+	"search_engines", "tracked_questions", "search_log", "digests",
+	"raw_bodies", "extraction_vectors", "source_health", "health_webhook",
+	"result_opens", "retention_policy", "links", "activity_log",
+	"pruned_extractions",
+}
+
 // ApplySchema creates all tables and indexes on the given database.
 func ApplySchema(db *sql.DB) error {
 	if _, err := db.Exec(Schema); err != nil {
@@ -135,10 +387,136 @@ func ApplySchema(db *sql.DB) error {
 	if _, err := db.Exec(Migration001UniqueURL); err != nil {
 		return err
 	}
-	applyColumnMigration(db, "sources", "original_fetch_interval", Migration002OriginalFetchInterval)
+	for _, m := range columnMigrations {
+		applyColumnMigration(db, m.table, m.column, m.ddl)
+	}
+	contentless, err := ftsContentless(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	if !contentless {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if _, err := tx.Exec(MigrationContentlessFTS); err != nil {
+			return fmt.Errorf("contentless fts: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec(MigrationActivityTriggers); err != nil {
+		return fmt.Errorf("activity triggers: %w", err)
+	}
 	return nil
 }
 
+// ftsContentless reports whether extractions_fts exists as the contentless
+// index of MigrationContentlessFTS.
+func ftsContentless(ctx context.Context, db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'extractions_fts'
+		AND sql LIKE '%contentless_delete%'`).Scan(&count)
+	return count > 0, err
+}
+
+// CheckMigrations reports what ApplySchema would change on db without
+// changing it: missing tables ("schema:<table>"), the unique URL index
+// ("001_unique_url"), missing columns by migration name, the contentless
+// search index ("fts_contentless") and the activity triggers
+// ("activity_triggers").
+// Pending table and column DDL is then replayed in a rolled-back
+// transaction; a non-nil error means ApplySchema would fail on this shard.
+// The unique index is not replayed: ApplySchema callers normalize URLs first.
+func CheckMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	var pending []string
+	for _, table := range schemaTables {
+		ok, err := objectExists(ctx, db, "table", table)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			pending = append(pending, "schema:"+table)
+		}
+	}
+	ok, err := objectExists(ctx, db, "index", "idx_sources_url_unique")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		pending = append(pending, "001_unique_url")
+	}
+	var todo []columnMigration
+	for _, m := range columnMigrations {
+		var count int
+		if err := db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, m.table, m.column).Scan(&count); err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			pending = append(pending, m.name)
+			todo = append(todo, m)
+		}
+	}
+	contentless, err := ftsContentless(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if !contentless {
+		pending = append(pending, "fts_contentless")
+	}
+	triggers := true
+	for _, name := range []string{"activity_fetch_log", "activity_extractions"} {
+		ok, err := objectExists(ctx, db, "trigger", name)
+		if err != nil {
+			return nil, err
+		}
+		triggers = triggers && ok
+	}
+	if !triggers {
+		pending = append(pending, "activity_triggers")
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return pending, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, Schema); err != nil {
+		return pending, fmt.Errorf("dry-run schema: %w", err)
+	}
+	for _, m := range todo {
+		if _, err := tx.ExecContext(ctx, m.ddl); err != nil {
+			return pending, fmt.Errorf("dry-run %s: %w", m.name, err)
+		}
+	}
+	if !contentless {
+		if _, err := tx.ExecContext(ctx, MigrationContentlessFTS); err != nil {
+			return pending, fmt.Errorf("dry-run fts_contentless: %w", err)
+		}
+	}
+	if !triggers {
+		if _, err := tx.ExecContext(ctx, MigrationActivityTriggers); err != nil {
+			return pending, fmt.Errorf("dry-run activity_triggers: %w", err)
+		}
+	}
+	return pending, nil
+}
+
+// objectExists reports whether sqlite_master has an object of the given type and name.
+func objectExists(ctx context.Context, db *sql.DB, typ, name string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = ? AND name = ?`, typ, name).Scan(&count)
+	return count > 0, err
+}
+
 // applyColumnMigration adds a column if it doesn't exist (idempotent).
 func applyColumnMigration(db *sql.DB, table, column, ddl string) {
 	var count int
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY FTS5 full-text search on extractions with snippet generation.
+// CLAUDE:SUMMARY FTS5 full-text search on extractions, filterable by source tag and language.
 package store
 
 import (
@@ -11,16 +11,32 @@ import (
 
 // Search performs a FTS5 full-text search on extractions.
 func (s *Store) Search(ctx context.Context, query string, limit int) ([]*SearchResult, error) {
+	return s.SearchTagged(ctx, query, "", limit)
+}
+
+// SearchTagged is Search restricted to extractions whose source carries tag.
+// An empty tag does not filter.
+func (s *Store) SearchTagged(ctx context.Context, query, tag string, limit int) ([]*SearchResult, error) {
+	return s.SearchFiltered(ctx, query, tag, "", limit)
+}
+
+// SearchFiltered is SearchTagged further restricted to extractions detected
+// as lang. Empty tag or lang do not filter.
+func (s *Store) SearchFiltered(ctx context.Context, query, tag, lang string, limit int) ([]*SearchResult, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 	rows, err := s.DB.QueryContext(ctx,
-		`SELECT e.id, e.source_id, e.title, e.extracted_text, rank
+		`SELECT e.id, e.source_id, e.title, e.extracted_text, e.lang, rank, e.text_ref
 		FROM extractions_fts f
 		JOIN extractions e ON e.rowid = f.rowid
 		WHERE extractions_fts MATCH ?
+		  AND (? = '' OR EXISTS (
+		    SELECT 1 FROM sources s, json_each(s.tags) t
+		    WHERE s.id = e.source_id AND t.value = ?))
+		  AND (? = '' OR e.lang = ?)
 		ORDER BY rank
-		LIMIT ?`, query, limit)
+		LIMIT ?`, query, tag, tag, lang, lang, limit)
 	if err != nil {
 		return nil, fmt.Errorf("search: %w", err)
 	}
@@ -29,7 +45,7 @@ func (s *Store) Search(ctx context.Context, query string, limit int) ([]*SearchR
 	var results []*SearchResult
 	for rows.Next() {
 		var r SearchResult
-		if err := rows.Scan(&r.ExtractionID, &r.SourceID, &r.Title, &r.Text, &r.Rank); err != nil {
+		if err := rows.Scan(&r.ExtractionID, &r.SourceID, &r.Title, &r.Text, &r.Lang, &r.Rank, &r.TextRef); err != nil {
 			return nil, fmt.Errorf("scan search result: %w", err)
 		}
 		results = append(results, &r)
@@ -1,10 +1,13 @@
-// CLAUDE:SUMMARY Source CRUD, DueSources scheduling query, and fetch status recording.
+// CLAUDE:SUMMARY Source CRUD, tag filtering, fetch credentials in config_json with JSON redaction, DueSources scheduling query, and fetch status recording.
 package store
 
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -29,16 +32,21 @@ func (s *Store) InsertSource(ctx context.Context, src *Source) error {
 	if src.LastStatus == "" {
 		src.LastStatus = "pending"
 	}
+	src.ConfigJSON = mergeFetchConfig(src.ConfigJSON, src.Headers, src.BasicAuth)
 
 	_, err := s.DB.ExecContext(ctx,
 		`INSERT INTO sources (id, name, url, source_type, fetch_interval, enabled,
 		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
-		original_fetch_interval, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		original_fetch_interval, created_at, updated_at, tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		src.ID, src.Name, src.URL, src.SourceType, src.FetchInterval, src.Enabled,
 		src.ConfigJSON, src.LastFetchedAt, src.LastHash, src.LastStatus, src.LastError,
 		src.FailCount, src.OriginalFetchInterval, src.CreatedAt, src.UpdatedAt,
+		encodeTags(src.Tags),
 	)
+	if err == nil {
+		s.logSourceActivity(ctx, ActivitySourceAdded, src.ID)
+	}
 	return err
 }
 
@@ -47,18 +55,18 @@ func (s *Store) GetSource(ctx context.Context, id string) (*Source, error) {
 	row := s.DB.QueryRowContext(ctx,
 		`SELECT id, name, url, source_type, fetch_interval, enabled,
 		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
-		original_fetch_interval, created_at, updated_at
+		original_fetch_interval, deleted_at, created_at, updated_at, tags
 		FROM sources WHERE id = ?`, id)
 	return scanSource(row)
 }
 
-// ListSources returns all sources in the shard.
+// ListSources returns all sources in the shard, excluding soft-deleted ones.
 func (s *Store) ListSources(ctx context.Context) ([]*Source, error) {
 	rows, err := s.DB.QueryContext(ctx,
 		`SELECT id, name, url, source_type, fetch_interval, enabled,
 		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
-		original_fetch_interval, created_at, updated_at
-		FROM sources ORDER BY created_at DESC`)
+		original_fetch_interval, deleted_at, created_at, updated_at, tags
+		FROM sources WHERE deleted_at IS NULL ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -75,39 +83,196 @@ func (s *Store) ListSources(ctx context.Context) ([]*Source, error) {
 	return sources, rows.Err()
 }
 
+// ListSourcesByTag returns live sources carrying tag, newest first.
+func (s *Store) ListSourcesByTag(ctx context.Context, tag string) ([]*Source, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT id, name, url, source_type, fetch_interval, enabled,
+		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
+		original_fetch_interval, deleted_at, created_at, updated_at, tags
+		FROM sources
+		WHERE deleted_at IS NULL
+		  AND EXISTS (SELECT 1 FROM json_each(sources.tags) WHERE value = ?)
+		ORDER BY created_at DESC`, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []*Source
+	for rows.Next() {
+		src, err := scanSourceRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+	}
+	return sources, rows.Err()
+}
+
+// ListDistinctTags returns the tags used by live sources, sorted.
+func (s *Store) ListDistinctTags(ctx context.Context) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT DISTINCT t.value FROM sources, json_each(sources.tags) t
+		WHERE sources.deleted_at IS NULL ORDER BY t.value`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
 // UpdateSource updates a source's mutable fields.
 func (s *Store) UpdateSource(ctx context.Context, src *Source) error {
 	src.UpdatedAt = time.Now().UnixMilli()
+	src.ConfigJSON = mergeFetchConfig(src.ConfigJSON, src.Headers, src.BasicAuth)
 	_, err := s.DB.ExecContext(ctx,
 		`UPDATE sources SET name=?, url=?, source_type=?, fetch_interval=?,
-		enabled=?, config_json=?, updated_at=?
+		enabled=?, config_json=?, tags=?, updated_at=?
 		WHERE id=?`,
 		src.Name, src.URL, src.SourceType, src.FetchInterval,
-		src.Enabled, src.ConfigJSON, src.UpdatedAt, src.ID,
+		src.Enabled, src.ConfigJSON, encodeTags(src.Tags), src.UpdatedAt, src.ID,
 	)
+	if err == nil {
+		s.logSourceActivity(ctx, ActivitySourceUpdated, src.ID)
+	}
 	return err
 }
 
+// SetSourcesEnabled sets the enabled flag of the live sources among ids in
+// one transaction. It returns how many sources matched and the IDs matching
+// no live source. DueSources reads the flag, so a disabled source is out of
+// the next scheduler tick.
+func (s *Store) SetSourcesEnabled(ctx context.Context, ids []string, enabled bool) (int, []string, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+	now := time.Now().UnixMilli()
+	updated, missing := 0, []string{}
+	for _, id := range ids {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE sources SET enabled=?, updated_at=? WHERE id=? AND deleted_at IS NULL`,
+			enabled, now, id)
+		if err != nil {
+			return 0, nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			missing = append(missing, id)
+			continue
+		}
+		updated++
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO activity_log (type, subject_id, detail, count, at)
+			SELECT ?, id, name, 0, ? FROM sources WHERE id = ?`,
+			ActivitySourceUpdated, now, id); err != nil {
+			return 0, nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return updated, missing, nil
+}
+
 // DeleteSource removes a source (cascades to extractions, chunks, fetch_log).
 func (s *Store) DeleteSource(ctx context.Context, id string) error {
+	s.logSourceActivity(ctx, ActivitySourcePurged, id)
 	_, err := s.DB.ExecContext(ctx, `DELETE FROM sources WHERE id = ?`, id)
 	return err
 }
 
+// SoftDeleteSource marks a source as deleted without removing its content.
+// The source disappears from listings and scheduling until restored or purged.
+func (s *Store) SoftDeleteSource(ctx context.Context, id string) error {
+	now := time.Now().UnixMilli()
+	res, err := s.DB.ExecContext(ctx,
+		`UPDATE sources SET deleted_at=?, updated_at=? WHERE id=? AND deleted_at IS NULL`,
+		now, now, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		s.logSourceActivity(ctx, ActivitySourceDeleted, id)
+	}
+	return nil
+}
+
+// RestoreSource clears the soft-delete mark of a source.
+func (s *Store) RestoreSource(ctx context.Context, id string) error {
+	now := time.Now().UnixMilli()
+	res, err := s.DB.ExecContext(ctx,
+		`UPDATE sources SET deleted_at=NULL, updated_at=? WHERE id=? AND deleted_at IS NOT NULL`, now, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		s.logSourceActivity(ctx, ActivitySourceRestored, id)
+	}
+	return nil
+}
+
+// ListDeletedSources returns soft-deleted sources, most recently deleted first.
+func (s *Store) ListDeletedSources(ctx context.Context) ([]*Source, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT id, name, url, source_type, fetch_interval, enabled,
+		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
+		original_fetch_interval, deleted_at, created_at, updated_at, tags
+		FROM sources WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []*Source
+	for rows.Next() {
+		src, err := scanSourceRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+	}
+	return sources, rows.Err()
+}
+
+// PurgeDeletedSources hard-deletes sources soft-deleted at or before cutoff (ms).
+// Extractions and fetch logs go with them via ON DELETE CASCADE.
+func (s *Store) PurgeDeletedSources(ctx context.Context, cutoff int64) (int64, error) {
+	_, _ = s.DB.ExecContext(ctx,
+		`INSERT INTO activity_log (type, subject_id, detail, count, at)
+		SELECT ?, id, name, 0, ? FROM sources WHERE deleted_at IS NOT NULL AND deleted_at <= ?`,
+		ActivitySourcePurged, time.Now().UnixMilli(), cutoff)
+	res, err := s.DB.ExecContext(ctx,
+		`DELETE FROM sources WHERE deleted_at IS NOT NULL AND deleted_at <= ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // GetSourceByURL returns an enabled source matching the given URL, or nil.
 func (s *Store) GetSourceByURL(ctx context.Context, url string) (*Source, error) {
 	row := s.DB.QueryRowContext(ctx,
 		`SELECT id, name, url, source_type, fetch_interval, enabled,
 		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
-		original_fetch_interval, created_at, updated_at
+		original_fetch_interval, deleted_at, created_at, updated_at, tags
 		FROM sources WHERE url = ? LIMIT 1`, url)
 	return scanSource(row)
 }
 
-// CountSources returns the total number of sources in the shard.
+// CountSources returns the number of live (not soft-deleted) sources in the shard.
 func (s *Store) CountSources(ctx context.Context) (int, error) {
 	var count int
-	err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM sources`).Scan(&count)
+	err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM sources WHERE deleted_at IS NULL`).Scan(&count)
 	return count, err
 }
 
@@ -119,9 +284,10 @@ func (s *Store) DueSources(ctx context.Context, maxFailCount int) ([]*Source, er
 	rows, err := s.DB.QueryContext(ctx,
 		`SELECT id, name, url, source_type, fetch_interval, enabled,
 		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
-		original_fetch_interval, created_at, updated_at
+		original_fetch_interval, deleted_at, created_at, updated_at, tags
 		FROM sources
 		WHERE enabled = 1
+		  AND deleted_at IS NULL
 		  AND fail_count < ?
 		  AND (last_fetched_at IS NULL OR last_fetched_at + fetch_interval <= ?)
 		ORDER BY last_fetched_at ASC NULLS FIRST`, maxFailCount, now)
@@ -146,7 +312,7 @@ func (s *Store) RecordFetchSuccess(ctx context.Context, id, hash string) error {
 	now := time.Now().UnixMilli()
 	_, err := s.DB.ExecContext(ctx,
 		`UPDATE sources SET last_fetched_at=?, last_hash=?, last_status='ok',
-		last_error='', fail_count=0, updated_at=?
+		last_error='', fail_count=0, probe_failures=0, next_probe_at=0, updated_at=?
 		WHERE id=?`, now, hash, now, id)
 	return err
 }
@@ -156,7 +322,7 @@ func (s *Store) RecordFetchUnchanged(ctx context.Context, id string) error {
 	now := time.Now().UnixMilli()
 	_, err := s.DB.ExecContext(ctx,
 		`UPDATE sources SET last_fetched_at=?, last_status='unchanged',
-		last_error='', fail_count=0, updated_at=?
+		last_error='', fail_count=0, probe_failures=0, next_probe_at=0, updated_at=?
 		WHERE id=?`, now, now, id)
 	return err
 }
@@ -173,13 +339,27 @@ func (s *Store) RecordFetchError(ctx context.Context, id, errMsg string) error {
 
 // ListBrokenSources returns sources in error or broken state.
 func (s *Store) ListBrokenSources(ctx context.Context) ([]*Source, error) {
+	return s.listBrokenSources(ctx, 0)
+}
+
+// ListProbeDueSources returns the broken sources whose next_probe_at is at
+// or before dueBy (ms).
+func (s *Store) ListProbeDueSources(ctx context.Context, dueBy int64) ([]*Source, error) {
+	return s.listBrokenSources(ctx, dueBy)
+}
+
+// listBrokenSources lists broken sources, restricted to those due for a
+// probe by dueBy when dueBy > 0.
+func (s *Store) listBrokenSources(ctx context.Context, dueBy int64) ([]*Source, error) {
 	rows, err := s.DB.QueryContext(ctx,
 		`SELECT id, name, url, source_type, fetch_interval, enabled,
 		config_json, last_fetched_at, last_hash, last_status, last_error, fail_count,
-		original_fetch_interval, created_at, updated_at
+		original_fetch_interval, deleted_at, created_at, updated_at, tags
 		FROM sources
-		WHERE last_status IN ('error','extract_error','broken') OR fail_count > 0
-		ORDER BY fail_count DESC`)
+		WHERE deleted_at IS NULL
+		  AND (last_status IN ('error','extract_error','broken') OR fail_count > 0)
+		  AND (? = 0 OR next_probe_at <= ?)
+		ORDER BY fail_count DESC`, dueBy, dueBy)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +380,7 @@ func (s *Store) ListBrokenSources(ctx context.Context) ([]*Source, error) {
 func (s *Store) ResetSource(ctx context.Context, id string) error {
 	now := time.Now().UnixMilli()
 	_, err := s.DB.ExecContext(ctx,
-		`UPDATE sources SET fail_count=0, last_status='pending', last_error='',
+		`UPDATE sources SET fail_count=0, probe_failures=0, next_probe_at=0, last_status='pending', last_error='',
 		original_fetch_interval=COALESCE(original_fetch_interval, NULL),
 		fetch_interval=COALESCE(original_fetch_interval, fetch_interval),
 		updated_at=?
@@ -227,11 +407,29 @@ func (s *Store) SetSourceBackoff(ctx context.Context, id string, maxMs int64) er
 	return err
 }
 
+// ProbeFailures returns the consecutive failed sweeper probes of a source.
+func (s *Store) ProbeFailures(ctx context.Context, id string) (int, error) {
+	var n int
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT probe_failures FROM sources WHERE id=?`, id).Scan(&n)
+	return n, err
+}
+
+// RecordProbeFailure sets the failed probe count of a source and the time
+// (ms) before which the sweeper must not probe it again.
+func (s *Store) RecordProbeFailure(ctx context.Context, id string, failures int, nextProbeAt int64) error {
+	now := time.Now().UnixMilli()
+	_, err := s.DB.ExecContext(ctx,
+		`UPDATE sources SET probe_failures=?, next_probe_at=?, updated_at=? WHERE id=?`,
+		failures, nextProbeAt, now, id)
+	return err
+}
+
 // UpdateSourceURL updates a source's URL and resets its error state.
 func (s *Store) UpdateSourceURL(ctx context.Context, id, newURL string) error {
 	now := time.Now().UnixMilli()
 	_, err := s.DB.ExecContext(ctx,
-		`UPDATE sources SET url=?, fail_count=0, last_status='pending', last_error='', updated_at=?
+		`UPDATE sources SET url=?, fail_count=0, probe_failures=0, next_probe_at=0, last_status='pending', last_error='', updated_at=?
 		WHERE id=?`, newURL, now, id)
 	return err
 }
@@ -244,6 +442,15 @@ func (s *Store) SetSourceStatus(ctx context.Context, id, status string) error {
 	return err
 }
 
+// UpdateSourceType sets the source_type of a source (auto-correction after
+// content sniffing).
+func (s *Store) UpdateSourceType(ctx context.Context, id, sourceType string) error {
+	now := time.Now().UnixMilli()
+	_, err := s.DB.ExecContext(ctx,
+		`UPDATE sources SET source_type=?, updated_at=? WHERE id=?`, sourceType, now, id)
+	return err
+}
+
 // UpdateSourceConfig updates the config_json field of a source.
 func (s *Store) UpdateSourceConfig(ctx context.Context, id, configJSON string) error {
 	now := time.Now().UnixMilli()
@@ -255,10 +462,12 @@ func (s *Store) UpdateSourceConfig(ctx context.Context, id, configJSON string) e
 func scanSource(row *sql.Row) (*Source, error) {
 	var src Source
 	var enabled int
+	var tagsJSON string
 	err := row.Scan(
 		&src.ID, &src.Name, &src.URL, &src.SourceType, &src.FetchInterval, &enabled,
 		&src.ConfigJSON, &src.LastFetchedAt, &src.LastHash, &src.LastStatus, &src.LastError,
-		&src.FailCount, &src.OriginalFetchInterval, &src.CreatedAt, &src.UpdatedAt,
+		&src.FailCount, &src.OriginalFetchInterval, &src.DeletedAt, &src.CreatedAt, &src.UpdatedAt,
+		&tagsJSON,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -267,20 +476,154 @@ func scanSource(row *sql.Row) (*Source, error) {
 		return nil, fmt.Errorf("scan source: %w", err)
 	}
 	src.Enabled = enabled != 0
+	src.Tags = decodeTags(tagsJSON)
+	src.Headers, src.BasicAuth = decodeFetchConfig(src.ConfigJSON)
 	return &src, nil
 }
 
 func scanSourceRows(rows *sql.Rows) (*Source, error) {
 	var src Source
 	var enabled int
+	var tagsJSON string
 	err := rows.Scan(
 		&src.ID, &src.Name, &src.URL, &src.SourceType, &src.FetchInterval, &enabled,
 		&src.ConfigJSON, &src.LastFetchedAt, &src.LastHash, &src.LastStatus, &src.LastError,
-		&src.FailCount, &src.OriginalFetchInterval, &src.CreatedAt, &src.UpdatedAt,
+		&src.FailCount, &src.OriginalFetchInterval, &src.DeletedAt, &src.CreatedAt, &src.UpdatedAt,
+		&tagsJSON,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("scan source: %w", err)
 	}
 	src.Enabled = enabled != 0
+	src.Tags = decodeTags(tagsJSON)
+	src.Headers, src.BasicAuth = decodeFetchConfig(src.ConfigJSON)
 	return &src, nil
 }
+
+// encodeTags serializes tags for the tags column; nil is stored as "[]".
+func encodeTags(tags []string) string {
+	if len(tags) == 0 {
+		return "[]"
+	}
+	b, _ := json.Marshal(tags)
+	return string(b)
+}
+
+// decodeTags parses the tags column. Malformed values read as no tags.
+func decodeTags(s string) []string {
+	var tags []string
+	if s == "" || json.Unmarshal([]byte(s), &tags) != nil || len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// redacted replaces secret values in JSON output.
+const redacted = "***"
+
+// fetchConfig is the credential part of config_json.
+type fetchConfig struct {
+	Headers   map[string]string `json:"headers"`
+	BasicAuth *BasicAuth        `json:"basic_auth"`
+}
+
+// mergeFetchConfig writes headers and auth into configJSON. A nil value
+// keeps the stored one; an empty map or username removes it.
+func mergeFetchConfig(configJSON string, headers map[string]string, auth *BasicAuth) string {
+	if headers == nil && auth == nil {
+		return configJSON
+	}
+	cfg := map[string]any{}
+	if configJSON != "" {
+		_ = json.Unmarshal([]byte(configJSON), &cfg)
+	}
+	if headers != nil {
+		if len(headers) == 0 {
+			delete(cfg, "headers")
+		} else {
+			cfg["headers"] = headers
+		}
+	}
+	if auth != nil {
+		if auth.Username == "" {
+			delete(cfg, "basic_auth")
+		} else {
+			cfg["basic_auth"] = auth
+		}
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return configJSON
+	}
+	return string(b)
+}
+
+// decodeFetchConfig reads the credentials stored in configJSON.
+func decodeFetchConfig(configJSON string) (map[string]string, *BasicAuth) {
+	var fc fetchConfig
+	if configJSON == "" || json.Unmarshal([]byte(configJSON), &fc) != nil {
+		return nil, nil
+	}
+	if len(fc.Headers) == 0 {
+		fc.Headers = nil
+	}
+	if fc.BasicAuth != nil && fc.BasicAuth.Username == "" {
+		fc.BasicAuth = nil
+	}
+	return fc.Headers, fc.BasicAuth
+}
+
+// MarshalJSON redacts header values and the basic-auth password, in the
+// fields and in config_json, so API responses never echo secrets.
+func (src Source) MarshalJSON() ([]byte, error) {
+	type plain Source
+	out := plain(src)
+	if out.Headers != nil {
+		out.Headers = redactHeaders(out.Headers)
+	}
+	if out.BasicAuth != nil {
+		out.BasicAuth = &BasicAuth{Username: out.BasicAuth.Username, Password: redacted}
+	}
+	out.ConfigJSON = redactConfigJSON(out.ConfigJSON)
+	return json.Marshal(out)
+}
+
+func redactHeaders(h map[string]string) map[string]string {
+	r := make(map[string]string, len(h))
+	for k := range h {
+		r[k] = redacted
+	}
+	return r
+}
+
+// redactConfigJSON masks the credentials inside a config_json document.
+func redactConfigJSON(configJSON string) string {
+	if !strings.Contains(configJSON, `"headers"`) && !strings.Contains(configJSON, `"basic_auth"`) &&
+		!strings.Contains(configJSON, `"proxy"`) {
+		return configJSON
+	}
+	var cfg map[string]any
+	if json.Unmarshal([]byte(configJSON), &cfg) != nil {
+		return "{}"
+	}
+	headers, auth := decodeFetchConfig(configJSON)
+	if headers != nil {
+		cfg["headers"] = redactHeaders(headers)
+	}
+	if auth != nil {
+		cfg["basic_auth"] = BasicAuth{Username: auth.Username, Password: redacted}
+	}
+	if proxy, ok := cfg["proxy"].(string); ok {
+		if u, err := url.Parse(proxy); err == nil && u.User != nil {
+			if _, set := u.User.Password(); set {
+				u.User = url.UserPassword(u.User.Username(), redacted)
+				cfg["proxy"] = u.String()
+			}
+		}
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Aggregate space statistics: source count, extraction count, last fetch time.
+// CLAUDE:SUMMARY Aggregate space statistics: source, extraction, fetch log and result open counts, most-opened extractions.
 package store
 
 import "context"
@@ -6,7 +6,7 @@ import "context"
 // Stats returns aggregate counters for the shard.
 func (s *Store) Stats(ctx context.Context) (*SpaceStats, error) {
 	var stats SpaceStats
-	err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM sources`).Scan(&stats.Sources)
+	err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM sources WHERE deleted_at IS NULL`).Scan(&stats.Sources)
 	if err != nil {
 		return nil, err
 	}
@@ -18,5 +18,13 @@ func (s *Store) Stats(ctx context.Context) (*SpaceStats, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM result_opens`).Scan(&stats.ResultOpens)
+	if err != nil {
+		return nil, err
+	}
+	stats.MostOpened, err = s.MostOpened(ctx, StatsMostOpened)
+	if err != nil {
+		return nil, err
+	}
 	return &stats, nil
 }
@@ -3,6 +3,10 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -884,3 +888,546 @@ func TestSetSourceStatus(t *testing.T) {
 		t.Errorf("status: got %q, want broken", got.LastStatus)
 	}
 }
+
+func TestSoftDeleteSource(t *testing.T) {
+	// WHAT: Soft-deleted sources leave listings, counts and scheduling but keep their rows.
+	// WHY: Soft delete is the default delete path; hidden sources must never be fetched.
+	db := openTestDB(t)
+	s := NewStore(db)
+	ctx := context.Background()
+
+	s.InsertSource(ctx, &Source{ID: "src-sd", Name: "SD", URL: "https://sd.com", Enabled: true})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-sd", SourceID: "src-sd", ContentHash: "h", ExtractedText: "t", URL: "https://sd.com", ExtractedAt: time.Now().UnixMilli()})
+
+	if err := s.SoftDeleteSource(ctx, "src-sd"); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	list, _ := s.ListSources(ctx)
+	if len(list) != 0 {
+		t.Errorf("list: got %d, want 0", len(list))
+	}
+	count, _ := s.CountSources(ctx)
+	if count != 0 {
+		t.Errorf("count: got %d, want 0", count)
+	}
+	due, _ := s.DueSources(ctx, 10)
+	if len(due) != 0 {
+		t.Errorf("due: got %d, want 0", len(due))
+	}
+	got, _ := s.GetSource(ctx, "src-sd")
+	if got == nil || got.DeletedAt == nil {
+		t.Fatal("soft-deleted source should still be readable with deleted_at set")
+	}
+	if ext, _ := s.GetExtraction(ctx, "ext-sd"); ext == nil {
+		t.Error("extraction should survive soft delete")
+	}
+
+	if err := s.RestoreSource(ctx, "src-sd"); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	list, _ = s.ListSources(ctx)
+	if len(list) != 1 {
+		t.Errorf("list after restore: got %d, want 1", len(list))
+	}
+}
+
+func TestInsertExtractionDedup_SharedBlob(t *testing.T) {
+	// WHAT: Two dossiers inserting the same body share one HTML and one text blob with refcount 2, neither shard holds the body, search still finds it; releasing both GCs them.
+	// WHY: Overlapping sources across dossiers must not store the same article body once per shard.
+	ctx := context.Background()
+	catalog, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open catalog: %v", err)
+	}
+	t.Cleanup(func() { catalog.Close() })
+	if err := ApplyContentSchema(catalog); err != nil {
+		t.Fatalf("content schema: %v", err)
+	}
+	cs := NewContentStore(catalog)
+
+	const html = "<article><p>Sovereign cloud procurement rules tightened.</p></article>"
+	const text = "Sovereign cloud procurement rules tightened."
+	var htmlRef, textRef string
+	for i, db := range []*sql.DB{openTestDB(t), openTestDB(t)} {
+		s := NewStore(db)
+		s.InsertSource(ctx, &Source{ID: "src", Name: "S", URL: "https://news.example/a", Enabled: true})
+		e := &Extraction{
+			ID: "ext", SourceID: "src", ContentHash: "h", Title: "Procurement",
+			ExtractedText: text, ExtractedHTML: html,
+			URL: "https://news.example/a", ExtractedAt: time.Now().UnixMilli(),
+		}
+		if err := s.InsertExtractionDedup(ctx, cs, e); err != nil {
+			t.Fatalf("dossier %d: insert: %v", i, err)
+		}
+		htmlRef, textRef = e.ContentRef, e.TextRef
+
+		var storedHTML, storedText string
+		db.QueryRow(`SELECT extracted_html, extracted_text FROM extractions WHERE id = 'ext'`).Scan(&storedHTML, &storedText)
+		if storedHTML != "" || storedText != "" {
+			t.Errorf("dossier %d: shard must not hold the body, got html=%q text=%q", i, storedHTML, storedText)
+		}
+		got, _ := s.GetExtraction(ctx, "ext")
+		if got.ContentRef != htmlRef || got.TextRef != textRef || textRef == "" {
+			t.Errorf("dossier %d: row should hold only the refs, got %q %q", i, got.ContentRef, got.TextRef)
+		}
+		res, _ := s.Search(ctx, "procurement", 10)
+		if len(res) != 1 || res[0].TextRef != textRef {
+			t.Errorf("dossier %d: FTS must still index the text, got %+v", i, res)
+		}
+		HydrateContent(ctx, cs, []*Extraction{got})
+		if got.ExtractedHTML != html || got.ExtractedText != text {
+			t.Errorf("dossier %d: hydrated: got html=%q text=%q", i, got.ExtractedHTML, got.ExtractedText)
+		}
+	}
+
+	var blobs int
+	catalog.QueryRow(`SELECT COUNT(*) FROM content_blobs`).Scan(&blobs)
+	if blobs != 2 {
+		t.Fatalf("blobs: got %d, want 2 (html + text)", blobs)
+	}
+	for _, ref := range []string{htmlRef, textRef} {
+		if n, _ := cs.RefCount(ctx, ref); n != 2 {
+			t.Fatalf("refcount: got %d, want 2", n)
+		}
+	}
+
+	cs.Release(ctx, htmlRef, textRef)
+	if n, _ := cs.RefCount(ctx, textRef); n != 1 {
+		t.Errorf("refcount after one release: got %d, want 1", n)
+	}
+	cs.Release(ctx, htmlRef, textRef)
+	catalog.QueryRow(`SELECT COUNT(*) FROM content_blobs`).Scan(&blobs)
+	if blobs != 0 {
+		t.Errorf("orphaned blobs should be collected, %d left", blobs)
+	}
+}
+
+func TestSourceTags_RoundTrip(t *testing.T) {
+	// WHAT: Tags survive insert, read and update; untagged sources read back as nil.
+	// WHY: Tags are stored as a JSON column and must not be lost or mangled by the scan path.
+	db := openTestDB(t)
+	s := NewStore(db)
+	ctx := context.Background()
+
+	s.InsertSource(ctx, &Source{ID: "src-t", Name: "T", URL: "https://t.example", Enabled: true, Tags: []string{"cloud", "eu"}})
+	s.InsertSource(ctx, &Source{ID: "src-u", Name: "U", URL: "https://u.example", Enabled: true})
+
+	got, _ := s.GetSource(ctx, "src-t")
+	if len(got.Tags) != 2 || got.Tags[0] != "cloud" || got.Tags[1] != "eu" {
+		t.Fatalf("tags: got %v, want [cloud eu]", got.Tags)
+	}
+	if u, _ := s.GetSource(ctx, "src-u"); u.Tags != nil {
+		t.Errorf("untagged: got %v, want nil", u.Tags)
+	}
+
+	got.Tags = []string{"ai"}
+	if err := s.UpdateSource(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, _ = s.GetSource(ctx, "src-t")
+	if len(got.Tags) != 1 || got.Tags[0] != "ai" {
+		t.Errorf("tags after update: got %v, want [ai]", got.Tags)
+	}
+}
+
+func TestSourceTags_Filtering(t *testing.T) {
+	// WHAT: Tag-filtered listing, distinct tags and tag-filtered search only see live sources carrying the tag.
+	// WHY: The UI filter dropdown and per-topic views rely on these queries.
+	db := openTestDB(t)
+	s := NewStore(db)
+	ctx := context.Background()
+
+	s.InsertSource(ctx, &Source{ID: "src-a", Name: "A", URL: "https://a.example", Enabled: true, Tags: []string{"cloud", "eu"}})
+	s.InsertSource(ctx, &Source{ID: "src-b", Name: "B", URL: "https://b.example", Enabled: true, Tags: []string{"ai"}})
+	s.InsertSource(ctx, &Source{ID: "src-c", Name: "C", URL: "https://c.example", Enabled: true, Tags: []string{"cloud", "legacy"}})
+	s.SoftDeleteSource(ctx, "src-c")
+	now := time.Now().UnixMilli()
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-a", SourceID: "src-a", ContentHash: "ha", ExtractedText: "sovereign datacenter", URL: "https://a.example", ExtractedAt: now})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-b", SourceID: "src-b", ContentHash: "hb", ExtractedText: "datacenter for models", URL: "https://b.example", ExtractedAt: now})
+
+	list, err := s.ListSourcesByTag(ctx, "cloud")
+	if err != nil {
+		t.Fatalf("list by tag: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != "src-a" {
+		t.Errorf("list by tag: got %d sources, want only src-a", len(list))
+	}
+
+	tags, err := s.ListDistinctTags(ctx)
+	if err != nil {
+		t.Fatalf("distinct tags: %v", err)
+	}
+	if strings.Join(tags, ",") != "ai,cloud,eu" {
+		t.Errorf("distinct tags: got %v, want [ai cloud eu]", tags)
+	}
+
+	res, err := s.SearchTagged(ctx, "datacenter", "ai", 10)
+	if err != nil {
+		t.Fatalf("tagged search: %v", err)
+	}
+	if len(res) != 1 || res[0].ExtractionID != "ext-b" {
+		t.Errorf("tagged search: got %d results, want only ext-b", len(res))
+	}
+	if res, _ := s.SearchTagged(ctx, "datacenter", "", 10); len(res) != 2 {
+		t.Errorf("untagged search: got %d results, want 2", len(res))
+	}
+}
+
+func TestSourceFetchAuth_PersistedAndRedacted(t *testing.T) {
+	// WHAT: Headers and basic auth round-trip through config_json and are masked in JSON output.
+	// WHY: The fetcher needs the real secrets, API responses must never echo them.
+	db := openTestDB(t)
+	s := NewStore(db)
+	ctx := context.Background()
+
+	s.InsertSource(ctx, &Source{
+		ID: "src-auth", Name: "A", URL: "https://api.example", Enabled: true,
+		ConfigJSON: `{"user_agent":"ua"}`,
+		Headers:    map[string]string{"X-Api-Key": "k-123"},
+		BasicAuth:  &BasicAuth{Username: "alice", Password: "s3cret"},
+	})
+	got, _ := s.GetSource(ctx, "src-auth")
+	if got.Headers["X-Api-Key"] != "k-123" || got.BasicAuth == nil || got.BasicAuth.Password != "s3cret" {
+		t.Fatalf("credentials: got %v %+v", got.Headers, got.BasicAuth)
+	}
+	if !strings.Contains(got.ConfigJSON, `"user_agent":"ua"`) {
+		t.Errorf("other config keys lost: %s", got.ConfigJSON)
+	}
+
+	out, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(out), "k-123") || strings.Contains(string(out), "s3cret") {
+		t.Errorf("secret in JSON output: %s", out)
+	}
+	if !strings.Contains(string(out), "alice") {
+		t.Errorf("username should stay visible: %s", out)
+	}
+
+	// nil keeps the stored credentials, an empty map clears the headers.
+	got.Headers, got.BasicAuth = nil, nil
+	s.UpdateSource(ctx, got)
+	if again, _ := s.GetSource(ctx, "src-auth"); again.Headers["X-Api-Key"] != "k-123" {
+		t.Errorf("nil headers should keep the stored ones, got %v", again.Headers)
+	}
+	got.Headers = map[string]string{}
+	s.UpdateSource(ctx, got)
+	if again, _ := s.GetSource(ctx, "src-auth"); again.Headers != nil || again.BasicAuth == nil {
+		t.Errorf("after clearing headers: got %v %+v", again.Headers, again.BasicAuth)
+	}
+}
+
+func TestDigest_WatermarkAndAfter(t *testing.T) {
+	// WHAT: A digest keeps its watermark across upserts and lists only newer extractions.
+	// WHY: Re-registering a digest must not resend what the channel already got.
+	s := NewStore(openTestDB(t))
+	ctx := context.Background()
+
+	s.InsertSource(ctx, &Source{ID: "src-d", Name: "D", URL: "https://d.example.com", SourceType: "web", Enabled: true})
+	for i, id := range []string{"e1", "e2", "e3"} {
+		s.InsertExtraction(ctx, &Extraction{ID: id, SourceID: "src-d", ContentHash: id,
+			ExtractedText: "text " + id, URL: "https://d.example.com/" + id, ExtractedAt: int64(1000 + i)})
+	}
+
+	if err := s.UpsertDigest(ctx, &Digest{Channel: "ops", Mode: "batched", IntervalMs: 60000, MaxItems: 5}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	seq, err := s.LatestExtractionSeq(ctx)
+	if err != nil || seq == 0 {
+		t.Fatalf("latest seq: %d %v", seq, err)
+	}
+	if err := s.AdvanceDigest(ctx, "ops", seq-1, 5000); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if err := s.UpsertDigest(ctx, &Digest{Channel: "ops", Mode: "immediate", IntervalMs: 0, MaxItems: 5}); err != nil {
+		t.Fatalf("re-upsert: %v", err)
+	}
+	d, err := s.GetDigest(ctx, "ops")
+	if err != nil || d == nil {
+		t.Fatalf("get: %v", err)
+	}
+	if d.Mode != "immediate" || d.LastSeq != seq-1 || d.LastSentAt == nil {
+		t.Errorf("digest after re-upsert: %+v", d)
+	}
+
+	after, err := s.ListExtractionsAfter(ctx, d.LastSeq, 10)
+	if err != nil {
+		t.Fatalf("after: %v", err)
+	}
+	if len(after) != 1 || after[0].ID != "e3" || after[0].SourceName != "D" || after[0].Seq != seq {
+		t.Errorf("after watermark: got %+v", after)
+	}
+
+	s.DeleteDigest(ctx, "ops")
+	if d, _ := s.GetDigest(ctx, "ops"); d != nil {
+		t.Error("digest still present after delete")
+	}
+}
+
+func TestExtractionLang_FilteredListingAndSearch(t *testing.T) {
+	// WHAT: lang defaults to "und" and filters source listings, dossier listings and search.
+	// WHY: Multilingual dossiers must be able to isolate one language without client-side filtering.
+	db := openTestDB(t)
+	s := NewStore(db)
+	ctx := context.Background()
+
+	s.InsertSource(ctx, &Source{ID: "src-l", Name: "L", URL: "https://l.example", Enabled: true})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-fr", SourceID: "src-l", ContentHash: "hfr", ExtractedText: "datacenter souverain", URL: "https://l.example/fr", ExtractedAt: 1000, Lang: "fr"})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-en", SourceID: "src-l", ContentHash: "hen", ExtractedText: "sovereign datacenter", URL: "https://l.example/en", ExtractedAt: 1001, Lang: "en"})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-und", SourceID: "src-l", ContentHash: "hund", ExtractedText: "datacenter", URL: "https://l.example/x", ExtractedAt: 1002})
+
+	if e, _ := s.GetExtraction(ctx, "ext-und"); e == nil || e.Lang != LangUndetermined {
+		t.Errorf("default lang: got %+v", e)
+	}
+	list, err := s.ListExtractionsLang(ctx, "src-l", "fr", 10)
+	if err != nil {
+		t.Fatalf("list lang: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != "ext-fr" {
+		t.Errorf("list lang: got %d extractions, want only ext-fr", len(list))
+	}
+	if all, _ := s.ListExtractions(ctx, "src-l", 10); len(all) != 3 {
+		t.Errorf("unfiltered list: got %d, want 3", len(all))
+	}
+	dossier, err := s.ListDossierExtractions(ctx, 10, 0, "", "en", false)
+	if err != nil {
+		t.Fatalf("dossier lang: %v", err)
+	}
+	if len(dossier) != 1 || dossier[0].ID != "ext-en" || dossier[0].Lang != "en" {
+		t.Errorf("dossier lang: got %+v", dossier)
+	}
+	res, err := s.SearchFiltered(ctx, "datacenter", "", "und", 10)
+	if err != nil {
+		t.Fatalf("search lang: %v", err)
+	}
+	if len(res) != 1 || res[0].ExtractionID != "ext-und" || res[0].Lang != "und" {
+		t.Errorf("search lang: got %+v", res)
+	}
+}
+
+func TestApplySchema_MigratesExternalContentFTS(t *testing.T) {
+	// WHAT: A shard with the former external-content extractions_fts is reindexed into the contentless index.
+	// WHY: Existing shards must keep their search results across the migration.
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+	if _, err := db.Exec(Schema); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`
+CREATE VIRTUAL TABLE extractions_fts USING fts5(
+    title, extracted_text, content='extractions', content_rowid='rowid',
+    tokenize='unicode61 remove_diacritics 2'
+);
+CREATE TRIGGER extractions_ai AFTER INSERT ON extractions BEGIN
+    INSERT INTO extractions_fts(rowid, title, extracted_text) VALUES (new.rowid, new.title, new.extracted_text);
+END;
+INSERT INTO sources (id, name, url, created_at, updated_at) VALUES ('src', 'S', 'https://old.example', 1, 1);
+INSERT INTO extractions (id, source_id, content_hash, extracted_text, url, extracted_at)
+VALUES ('ext-old', 'src', 'h', 'legacy archive wording', 'https://old.example/1', 1);`); err != nil {
+		t.Fatalf("old shard: %v", err)
+	}
+	if pending, _ := CheckMigrations(ctx, db); !slices.Contains(pending, "fts_contentless") {
+		t.Errorf("pending %v: missing fts_contentless", pending)
+	}
+
+	if err := ApplySchema(db); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+	s := NewStore(db)
+	res, err := s.Search(ctx, "legacy", 10)
+	if err != nil || len(res) != 1 || res[0].ExtractionID != "ext-old" {
+		t.Fatalf("search after migration: %+v %v", res, err)
+	}
+	if err := s.CheckFTSIntegrity(ctx); err != nil {
+		t.Errorf("integrity after migration: %v", err)
+	}
+	if pending, _ := CheckMigrations(ctx, db); len(pending) != 0 {
+		t.Errorf("pending after apply: %v", pending)
+	}
+}
+
+func TestFTSIntegrity_DetectsDriftAndRebuilds(t *testing.T) {
+	// WHAT: Rows inserted or deleted behind the FTS triggers are reported as drift; RebuildFTS restores search.
+	// WHY: Manual edits or crashes leave extractions_fts stale and search silently wrong.
+	db := openTestDB(t)
+	s := NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+
+	s.InsertSource(ctx, &Source{ID: "src-fts", Name: "FTS", URL: "https://fts.com", Enabled: true})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-1", SourceID: "src-fts", ContentHash: "a", Title: "Alpha", ExtractedText: "original wording", URL: "https://fts.com/1", ExtractedAt: now})
+	if err := s.CheckFTSIntegrity(ctx); err != nil {
+		t.Fatalf("fresh index: %v", err)
+	}
+
+	// Manual edits without the triggers: the index misses the new row and
+	// keeps the tokens of the deleted one.
+	if _, err := db.Exec(`DROP TRIGGER extractions_ai; DROP TRIGGER extractions_ad`); err != nil {
+		t.Fatal(err)
+	}
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-2", SourceID: "src-fts", ContentHash: "b", Title: "Beta", ExtractedText: "replacement phrasing", URL: "https://fts.com/2", ExtractedAt: now})
+	if _, err := db.Exec(`DELETE FROM extractions WHERE id = 'ext-1'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckFTSIntegrity(ctx); !errors.Is(err, ErrFTSDrift) {
+		t.Fatalf("after drift: got %v, want ErrFTSDrift", err)
+	}
+	if res, _ := s.Search(ctx, "replacement", 10); len(res) != 0 {
+		t.Errorf("stale index should miss the new text, got %d results", len(res))
+	}
+
+	if err := s.RebuildFTS(ctx, nil); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if err := s.CheckFTSIntegrity(ctx); err != nil {
+		t.Errorf("after rebuild: %v", err)
+	}
+	res, err := s.Search(ctx, "replacement", 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(res) != 1 || res[0].ExtractionID != "ext-2" {
+		t.Errorf("search after rebuild: got %+v", res)
+	}
+	var stale int
+	db.QueryRow(`SELECT COUNT(*) FROM extractions_fts WHERE extractions_fts MATCH 'original'`).Scan(&stale)
+	if stale != 0 {
+		t.Errorf("old tokens still indexed: %d rows", stale)
+	}
+}
+
+func TestResultOpens_InsertAndMostOpened(t *testing.T) {
+	// WHAT: Opens are logged with their query, unknown extractions are refused, and MostOpened ranks by open count.
+	// WHY: Click-through data feeds future ranking; a typo'd or foreign extraction ID must not create orphan rows.
+	db := openTestDB(t)
+	s := NewStore(db)
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+
+	s.InsertSource(ctx, &Source{ID: "src-o", Name: "O", URL: "https://o.com", Enabled: true})
+	for _, id := range []string{"ext-a", "ext-b", "ext-c"} {
+		s.InsertExtraction(ctx, &Extraction{ID: id, SourceID: "src-o", ContentHash: id, Title: "T " + id, ExtractedText: "text", URL: "https://o.com/" + id, ExtractedAt: now})
+	}
+	for _, id := range []string{"ext-b", "ext-a", "ext-b", "ext-c", "ext-b", "ext-a"} {
+		ok, err := s.LogResultOpen(ctx, id, "hydrogène")
+		if err != nil || !ok {
+			t.Fatalf("log open %s: ok=%v err=%v", id, ok, err)
+		}
+	}
+	if ok, err := s.LogResultOpen(ctx, "ext-missing", ""); err != nil || ok {
+		t.Errorf("unknown extraction: ok=%v err=%v, want ok=false", ok, err)
+	}
+	var query string
+	db.QueryRow(`SELECT query FROM result_opens LIMIT 1`).Scan(&query)
+	if query != "hydrogène" {
+		t.Errorf("query: got %q", query)
+	}
+
+	top, err := s.MostOpened(ctx, 2)
+	if err != nil {
+		t.Fatalf("most opened: %v", err)
+	}
+	if len(top) != 2 || top[0].ExtractionID != "ext-b" || top[0].Opens != 3 || top[1].ExtractionID != "ext-a" || top[1].Opens != 2 {
+		t.Fatalf("top 2: got %+v", top)
+	}
+	if top[0].Title != "T ext-b" || top[0].URL != "https://o.com/ext-b" || top[0].LastOpenedAt == 0 {
+		t.Errorf("top entry: got %+v", top[0])
+	}
+
+	stats, err := s.Stats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.ResultOpens != 6 || len(stats.MostOpened) != 3 {
+		t.Errorf("stats: %d opens, %d most opened; want 6 and 3", stats.ResultOpens, len(stats.MostOpened))
+	}
+
+	// Opens go with their extraction.
+	db.Exec(`DELETE FROM extractions WHERE id = 'ext-b'`)
+	if top, _ := s.MostOpened(ctx, 10); len(top) != 2 || top[0].ExtractionID != "ext-a" {
+		t.Errorf("after delete: got %+v", top)
+	}
+}
+
+func TestListActivity_OrdersMixedEvents(t *testing.T) {
+	// WHAT: Fetches, extractions, source mutations and question runs merge into one feed in recording order; afterSeq resumes after a given event.
+	// WHY: The dossier activity feed is polled incrementally across event types.
+	ctx := context.Background()
+	s := NewStore(openTestDB(t))
+	base := time.Now().UnixMilli()
+
+	s.InsertSource(ctx, &Source{ID: "src-act", Name: "Wire", URL: "https://act.example", Enabled: true})
+	s.InsertFetchLog(ctx, &FetchLogEntry{ID: "fl-1", SourceID: "src-act", Status: "ok", FetchedAt: base - 3000})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-1", SourceID: "src-act", ContentHash: "h1", Title: "Story", URL: "https://act.example/1", ExtractedAt: base - 2000})
+	s.InsertFetchLog(ctx, &FetchLogEntry{ID: "fl-2", SourceID: "src-act", Status: "error", ErrorMessage: "timeout", FetchedAt: base - 2000})
+	s.InsertQuestion(ctx, &TrackedQuestion{ID: "q-act", Text: "Who acts?", Enabled: true})
+	if err := s.RecordQuestionRun(ctx, "q-act", 3); err != nil {
+		t.Fatalf("record run: %v", err)
+	}
+
+	events, err := s.ListActivity(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("list activity: %v", err)
+	}
+	var got []string
+	for i, e := range events {
+		got = append(got, e.Type+":"+e.ID)
+		if i > 0 && e.Seq <= events[i-1].Seq {
+			t.Errorf("event %d seq %d not after %d", i, e.Seq, events[i-1].Seq)
+		}
+	}
+	want := "source_added:" + events[0].ID + ",fetch:fl-1,extraction:ext-1,fetch:fl-2,question_run:" + events[4].ID
+	if len(events) != 5 || strings.Join(got, ",") != want {
+		t.Fatalf("order: got %v", got)
+	}
+	if events[0].Detail != "Wire" || events[2].Detail != "Story" || events[3].Status != "error" || events[3].Detail != "timeout" {
+		t.Errorf("fields: got %+v, %+v, %+v", events[0], events[2], events[3])
+	}
+	if run := events[4]; run.Count != 3 || run.SubjectID != "q-act" || run.Detail != "Who acts?" {
+		t.Errorf("question_run: got %+v", run)
+	}
+
+	after, err := s.ListActivity(ctx, 2, events[1].Seq)
+	if err != nil {
+		t.Fatalf("after: %v", err)
+	}
+	if len(after) != 2 || after[0].ID != "ext-1" || after[1].ID != "fl-2" {
+		t.Errorf("after fl-1: got %+v", after)
+	}
+	recent, _ := s.ListActivity(ctx, 2, 0)
+	if len(recent) != 2 || recent[0].ID != "fl-2" || recent[1].Seq != events[4].Seq {
+		t.Errorf("most recent: got %+v", recent)
+	}
+}
+
+func TestListActivity_LateCommitAfterPoll(t *testing.T) {
+	// WHAT: An event recorded after a poll shows up in the next poll even when its timestamp is older than everything already seen.
+	// WHY: Fetch and extraction times are set before a slow job commits; a timestamp cursor would skip them for good.
+	ctx := context.Background()
+	s := NewStore(openTestDB(t))
+	now := time.Now().UnixMilli()
+
+	s.InsertSource(ctx, &Source{ID: "src-late", Name: "Late", URL: "https://late.example", Enabled: true})
+	s.InsertFetchLog(ctx, &FetchLogEntry{ID: "fl-new", SourceID: "src-late", Status: "ok", FetchedAt: now})
+	seen, err := s.ListActivity(ctx, 10, 0)
+	if err != nil || len(seen) != 2 {
+		t.Fatalf("first poll: %+v, %v", seen, err)
+	}
+
+	// A job started a minute ago commits only now.
+	s.InsertFetchLog(ctx, &FetchLogEntry{ID: "fl-old", SourceID: "src-late", Status: "ok", FetchedAt: now - 60000})
+	s.InsertExtraction(ctx, &Extraction{ID: "ext-old", SourceID: "src-late", ContentHash: "h", URL: "https://late.example/a", ExtractedAt: now - 60000})
+
+	next, err := s.ListActivity(ctx, 10, seen[len(seen)-1].Seq)
+	if err != nil {
+		t.Fatalf("second poll: %v", err)
+	}
+	if len(next) != 2 || next[0].ID != "fl-old" || next[1].ID != "ext-old" {
+		t.Errorf("second poll: got %+v", next)
+	}
+}
@@ -0,0 +1,40 @@
+// CLAUDE:SUMMARY Recent extraction texts for trending topics: live sources only, newest first, text truncated and rows bounded in SQL.
+package store
+
+import "context"
+
+// ExtractionText is the analysable part of an extraction.
+type ExtractionText struct {
+	ID          string
+	ContentHash string
+	Title       string
+	Text        string
+	TextRef     string // shared blob hash when Text is deduplicated
+}
+
+// RecentExtractionTexts returns the extractions of live sources extracted
+// at or after since (ms), newest first, at most limit rows with the text cut
+// to maxRunes characters. Deduplicated texts are left empty with their
+// TextRef set (see HydrateExtractionTexts).
+func (s *Store) RecentExtractionTexts(ctx context.Context, since int64, limit, maxRunes int) ([]*ExtractionText, error) {
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT e.id, e.content_hash, e.title, substr(e.extracted_text, 1, ?), e.text_ref
+		FROM extractions e JOIN sources s ON s.id = e.source_id
+		WHERE s.deleted_at IS NULL AND e.extracted_at >= ?
+		ORDER BY e.extracted_at DESC, e.id DESC LIMIT ?`,
+		maxRunes, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*ExtractionText
+	for rows.Next() {
+		var t ExtractionText
+		if err := rows.Scan(&t.ID, &t.ContentHash, &t.Title, &t.Text, &t.TextRef); err != nil {
+			return nil, err
+		}
+		result = append(result, &t)
+	}
+	return result, rows.Err()
+}
@@ -1,23 +1,37 @@
-// CLAUDE:SUMMARY All store data types: Source, Extraction, FetchLogEntry, SearchEngine, TrackedQuestion, Stats.
+// CLAUDE:SUMMARY All store data types: Source, BasicAuth, Extraction, FetchLogEntry, SearchEngine, TrackedQuestion, Digest, Stats.
 package store
 
 // Source represents a monitored URL.
 type Source struct {
-	ID                    string `json:"id"`
-	Name                  string `json:"name"`
-	URL                   string `json:"url"`
-	SourceType            string `json:"source_type"`
-	FetchInterval         int64  `json:"fetch_interval"` // ms
-	Enabled               bool   `json:"enabled"`
-	ConfigJSON            string `json:"config_json"`
-	LastFetchedAt         *int64 `json:"last_fetched_at,omitempty"`
-	LastHash              string `json:"last_hash"`
-	LastStatus            string `json:"last_status"`
-	LastError             string `json:"last_error"`
-	FailCount             int    `json:"fail_count"`
-	OriginalFetchInterval *int64 `json:"original_fetch_interval,omitempty"` // non-nil when backoff is active
-	CreatedAt             int64  `json:"created_at"`
-	UpdatedAt             int64  `json:"updated_at"`
+	ID                    string   `json:"id"`
+	Name                  string   `json:"name"`
+	URL                   string   `json:"url"`
+	SourceType            string   `json:"source_type"`
+	FetchInterval         int64    `json:"fetch_interval"` // ms
+	Enabled               bool     `json:"enabled"`
+	ConfigJSON            string   `json:"config_json"`
+	LastFetchedAt         *int64   `json:"last_fetched_at,omitempty"`
+	LastHash              string   `json:"last_hash"`
+	LastStatus            string   `json:"last_status"`
+	LastError             string   `json:"last_error"`
+	FailCount             int      `json:"fail_count"`
+	OriginalFetchInterval *int64   `json:"original_fetch_interval,omitempty"` // non-nil when backoff is active
+	DeletedAt             *int64   `json:"deleted_at,omitempty"`              // non-nil when soft-deleted
+	CreatedAt             int64    `json:"created_at"`
+	UpdatedAt             int64    `json:"updated_at"`
+	Tags                  []string `json:"tags,omitempty"`
+
+	// Fetch credentials, persisted in config_json ("headers", "basic_auth").
+	// nil leaves the stored value unchanged on insert/update. Secret values
+	// are redacted when a Source is marshalled to JSON.
+	Headers   map[string]string `json:"headers,omitempty"`
+	BasicAuth *BasicAuth        `json:"basic_auth,omitempty"`
+}
+
+// BasicAuth holds HTTP basic-auth credentials for fetching a source.
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
 }
 
 // Extraction represents content extracted from a source at a point in time.
@@ -31,6 +45,28 @@ type Extraction struct {
 	URL           string `json:"url"`
 	ExtractedAt   int64  `json:"extracted_at"`
 	MetadataJSON  string `json:"metadata_json"`
+	ContentRef    string `json:"content_ref,omitempty"` // shared blob hash when extracted_html is deduplicated
+	TextRef       string `json:"text_ref,omitempty"`    // shared blob hash when extracted_text is deduplicated
+	Lang          string `json:"lang"`                  // detected ISO 639-1 code, or "und"
+	Pinned        bool   `json:"pinned"`                // kept by retention pruning
+}
+
+// LangUndetermined is the lang of extractions whose language is unknown.
+const LangUndetermined = "und"
+
+// DossierExtraction is an extraction annotated with its source, for
+// listings that span every source of a dossier.
+type DossierExtraction struct {
+	Extraction
+	SourceName string `json:"source_name"`
+	SourceType string `json:"source_type"`
+}
+
+// DigestItem is a dossier extraction with the activity_log sequence of its
+// insert, the digest watermark.
+type DigestItem struct {
+	*DossierExtraction
+	Seq int64
 }
 
 // FetchLogEntry is one fetch attempt record.
@@ -51,7 +87,9 @@ type SearchResult struct {
 	SourceID     string  `json:"source_id"`
 	Title        string  `json:"title"`
 	Text         string  `json:"text"`
+	Lang         string  `json:"lang"`
 	Rank         float64 `json:"rank"`
+	TextRef      string  `json:"-"` // shared blob hash when Text is deduplicated
 }
 
 // SpaceStats holds aggregate counters for a veille space.
@@ -59,6 +97,18 @@ type SpaceStats struct {
 	Sources     int `json:"sources"`
 	Extractions int `json:"extractions"`
 	FetchLogs   int `json:"fetch_logs"`
+	ResultOpens int `json:"result_opens"`
+
+	MostOpened []*OpenedExtraction `json:"most_opened"`
+}
+
+// OpenedExtraction is an extraction ranked by result opens.
+type OpenedExtraction struct {
+	ExtractionID string `json:"extraction_id"`
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	Opens        int    `json:"opens"`
+	LastOpenedAt int64  `json:"last_opened_at"`
 }
 
 // SearchEngine describes a search engine configuration.
@@ -86,12 +136,17 @@ type TrackedQuestion struct {
 	ScheduleMs      int64  `json:"schedule_ms"`
 	MaxResults      int    `json:"max_results"`
 	FollowLinks     bool   `json:"follow_links"`
+	FollowDepth     int    `json:"follow_depth"` // link hops followed from result pages (needs FollowLinks)
 	Enabled         bool   `json:"enabled"`
 	LastRunAt       *int64 `json:"last_run_at,omitempty"`
 	LastResultCount int    `json:"last_result_count"`
 	TotalResults    int    `json:"total_results"`
 	CreatedAt       int64  `json:"created_at"`
 	UpdatedAt       int64  `json:"updated_at"`
+
+	// Notification rules evaluated after each run.
+	NotifyMinResults int    `json:"notify_min_results"`       // notify only if ≥ N new results (default 1)
+	NotifyPattern    string `json:"notify_pattern,omitempty"` // optional regex a new result must match
 }
 
 // SearchLogEntry records a user search query.
@@ -101,3 +156,18 @@ type SearchLogEntry struct {
 	ResultCount int    `json:"result_count"`
 	SearchedAt  int64  `json:"searched_at"`
 }
+
+// Digest is a channel subscription to the shard's new extractions.
+// LastSeq is the watermark: the activity_log sequence of the last
+// extraction already sent. The sequence follows insertion order, so an
+// extraction committed late with an older extracted_at is still sent.
+type Digest struct {
+	Channel    string `json:"channel"`
+	Mode       string `json:"mode"`        // "immediate" | "batched"
+	IntervalMs int64  `json:"interval_ms"` // batched: minimum ms between sends
+	MaxItems   int    `json:"max_items"`   // batched: extractions listed per message
+	LastSeq    int64  `json:"last_seq"`
+	LastSentAt *int64 `json:"last_sent_at,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
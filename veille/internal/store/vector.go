@@ -0,0 +1,30 @@
+// CLAUDE:SUMMARY Extraction ↔ vector index ID mapping for embedded extractions (related extractions lookup).
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PutExtractionVector records that extractionID is indexed under vectorID,
+// embedded with model. Re-embedding an extraction replaces its mapping.
+func (s *Store) PutExtractionVector(ctx context.Context, extractionID, vectorID, model string) error {
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT OR REPLACE INTO extraction_vectors (extraction_id, vector_id, model, embedded_at) VALUES (?, ?, ?, ?)`,
+		extractionID, vectorID, model, time.Now().UnixMilli())
+	return err
+}
+
+// ExtractionByVector returns the extraction ID indexed under vectorID, or ""
+// if the vector does not belong to this shard.
+func (s *Store) ExtractionByVector(ctx context.Context, vectorID string) (string, error) {
+	var id string
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT extraction_id FROM extraction_vectors WHERE vector_id = ?`, vectorID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return id, err
+}
@@ -0,0 +1,49 @@
+// CLAUDE:SUMMARY French and English stopwords (3+ letters) excluded from trending topics, plus frequent web boilerplate.
+package trends
+
+// stopwords holds lowercased French and English function words and common
+// verbs, and words frequent in page boilerplate. Words under 3 runes are
+// already dropped by Tokenize.
+var stopwords = toSet(
+	// French
+	"les", "des", "une", "est", "sont", "dans", "par", "pour", "sur", "avec",
+	"sans", "sous", "entre", "vers", "chez", "aux", "que", "qui", "quoi",
+	"dont", "quand", "comme", "mais", "donc", "car", "puis", "ainsi", "alors",
+	"aussi", "encore", "tout", "tous", "toute", "toutes", "plus", "moins",
+	"très", "trop", "peu", "bien", "leur", "leurs", "son", "ses", "mon",
+	"mes", "ton", "tes", "notre", "nos", "votre", "vos", "cette", "ces",
+	"cet", "celui", "celle", "ceux", "celles", "elle", "elles", "ils", "nous",
+	"vous", "lui", "eux", "même", "mêmes", "autre", "autres", "été", "être",
+	"avoir", "avait", "avaient", "ont", "était", "étaient", "sera", "seront",
+	"fait", "faire", "peut", "peuvent", "doit", "selon", "après", "avant",
+	"depuis", "pendant", "contre", "lors", "jusqu", "aujourd", "hui", "non",
+	"oui", "ici", "où", "déjà", "fois", "chaque", "quel", "quelle", "quels",
+	"quelles", "avons", "avez", "sommes", "êtes", "suis", "dit", "plusieurs",
+	"cela", "ceci", "rien", "dès", "afin", "parce", "lorsque", "tandis",
+	"ans", "année", "années", "jour", "jours",
+	// English
+	"the", "and", "for", "are", "but", "not", "you", "all", "any", "can",
+	"had", "her", "was", "one", "our", "out", "has", "have", "his", "how",
+	"its", "may", "new", "now", "old", "see", "two", "who", "did", "get",
+	"him", "let", "say", "she", "too", "use", "that", "with", "this", "from",
+	"they", "will", "would", "there", "their", "what", "about", "which",
+	"when", "make", "like", "than", "then", "them", "these", "some", "into",
+	"over", "also", "been", "were", "more", "most", "other", "such", "only",
+	"very", "just", "each", "where", "while", "after", "before", "could",
+	"should", "being", "does", "doing", "here", "those", "through", "under",
+	"until", "upon", "your", "yours", "because", "between", "both", "same",
+	"said", "says", "many", "much", "well", "still", "even", "back", "year",
+	"years", "week", "day", "days", "don", "doesn", "didn", "isn", "aren",
+	"wasn", "weren", "won", "couldn", "wouldn", "shouldn",
+	// Web boilerplate
+	"http", "https", "www", "com", "html", "read", "click", "cookies",
+	"share", "subscribe", "newsletter", "lire", "suite", "partager",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
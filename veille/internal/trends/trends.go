@@ -0,0 +1,202 @@
+// CLAUDE:SUMMARY Trending topics: tokenizes extraction titles/text (French + English stopwords removed) and ranks recurring terms and bigrams by the number of extractions containing them.
+// Package trends finds the themes that recur across a set of extractions.
+//
+// Each document counts at most once per term, so a long article repeating a
+// word does not outweigh several articles mentioning it. Bigrams are built
+// from adjacent kept tokens; a stopword or punctuation breaks adjacency.
+// A unigram is dropped when a bigram containing it covers the same documents.
+package trends
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// minTokenRunes is the minimum length of a kept token.
+	minTokenRunes = 3
+	// titleWeight is how many times a title counts toward a document's terms
+	// when choosing representatives; titles state the theme.
+	titleWeight = 2
+)
+
+// Doc is one extraction to analyse.
+type Doc struct {
+	ID    string
+	Title string
+	Text  string
+}
+
+// Topic is a recurring term or bigram.
+type Topic struct {
+	Term          string   `json:"term"`
+	Count         int      `json:"count"`          // documents containing the term
+	ExtractionIDs []string `json:"extraction_ids"` // representative documents, strongest first
+}
+
+// Options bounds the ranking.
+type Options struct {
+	Limit          int // topics returned; <= 0 means 20
+	MinCount       int // minimum document count; <= 0 means 2
+	Representative int // extraction IDs per topic; <= 0 means 3
+}
+
+// termStats accumulates one term over all documents.
+type termStats struct {
+	docs   []string       // document IDs, in input order
+	weight map[string]int // per document: occurrences, titles weighted
+}
+
+// Rank returns the topics of docs, most frequent first. Ties are broken by
+// bigrams before unigrams, then alphabetically.
+func Rank(docs []Doc, opts Options) []Topic {
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	if opts.MinCount <= 0 {
+		opts.MinCount = 2
+	}
+	if opts.Representative <= 0 {
+		opts.Representative = 3
+	}
+
+	stats := map[string]*termStats{}
+	add := func(term, docID string, w int) {
+		st := stats[term]
+		if st == nil {
+			st = &termStats{weight: map[string]int{}}
+			stats[term] = st
+		}
+		if _, seen := st.weight[docID]; !seen {
+			st.docs = append(st.docs, docID)
+		}
+		st.weight[docID] += w
+	}
+	for _, d := range docs {
+		for _, part := range []struct {
+			text string
+			w    int
+		}{{d.Title, titleWeight}, {d.Text, 1}} {
+			for _, run := range Tokenize(part.text) {
+				for i, tok := range run {
+					add(tok, d.ID, part.w)
+					if i > 0 {
+						add(run[i-1]+" "+tok, d.ID, part.w)
+					}
+				}
+			}
+		}
+	}
+
+	var topics []Topic
+	for term, st := range stats {
+		if len(st.docs) < opts.MinCount {
+			continue
+		}
+		topics = append(topics, Topic{Term: term, Count: len(st.docs)})
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		a, b := topics[i], topics[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if ab, bb := isBigram(a.Term), isBigram(b.Term); ab != bb {
+			return ab
+		}
+		return a.Term < b.Term
+	})
+
+	// Drop unigrams subsumed by a kept bigram with the same document count.
+	covered := map[string]int{}
+	out := make([]Topic, 0, opts.Limit)
+	for _, t := range topics {
+		if !isBigram(t.Term) && covered[t.Term] >= t.Count {
+			continue
+		}
+		if isBigram(t.Term) {
+			for _, w := range strings.Fields(t.Term) {
+				covered[w] = max(covered[w], t.Count)
+			}
+		}
+		t.ExtractionIDs = representatives(stats[t.Term], opts.Representative)
+		out = append(out, t)
+		if len(out) == opts.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// representatives returns up to n document IDs of st, highest weight first,
+// input order on ties.
+func representatives(st *termStats, n int) []string {
+	ids := append([]string(nil), st.docs...)
+	sort.SliceStable(ids, func(i, j int) bool { return st.weight[ids[i]] > st.weight[ids[j]] })
+	return ids[:min(n, len(ids))]
+}
+
+func isBigram(term string) bool { return strings.Contains(term, " ") }
+
+// Tokenize splits text into runs of adjacent kept tokens: lowercased words
+// of at least 3 runes that are not stopwords or numbers. Stopwords,
+// discarded tokens and sentence punctuation end a run.
+func Tokenize(text string) [][]string {
+	var runs [][]string
+	var cur []string
+	flush := func() {
+		if len(cur) > 0 {
+			runs = append(runs, cur)
+			cur = nil
+		}
+	}
+	var word strings.Builder
+	emit := func() {
+		if word.Len() == 0 {
+			return
+		}
+		tok := strings.Trim(strings.ToLower(word.String()), "-")
+		word.Reset()
+		if keep(tok) {
+			cur = append(cur, tok)
+		} else {
+			flush()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		case r == '\'' || r == '’':
+			// Elision (l'énergie, d'un): drop the article, keep the word.
+			if word.Len() <= 2 {
+				word.Reset()
+			} else {
+				emit()
+			}
+		case r == '-' && word.Len() > 0:
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			emit()
+		default:
+			emit()
+			flush()
+		}
+	}
+	emit()
+	flush()
+	return runs
+}
+
+// keep reports whether tok is a meaningful token.
+func keep(tok string) bool {
+	if len([]rune(tok)) < minTokenRunes || stopwords[tok] {
+		return false
+	}
+	for _, r := range tok {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package trends
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRank_DominantTermFirst(t *testing.T) {
+	// WHAT: The term present in most documents ranks first, with its count and representatives.
+	// WHY: This is the core contract of trending topics.
+	docs := []Doc{
+		{ID: "e1", Title: "Hydrogène : nouvelle usine", Text: "L'hydrogène vert attire les investisseurs."},
+		{ID: "e2", Title: "Budget", Text: "Le budget prévoit des aides pour l'hydrogène."},
+		{ID: "e3", Title: "Hydrogen plans", Text: "The hydrogène sector and the budget debate."},
+		{ID: "e4", Title: "Football", Text: "Match nul au stade, le club explose."},
+		{ID: "e5", Title: "Météo", Text: "Pluie et vent sur la côte."},
+	}
+	topics := Rank(docs, Options{})
+	if len(topics) == 0 {
+		t.Fatal("no topics")
+	}
+	top := topics[0]
+	if top.Term != "hydrogène" || top.Count != 3 {
+		t.Fatalf("top topic: got %+v, want hydrogène x3", top)
+	}
+	// e1 has it in the title (weighted), e2 and e3 only in the text, input order on ties.
+	if want := []string{"e1", "e2", "e3"}; !reflect.DeepEqual(top.ExtractionIDs, want) {
+		t.Errorf("representatives: got %v, want %v", top.ExtractionIDs, want)
+	}
+	for _, tp := range topics {
+		if tp.Term == "pluie" || tp.Term == "les" || tp.Term == "the" {
+			t.Errorf("unexpected topic %q (single document or stopword)", tp.Term)
+		}
+	}
+}
+
+func TestRank_BigramSubsumesUnigrams(t *testing.T) {
+	// WHAT: A bigram covering the same documents as its words replaces them.
+	// WHY: "intelligence artificielle" is one topic, not three.
+	docs := []Doc{
+		{ID: "a", Text: "L'intelligence artificielle progresse."},
+		{ID: "b", Text: "Régulation de l'intelligence artificielle en Europe."},
+	}
+	topics := Rank(docs, Options{})
+	var terms []string
+	for _, tp := range topics {
+		terms = append(terms, tp.Term)
+	}
+	if want := []string{"intelligence artificielle"}; !reflect.DeepEqual(terms, want) {
+		t.Errorf("topics: got %v, want %v", terms, want)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	// WHAT: Elisions, stopwords, numbers and punctuation split runs; hyphenated words stay whole.
+	// WHY: Bigrams must only join words that are adjacent in the text.
+	got := Tokenize("L'énergie solaire, 2024 : the state-of-the-art panels.")
+	want := [][]string{{"énergie", "solaire"}, {"state-of-the-art", "panels"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize: got %v, want %v", got, want)
+	}
+}
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Registers 15 MCP tools for veille CRUD operations via kit.RegisterMCPTool.
+// CLAUDE:SUMMARY Registers 16 MCP tools for veille CRUD operations via kit.RegisterMCPTool; add_source, list_sources, search, search_all, list_extractions, stats, fetch_history, run_question and question_results decode through the request types shared with connectivity (requests.go).
 package veille
 
 import (
@@ -17,6 +17,7 @@ func (svc *Service) RegisterMCP(srv *mcp.Server) {
 	svc.registerDeleteSource(srv)
 	svc.registerFetchNow(srv)
 	svc.registerSearch(srv)
+	svc.registerSearchAll(srv)
 	svc.registerListExtractions(srv)
 	svc.registerStats(srv)
 	svc.registerFetchHistory(srv)
@@ -42,44 +43,28 @@ func inputSchema(properties map[string]any, required []string) map[string]any {
 // --- Sources ---
 
 func (svc *Service) registerAddSource(srv *mcp.Server) {
-	type req struct {
-		DossierID string `json:"dossier_id"`
-		Name      string `json:"name"`
-		URL       string `json:"url"`
-		Type      string `json:"source_type"`
-		Interval  int64  `json:"fetch_interval"`
-	}
-
 	tool := &mcp.Tool{
 		Name:        "veille_add_source",
 		Description: "Add a new monitored source to a veille dossier",
 		InputSchema: inputSchema(map[string]any{
-			"dossier_id":    map[string]any{"type": "string", "description": "Dossier ID"},
-			"name":          map[string]any{"type": "string", "description": "Source name"},
-			"url":           map[string]any{"type": "string", "description": "URL to monitor"},
-			"source_type":   map[string]any{"type": "string", "description": "Source type: web, rss, api"},
+			"dossier_id":     map[string]any{"type": "string", "description": "Dossier ID"},
+			"name":           map[string]any{"type": "string", "description": "Source name"},
+			"url":            map[string]any{"type": "string", "description": "URL to monitor"},
+			"source_type":    map[string]any{"type": "string", "description": "Source type: web, rss, sitemap, api"},
 			"fetch_interval": map[string]any{"type": "integer", "description": "Fetch interval in ms"},
+			"tags":           map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Topic tags"},
+			"headers":        map[string]any{"type": "object", "description": "Extra HTTP headers sent when fetching"},
+			"basic_auth":     map[string]any{"type": "object", "description": "HTTP basic auth: {username, password}"},
 		}, []string{"dossier_id", "name", "url"}),
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		src := &Source{
-			Name:          p.Name,
-			URL:           p.URL,
-			SourceType:    p.Type,
-			FetchInterval: p.Interval,
-			Enabled:       true,
-		}
-		if err := svc.AddSource(ctx, p.DossierID, src); err != nil {
-			return nil, err
-		}
-		return src, nil
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p addSourceRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
@@ -89,26 +74,22 @@ func (svc *Service) registerAddSource(srv *mcp.Server) {
 }
 
 func (svc *Service) registerListSources(srv *mcp.Server) {
-	type req struct {
-		DossierID string `json:"dossier_id"`
-	}
-
 	tool := &mcp.Tool{
 		Name:        "veille_list_sources",
 		Description: "List all monitored sources in a veille dossier",
 		InputSchema: inputSchema(map[string]any{
 			"dossier_id": map[string]any{"type": "string", "description": "Dossier ID"},
+			"tag":        map[string]any{"type": "string", "description": "Only sources with this tag"},
 		}, []string{"dossier_id"}),
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		return svc.ListSources(ctx, p.DossierID)
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p listSourcesRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
@@ -119,12 +100,15 @@ func (svc *Service) registerListSources(srv *mcp.Server) {
 
 func (svc *Service) registerUpdateSource(srv *mcp.Server) {
 	type req struct {
-		DossierID string `json:"dossier_id"`
-		SourceID  string `json:"source_id"`
-		Name      string `json:"name"`
-		URL       string `json:"url"`
-		Enabled   *bool  `json:"enabled"`
-		Interval  int64  `json:"fetch_interval"`
+		DossierID string            `json:"dossier_id"`
+		SourceID  string            `json:"source_id"`
+		Name      string            `json:"name"`
+		URL       string            `json:"url"`
+		Enabled   *bool             `json:"enabled"`
+		Interval  int64             `json:"fetch_interval"`
+		Tags      []string          `json:"tags"`
+		Headers   map[string]string `json:"headers"`
+		BasicAuth *BasicAuth        `json:"basic_auth"`
 	}
 
 	tool := &mcp.Tool{
@@ -132,11 +116,14 @@ func (svc *Service) registerUpdateSource(srv *mcp.Server) {
 		Description: "Update a monitored source",
 		InputSchema: inputSchema(map[string]any{
 			"dossier_id":     map[string]any{"type": "string"},
-			"source_id":     map[string]any{"type": "string"},
-			"name":          map[string]any{"type": "string"},
-			"url":           map[string]any{"type": "string"},
-			"enabled":       map[string]any{"type": "boolean"},
+			"source_id":      map[string]any{"type": "string"},
+			"name":           map[string]any{"type": "string"},
+			"url":            map[string]any{"type": "string"},
+			"enabled":        map[string]any{"type": "boolean"},
 			"fetch_interval": map[string]any{"type": "integer"},
+			"tags":           map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"headers":        map[string]any{"type": "object"},
+			"basic_auth":     map[string]any{"type": "object"},
 		}, []string{"dossier_id", "source_id"}),
 	}
 
@@ -147,6 +134,9 @@ func (svc *Service) registerUpdateSource(srv *mcp.Server) {
 			Name:          p.Name,
 			URL:           p.URL,
 			FetchInterval: p.Interval,
+			Tags:          p.Tags,
+			Headers:       p.Headers,
+			BasicAuth:     p.BasicAuth,
 		}
 		if p.Enabled != nil {
 			src.Enabled = *p.Enabled
@@ -239,12 +229,6 @@ func (svc *Service) registerFetchNow(srv *mcp.Server) {
 // --- Read operations ---
 
 func (svc *Service) registerSearch(srv *mcp.Server) {
-	type req struct {
-		DossierID string `json:"dossier_id"`
-		Query     string `json:"query"`
-		Limit     int    `json:"limit"`
-	}
-
 	tool := &mcp.Tool{
 		Name:        "veille_search",
 		Description: "Full-text search on extractions",
@@ -252,17 +236,18 @@ func (svc *Service) registerSearch(srv *mcp.Server) {
 			"dossier_id": map[string]any{"type": "string"},
 			"query":      map[string]any{"type": "string", "description": "FTS5 search query"},
 			"limit":      map[string]any{"type": "integer", "description": "Max results"},
+			"tag":        map[string]any{"type": "string", "description": "Only extractions from sources with this tag"},
+			"lang":       map[string]any{"type": "string", "description": "Only extractions in this language (fr, en, es, de, it, und)"},
 		}, []string{"dossier_id", "query"}),
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		return svc.Search(ctx, p.DossierID, p.Query, p.Limit)
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p searchRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
@@ -271,31 +256,53 @@ func (svc *Service) registerSearch(srv *mcp.Server) {
 	kit.RegisterMCPTool(srv, tool, endpoint, decode)
 }
 
-func (svc *Service) registerListExtractions(srv *mcp.Server) {
-	type req struct {
-		DossierID string `json:"dossier_id"`
-		SourceID  string `json:"source_id"`
-		Limit     int    `json:"limit"`
+func (svc *Service) registerSearchAll(srv *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        "veille_search_all",
+		Description: "Full-text search across every dossier of the authenticated caller, merged by rank and tagged with dossier_id",
+		InputSchema: inputSchema(map[string]any{
+			"query": map[string]any{"type": "string", "description": "FTS5 search query"},
+			"limit": map[string]any{"type": "integer", "description": "Max merged results (default 20, max 200)"},
+			"tag":   map[string]any{"type": "string", "description": "Only extractions from sources with this tag"},
+			"lang":  map[string]any{"type": "string", "description": "Only extractions in this language (fr, en, es, de, it, und)"},
+		}, []string{"query"}),
+	}
+
+	endpoint := func(ctx context.Context, r any) (any, error) {
+		return r.(toolRequest).run(ctx, svc)
+	}
+
+	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
+		var p searchAllRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
+			return nil, err
+		}
+		return &kit.MCPDecodeResult{Request: &p}, nil
 	}
 
+	kit.RegisterMCPTool(srv, tool, endpoint, decode)
+}
+
+func (svc *Service) registerListExtractions(srv *mcp.Server) {
 	tool := &mcp.Tool{
 		Name:        "veille_list_extractions",
-		Description: "List extractions for a source",
+		Description: `List extractions for a source, newest first, one page per call: {"extractions": [...], "next_cursor": "..."}`,
 		InputSchema: inputSchema(map[string]any{
 			"dossier_id": map[string]any{"type": "string"},
 			"source_id":  map[string]any{"type": "string"},
-			"limit":      map[string]any{"type": "integer"},
+			"limit":      map[string]any{"type": "integer", "description": "Page size (default 50, max 200)"},
+			"cursor":     map[string]any{"type": "string", "description": "next_cursor of the previous page"},
+			"lang":       map[string]any{"type": "string", "description": "Only extractions in this language"},
 		}, []string{"dossier_id"}),
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		return svc.ListExtractions(ctx, p.DossierID, p.SourceID, p.Limit)
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p listExtractionsRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
@@ -305,10 +312,6 @@ func (svc *Service) registerListExtractions(srv *mcp.Server) {
 }
 
 func (svc *Service) registerStats(srv *mcp.Server) {
-	type req struct {
-		DossierID string `json:"dossier_id"`
-	}
-
 	tool := &mcp.Tool{
 		Name:        "veille_stats",
 		Description: "Get dossier statistics (sources, extractions, fetch logs)",
@@ -318,13 +321,12 @@ func (svc *Service) registerStats(srv *mcp.Server) {
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		return svc.Stats(ctx, p.DossierID)
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p statsRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
@@ -334,30 +336,24 @@ func (svc *Service) registerStats(srv *mcp.Server) {
 }
 
 func (svc *Service) registerFetchHistory(srv *mcp.Server) {
-	type req struct {
-		DossierID string `json:"dossier_id"`
-		SourceID  string `json:"source_id"`
-		Limit     int    `json:"limit"`
-	}
-
 	tool := &mcp.Tool{
 		Name:        "veille_fetch_history",
-		Description: "Get fetch history for a source",
+		Description: `Get fetch history for a source, newest first, one page per call: {"entries": [...], "next_cursor": "..."}`,
 		InputSchema: inputSchema(map[string]any{
 			"dossier_id": map[string]any{"type": "string"},
 			"source_id":  map[string]any{"type": "string"},
-			"limit":      map[string]any{"type": "integer"},
+			"limit":      map[string]any{"type": "integer", "description": "Page size (default 50, max 200)"},
+			"cursor":     map[string]any{"type": "string", "description": "next_cursor of the previous page"},
 		}, []string{"dossier_id", "source_id"}),
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		return svc.FetchHistory(ctx, p.DossierID, p.SourceID, p.Limit)
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p fetchHistoryRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
@@ -370,13 +366,16 @@ func (svc *Service) registerFetchHistory(srv *mcp.Server) {
 
 func (svc *Service) registerAddQuestion(srv *mcp.Server) {
 	type req struct {
-		DossierID   string `json:"dossier_id"`
-		Text        string `json:"text"`
-		Keywords    string `json:"keywords"`
-		Channels    string `json:"channels"`
-		ScheduleMs  int64  `json:"schedule_ms"`
-		MaxResults  int    `json:"max_results"`
-		FollowLinks *bool  `json:"follow_links"`
+		DossierID        string `json:"dossier_id"`
+		Text             string `json:"text"`
+		Keywords         string `json:"keywords"`
+		Channels         string `json:"channels"`
+		ScheduleMs       int64  `json:"schedule_ms"`
+		MaxResults       int    `json:"max_results"`
+		FollowLinks      *bool  `json:"follow_links"`
+		FollowDepth      int    `json:"follow_depth"`
+		NotifyMinResults int    `json:"notify_min_results"`
+		NotifyPattern    string `json:"notify_pattern"`
 	}
 
 	tool := &mcp.Tool{
@@ -390,18 +389,24 @@ func (svc *Service) registerAddQuestion(srv *mcp.Server) {
 			"schedule_ms": map[string]any{"type": "integer", "description": "Run interval in ms (default 86400000 = 24h)"},
 			"max_results": map[string]any{"type": "integer", "description": "Max results per run (default 20)"},
 			"follow_links": map[string]any{"type": "boolean", "description": "Fetch full page or snippet only"},
+			"follow_depth": map[string]any{"type": "integer", "description": "Link hops followed from fetched result pages (0-3, default 0; needs follow_links)"},
+			"notify_min_results": map[string]any{"type": "integer", "description": "Notify only if a run yields at least N new results (default 1)"},
+			"notify_pattern":     map[string]any{"type": "string", "description": "Optional regex a new result must match to notify"},
 		}, []string{"dossier_id", "text"}),
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
 		p := r.(*req)
 		q := &TrackedQuestion{
-			Text:       p.Text,
-			Keywords:   p.Keywords,
-			Channels:   p.Channels,
-			ScheduleMs: p.ScheduleMs,
-			MaxResults: p.MaxResults,
-			Enabled:    true,
+			Text:             p.Text,
+			Keywords:         p.Keywords,
+			Channels:         p.Channels,
+			ScheduleMs:       p.ScheduleMs,
+			MaxResults:       p.MaxResults,
+			FollowDepth:      p.FollowDepth,
+			NotifyMinResults: p.NotifyMinResults,
+			NotifyPattern:    p.NotifyPattern,
+			Enabled:          true,
 		}
 		if p.FollowLinks != nil {
 			q.FollowLinks = *p.FollowLinks
@@ -456,15 +461,18 @@ func (svc *Service) registerListQuestions(srv *mcp.Server) {
 
 func (svc *Service) registerUpdateQuestion(srv *mcp.Server) {
 	type req struct {
-		DossierID   string `json:"dossier_id"`
-		QuestionID  string `json:"question_id"`
-		Text        string `json:"text"`
-		Keywords    string `json:"keywords"`
-		Channels    string `json:"channels"`
-		ScheduleMs  int64  `json:"schedule_ms"`
-		MaxResults  int    `json:"max_results"`
-		FollowLinks *bool  `json:"follow_links"`
-		Enabled     *bool  `json:"enabled"`
+		DossierID        string `json:"dossier_id"`
+		QuestionID       string `json:"question_id"`
+		Text             string `json:"text"`
+		Keywords         string `json:"keywords"`
+		Channels         string `json:"channels"`
+		ScheduleMs       int64  `json:"schedule_ms"`
+		MaxResults       int    `json:"max_results"`
+		FollowLinks      *bool  `json:"follow_links"`
+		FollowDepth      int    `json:"follow_depth"`
+		NotifyMinResults int    `json:"notify_min_results"`
+		NotifyPattern    string `json:"notify_pattern"`
+		Enabled          *bool  `json:"enabled"`
 	}
 
 	tool := &mcp.Tool{
@@ -479,6 +487,9 @@ func (svc *Service) registerUpdateQuestion(srv *mcp.Server) {
 			"schedule_ms": map[string]any{"type": "integer"},
 			"max_results": map[string]any{"type": "integer"},
 			"follow_links": map[string]any{"type": "boolean"},
+			"follow_depth": map[string]any{"type": "integer"},
+			"notify_min_results": map[string]any{"type": "integer"},
+			"notify_pattern":     map[string]any{"type": "string"},
 			"enabled":     map[string]any{"type": "boolean"},
 		}, []string{"dossier_id", "question_id"}),
 	}
@@ -486,12 +497,15 @@ func (svc *Service) registerUpdateQuestion(srv *mcp.Server) {
 	endpoint := func(ctx context.Context, r any) (any, error) {
 		p := r.(*req)
 		q := &TrackedQuestion{
-			ID:         p.QuestionID,
-			Text:       p.Text,
-			Keywords:   p.Keywords,
-			Channels:   p.Channels,
-			ScheduleMs: p.ScheduleMs,
-			MaxResults: p.MaxResults,
+			ID:               p.QuestionID,
+			Text:             p.Text,
+			Keywords:         p.Keywords,
+			Channels:         p.Channels,
+			ScheduleMs:       p.ScheduleMs,
+			MaxResults:       p.MaxResults,
+			FollowDepth:      p.FollowDepth,
+			NotifyMinResults: p.NotifyMinResults,
+			NotifyPattern:    p.NotifyPattern,
 		}
 		if p.FollowLinks != nil {
 			q.FollowLinks = *p.FollowLinks
@@ -551,11 +565,6 @@ func (svc *Service) registerDeleteQuestion(srv *mcp.Server) {
 }
 
 func (svc *Service) registerRunQuestion(srv *mcp.Server) {
-	type req struct {
-		DossierID  string `json:"dossier_id"`
-		QuestionID string `json:"question_id"`
-	}
-
 	tool := &mcp.Tool{
 		Name:        "veille_run_question",
 		Description: "Run a tracked question immediately",
@@ -566,17 +575,12 @@ func (svc *Service) registerRunQuestion(srv *mcp.Server) {
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		count, err := svc.RunQuestionNow(ctx, p.DossierID, p.QuestionID)
-		if err != nil {
-			return nil, err
-		}
-		return map[string]any{"status": "ok", "new_results": count}, nil
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p runQuestionRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
@@ -586,30 +590,24 @@ func (svc *Service) registerRunQuestion(srv *mcp.Server) {
 }
 
 func (svc *Service) registerQuestionResults(srv *mcp.Server) {
-	type req struct {
-		DossierID  string `json:"dossier_id"`
-		QuestionID string `json:"question_id"`
-		Limit      int    `json:"limit"`
-	}
-
 	tool := &mcp.Tool{
 		Name:        "veille_question_results",
-		Description: "Get extraction results for a tracked question",
+		Description: `Get extraction results for a tracked question, newest first, one page per call: {"results": [...], "next_cursor": "..."}`,
 		InputSchema: inputSchema(map[string]any{
 			"dossier_id":  map[string]any{"type": "string"},
 			"question_id": map[string]any{"type": "string"},
-			"limit":       map[string]any{"type": "integer"},
+			"limit":       map[string]any{"type": "integer", "description": "Page size (default 50, max 200)"},
+			"cursor":      map[string]any{"type": "string", "description": "next_cursor of the previous page"},
 		}, []string{"dossier_id", "question_id"}),
 	}
 
 	endpoint := func(ctx context.Context, r any) (any, error) {
-		p := r.(*req)
-		return svc.QuestionResults(ctx, p.DossierID, p.QuestionID, p.Limit)
+		return r.(toolRequest).run(ctx, svc)
 	}
 
 	decode := func(r *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
-		var p req
-		if err := json.Unmarshal(r.Params.Arguments, &p); err != nil {
+		var p questionResultsRequest
+		if err := decodeToolRequest(r.Params.Arguments, &p); err != nil {
 			return nil, err
 		}
 		return &kit.MCPDecodeResult{Request: &p}, nil
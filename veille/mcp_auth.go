@@ -0,0 +1,66 @@
+// CLAUDE:SUMMARY MCP caller authentication: receiving middleware that turns the bearer token of a tools/call (_meta "authorization") into the audit actor and the veille_search_all scope.
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MCPAuthMetaKey is the _meta key of a tools/call carrying the caller's
+// bearer token, as "Bearer <token>" or the bare token. Transports without
+// HTTP headers (QUIC) have no other place for it.
+const MCPAuthMetaKey = "authorization"
+
+// ErrMCPUnauthenticated is returned for an MCP call whose bearer token is
+// refused by the authenticator.
+var ErrMCPUnauthenticated = errors.New("veille: invalid MCP bearer token")
+
+// MCPCaller is the authenticated caller of an MCP call.
+type MCPCaller struct {
+	UserID string
+	Scope  SearchScope
+}
+
+// MCPAuthenticator verifies a bearer token and returns its caller.
+type MCPAuthenticator func(ctx context.Context, token string) (MCPCaller, error)
+
+// MCPAuthMiddleware returns a receiving middleware for the MCP server that
+// authenticates each tools/call carrying a bearer token in
+// _meta[MCPAuthMetaKey] and runs it with the caller as audit actor
+// (WithActor) and search scope (WithSearchScope). A refused token fails the
+// call with ErrMCPUnauthenticated; a call without token runs anonymous, so
+// veille_search_all answers ErrNoSearchScope.
+func MCPAuthMiddleware(authenticate MCPAuthenticator) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			token := bearerToken(req.GetParams().GetMeta())
+			if token == "" {
+				return next(ctx, method, req)
+			}
+			caller, err := authenticate(ctx, token)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrMCPUnauthenticated, err)
+			}
+			ctx = WithSearchScope(WithActor(ctx, caller.UserID), caller.Scope)
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// bearerToken returns the token of meta[MCPAuthMetaKey], without its
+// "Bearer " prefix, or "".
+func bearerToken(meta map[string]any) string {
+	v, _ := meta[MCPAuthMetaKey].(string)
+	v = strings.TrimSpace(v)
+	if len(v) > len("bearer ") && strings.EqualFold(v[:len("bearer ")], "bearer ") {
+		v = strings.TrimSpace(v[len("bearer "):])
+	}
+	return v
+}
@@ -0,0 +1,128 @@
+package veille
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connectMCP serves svc's tools behind MCPAuthMiddleware on in-memory
+// transports and returns a connected client session.
+func connectMCP(t *testing.T, svc *Service, authenticate MCPAuthenticator) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	srv := mcp.NewServer(&mcp.Implementation{Name: "veille-test", Version: "test"}, nil)
+	svc.RegisterMCP(srv)
+	srv.AddReceivingMiddleware(MCPAuthMiddleware(authenticate))
+	serverT, clientT := mcp.NewInMemoryTransports()
+	ss, err := srv.Connect(ctx, serverT, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "test"}, nil).Connect(ctx, clientT, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+// callSearchAll calls veille_search_all with token in _meta ("" = none) and
+// returns the result text, or the protocol error.
+func callSearchAll(t *testing.T, cs *mcp.ClientSession, token string) (string, bool, error) {
+	t.Helper()
+	params := &mcp.CallToolParams{
+		Name:      "veille_search_all",
+		Arguments: map[string]any{"query": "sovereign"},
+	}
+	if token != "" {
+		params.Meta = mcp.Meta{MCPAuthMetaKey: "Bearer " + token}
+	}
+	res, err := cs.CallTool(context.Background(), params)
+	if err != nil {
+		return "", false, err
+	}
+	var text strings.Builder
+	for _, c := range res.Content {
+		if tc, ok := c.(*mcp.TextContent); ok {
+			text.WriteString(tc.Text)
+		}
+	}
+	return text.String(), res.IsError, nil
+}
+
+func TestMCPAuth_SearchAllThroughServer(t *testing.T) {
+	// WHAT: veille_search_all called through the MCP server is scoped to the
+	// caller of the bearer token in _meta; no token or a bad one gets nothing.
+	// WHY: the MCP/QUIC transport has no session cookie — without this the
+	// tool could only ever answer ErrNoSearchScope.
+	svc := newSearchAllService(t)
+	var actors []string
+	cs := connectMCP(t, svc, func(ctx context.Context, token string) (MCPCaller, error) {
+		if token != "alice-token" {
+			return MCPCaller{}, errors.New("unknown token")
+		}
+		actors = append(actors, "alice")
+		return MCPCaller{UserID: "alice", Scope: SearchScope{OwnerID: "alice"}}, nil
+	})
+
+	text, isErr, err := callSearchAll(t, cs, "alice-token")
+	if err != nil || isErr {
+		t.Fatalf("authenticated call: err=%v isError=%v text=%s", err, isErr, text)
+	}
+	var res CrossSearchResult
+	if err := json.Unmarshal([]byte(text), &res); err != nil {
+		t.Fatalf("decode %q: %v", text, err)
+	}
+	got := map[string]bool{}
+	for _, r := range res.Results {
+		got[r.DossierID] = true
+	}
+	if len(res.Results) != 2 || !got["d-a"] || !got["d-b"] {
+		t.Errorf("alice hits = %v, want d-a and d-b only", got)
+	}
+	if len(actors) != 1 {
+		t.Errorf("authenticator calls = %d, want 1", len(actors))
+	}
+
+	text, isErr, err = callSearchAll(t, cs, "")
+	if err == nil && !isErr {
+		t.Fatalf("anonymous call succeeded: %s", text)
+	}
+	if err == nil && !strings.Contains(text, ErrNoSearchScope.Error()) {
+		t.Errorf("anonymous call error = %q, want %q", text, ErrNoSearchScope)
+	}
+
+	text, isErr, err = callSearchAll(t, cs, "mallory-token")
+	if err == nil && !isErr {
+		t.Fatalf("bad token call succeeded: %s", text)
+	}
+	msg := text
+	if err != nil {
+		msg = err.Error()
+	}
+	if !strings.Contains(msg, "invalid MCP bearer token") {
+		t.Errorf("bad token error = %q, want ErrMCPUnauthenticated", msg)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	// WHAT: the token is read with or without its Bearer prefix.
+	// WHY: clients send either form in _meta.authorization.
+	cases := map[string]string{
+		"Bearer abc": "abc", "bearer  abc ": "abc", "abc": "abc", "": "",
+	}
+	for in, want := range cases {
+		if got := bearerToken(map[string]any{MCPAuthMetaKey: in}); got != want {
+			t.Errorf("bearerToken(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := bearerToken(nil); got != "" {
+		t.Errorf("bearerToken(nil) = %q", got)
+	}
+}
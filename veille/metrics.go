@@ -0,0 +1,95 @@
+// CLAUDE:SUMMARY Metrics interface and Prometheus-backed implementation for scheduler and pipeline instrumentation.
+package veille
+
+import (
+	"fmt"
+
+	"github.com/hazyhaar/chrc/veille/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics records operational counters and histograms emitted by the
+// scheduler and pipeline. The default is a no-op; see NewPrometheusMetrics.
+type Metrics = metrics.Metrics
+
+// Metric names, re-exported for custom Metrics implementations.
+const (
+	MetricFetchTotal       = metrics.FetchTotal
+	MetricFetchDuration    = metrics.FetchDuration
+	MetricExtractionsTotal = metrics.ExtractionsTotal
+	MetricSchedulerTick    = metrics.SchedulerTick
+)
+
+// WithMetrics sets the metrics sink for the scheduler and pipeline.
+func WithMetrics(m Metrics) ServiceOption {
+	return func(svc *Service) { svc.metrics = m }
+}
+
+// PrometheusMetrics implements Metrics with a fixed set of Prometheus collectors.
+// Labels are limited to source_type and status; unknown metric names or
+// label counts are dropped so cardinality stays bounded.
+type PrometheusMetrics struct {
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// NewPrometheusMetrics creates the veille collectors and registers them on reg.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		counters: map[string]*prometheus.CounterVec{
+			metrics.FetchTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: metrics.FetchTotal,
+				Help: "Pipeline jobs by source type and outcome.",
+			}, []string{"source_type", "status"}),
+			metrics.ExtractionsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: metrics.ExtractionsTotal,
+				Help: "Extractions stored by source type.",
+			}, []string{"source_type"}),
+		},
+		histograms: map[string]*prometheus.HistogramVec{
+			metrics.FetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    metrics.FetchDuration,
+				Help:    "Pipeline job duration in seconds by source type.",
+				Buckets: prometheus.DefBuckets,
+			}, []string{"source_type"}),
+			metrics.SchedulerTick: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    metrics.SchedulerTick,
+				Help:    "Duration of one scheduler pass over all shards in seconds.",
+				Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+			}, nil),
+		},
+	}
+	for name, c := range m.counters {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("register %s: %w", name, err)
+		}
+	}
+	for name, h := range m.histograms {
+		if err := reg.Register(h); err != nil {
+			return nil, fmt.Errorf("register %s: %w", name, err)
+		}
+	}
+	return m, nil
+}
+
+// Add implements Metrics.
+func (m *PrometheusMetrics) Add(name string, delta float64, labels ...string) {
+	c, ok := m.counters[name]
+	if !ok {
+		return
+	}
+	if counter, err := c.GetMetricWithLabelValues(labels...); err == nil {
+		counter.Add(delta)
+	}
+}
+
+// Observe implements Metrics.
+func (m *PrometheusMetrics) Observe(name string, value float64, labels ...string) {
+	h, ok := m.histograms[name]
+	if !ok {
+		return
+	}
+	if obs, err := h.GetMetricWithLabelValues(labels...); err == nil {
+		obs.Observe(value)
+	}
+}
@@ -0,0 +1,113 @@
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+
+	_ "modernc.org/sqlite"
+)
+
+// gatherValue returns the counter value of name with the given labels, or -1 if absent.
+func gatherValue(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+	metric:
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if labels[lp.GetName()] != lp.GetValue() {
+					continue metric
+				}
+			}
+			if c := m.GetCounter(); c != nil {
+				return c.GetValue()
+			}
+			if h := m.GetHistogram(); h != nil {
+				return float64(h.GetSampleCount())
+			}
+		}
+	}
+	return -1
+}
+
+func TestMetrics_FetchNowIncrementsCounters(t *testing.T) {
+	// WHAT: FetchNow on a document source increments fetch, duration and extraction metrics.
+	// WHY: Operators scrape these counters; instrumentation must fire on every pipeline job.
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db.Exec("PRAGMA foreign_keys=ON")
+	if err := store.ApplySchema(db); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	reg := prometheus.NewRegistry()
+	m, err := NewPrometheusMetrics(reg)
+	if err != nil {
+		t.Fatalf("metrics: %v", err)
+	}
+	svc, err := New(&testPool{db: db}, nil, nil, WithMetrics(m))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "note.txt")
+	if err := os.WriteFile(path, []byte("Quarterly report on sovereign cloud adoption."), 0o644); err != nil {
+		t.Fatalf("write doc: %v", err)
+	}
+	st := store.NewStore(db)
+	st.InsertSource(ctx, &store.Source{ID: "src-doc", Name: "Doc", URL: path, SourceType: "document", Enabled: true})
+
+	if err := svc.FetchNow(ctx, "d1", "src-doc"); err != nil {
+		t.Fatalf("fetch now: %v", err)
+	}
+
+	if got := gatherValue(t, reg, MetricFetchTotal, map[string]string{"source_type": "document", "status": "ok"}); got != 1 {
+		t.Errorf("fetch_total{document,ok}: got %v, want 1", got)
+	}
+	if got := gatherValue(t, reg, MetricFetchDuration, map[string]string{"source_type": "document"}); got != 1 {
+		t.Errorf("fetch_duration samples: got %v, want 1", got)
+	}
+	if got := gatherValue(t, reg, MetricExtractionsTotal, map[string]string{"source_type": "document"}); got != 1 {
+		t.Errorf("extractions_total{document}: got %v, want 1", got)
+	}
+
+	// Missing file: the job fails and is counted as an error.
+	st.InsertSource(ctx, &store.Source{ID: "src-gone", Name: "Gone", URL: path + ".missing", SourceType: "document", Enabled: true})
+	svc.FetchNow(ctx, "d1", "src-gone")
+	if got := gatherValue(t, reg, MetricFetchTotal, map[string]string{"source_type": "document", "status": "error"}); got != 1 {
+		t.Errorf("fetch_total{document,error}: got %v, want 1", got)
+	}
+}
+
+func TestPrometheusMetrics_DropsUnknownLabels(t *testing.T) {
+	// WHAT: Unknown metric names and wrong label counts are ignored.
+	// WHY: A mislabelled call must not panic nor create unbounded series.
+	reg := prometheus.NewRegistry()
+	m, err := NewPrometheusMetrics(reg)
+	if err != nil {
+		t.Fatalf("metrics: %v", err)
+	}
+	m.Add("veille_unknown_total", 1, "x")
+	m.Add(MetricFetchTotal, 1, "web")
+	m.Observe(MetricSchedulerTick, 0.5, "extra")
+
+	if got := gatherValue(t, reg, MetricFetchTotal, map[string]string{"source_type": "web"}); got != -1 {
+		t.Errorf("fetch_total with missing label should not exist, got %v", got)
+	}
+}
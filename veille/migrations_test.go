@@ -0,0 +1,91 @@
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+
+	_ "modernc.org/sqlite"
+)
+
+// mapPool resolves each dossier to its own database.
+type mapPool map[string]*sql.DB
+
+func (mp mapPool) Resolve(_ context.Context, dossierID string) (*sql.DB, error) {
+	db, ok := mp[dossierID]
+	if !ok {
+		return nil, fmt.Errorf("unknown dossier %q", dossierID)
+	}
+	return db, nil
+}
+
+func openShardDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open shard: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestCheckShardMigrations_ReportsOldShard(t *testing.T) {
+	// WHAT: A shard on the pre-migration schema is reported with its pending migrations; a current shard is not.
+	// WHY: Operators must be able to preview startup migrations without touching the shards.
+	ctx := context.Background()
+
+	current := openShardDB(t)
+	if err := ApplySchema(current); err != nil {
+		t.Fatalf("apply schema: %v", err)
+	}
+	old := openShardDB(t)
+	if _, err := old.Exec(store.Schema); err != nil { // base tables only, no migrations
+		t.Fatalf("old schema: %v", err)
+	}
+
+	catalogDB := openCatalogDB(t)
+	insertShard(t, catalogDB, "d-current", "active")
+	insertShard(t, catalogDB, "d-old", "active")
+	insertShard(t, catalogDB, "d-missing", "active")
+
+	svc, err := New(mapPool{"d-current": current, "d-old": old}, nil, nil, WithCatalogDB(catalogDB))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	statuses, err := svc.CheckShardMigrations(ctx)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	byID := map[string]MigrationStatus{}
+	for _, st := range statuses {
+		byID[st.DossierID] = st
+	}
+
+	if st := byID["d-current"]; len(st.Pending) != 0 || st.Error != "" {
+		t.Errorf("current shard: got %+v, want nothing pending", st)
+	}
+	oldStatus := byID["d-old"]
+	if oldStatus.Error != "" {
+		t.Errorf("old shard dry run: %s", oldStatus.Error)
+	}
+	for _, want := range []string{"001_unique_url", "003_soft_delete", "005_notify_pattern"} {
+		if !slices.Contains(oldStatus.Pending, want) {
+			t.Errorf("old shard pending %v: missing %s", oldStatus.Pending, want)
+		}
+	}
+	if st := byID["d-missing"]; st.Error == "" {
+		t.Error("unresolvable shard should report an error")
+	}
+
+	// The check must not have migrated the old shard.
+	var n int
+	old.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('sources') WHERE name = 'deleted_at'`).Scan(&n)
+	if n != 0 {
+		t.Error("dry run applied migration 003 to the old shard")
+	}
+}
@@ -0,0 +1,123 @@
+// CLAUDE:SUMMARY OPML import: parses nested outlines into RSS feeds and adds them to a dossier with a per-feed result.
+package veille
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hazyhaar/pkg/horosafe"
+)
+
+// OPML import statuses.
+const (
+	OPMLAdded         = "added"
+	OPMLDuplicate     = "duplicate"      // URL already in the dossier (or earlier in the file)
+	OPMLRejectedSSRF  = "rejected_ssrf"  // private address or unsafe scheme
+	OPMLInvalid       = "invalid"        // any other validation error
+	OPMLQuotaExceeded = "quota_exceeded" // MaxSourcesPerSpace reached on this feed
+	OPMLSkipped       = "skipped"        // not tried, quota already reached
+)
+
+// OPMLFeed is an RSS/Atom feed listed in an OPML document.
+type OPMLFeed struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// OPMLImportResult is the outcome of importing one feed.
+type OPMLImportResult struct {
+	OPMLFeed
+	Status   string `json:"status"`
+	SourceID string `json:"source_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ParseOPML returns the feeds of an OPML document in document order.
+// Outlines without xmlUrl are folders: their children are walked at any
+// depth.
+func ParseOPML(r io.Reader) ([]OPMLFeed, error) {
+	var doc struct {
+		XMLName xml.Name `xml:"opml"`
+		Body    struct {
+			Outlines []opmlOutline `xml:"outline"`
+		} `xml:"body"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: opml: %v", ErrInvalidInput, err)
+	}
+	var feeds []OPMLFeed
+	var walk func([]opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for _, o := range outlines {
+			if u := strings.TrimSpace(o.XMLURL); u != "" {
+				title := strings.TrimSpace(o.Title)
+				if title == "" {
+					title = strings.TrimSpace(o.Text)
+				}
+				if title == "" {
+					title = u
+				}
+				if len(title) > maxNameLen {
+					title = strings.ToValidUTF8(title[:maxNameLen], "")
+				}
+				feeds = append(feeds, OPMLFeed{Title: title, URL: u})
+			}
+			walk(o.Outlines)
+		}
+	}
+	walk(doc.Body.Outlines)
+	return feeds, nil
+}
+
+// ImportOPML adds every feed of an OPML document to the dossier as an rss
+// source. Each feed gets a result; once the source quota is reached the
+// remaining feeds are skipped.
+func (svc *Service) ImportOPML(ctx context.Context, dossierID string, r io.Reader) ([]OPMLImportResult, error) {
+	feeds, err := ParseOPML(r)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]OPMLImportResult, 0, len(feeds))
+	added, quota := 0, false
+	for _, f := range feeds {
+		res := OPMLImportResult{OPMLFeed: f}
+		if quota {
+			res.Status = OPMLSkipped
+			results = append(results, res)
+			continue
+		}
+		src := &Source{Name: f.Title, URL: f.URL, SourceType: "rss", Enabled: true}
+		err := svc.AddSource(ctx, dossierID, src)
+		switch {
+		case err == nil:
+			res.Status, res.SourceID = OPMLAdded, src.ID
+			added++
+		case errors.Is(err, ErrDuplicateSource):
+			res.Status = OPMLDuplicate
+		case errors.Is(err, ErrQuotaExceeded):
+			res.Status, res.Error = OPMLQuotaExceeded, err.Error()
+			quota = true
+		case errors.Is(err, horosafe.ErrSSRF) || errors.Is(err, horosafe.ErrUnsafeScheme):
+			res.Status, res.Error = OPMLRejectedSSRF, err.Error()
+		case errors.Is(err, ErrInvalidInput):
+			res.Status, res.Error = OPMLInvalid, err.Error()
+		default:
+			// Store or shard failure: not a property of this feed.
+			return results, err
+		}
+		results = append(results, res)
+	}
+	svc.auditLog(ctx, "import_opml", fmt.Sprintf(`{"dossier_id":%q,"feeds":%d,"added":%d}`, dossierID, len(feeds), added))
+	return results, nil
+}
@@ -0,0 +1,72 @@
+package veille
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const sampleOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Export</title></head>
+  <body>
+    <outline text="Tech">
+      <outline text="Example News" type="rss" xmlUrl="https://example.com/feed.xml"/>
+      <outline text="Nested">
+        <outline title="Example Blog" type="rss" xmlUrl="https://example.org/blog/rss"/>
+      </outline>
+    </outline>
+    <outline text="Example News again" type="rss" xmlUrl="https://example.com/feed.xml"/>
+    <outline text="Router" type="rss" xmlUrl="http://192.168.1.1/feed"/>
+  </body>
+</opml>`
+
+func TestParseOPML_Nested(t *testing.T) {
+	feeds, err := ParseOPML(strings.NewReader(sampleOPML))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(feeds) != 4 {
+		t.Fatalf("got %d feeds, want 4: %+v", len(feeds), feeds)
+	}
+	if feeds[1].Title != "Example Blog" || feeds[1].URL != "https://example.org/blog/rss" {
+		t.Errorf("nested feed: %+v", feeds[1])
+	}
+	if _, err := ParseOPML(strings.NewReader("<html></html>")); err == nil {
+		t.Error("expected error for non-OPML document")
+	}
+}
+
+func TestImportOPML_ClassifiesFeeds(t *testing.T) {
+	// WHAT: Importing an OPML adds valid feeds as rss sources and reports duplicates and SSRF rejections per feed.
+	// WHY: Users migrating from another reader need to see exactly which feeds were not imported and why.
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	results, err := svc.ImportOPML(ctx, "d1", strings.NewReader(sampleOPML))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	want := []string{OPMLAdded, OPMLAdded, OPMLDuplicate, OPMLRejectedSSRF}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("feed %d (%s): status %q, want %q (%s)", i, r.URL, r.Status, want[i], r.Error)
+		}
+	}
+
+	sources, err := svc.ListSources(ctx, "d1")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("got %d sources, want 2", len(sources))
+	}
+	for _, s := range sources {
+		if s.SourceType != "rss" {
+			t.Errorf("source %s: type %q, want rss", s.URL, s.SourceType)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// CLAUDE:SUMMARY PreviewFetch: runs fetch + extraction for a URL against a throwaway in-memory store, without touching any shard.
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/crawl"
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/pipeline"
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// PreviewTimeout bounds a whole PreviewFetch call.
+const PreviewTimeout = 15 * time.Second
+
+// Caps on what PreviewFetch returns.
+const (
+	maxPreviewExtractions = 100
+	maxPreviewLinks       = 200
+)
+
+// FetchPreview is what the pipeline would store for a URL.
+type FetchPreview struct {
+	URL         string        `json:"url"`
+	SourceType  string        `json:"source_type"`
+	Extractions []*Extraction `json:"extractions"` // one for web, one per feed entry for rss
+	Links       []string      `json:"links"`       // absolute http(s) links found in the extracted HTML
+}
+
+// PreviewFetch fetches rawURL and runs the extraction for sourceType ("web"
+// or "rss", default "web") in a throwaway in-memory store. No shard, fetch
+// log, buffer, shared content or post-processor is involved. The URL goes
+// through the same normalization and SSRF validation as AddSource.
+// Requires the "sqlite" database/sql driver to be registered.
+func (svc *Service) PreviewFetch(ctx context.Context, rawURL, sourceType string) (*FetchPreview, error) {
+	if sourceType == "" {
+		sourceType = "web"
+	}
+	if sourceType != "web" && sourceType != "rss" {
+		return nil, fmt.Errorf("%w: preview supports web and rss sources, not %q", ErrInvalidInput, sourceType)
+	}
+	if rawURL == "" || len(rawURL) > maxURLLen {
+		return nil, fmt.Errorf("%w: url is required (max %d characters)", ErrInvalidInput, maxURLLen)
+	}
+	normalized, err := NormalizeSourceURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.config.Fetch.CheckEgress(normalized); err != nil {
+		return nil, err
+	}
+	if err := svc.urlValidator(normalized); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, PreviewTimeout)
+	defer cancel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("preview store: %w", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1) // each :memory: connection is a separate database
+	if err := store.ApplySchema(db); err != nil {
+		return nil, fmt.Errorf("preview schema: %w", err)
+	}
+	st := store.NewStore(db)
+
+	src := &Source{ID: "preview", Name: "preview", URL: normalized, SourceType: sourceType, Enabled: true}
+	if err := st.InsertSource(ctx, src); err != nil {
+		return nil, fmt.Errorf("preview source: %w", err)
+	}
+
+	// A dedicated fetcher: no shared circuit breaker state, same limits.
+	f := fetch.New(fetch.Config{
+		Timeout:           PreviewTimeout,
+		MaxResponseBytes:  svc.config.Fetch.MaxResponseBytes,
+		TypeMaxBytes:      svc.config.Fetch.TypeMaxBytes,
+		UserAgent:         svc.config.Fetch.UserAgent,
+		URLValidator:      svc.urlValidator,
+		AllowHosts:        svc.config.Fetch.AllowHosts,
+		DenyHosts:         svc.config.Fetch.DenyHosts,
+		Proxy:             svc.config.Fetch.Proxy,
+		AllowPrivateProxy: svc.config.Fetch.AllowPrivateProxy,
+		BreakerThreshold:  -1,
+	})
+	p := pipeline.New(f, svc.logger)
+	if err := p.HandleJob(ctx, st, &pipeline.Job{SourceID: src.ID, URL: src.URL}); err != nil {
+		return nil, err
+	}
+
+	exts, err := st.ListExtractions(ctx, src.ID, maxPreviewExtractions)
+	if err != nil {
+		return nil, fmt.Errorf("preview extractions: %w", err)
+	}
+	preview := &FetchPreview{URL: normalized, SourceType: sourceType, Extractions: exts}
+	for _, e := range exts {
+		preview.Links = appendLinks(preview.Links, e.ExtractedHTML, e.URL)
+	}
+	return preview, nil
+}
+
+// appendLinks adds the distinct absolute http(s) links of fragment, resolved
+// against base, to links (up to maxPreviewLinks).
+func appendLinks(links []string, fragment, base string) []string {
+	for _, l := range crawl.Links(fragment, base, 0) {
+		if len(links) >= maxPreviewLinks {
+			break
+		}
+		if !slices.Contains(links, l) {
+			links = append(links, l)
+		}
+	}
+	return links
+}
@@ -0,0 +1,75 @@
+package veille
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestPreviewFetch_ExtractsWithoutPersisting(t *testing.T) {
+	// WHAT: PreviewFetch returns the extracted title, text and links, and leaves the shard untouched.
+	// WHY: Users check what the extractor produces before committing a source.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html><html><head><title>Sovereign Cloud Weekly</title></head>
+		<body><main><article>
+		<h1>Sovereign Cloud Weekly</h1>
+		<p>European regulators published new guidance on data residency for public
+		sector workloads. Providers have six months to comply with the updated rules,
+		see the <a href="/guidance#summary">full guidance</a>.</p>
+		</article></main></body></html>`))
+	}))
+	defer srv.Close()
+
+	svc, db := setupTestService(t)
+	svc.urlValidator = func(string) error { return nil }
+	ctx := context.Background()
+
+	preview, err := svc.PreviewFetch(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if len(preview.Extractions) != 1 {
+		t.Fatalf("extractions: got %d, want 1", len(preview.Extractions))
+	}
+	e := preview.Extractions[0]
+	if e.Title != "Sovereign Cloud Weekly" {
+		t.Errorf("title: got %q", e.Title)
+	}
+	if !strings.Contains(e.ExtractedText, "data residency") {
+		t.Errorf("text: got %q", e.ExtractedText)
+	}
+	if !slices.Contains(preview.Links, srv.URL+"/guidance") {
+		t.Errorf("links: got %v, want %s/guidance", preview.Links, srv.URL)
+	}
+
+	st := store.NewStore(db)
+	if n, _ := st.CountSources(ctx); n != 0 {
+		t.Errorf("preview created %d sources in the shard", n)
+	}
+	var logs int
+	db.QueryRow(`SELECT COUNT(*) FROM fetch_log`).Scan(&logs)
+	if logs != 0 {
+		t.Errorf("preview wrote %d fetch_log entries", logs)
+	}
+}
+
+func TestPreviewFetch_RejectsUnsafeInput(t *testing.T) {
+	// WHAT: Unsupported source types and SSRF-blocked URLs are rejected before any fetch.
+	// WHY: The preview endpoint fetches arbitrary user URLs.
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.PreviewFetch(ctx, "https://example.com", "document"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("document type: got %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.PreviewFetch(ctx, "http://127.0.0.1/admin", "web"); err == nil {
+		t.Error("loopback URL should be blocked")
+	}
+}
@@ -0,0 +1,83 @@
+// CLAUDE:SUMMARY Per-dossier source quota: catalog override of MaxSourcesPerSpace, resolved by AddSource, managed by admins.
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxSourcesOverride bounds a per-dossier source quota override.
+const maxSourcesOverride = 100_000
+
+// ErrNoCatalog is returned by quota overrides without WithCatalogDB.
+var ErrNoCatalog = errors.New("veille: catalog database required (WithCatalogDB)")
+
+// SourceQuota is the effective source limit of a dossier.
+type SourceQuota struct {
+	DossierID  string `json:"dossier_id"`
+	MaxSources int    `json:"max_sources"`
+	Override   bool   `json:"override"` // false = MaxSourcesPerSpace default
+	Sources    int    `json:"sources"`  // current count
+}
+
+// maxSources returns the source limit of dossierID: its catalog override,
+// else MaxSourcesPerSpace.
+func (svc *Service) maxSources(ctx context.Context, dossierID string) (n int, override bool, err error) {
+	if svc.quotas == nil {
+		return MaxSourcesPerSpace, false, nil
+	}
+	n, ok, err := svc.quotas.MaxSources(ctx, dossierID)
+	if err != nil {
+		return 0, false, fmt.Errorf("source quota: %w", err)
+	}
+	if !ok {
+		return MaxSourcesPerSpace, false, nil
+	}
+	return n, true, nil
+}
+
+// GetSourceQuota returns the effective source limit and count of a dossier.
+func (svc *Service) GetSourceQuota(ctx context.Context, dossierID string) (*SourceQuota, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := st.CountSources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count sources: %w", err)
+	}
+	n, override, err := svc.maxSources(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	return &SourceQuota{DossierID: dossierID, MaxSources: n, Override: override, Sources: count}, nil
+}
+
+// SetSourceQuota overrides the source limit of a dossier (1 to 100000).
+// Lowering it below the current count blocks new sources only.
+func (svc *Service) SetSourceQuota(ctx context.Context, dossierID string, maxSources int) error {
+	if svc.quotas == nil {
+		return ErrNoCatalog
+	}
+	if dossierID == "" || maxSources < 1 || maxSources > maxSourcesOverride {
+		return fmt.Errorf("%w: max_sources must be between 1 and %d", ErrInvalidInput, maxSourcesOverride)
+	}
+	if err := svc.quotas.SetMaxSources(ctx, dossierID, maxSources); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "set_source_quota", fmt.Sprintf(`{"dossier_id":%q,"max_sources":%d}`, dossierID, maxSources))
+	return nil
+}
+
+// DeleteSourceQuota removes the override; MaxSourcesPerSpace applies again.
+func (svc *Service) DeleteSourceQuota(ctx context.Context, dossierID string) error {
+	if svc.quotas == nil {
+		return ErrNoCatalog
+	}
+	if err := svc.quotas.DeleteMaxSources(ctx, dossierID); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "delete_source_quota", fmt.Sprintf(`{"dossier_id":%q}`, dossierID))
+	return nil
+}
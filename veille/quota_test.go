@@ -0,0 +1,79 @@
+package veille
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func newQuotaService(t *testing.T) (*Service, *sql.DB) {
+	t.Helper()
+	_, db := setupTestService(t)
+	svc, err := New(&testPool{db: db}, nil, nil,
+		WithCatalogDB(openCatalogDB(t)), WithURLValidator(func(string) error { return nil }))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	return svc, db
+}
+
+func TestSourceQuota_RaisedOverrideAllowsMore(t *testing.T) {
+	// WHAT: With the default limit reached, AddSource fails; a higher per-dossier override lets the next source in.
+	// WHY: Premium and internal dossiers need more than MaxSourcesPerSpace without changing the global default.
+	svc, db := newQuotaService(t)
+	ctx := context.Background()
+	st := store.NewStore(db)
+	for i := range MaxSourcesPerSpace {
+		st.InsertSource(ctx, &store.Source{ID: fmt.Sprintf("s%d", i), Name: "S", URL: fmt.Sprintf("https://s.example/%d", i), SourceType: "web", Enabled: true})
+	}
+
+	err := svc.AddSource(ctx, "d1", &Source{Name: "Extra", URL: "https://extra.example", SourceType: "web", Enabled: true})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("at default limit: got %v, want ErrQuotaExceeded", err)
+	}
+
+	if err := svc.SetSourceQuota(ctx, "d1", MaxSourcesPerSpace+5); err != nil {
+		t.Fatalf("set quota: %v", err)
+	}
+	if err := svc.AddSource(ctx, "d1", &Source{Name: "Extra", URL: "https://extra.example", SourceType: "web", Enabled: true}); err != nil {
+		t.Fatalf("with raised quota: %v", err)
+	}
+	q, err := svc.GetSourceQuota(ctx, "d1")
+	if err != nil || !q.Override || q.MaxSources != MaxSourcesPerSpace+5 || q.Sources != MaxSourcesPerSpace+1 {
+		t.Fatalf("quota: %+v, %v", q, err)
+	}
+}
+
+func TestSourceQuota_LoweredOverrideRejectsAtThreshold(t *testing.T) {
+	// WHAT: A lowered override rejects the source past the new threshold, reports that limit, and removing it restores the default.
+	// WHY: The error must tell the user the limit that actually applies to their dossier.
+	svc, _ := newQuotaService(t)
+	ctx := context.Background()
+	if err := svc.SetSourceQuota(ctx, "d1", 2); err != nil {
+		t.Fatalf("set quota: %v", err)
+	}
+	for i := range 2 {
+		if err := svc.AddSource(ctx, "d1", &Source{Name: "S", URL: fmt.Sprintf("https://low.example/%d", i), SourceType: "web", Enabled: true}); err != nil {
+			t.Fatalf("add %d: %v", i, err)
+		}
+	}
+	err := svc.AddSource(ctx, "d1", &Source{Name: "S", URL: "https://low.example/2", SourceType: "web", Enabled: true})
+	if !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "maximum 2 sources") {
+		t.Fatalf("past lowered quota: got %v, want ErrQuotaExceeded with limit 2", err)
+	}
+
+	if err := svc.SetSourceQuota(ctx, "d1", 0); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("zero quota: got %v, want ErrInvalidInput", err)
+	}
+	if err := svc.DeleteSourceQuota(ctx, "d1"); err != nil {
+		t.Fatalf("delete quota: %v", err)
+	}
+	if err := svc.AddSource(ctx, "d1", &Source{Name: "S", URL: "https://low.example/2", SourceType: "web", Enabled: true}); err != nil {
+		t.Errorf("after removing override: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+// CLAUDE:SUMMARY Related extractions: embeds stored extractions via horosembed_embed into horosvec_insert and finds nearest neighbours via horosvec_search, gated on both services being on the router.
+package veille
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+	"github.com/hazyhaar/pkg/connectivity"
+)
+
+// Connectivity services used for related extractions.
+const (
+	embedService        = "horosembed_embed"
+	vectorInsertService = "horosvec_insert"
+	vectorSearchService = "horosvec_search"
+)
+
+// Related result bounds.
+const (
+	defaultRelatedTopK = 10
+	maxRelatedTopK     = 100
+)
+
+// ErrVectorsDisabled is returned by Related when the router does not expose
+// an embedder (horosembed_embed) and a vector index (horosvec_insert,
+// horosvec_search).
+var ErrVectorsDisabled = errors.New("veille: related extractions require embed and vector services")
+
+// RelatedExtraction is an extraction found near another, with its similarity
+// score as reported by the vector index (higher is closer).
+type RelatedExtraction struct {
+	Extraction *Extraction `json:"extraction"`
+	Score      float64     `json:"score"`
+}
+
+// vectorsAvailable reports whether router exposes the embed and vector services.
+func vectorsAvailable(router *connectivity.Router) bool {
+	if router == nil {
+		return false
+	}
+	need := map[string]bool{embedService: true, vectorInsertService: true, vectorSearchService: true}
+	for si := range router.ListServices() {
+		delete(need, si.Name)
+	}
+	return len(need) == 0
+}
+
+// vectorID is the vector index ID of an extraction: the index is shared by
+// all dossiers, so the ID is derived from both.
+func vectorID(dossierID, extractionID string) string {
+	h := sha256.Sum256([]byte(dossierID + "\x00" + extractionID))
+	return hex.EncodeToString(h[:16])
+}
+
+// embedText is the text embedded for an extraction.
+func embedText(e *store.Extraction) string {
+	if e.Title == "" {
+		return e.ExtractedText
+	}
+	return e.Title + "\n" + e.ExtractedText
+}
+
+// embed returns the vector of text and the embedder's model name.
+func (svc *Service) embed(ctx context.Context, text string) ([]float32, string, error) {
+	payload, _ := json.Marshal(map[string]string{"text": text})
+	resp, err := svc.router.Call(ctx, embedService, payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("embed: %w", err)
+	}
+	var out struct {
+		Vector []float32 `json:"vector"`
+		Model  string    `json:"model"`
+	}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, "", fmt.Errorf("embed: decode: %w", err)
+	}
+	if len(out.Vector) == 0 {
+		return nil, "", fmt.Errorf("embed: empty vector")
+	}
+	return out.Vector, out.Model, nil
+}
+
+// indexExtraction embeds e, inserts it in the vector index tagged with its
+// dossier, and records the mapping in the shard. Set as the pipeline indexer.
+func (svc *Service) indexExtraction(ctx context.Context, s *store.Store, dossierID string, e *store.Extraction) error {
+	if e.ExtractedText == "" {
+		return nil
+	}
+	vec, model, err := svc.embed(ctx, embedText(e))
+	if err != nil {
+		return err
+	}
+	vid := vectorID(dossierID, e.ID)
+	payload, _ := json.Marshal(map[string]any{
+		"ids":      []string{vid},
+		"vectors":  [][]float32{vec},
+		"metadata": []map[string]string{{"dossier_id": dossierID}},
+	})
+	if _, err := svc.router.Call(ctx, vectorInsertService, payload); err != nil {
+		return fmt.Errorf("vector insert: %w", err)
+	}
+	return s.PutExtractionVector(ctx, e.ID, vid, model)
+}
+
+// Related returns the extractions of the dossier nearest to extractionID in
+// embedding space, closest first, at most topK (default 10, capped at 100).
+// The extraction itself is included when it is indexed. Only extractions
+// stored while the vector services were configured are indexed; tracked
+// question results are not. Returns ErrVectorsDisabled without the services
+// and ErrExtractionNotFound for an unknown extraction.
+func (svc *Service) Related(ctx context.Context, dossierID, extractionID string, topK int) ([]*RelatedExtraction, error) {
+	if !svc.vectors {
+		return nil, ErrVectorsDisabled
+	}
+	if topK <= 0 {
+		topK = defaultRelatedTopK
+	}
+	topK = min(topK, maxRelatedTopK)
+
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := st.GetExtraction(ctx, extractionID)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrExtractionNotFound
+	}
+	if err := store.HydrateContent(ctx, svc.content, []*store.Extraction{e}); err != nil {
+		return nil, err
+	}
+
+	vec, _, err := svc.embed(ctx, embedText(e))
+	if err != nil {
+		return nil, err
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"vector": vec,
+		"top_k":  topK,
+		"filter": map[string]string{"dossier_id": dossierID},
+	})
+	resp, err := svc.router.Call(ctx, vectorSearchService, payload)
+	if err != nil {
+		return nil, fmt.Errorf("vector search: %w", err)
+	}
+	var out struct {
+		Results []struct {
+			ID    string  `json:"id"`
+			Score float64 `json:"score"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return nil, fmt.Errorf("vector search: decode: %w", err)
+	}
+
+	related := make([]*RelatedExtraction, 0, len(out.Results))
+	for _, r := range out.Results {
+		id, err := st.ExtractionByVector(ctx, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			continue // another dossier's vector, or a deleted extraction
+		}
+		ext, err := st.GetExtraction(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if ext == nil {
+			continue
+		}
+		related = append(related, &RelatedExtraction{Extraction: ext, Score: r.Score})
+	}
+	if err := store.HydrateContent(ctx, svc.content, extractionsOf(related)); err != nil {
+		return nil, err
+	}
+	return related, nil
+}
+
+// extractionsOf returns the extractions of related, in order.
+func extractionsOf(related []*RelatedExtraction) []*store.Extraction {
+	exts := make([]*store.Extraction, len(related))
+	for i, r := range related {
+		exts[i] = r.Extraction
+	}
+	return exts
+}
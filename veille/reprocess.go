@@ -0,0 +1,33 @@
+// CLAUDE:SUMMARY Reprocess: re-runs web extraction on the raw body kept for an extraction and updates it in place.
+package veille
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hazyhaar/chrc/veille/internal/pipeline"
+)
+
+// ErrExtractionNotFound is returned by Reprocess for an unknown extraction.
+var ErrExtractionNotFound = pipeline.ErrExtractionNotFound
+
+// ErrNoRawBody is returned by Reprocess when no raw body is kept for the
+// extraction (Config.RetainRawBodies off, non-web source, or trimmed by
+// Config.RawBodiesPerSource).
+var ErrNoRawBody = pipeline.ErrNoRawBody
+
+// Reprocess re-runs the extract step on the raw body kept for extractionID
+// and updates the extraction in place (same ID): title, text, HTML, hash and
+// lang are replaced and the search index follows.
+func (svc *Service) Reprocess(ctx context.Context, dossierID, extractionID string) (*Extraction, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := svc.pipeline.Reprocess(ctx, st, extractionID)
+	if err != nil {
+		return nil, err
+	}
+	svc.auditLog(ctx, "reprocess_extraction", fmt.Sprintf(`{"dossier_id":%q,"extraction_id":%q}`, dossierID, extractionID))
+	return e, nil
+}
@@ -0,0 +1,247 @@
+// CLAUDE:SUMMARY Request types shared by the MCP tools and connectivity handlers (add_source, list_sources, search, search_all, list_extractions, stats, fetch_history, run_question, question_results): one decode/validate/run path per tool.
+package veille
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// toolRequest is a veille tool call decoded from JSON arguments, reachable
+// both as an MCP tool and as a connectivity service.
+type toolRequest interface {
+	// validate checks the fields the tool's input schema marks as required.
+	validate() error
+	run(ctx context.Context, svc *Service) (any, error)
+}
+
+// decodeToolRequest unmarshals data into req and validates it.
+func decodeToolRequest(data []byte, req toolRequest) error {
+	if err := json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return req.validate()
+}
+
+// callTool is the connectivity handler body for a toolRequest.
+func (svc *Service) callTool(ctx context.Context, payload []byte, req toolRequest) ([]byte, error) {
+	if err := decodeToolRequest(payload, req); err != nil {
+		return nil, err
+	}
+	out, err := req.run(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+func requireField(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("%w: %s is required", ErrInvalidInput, name)
+	}
+	return nil
+}
+
+type addSourceRequest struct {
+	DossierID string            `json:"dossier_id"`
+	Name      string            `json:"name"`
+	URL       string            `json:"url"`
+	Type      string            `json:"source_type"`
+	Interval  int64             `json:"fetch_interval"`
+	Tags      []string          `json:"tags"`
+	Headers   map[string]string `json:"headers"`
+	BasicAuth *BasicAuth        `json:"basic_auth"`
+}
+
+func (r *addSourceRequest) validate() error {
+	if err := requireField("dossier_id", r.DossierID); err != nil {
+		return err
+	}
+	if err := requireField("name", r.Name); err != nil {
+		return err
+	}
+	return requireField("url", r.URL)
+}
+
+func (r *addSourceRequest) run(ctx context.Context, svc *Service) (any, error) {
+	src := &Source{
+		Name:          r.Name,
+		URL:           r.URL,
+		SourceType:    r.Type,
+		FetchInterval: r.Interval,
+		Enabled:       true,
+		Tags:          r.Tags,
+		Headers:       r.Headers,
+		BasicAuth:     r.BasicAuth,
+	}
+	if err := svc.AddSource(ctx, r.DossierID, src); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+type listSourcesRequest struct {
+	DossierID string `json:"dossier_id"`
+	Tag       string `json:"tag"`
+}
+
+func (r *listSourcesRequest) validate() error {
+	return requireField("dossier_id", r.DossierID)
+}
+
+func (r *listSourcesRequest) run(ctx context.Context, svc *Service) (any, error) {
+	return svc.ListSources(ctx, r.DossierID, ListOpts{Tag: r.Tag})
+}
+
+type searchRequest struct {
+	DossierID string `json:"dossier_id"`
+	Query     string `json:"query"`
+	Limit     int    `json:"limit"`
+	Tag       string `json:"tag"`
+	Lang      string `json:"lang"`
+}
+
+func (r *searchRequest) validate() error {
+	if err := requireField("dossier_id", r.DossierID); err != nil {
+		return err
+	}
+	return requireField("query", r.Query)
+}
+
+func (r *searchRequest) run(ctx context.Context, svc *Service) (any, error) {
+	return svc.Search(ctx, r.DossierID, r.Query, r.Limit, ListOpts{Tag: r.Tag, Lang: r.Lang})
+}
+
+// searchAllRequest searches every dossier in the caller's scope. The scope
+// comes from the authenticated caller on ctx (WithSearchScope), never from
+// the arguments; a call without one fails with ErrNoSearchScope.
+type searchAllRequest struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+	Tag   string `json:"tag"`
+	Lang  string `json:"lang"`
+}
+
+func (r *searchAllRequest) validate() error {
+	return requireField("query", r.Query)
+}
+
+func (r *searchAllRequest) run(ctx context.Context, svc *Service) (any, error) {
+	scope, ok := searchScopeFrom(ctx)
+	if !ok {
+		return nil, ErrNoSearchScope
+	}
+	return svc.SearchAll(ctx, r.Query, r.Limit, scope, ListOpts{Tag: r.Tag, Lang: r.Lang})
+}
+
+// listExtractionsRequest pages through a source's extractions, newest first.
+// cursor is the next_cursor of the previous call ("" for the first page).
+type listExtractionsRequest struct {
+	DossierID string `json:"dossier_id"`
+	SourceID  string `json:"source_id"`
+	Limit     int    `json:"limit"`
+	Cursor    string `json:"cursor"`
+	Lang      string `json:"lang"`
+}
+
+func (r *listExtractionsRequest) validate() error {
+	return requireField("dossier_id", r.DossierID)
+}
+
+func (r *listExtractionsRequest) run(ctx context.Context, svc *Service) (any, error) {
+	exts, next, err := svc.ListExtractionsPage(ctx, r.DossierID, r.SourceID, r.Limit, r.Cursor, ListOpts{Lang: r.Lang})
+	if err != nil {
+		return nil, err
+	}
+	if exts == nil {
+		exts = []*Extraction{}
+	}
+	return map[string]any{"extractions": exts, "next_cursor": next}, nil
+}
+
+// statsRequest returns SpaceStats, the same JSON as HTTP
+// GET /api/dossiers/{dossierID}/stats.
+type statsRequest struct {
+	DossierID string `json:"dossier_id"`
+}
+
+func (r *statsRequest) validate() error {
+	return requireField("dossier_id", r.DossierID)
+}
+
+func (r *statsRequest) run(ctx context.Context, svc *Service) (any, error) {
+	return svc.Stats(ctx, r.DossierID)
+}
+
+// fetchHistoryRequest pages through a source's fetch log, newest first.
+type fetchHistoryRequest struct {
+	DossierID string `json:"dossier_id"`
+	SourceID  string `json:"source_id"`
+	Limit     int    `json:"limit"`
+	Cursor    string `json:"cursor"`
+}
+
+func (r *fetchHistoryRequest) validate() error {
+	if err := requireField("dossier_id", r.DossierID); err != nil {
+		return err
+	}
+	return requireField("source_id", r.SourceID)
+}
+
+func (r *fetchHistoryRequest) run(ctx context.Context, svc *Service) (any, error) {
+	entries, next, err := svc.FetchHistoryPage(ctx, r.DossierID, r.SourceID, r.Limit, r.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*FetchLogEntry{}
+	}
+	return map[string]any{"entries": entries, "next_cursor": next}, nil
+}
+
+type runQuestionRequest struct {
+	DossierID  string `json:"dossier_id"`
+	QuestionID string `json:"question_id"`
+}
+
+func (r *runQuestionRequest) validate() error {
+	if err := requireField("dossier_id", r.DossierID); err != nil {
+		return err
+	}
+	return requireField("question_id", r.QuestionID)
+}
+
+func (r *runQuestionRequest) run(ctx context.Context, svc *Service) (any, error) {
+	count, err := svc.RunQuestionNow(ctx, r.DossierID, r.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"status": "ok", "new_results": count}, nil
+}
+
+// questionResultsRequest pages through the extractions stored for a tracked
+// question (its results live under the question ID as source).
+type questionResultsRequest struct {
+	DossierID  string `json:"dossier_id"`
+	QuestionID string `json:"question_id"`
+	Limit      int    `json:"limit"`
+	Cursor     string `json:"cursor"`
+}
+
+func (r *questionResultsRequest) validate() error {
+	if err := requireField("dossier_id", r.DossierID); err != nil {
+		return err
+	}
+	return requireField("question_id", r.QuestionID)
+}
+
+func (r *questionResultsRequest) run(ctx context.Context, svc *Service) (any, error) {
+	exts, next, err := svc.ListExtractionsPage(ctx, r.DossierID, r.QuestionID, r.Limit, r.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if exts == nil {
+		exts = []*Extraction{}
+	}
+	return map[string]any{"results": exts, "next_cursor": next}, nil
+}
@@ -0,0 +1,87 @@
+// CLAUDE:SUMMARY Per-dossier extraction retention: policy (keep_days / max_rows) stored in the shard, enforced by the purger loop or on demand.
+package veille
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Retention policy bounds.
+const (
+	maxRetentionDays = 36_500
+	maxRetentionRows = 10_000_000
+)
+
+// SetRetention sets the dossier's extraction retention policy: extractions
+// older than keepDays and beyond the newest maxRows are pruned. A zero
+// field disables that limit; at least one must be set.
+func (svc *Service) SetRetention(ctx context.Context, dossierID string, keepDays, maxRows int) error {
+	if keepDays < 0 || keepDays > maxRetentionDays {
+		return fmt.Errorf("%w: keep_days must be between 0 and %d", ErrInvalidInput, maxRetentionDays)
+	}
+	if maxRows < 0 || maxRows > maxRetentionRows {
+		return fmt.Errorf("%w: max_rows must be between 0 and %d", ErrInvalidInput, maxRetentionRows)
+	}
+	if keepDays == 0 && maxRows == 0 {
+		return fmt.Errorf("%w: keep_days or max_rows required", ErrInvalidInput)
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	if err := st.SetRetentionPolicy(ctx, keepDays, maxRows); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "set_retention", fmt.Sprintf(`{"dossier_id":%q,"keep_days":%d,"max_rows":%d}`, dossierID, keepDays, maxRows))
+	return nil
+}
+
+// GetRetention returns the dossier's retention policy, or nil if it keeps
+// every extraction.
+func (svc *Service) GetRetention(ctx context.Context, dossierID string) (*RetentionPolicy, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	return st.GetRetentionPolicy(ctx)
+}
+
+// DeleteRetention removes the dossier's retention policy; extractions are
+// kept indefinitely again.
+func (svc *Service) DeleteRetention(ctx context.Context, dossierID string) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	if err := st.DeleteRetentionPolicy(ctx); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "delete_retention", fmt.Sprintf(`{"dossier_id":%q}`, dossierID))
+	return nil
+}
+
+// PruneExtractions deletes the dossier's unpinned extractions outside its
+// retention policy (pinned ones neither go nor count toward max_rows), oldest first, in batches of Config.PruneBatchSize, and releases
+// their shared content. Returns the deleted count; 0 without a policy.
+// The purger loop calls it every Config.PurgeInterval.
+func (svc *Service) PruneExtractions(ctx context.Context, dossierID string) (int64, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return 0, err
+	}
+	policy, err := st.GetRetentionPolicy(ctx)
+	if err != nil || policy == nil {
+		return 0, err
+	}
+	var cutoff int64
+	if policy.KeepDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -policy.KeepDays).UnixMilli()
+	}
+	n, refs, err := st.PruneExtractions(ctx, cutoff, policy.MaxRows, svc.config.PruneBatchSize)
+	svc.releaseContent(ctx, dossierID, refs)
+	if n > 0 {
+		svc.auditLog(ctx, "prune_extractions", fmt.Sprintf(`{"dossier_id":%q,"pruned":%d}`, dossierID, n))
+	}
+	return n, err
+}
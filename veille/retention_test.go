@@ -0,0 +1,188 @@
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// insertAgedExtractions adds one extraction per age (days before now) to src.
+func insertAgedExtractions(t *testing.T, st *store.Store, sourceID string, ages ...int) {
+	t.Helper()
+	for i, days := range ages {
+		err := st.InsertExtraction(context.Background(), &store.Extraction{
+			ID: fmt.Sprintf("ext-%02d", i), SourceID: sourceID, ContentHash: fmt.Sprintf("h%d", i),
+			ExtractedText: fmt.Sprintf("retention sample %d", i), URL: "https://r.example.com",
+			ExtractedAt: time.Now().AddDate(0, 0, -days).UnixMilli(),
+		})
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+}
+
+func remainingExtractionIDs(t *testing.T, st *store.Store) map[string]bool {
+	t.Helper()
+	rows, err := st.DB.Query(`SELECT id FROM extractions`)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+	ids := map[string]bool{}
+	for rows.Next() {
+		var id string
+		rows.Scan(&id)
+		ids[id] = true
+	}
+	return ids
+}
+
+func TestPruneExtractions_KeepDaysAndMaxRows(t *testing.T) {
+	// WHAT: keep_days removes only extractions older than the window (in several small batches, FTS included); max_rows then keeps only the newest N.
+	// WHY: Long-running dossiers must stay bounded without losing recent results.
+	svc, db := setupTestService(t)
+	svc.config.PruneBatchSize = 2
+	ctx := context.Background()
+	st := store.NewStore(db)
+
+	src := &Source{Name: "R", URL: "https://r.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	// ext-00..ext-06 aged 1, 5, 10, 40, 60, 90, 120 days.
+	insertAgedExtractions(t, st, src.ID, 1, 5, 10, 40, 60, 90, 120)
+
+	if n, err := svc.PruneExtractions(ctx, "d1"); err != nil || n != 0 {
+		t.Fatalf("prune without policy: n=%d err=%v, want 0", n, err)
+	}
+
+	if err := svc.SetRetention(ctx, "d1", 30, 0); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+	n, err := svc.PruneExtractions(ctx, "d1")
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if n != 4 {
+		t.Errorf("pruned %d, want 4", n)
+	}
+	ids := remainingExtractionIDs(t, st)
+	for _, id := range []string{"ext-00", "ext-01", "ext-02"} {
+		if !ids[id] {
+			t.Errorf("%s inside the window was pruned", id)
+		}
+	}
+	if len(ids) != 3 {
+		t.Errorf("remaining %v, want 3 extractions", ids)
+	}
+	var fts int
+	db.QueryRow(`SELECT COUNT(*) FROM extractions_fts WHERE extractions_fts MATCH 'retention'`).Scan(&fts)
+	if fts != 3 {
+		t.Errorf("fts rows: got %d, want 3", fts)
+	}
+
+	if err := svc.SetRetention(ctx, "d1", 30, 1); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+	if _, err := svc.PruneExtractions(ctx, "d1"); err != nil {
+		t.Fatalf("prune max_rows: %v", err)
+	}
+	if ids := remainingExtractionIDs(t, st); len(ids) != 1 || !ids["ext-00"] {
+		t.Errorf("after max_rows=1: %v, want only ext-00", ids)
+	}
+}
+
+func TestSetRetention_Validation(t *testing.T) {
+	// WHAT: An empty or out-of-range policy is ErrInvalidInput; DeleteRetention clears the policy.
+	// WHY: A policy with both limits at zero would silently do nothing.
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct{ days, rows int }{{0, 0}, {-1, 10}, {10, -1}, {maxRetentionDays + 1, 0}} {
+		if err := svc.SetRetention(ctx, "d1", tc.days, tc.rows); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("SetRetention(%d, %d): got %v, want ErrInvalidInput", tc.days, tc.rows, err)
+		}
+	}
+	if err := svc.SetRetention(ctx, "d1", 0, 100); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if p, _ := svc.GetRetention(ctx, "d1"); p == nil || p.MaxRows != 100 {
+		t.Errorf("get: %+v", p)
+	}
+	if err := svc.DeleteRetention(ctx, "d1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if p, _ := svc.GetRetention(ctx, "d1"); p != nil {
+		t.Errorf("after delete: %+v", p)
+	}
+}
+
+func TestSetPinned_RoundTripAndFilter(t *testing.T) {
+	// WHAT: SetPinned flips the flag seen by GetExtraction and ListOpts.Pinned; an unknown extraction is ErrExtractionNotFound.
+	// WHY: Users save important results and list them alone.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	st := store.NewStore(db)
+
+	src := &Source{Name: "P", URL: "https://p.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	insertAgedExtractions(t, st, src.ID, 1, 2, 3)
+
+	if err := svc.SetPinned(ctx, "d1", "ext-01", true); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if e, _ := st.GetExtraction(ctx, "ext-01"); e == nil || !e.Pinned {
+		t.Errorf("ext-01 not pinned: %+v", e)
+	}
+	pinned, _, err := svc.ListDossierExtractions(ctx, "d1", 10, "", ListOpts{Pinned: true})
+	if err != nil {
+		t.Fatalf("list pinned: %v", err)
+	}
+	if len(pinned) != 1 || pinned[0].ID != "ext-01" || !pinned[0].Pinned {
+		t.Errorf("pinned listing: got %d items, want only ext-01", len(pinned))
+	}
+
+	if err := svc.SetPinned(ctx, "d1", "ext-01", false); err != nil {
+		t.Fatalf("unpin: %v", err)
+	}
+	if pinned, _, _ := svc.ListDossierExtractions(ctx, "d1", 10, "", ListOpts{Pinned: true}); len(pinned) != 0 {
+		t.Errorf("after unpin: %d pinned", len(pinned))
+	}
+	if err := svc.SetPinned(ctx, "d1", "nope", true); !errors.Is(err, ErrExtractionNotFound) {
+		t.Errorf("unknown extraction: got %v, want ErrExtractionNotFound", err)
+	}
+}
+
+func TestPruneExtractions_KeepsPinned(t *testing.T) {
+	// WHAT: A pinned extraction outside both keep_days and max_rows survives pruning and does not count toward max_rows.
+	// WHY: Pinning exists so that retention never loses a saved result.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	st := store.NewStore(db)
+
+	src := &Source{Name: "K", URL: "https://k.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	// ext-00..ext-03 aged 1, 2, 100, 200 days; ext-03 pinned.
+	insertAgedExtractions(t, st, src.ID, 1, 2, 100, 200)
+	if err := svc.SetPinned(ctx, "d1", "ext-03", true); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if err := svc.SetRetention(ctx, "d1", 30, 1); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+	if _, err := svc.PruneExtractions(ctx, "d1"); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	ids := remainingExtractionIDs(t, st)
+	if len(ids) != 2 || !ids["ext-00"] || !ids["ext-03"] {
+		t.Errorf("remaining %v, want ext-00 (newest unpinned) and ext-03 (pinned)", ids)
+	}
+}
@@ -18,6 +18,7 @@ CREATE TABLE IF NOT EXISTS shards (
     endpoint   TEXT NOT NULL DEFAULT '',
     config     TEXT NOT NULL DEFAULT '{}',
     status     TEXT NOT NULL DEFAULT 'active',
+    owner_id   TEXT NOT NULL DEFAULT '',
     size_bytes INTEGER NOT NULL DEFAULT 0,
     created_at INTEGER NOT NULL,
     updated_at INTEGER NOT NULL
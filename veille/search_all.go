@@ -0,0 +1,145 @@
+// CLAUDE:SUMMARY Cross-dossier full-text search: FTS in each active shard visible to the caller (owner scoping as in the HTTP layer), merged by bm25 rank with the dossier ID attached.
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Cross-dossier search bounds.
+const (
+	maxSearchAllResults  = 200 // merged results returned
+	maxSearchAllDossiers = 100 // shards searched per call
+	searchAllPerDossier  = 50  // results taken from each shard
+)
+
+// SearchScope selects the dossiers SearchAll reaches, with the rules of the
+// HTTP dossier access check: an admin reaches every active dossier, anyone
+// else only the active dossiers they own (never those without an owner, so
+// an empty non-admin scope reaches nothing).
+type SearchScope struct {
+	OwnerID string
+	Admin   bool
+}
+
+// ErrNoSearchScope is returned by the veille_search_all tool when the call
+// carries no caller scope (WithSearchScope).
+var ErrNoSearchScope = errors.New("veille: search_all requires an authenticated caller")
+
+type searchScopeKey struct{}
+
+// WithSearchScope attaches the authenticated caller's scope to ctx. Only
+// trusted server code (the auth layer in front of the tool transports) may
+// set it: veille_search_all takes its scope from ctx, never from the tool
+// arguments.
+func WithSearchScope(ctx context.Context, scope SearchScope) context.Context {
+	return context.WithValue(ctx, searchScopeKey{}, scope)
+}
+
+// searchScopeFrom returns the caller scope set by WithSearchScope.
+func searchScopeFrom(ctx context.Context) (SearchScope, bool) {
+	scope, ok := ctx.Value(searchScopeKey{}).(SearchScope)
+	return scope, ok
+}
+
+// DossierSearchResult is a SearchResult tagged with its dossier.
+type DossierSearchResult struct {
+	DossierID string `json:"dossier_id"`
+	*SearchResult
+}
+
+// CrossSearchResult is the merged result of SearchAll.
+type CrossSearchResult struct {
+	Results   []*DossierSearchResult `json:"results"`
+	Dossiers  int                    `json:"dossiers"`  // dossiers searched
+	Truncated bool                   `json:"truncated"` // more than maxSearchAllDossiers in scope
+}
+
+// SearchAll runs the FTS query in every active dossier of scope and merges
+// the results by bm25 rank (best first), at most limit (default 20, max 200)
+// and searchAllPerDossier per dossier. bm25 is computed per shard, so ranks
+// from different dossiers are comparable only roughly. A dossier whose
+// search fails is skipped; the error is returned only if all of them fail
+// (e.g. FTS syntax). opts filter by tag and language as in Search.
+// Requires WithCatalogDB.
+func (svc *Service) SearchAll(ctx context.Context, query string, limit int, scope SearchScope, opts ...ListOpts) (*CrossSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidInput)
+	}
+	if len(query) > maxQueryLen {
+		return nil, fmt.Errorf("%w: query exceeds %d bytes", ErrInvalidInput, maxQueryLen)
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	limit = min(limit, maxSearchAllResults)
+	if svc.catalogDB == nil {
+		return nil, ErrNoCatalog
+	}
+
+	ids, err := svc.scopedShards(ctx, scope, maxSearchAllDossiers+1)
+	if err != nil {
+		return nil, fmt.Errorf("list dossiers: %w", err)
+	}
+	out := &CrossSearchResult{Results: []*DossierSearchResult{}}
+	if len(ids) > maxSearchAllDossiers {
+		ids, out.Truncated = ids[:maxSearchAllDossiers], true
+	}
+	out.Dossiers = len(ids)
+
+	var firstErr error
+	failed := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		results, err := svc.Search(ctx, id, query, min(limit, searchAllPerDossier), opts...)
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			svc.logger.Warn("veille: search all", "dossier_id", id, "error", err)
+			continue
+		}
+		for _, r := range results {
+			out.Results = append(out.Results, &DossierSearchResult{DossierID: id, SearchResult: r})
+		}
+	}
+	if len(ids) > 0 && failed == len(ids) {
+		return nil, firstErr
+	}
+	sort.SliceStable(out.Results, func(i, j int) bool { return out.Results[i].Rank < out.Results[j].Rank })
+	if len(out.Results) > limit {
+		out.Results = out.Results[:limit]
+	}
+	return out, nil
+}
+
+// scopedShards returns up to limit active shard IDs visible to scope, by ID.
+func (svc *Service) scopedShards(ctx context.Context, scope SearchScope, limit int) ([]string, error) {
+	query := `SELECT id FROM shards WHERE status = 'active' ORDER BY id LIMIT ?`
+	args := []any{limit}
+	if !scope.Admin {
+		query = `SELECT id FROM shards WHERE status = 'active' AND owner_id = ? AND owner_id != '' ORDER BY id LIMIT ?`
+		args = []any{scope.OwnerID, limit}
+	}
+	rows, err := svc.catalogDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,109 @@
+package veille
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+// newSearchAllService returns a service over three shards with one matching
+// extraction each: d-a and d-b owned by alice, d-other by bob.
+func newSearchAllService(t *testing.T) *Service {
+	t.Helper()
+	ctx := context.Background()
+	catalogDB := openCatalogDB(t)
+	pool := mapPool{"d-a": openShardDB(t), "d-b": openShardDB(t), "d-other": openShardDB(t)}
+	owners := map[string]string{"d-a": "alice", "d-b": "alice", "d-other": "bob"}
+	for id, db := range pool {
+		if err := ApplySchema(db); err != nil {
+			t.Fatalf("apply schema: %v", err)
+		}
+		insertShard(t, catalogDB, id, "active")
+		if _, err := catalogDB.Exec(`UPDATE shards SET owner_id = ? WHERE id = ?`, owners[id], id); err != nil {
+			t.Fatalf("set owner: %v", err)
+		}
+	}
+	svc, err := New(pool, nil, nil, WithCatalogDB(catalogDB))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	for id, db := range pool {
+		src := &Source{Name: "Src", URL: "https://" + id + ".example/", SourceType: "web", Enabled: true}
+		if err := svc.AddSource(ctx, id, src); err != nil {
+			t.Fatalf("%s: add source: %v", id, err)
+		}
+		e := &Extraction{
+			ID: "ext-" + id, SourceID: src.ID, ContentHash: "h-" + id, Title: "Report " + id,
+			ExtractedText: "Quarterly report on sovereign cloud adoption.", URL: src.URL,
+			ExtractedAt: time.Now().UnixMilli(),
+		}
+		if err := store.NewStore(db).InsertExtraction(ctx, e); err != nil {
+			t.Fatalf("%s: insert: %v", id, err)
+		}
+	}
+	return svc
+}
+
+func TestSearchAll_MergesScopedDossiers(t *testing.T) {
+	// WHAT: SearchAll merges FTS hits from every owned shard, tags each with its dossier and skips other owners' shards.
+	// WHY: veille_search_all must answer across dossiers without widening the HTTP ownership scope.
+	ctx := context.Background()
+	svc := newSearchAllService(t)
+
+	res, err := svc.SearchAll(ctx, "sovereign", 10, SearchScope{OwnerID: "alice"})
+	if err != nil {
+		t.Fatalf("search all: %v", err)
+	}
+	if res.Dossiers != 2 || len(res.Results) != 2 {
+		t.Fatalf("got %d dossiers, %d results, want 2 and 2", res.Dossiers, len(res.Results))
+	}
+	seen := map[string]bool{}
+	for _, r := range res.Results {
+		if r.ExtractionID != "ext-"+r.DossierID {
+			t.Errorf("result %s tagged with dossier %s", r.ExtractionID, r.DossierID)
+		}
+		seen[r.DossierID] = true
+	}
+	if !seen["d-a"] || !seen["d-b"] || seen["d-other"] {
+		t.Errorf("dossiers: got %v, want d-a and d-b only", seen)
+	}
+	if res.Results[0].Rank > res.Results[1].Rank {
+		t.Errorf("results not ordered by rank: %v > %v", res.Results[0].Rank, res.Results[1].Rank)
+	}
+
+	res, err = svc.SearchAll(ctx, "sovereign", 10, SearchScope{Admin: true})
+	if err != nil || len(res.Results) != 3 {
+		t.Fatalf("admin: got %v results, err %v, want 3", res, err)
+	}
+	res, err = svc.SearchAll(ctx, "sovereign", 10, SearchScope{})
+	if err != nil || len(res.Results) != 0 {
+		t.Fatalf("no owner: got %v, err %v, want no results", res, err)
+	}
+}
+
+func TestSearchAllTool_ScopeFromCaller(t *testing.T) {
+	// WHAT: The veille_search_all tool takes its scope from ctx; owner_id/role arguments cannot widen it, and a call without a scope is refused.
+	// WHY: The tool transports are reachable without HTTP auth, so a self-declared role or owner must not grant access.
+	ctx := context.Background()
+	svc := newSearchAllService(t)
+
+	out, err := svc.handleSearchAll(WithSearchScope(ctx, SearchScope{OwnerID: "bob"}),
+		[]byte(`{"query":"sovereign","role":"admin","owner_id":"alice"}`))
+	if err != nil {
+		t.Fatalf("tool: %v", err)
+	}
+	var tool CrossSearchResult
+	if err := json.Unmarshal(out, &tool); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(tool.Results) != 1 || tool.Results[0].DossierID != "d-other" {
+		t.Errorf("tool scope widened by arguments: got %d results", len(tool.Results))
+	}
+	if _, err := svc.handleSearchAll(ctx, []byte(`{"query":"sovereign","role":"admin"}`)); !errors.Is(err, ErrNoSearchScope) {
+		t.Errorf("no scope: got %v, want ErrNoSearchScope", err)
+	}
+}
@@ -0,0 +1,150 @@
+package veille
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestDeleteSource_RestoreKeepsExtractions(t *testing.T) {
+	// WHAT: A deleted source is hidden, then restored with its extractions intact.
+	// WHY: Accidental deletes must be recoverable without data loss.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+
+	src := &Source{Name: "Keep", URL: "https://keep.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	st := store.NewStore(db)
+	st.InsertExtraction(ctx, &store.Extraction{ID: "ext-k", SourceID: src.ID, ContentHash: "h", ExtractedText: "kept content", URL: src.URL, ExtractedAt: time.Now().UnixMilli()})
+
+	if err := svc.DeleteSource(ctx, "d1", src.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	sources, _ := svc.ListSources(ctx, "d1")
+	if len(sources) != 0 {
+		t.Fatalf("deleted source still listed: %d", len(sources))
+	}
+	due, _ := st.DueSources(ctx, 10)
+	if len(due) != 0 {
+		t.Errorf("deleted source still due: %d", len(due))
+	}
+	deleted, _ := svc.ListDeletedSources(ctx, "d1")
+	if len(deleted) != 1 || deleted[0].DeletedAt == nil {
+		t.Fatalf("deleted list: got %d", len(deleted))
+	}
+
+	// Re-adding the same URL is refused while the deleted source exists.
+	err := svc.AddSource(ctx, "d1", &Source{Name: "Again", URL: "https://keep.example.com", Enabled: true})
+	if !errors.Is(err, ErrDuplicateSource) {
+		t.Errorf("re-add: expected ErrDuplicateSource, got %v", err)
+	}
+
+	if err := svc.RestoreSource(ctx, "d1", src.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	sources, _ = svc.ListSources(ctx, "d1")
+	if len(sources) != 1 || sources[0].DeletedAt != nil {
+		t.Fatalf("restored source not listed: %d", len(sources))
+	}
+	exts, _ := svc.ListExtractions(ctx, "d1", src.ID, 10)
+	if len(exts) != 1 || exts[0].ExtractedText != "kept content" {
+		t.Errorf("extractions after restore: got %d", len(exts))
+	}
+}
+
+func TestPurgeDeletedSources_RemovesContent(t *testing.T) {
+	// WHAT: Sources deleted longer than the retention window are purged with their extractions.
+	// WHY: Soft-delete must not retain data forever.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+
+	old := &Source{Name: "Old", URL: "https://old.example.com", Enabled: true}
+	recent := &Source{Name: "Recent", URL: "https://recent.example.com", Enabled: true}
+	svc.AddSource(ctx, "d1", old)
+	svc.AddSource(ctx, "d1", recent)
+	st := store.NewStore(db)
+	now := time.Now().UnixMilli()
+	st.InsertExtraction(ctx, &store.Extraction{ID: "ext-o", SourceID: old.ID, ContentHash: "h1", ExtractedText: "old", URL: old.URL, ExtractedAt: now})
+	st.InsertExtraction(ctx, &store.Extraction{ID: "ext-r", SourceID: recent.ID, ContentHash: "h2", ExtractedText: "recent", URL: recent.URL, ExtractedAt: now})
+
+	svc.DeleteSource(ctx, "d1", old.ID)
+	svc.DeleteSource(ctx, "d1", recent.ID)
+	// Age the first deletion past the retention window.
+	expired := time.Now().Add(-svc.config.SourceRetention - time.Hour).UnixMilli()
+	db.Exec(`UPDATE sources SET deleted_at = ? WHERE id = ?`, expired, old.ID)
+
+	n, err := svc.PurgeDeletedSources(ctx, "d1")
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("purged: got %d, want 1", n)
+	}
+	if got, _ := st.GetSource(ctx, old.ID); got != nil {
+		t.Error("expired source should be gone")
+	}
+	if got, _ := st.GetExtraction(ctx, "ext-o"); got != nil {
+		t.Error("extractions of purged source should be gone")
+	}
+	if got, _ := st.GetSource(ctx, recent.ID); got == nil || got.DeletedAt == nil {
+		t.Error("recently deleted source should still be restorable")
+	}
+}
+
+func TestPurgeSource_HardDeletesImmediately(t *testing.T) {
+	// WHAT: PurgeSource removes a live source and its extractions at once; it is neither listed nor restorable and its URL is free again.
+	// WHY: The explicit ?purge=true path must really erase, unlike the default soft delete.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+
+	src := &Source{Name: "Gone", URL: "https://gone.example.com", Enabled: true}
+	if err := svc.AddSource(ctx, "d1", src); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	store.NewStore(db).InsertExtraction(ctx, &store.Extraction{ID: "ext-g", SourceID: src.ID, ContentHash: "h", ExtractedText: "gone", URL: src.URL, ExtractedAt: time.Now().UnixMilli()})
+
+	if err := svc.PurgeSource(ctx, "d1", src.ID); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	var n int
+	db.QueryRow(`SELECT COUNT(*) FROM extractions WHERE source_id = ?`, src.ID).Scan(&n)
+	if n != 0 {
+		t.Errorf("extractions after purge: %d", n)
+	}
+	if deleted, _ := svc.ListDeletedSources(ctx, "d1"); len(deleted) != 0 {
+		t.Errorf("purged source listed as deleted: %d", len(deleted))
+	}
+	if err := svc.RestoreSource(ctx, "d1", src.ID); !errors.Is(err, ErrSourceNotFound) {
+		t.Errorf("restore after purge: got %v, want ErrSourceNotFound", err)
+	}
+	if err := svc.AddSource(ctx, "d1", &Source{Name: "Again", URL: "https://gone.example.com", Enabled: true}); err != nil {
+		t.Errorf("re-add after purge: %v", err)
+	}
+}
+
+func TestSourceNotFound_Typed(t *testing.T) {
+	// WHAT: Unknown source IDs fail with ErrSourceNotFound in update, delete, purge, restore and fetch-now.
+	// WHY: The HTTP layer maps the typed error to 404 instead of a 500.
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	errs := map[string]error{
+		"update":    svc.UpdateSource(ctx, "d1", &Source{ID: "nope", Name: "x", URL: "https://x.example"}),
+		"delete":    svc.DeleteSource(ctx, "d1", "nope"),
+		"purge":     svc.PurgeSource(ctx, "d1", "nope"),
+		"restore":   svc.RestoreSource(ctx, "d1", "nope"),
+		"fetch_now": svc.FetchNow(ctx, "d1", "nope"),
+	}
+	for op, err := range errs {
+		if !errors.Is(err, ErrSourceNotFound) {
+			t.Errorf("%s: got %v, want ErrSourceNotFound", op, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// CLAUDE:SUMMARY Dossier templates: export source and question definitions (no data) and apply them to another dossier.
+package veille
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// TemplateVersion is the format version written by ExportTemplate.
+const TemplateVersion = 1
+
+// Template holds the source and question definitions of a dossier,
+// without extractions, fetch state or credentials.
+type Template struct {
+	Version   int                `json:"version"`
+	Sources   []TemplateSource   `json:"sources"`
+	Questions []TemplateQuestion `json:"questions"`
+}
+
+// TemplateSource is the reusable part of a Source.
+type TemplateSource struct {
+	Name          string   `json:"name"`
+	URL           string   `json:"url"`
+	SourceType    string   `json:"source_type"`
+	FetchInterval int64    `json:"fetch_interval"`
+	Enabled       bool     `json:"enabled"`
+	ConfigJSON    string   `json:"config_json,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// TemplateQuestion is the reusable part of a TrackedQuestion.
+type TemplateQuestion struct {
+	Text             string `json:"text"`
+	Keywords         string `json:"keywords"`
+	Channels         string `json:"channels"`
+	ScheduleMs       int64  `json:"schedule_ms"`
+	MaxResults       int    `json:"max_results"`
+	FollowLinks      bool   `json:"follow_links"`
+	FollowDepth      int    `json:"follow_depth,omitempty"`
+	Enabled          bool   `json:"enabled"`
+	NotifyMinResults int    `json:"notify_min_results"`
+	NotifyPattern    string `json:"notify_pattern,omitempty"`
+}
+
+// TemplateResult reports what ApplyTemplate did.
+type TemplateResult struct {
+	SourcesAdded     int             `json:"sources_added"`
+	SourcesSkipped   int             `json:"sources_skipped"` // URL already in the dossier
+	QuestionsAdded   int             `json:"questions_added"`
+	QuestionsSkipped int             `json:"questions_skipped"` // same text already tracked
+	Failed           []TemplateError `json:"failed,omitempty"`
+}
+
+// TemplateError is a template entry ApplyTemplate rejected.
+type TemplateError struct {
+	Kind  string `json:"kind"` // "source" or "question"
+	Name  string `json:"name"` // source URL or question text
+	Error string `json:"error"`
+}
+
+// templateConfigDrop lists config_json keys never exported: credentials,
+// deployment-specific proxies and auto-repair state.
+var templateConfigDrop = []string{"headers", "basic_auth", "proxy", "user_agent", "tried_uas"}
+
+// ExportTemplate returns the source and question definitions of a dossier.
+// Question auto-sources are left out (ApplyTemplate recreates them with the
+// question), as are soft-deleted sources and fetch credentials.
+func (svc *Service) ExportTemplate(ctx context.Context, dossierID string) (*Template, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := st.ListSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := st.ListQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &Template{Version: TemplateVersion, Sources: []TemplateSource{}, Questions: []TemplateQuestion{}}
+	// ListSources is newest first; export in creation order.
+	for i := len(sources) - 1; i >= 0; i-- {
+		s := sources[i]
+		if s.SourceType == "question" {
+			continue
+		}
+		tmpl.Sources = append(tmpl.Sources, TemplateSource{
+			Name:          s.Name,
+			URL:           s.URL,
+			SourceType:    s.SourceType,
+			FetchInterval: s.FetchInterval,
+			Enabled:       s.Enabled,
+			ConfigJSON:    templateConfig(s.ConfigJSON),
+			Tags:          s.Tags,
+		})
+	}
+	for _, q := range questions {
+		tmpl.Questions = append(tmpl.Questions, TemplateQuestion{
+			Text:             q.Text,
+			Keywords:         q.Keywords,
+			Channels:         q.Channels,
+			ScheduleMs:       q.ScheduleMs,
+			MaxResults:       q.MaxResults,
+			FollowLinks:      q.FollowLinks,
+			FollowDepth:      q.FollowDepth,
+			Enabled:          q.Enabled,
+			NotifyMinResults: q.NotifyMinResults,
+			NotifyPattern:    q.NotifyPattern,
+		})
+	}
+	return tmpl, nil
+}
+
+// ApplyTemplate adds the template's sources and questions to a dossier.
+// Each source goes through AddSource (validation, URL normalization, SSRF
+// checks, quota). Sources whose URL is already present and questions whose
+// text is already tracked are skipped; other rejected entries are reported
+// in Failed without stopping the rest.
+func (svc *Service) ApplyTemplate(ctx context.Context, dossierID string, tmpl *Template) (*TemplateResult, error) {
+	if tmpl == nil {
+		return nil, fmt.Errorf("%w: template is required", ErrInvalidInput)
+	}
+	if tmpl.Version > TemplateVersion {
+		return nil, fmt.Errorf("%w: template version %d is newer than supported %d", ErrInvalidInput, tmpl.Version, TemplateVersion)
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TemplateResult{}
+	for _, ts := range tmpl.Sources {
+		src := &Source{
+			Name:          ts.Name,
+			URL:           ts.URL,
+			SourceType:    ts.SourceType,
+			FetchInterval: ts.FetchInterval,
+			Enabled:       ts.Enabled,
+			ConfigJSON:    ts.ConfigJSON,
+			Tags:          ts.Tags,
+		}
+		err := svc.AddSource(ctx, dossierID, src)
+		switch {
+		case err == nil:
+			res.SourcesAdded++
+		case errors.Is(err, ErrDuplicateSource):
+			res.SourcesSkipped++
+		default:
+			res.Failed = append(res.Failed, TemplateError{Kind: "source", Name: ts.URL, Error: err.Error()})
+		}
+	}
+
+	existing, err := st.ListQuestions(ctx)
+	if err != nil {
+		return res, err
+	}
+	tracked := make(map[string]bool, len(existing))
+	for _, q := range existing {
+		tracked[q.Text] = true
+	}
+	for _, tq := range tmpl.Questions {
+		if tracked[tq.Text] {
+			res.QuestionsSkipped++
+			continue
+		}
+		q := &TrackedQuestion{
+			Text:             tq.Text,
+			Keywords:         tq.Keywords,
+			Channels:         tq.Channels,
+			ScheduleMs:       tq.ScheduleMs,
+			MaxResults:       tq.MaxResults,
+			FollowLinks:      tq.FollowLinks,
+			FollowDepth:      tq.FollowDepth,
+			Enabled:          tq.Enabled,
+			NotifyMinResults: tq.NotifyMinResults,
+			NotifyPattern:    tq.NotifyPattern,
+		}
+		if err := svc.AddQuestion(ctx, dossierID, q); err != nil {
+			res.Failed = append(res.Failed, TemplateError{Kind: "question", Name: tq.Text, Error: err.Error()})
+			continue
+		}
+		tracked[tq.Text] = true
+		res.QuestionsAdded++
+	}
+
+	svc.auditLog(ctx, "apply_template", fmt.Sprintf(`{"dossier_id":%q,"sources_added":%d,"questions_added":%d,"failed":%d}`,
+		dossierID, res.SourcesAdded, res.QuestionsAdded, len(res.Failed)))
+	return res, nil
+}
+
+// templateConfig strips credentials and repair state from config_json.
+func templateConfig(configJSON string) string {
+	var cfg map[string]any
+	if configJSON == "" || json.Unmarshal([]byte(configJSON), &cfg) != nil {
+		return ""
+	}
+	for _, k := range templateConfigDrop {
+		delete(cfg, k)
+	}
+	if len(cfg) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
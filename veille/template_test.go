@@ -0,0 +1,97 @@
+package veille
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTemplate_RoundTrip(t *testing.T) {
+	// WHAT: A dossier built from another dossier's template has the same source and question definitions; re-applying skips everything.
+	// WHY: Teams clone dossier setups; duplicates and credentials must not be carried over.
+	ctx := context.Background()
+	pool := mapPool{"src": openShardDB(t), "dst": openShardDB(t)}
+	for _, db := range pool {
+		if err := ApplySchema(db); err != nil {
+			t.Fatalf("apply schema: %v", err)
+		}
+	}
+	svc, err := New(pool, nil, nil)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	sources := []*Source{
+		{Name: "Wire", URL: "https://wire.example/feed", SourceType: "rss", FetchInterval: 1800000, Enabled: true,
+			Tags: []string{"news"}, ConfigJSON: `{"title_field":"description"}`},
+		{Name: "API", URL: "https://api.example/items", SourceType: "web", FetchInterval: 3600000, Enabled: false,
+			Headers: map[string]string{"X-Api-Key": "k-123"}},
+	}
+	for _, s := range sources {
+		if err := svc.AddSource(ctx, "src", s); err != nil {
+			t.Fatalf("add source: %v", err)
+		}
+	}
+	if err := svc.AddQuestion(ctx, "src", &TrackedQuestion{
+		Text: "sovereign cloud tenders", Keywords: "cloud", Channels: `["brave"]`,
+		ScheduleMs: 86400000, MaxResults: 10, Enabled: true, NotifyMinResults: 3,
+	}); err != nil {
+		t.Fatalf("add question: %v", err)
+	}
+
+	tmpl, err := svc.ExportTemplate(ctx, "src")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if len(tmpl.Sources) != 2 || len(tmpl.Questions) != 1 {
+		t.Fatalf("template: %d sources, %d questions; want 2 and 1 (question auto-source excluded)", len(tmpl.Sources), len(tmpl.Questions))
+	}
+	if strings.Contains(tmpl.Sources[1].ConfigJSON, "k-123") {
+		t.Error("template must not carry fetch credentials")
+	}
+
+	res, err := svc.ApplyTemplate(ctx, "dst", tmpl)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if res.SourcesAdded != 2 || res.QuestionsAdded != 1 || len(res.Failed) != 0 {
+		t.Fatalf("apply result: %+v", res)
+	}
+
+	again, _ := svc.ExportTemplate(ctx, "dst")
+	for i, want := range tmpl.Sources {
+		got := again.Sources[i]
+		if got.Name != want.Name || got.URL != want.URL || got.SourceType != want.SourceType ||
+			got.FetchInterval != want.FetchInterval || got.Enabled != want.Enabled ||
+			got.ConfigJSON != want.ConfigJSON || strings.Join(got.Tags, ",") != strings.Join(want.Tags, ",") {
+			t.Errorf("source %d: got %+v, want %+v", i, got, want)
+		}
+	}
+	if again.Questions[0] != tmpl.Questions[0] {
+		t.Errorf("question: got %+v, want %+v", again.Questions[0], tmpl.Questions[0])
+	}
+
+	res, err = svc.ApplyTemplate(ctx, "dst", tmpl)
+	if err != nil {
+		t.Fatalf("re-apply: %v", err)
+	}
+	if res.SourcesAdded != 0 || res.SourcesSkipped != 2 || res.QuestionsAdded != 0 || res.QuestionsSkipped != 1 {
+		t.Errorf("re-apply result: %+v", res)
+	}
+}
+
+func TestApplyTemplate_RevalidatesURLs(t *testing.T) {
+	// WHAT: Template sources go through SSRF validation; rejected entries are reported, the rest applied.
+	// WHY: A template is user-supplied input like any other source.
+	svc, _ := setupTestService(t)
+	res, err := svc.ApplyTemplate(context.Background(), "d1", &Template{Version: TemplateVersion, Sources: []TemplateSource{
+		{Name: "internal", URL: "http://127.0.0.1/admin", SourceType: "web", FetchInterval: 3600000},
+		{Name: "ok", URL: "https://ok.example", SourceType: "web", FetchInterval: 3600000},
+	}})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if res.SourcesAdded != 1 || len(res.Failed) != 1 || res.Failed[0].Name != "http://127.0.0.1/admin" {
+		t.Errorf("result: %+v", res)
+	}
+}
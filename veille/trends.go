@@ -0,0 +1,83 @@
+// CLAUDE:SUMMARY Trends: recurring terms and bigrams over a dossier's recent extractions (bounded SQL read, content-hash dedup, in-Go ranking).
+package veille
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+	"github.com/hazyhaar/chrc/veille/internal/trends"
+)
+
+// Trend bounds.
+const (
+	DefaultTrendWindow = 7 * 24 * time.Hour
+	defaultTrendLimit  = 20
+	maxTrendLimit      = 100
+	trendMaxRows       = 2000 // most recent extractions analysed
+	trendMaxRunes      = 4000 // text analysed per extraction
+)
+
+// TrendTopic is a recurring term or bigram: how many extractions contain
+// it and the most representative of them.
+type TrendTopic = trends.Topic
+
+// TrendOpts selects the extractions and topics of Trends.
+type TrendOpts struct {
+	Window   time.Duration // extractions of the last Window; 0 = DefaultTrendWindow
+	Limit    int           // topics returned; 0 = 20, max 100
+	MinCount int           // minimum extractions per topic; 0 = 2
+}
+
+// TrendReport is the result of Trends.
+type TrendReport struct {
+	Since       int64        `json:"since"`       // window start (ms)
+	Extractions int          `json:"extractions"` // distinct extractions analysed
+	Topics      []TrendTopic `json:"topics"`
+}
+
+// Trends ranks the terms and bigrams recurring across the dossier's
+// extractions of the window, French and English stopwords removed. Each
+// extraction counts once per term and extractions with the same content
+// hash count once. Only the newest 2000 extractions are analysed.
+func (svc *Service) Trends(ctx context.Context, dossierID string, opts TrendOpts) (*TrendReport, error) {
+	if opts.Window < 0 || opts.Limit < 0 || opts.MinCount < 0 {
+		return nil, fmt.Errorf("%w: negative trend option", ErrInvalidInput)
+	}
+	if opts.Window == 0 {
+		opts.Window = DefaultTrendWindow
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultTrendLimit
+	}
+	opts.Limit = min(opts.Limit, maxTrendLimit)
+
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().Add(-opts.Window).UnixMilli()
+	rows, err := st.RecentExtractionTexts(ctx, since, trendMaxRows, trendMaxRunes)
+	if err != nil {
+		return nil, fmt.Errorf("recent extractions: %w", err)
+	}
+	if err := store.HydrateExtractionTexts(ctx, svc.content, rows, trendMaxRunes); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(rows))
+	docs := make([]trends.Doc, 0, len(rows))
+	for _, r := range rows {
+		if seen[r.ContentHash] {
+			continue
+		}
+		seen[r.ContentHash] = true
+		docs = append(docs, trends.Doc{ID: r.ID, Title: r.Title, Text: r.Text})
+	}
+	topics := trends.Rank(docs, trends.Options{Limit: opts.Limit, MinCount: opts.MinCount})
+	if topics == nil {
+		topics = []TrendTopic{}
+	}
+	return &TrendReport{Since: since, Extractions: len(docs), Topics: topics}, nil
+}
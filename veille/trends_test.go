@@ -0,0 +1,60 @@
+package veille
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/chrc/veille/internal/store"
+)
+
+func TestTrends_DominantTermRanksFirst(t *testing.T) {
+	// WHAT: A term recurring across recent extractions ranks first; old rows and content-hash duplicates are ignored.
+	// WHY: Analysts read the top of the list; mirrored copies and stale rows must not inflate a theme.
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+	st := store.NewStore(db)
+	st.InsertSource(ctx, &store.Source{ID: "src-a", Name: "A", URL: "https://a.example", SourceType: "web", Enabled: true})
+
+	now := time.Now().UnixMilli()
+	texts := []string{
+		"La sécheresse menace les récoltes de blé.",
+		"Nouvelle alerte sécheresse dans le sud.",
+		"Sécheresse : les nappes phréatiques au plus bas.",
+		"Les récoltes de maïs en hausse.",
+		"Un concert gratuit samedi.",
+	}
+	for i, text := range texts {
+		st.InsertExtraction(ctx, &store.Extraction{
+			ID: fmt.Sprintf("ext-%d", i), SourceID: "src-a", ContentHash: fmt.Sprintf("h%d", i),
+			ExtractedText: text, URL: "https://a.example", ExtractedAt: now - int64(i)*1000,
+		})
+	}
+	// Mirror of ext-4 (same hash) and an old article outside the window.
+	st.InsertExtraction(ctx, &store.Extraction{ID: "mirror", SourceID: "src-a", ContentHash: "h4",
+		ExtractedText: "Un concert gratuit samedi.", URL: "https://a.example/m", ExtractedAt: now})
+	st.InsertExtraction(ctx, &store.Extraction{ID: "old", SourceID: "src-a", ContentHash: "h-old",
+		ExtractedText: "Concert annulé.", URL: "https://a.example/o", ExtractedAt: now - int64(30*24*time.Hour/time.Millisecond)})
+
+	rep, err := svc.Trends(ctx, "d1", TrendOpts{})
+	if err != nil {
+		t.Fatalf("trends: %v", err)
+	}
+	if rep.Extractions != 5 {
+		t.Errorf("extractions analysed: got %d, want 5", rep.Extractions)
+	}
+	if len(rep.Topics) == 0 || rep.Topics[0].Term != "sécheresse" || rep.Topics[0].Count != 3 {
+		t.Fatalf("top topic: got %+v, want sécheresse x3", rep.Topics)
+	}
+	for _, tp := range rep.Topics {
+		if tp.Term == "concert" {
+			t.Errorf("concert counted from a duplicate or an old row: %+v", tp)
+		}
+	}
+
+	if _, err := svc.Trends(ctx, "d1", TrendOpts{Window: -time.Hour}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("negative window: got %v, want ErrInvalidInput", err)
+	}
+}
@@ -8,6 +8,8 @@
 package veille
 
 import (
+	"github.com/hazyhaar/chrc/veille/internal/pipeline"
+	"github.com/hazyhaar/chrc/veille/internal/question"
 	"github.com/hazyhaar/chrc/veille/internal/repair"
 	"github.com/hazyhaar/chrc/veille/internal/store"
 )
@@ -15,6 +17,7 @@ import (
 // Re-export store types for public API.
 type (
 	Source          = store.Source
+	BasicAuth       = store.BasicAuth
 	Extraction      = store.Extraction
 	FetchLogEntry   = store.FetchLogEntry
 	SearchResult    = store.SearchResult
@@ -23,4 +26,19 @@ type (
 	SearchEngine    = store.SearchEngine
 	SearchLogEntry  = store.SearchLogEntry
 	SweepResult     = repair.SweepResult
+
+	DossierExtraction = store.DossierExtraction
+	HealthWebhook     = store.HealthWebhook
+	OpenedExtraction  = store.OpenedExtraction
+	RetentionPolicy   = store.RetentionPolicy
+	ActivityEvent     = store.ActivityEvent
+
+	QuestionNotification = question.Notification
+
+	PostProcessor     = pipeline.PostProcessor
+	PostProcessorFunc = pipeline.PostProcessorFunc
+
+	Extractor     = pipeline.Extractor
+	ExtractorFunc = pipeline.ExtractorFunc
+	HTMLExtractor = pipeline.HTMLExtractor
 )
@@ -1,20 +1,37 @@
-// CLAUDE:SUMMARY Input validation for source fields: name, URL, source_type, fetch_interval, config_json.
+// CLAUDE:SUMMARY Input validation for source fields: name, URL, source_type, fetch_interval, tags, headers, basic_auth, config_json (rss title_field, sitemap max_urls, api JSONPath, web follow_depth, proxy, hash_ignore), question follow_depth and notification rules.
 // CLAUDE:EXPORTS validateSourceInput, MaxSourcesPerSpace, allowedSourceTypes
 package veille
 
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+
+	"github.com/hazyhaar/chrc/veille/internal/apifetch"
+	"github.com/hazyhaar/chrc/veille/internal/crawl"
+	"github.com/hazyhaar/chrc/veille/internal/fetch"
+	"github.com/hazyhaar/chrc/veille/internal/pipeline"
 )
 
 const (
 	maxNameLen     = 512
 	maxURLLen      = 4096
 	maxConfigLen   = 8192
+	maxPatternLen  = 512
+	maxQueryLen    = 1024
+	maxTagLen      = 64
+	maxTags        = 20
+	maxHeaders     = 20
+	maxHeaderLen   = 4096
 	minFetchMs     = 60_000      // 1 minute
 	maxFetchMs     = 604_800_000 // 7 days
 
-	// MaxSourcesPerSpace is the maximum number of sources per space.
+	// MaxSourcesPerSpace is the default maximum number of sources per space,
+	// overridable per dossier with SetSourceQuota.
 	MaxSourcesPerSpace = 1000
 )
 
@@ -22,6 +39,7 @@ const (
 var allowedSourceTypes = map[string]bool{
 	"web":      true,
 	"rss":      true,
+	"sitemap":  true,
 	"api":      true,
 	"document": true,
 	"question": true,
@@ -56,6 +74,14 @@ func validateSourceInput(s *Source, knownTypes ...map[string]bool) error {
 		return fmt.Errorf("%w: fetch_interval must be between %d and %d ms", ErrInvalidInput, minFetchMs, maxFetchMs)
 	}
 
+	if err := normalizeTags(s); err != nil {
+		return err
+	}
+
+	if err := validateFetchAuth(s); err != nil {
+		return err
+	}
+
 	if s.ConfigJSON != "" && s.ConfigJSON != "{}" {
 		if len(s.ConfigJSON) > maxConfigLen {
 			return fmt.Errorf("%w: config_json exceeds %d bytes", ErrInvalidInput, maxConfigLen)
@@ -63,7 +89,163 @@ func validateSourceInput(s *Source, knownTypes ...map[string]bool) error {
 		if !json.Valid([]byte(s.ConfigJSON)) {
 			return fmt.Errorf("%w: config_json is not valid JSON", ErrInvalidInput)
 		}
+		if proxy := configProxy(s.ConfigJSON); proxy != "" {
+			if _, err := fetch.ParseProxyURL(proxy); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+			}
+		}
+		if err := validateHashIgnore(s.ConfigJSON); err != nil {
+			return err
+		}
+		if s.SourceType == "rss" {
+			var cfg pipeline.RSSConfig
+			if err := json.Unmarshal([]byte(s.ConfigJSON), &cfg); err != nil {
+				return fmt.Errorf("%w: rss config_json: %v", ErrInvalidInput, err)
+			}
+			if cfg.TitleField != "" && !pipeline.ValidRSSTitleField(cfg.TitleField) {
+				return fmt.Errorf("%w: unknown title_field %q", ErrInvalidInput, cfg.TitleField)
+			}
+		}
+		if s.SourceType == "web" || s.SourceType == "" {
+			var cfg pipeline.WebConfig
+			if err := json.Unmarshal([]byte(s.ConfigJSON), &cfg); err != nil {
+				return fmt.Errorf("%w: web config_json: %v", ErrInvalidInput, err)
+			}
+			if err := validateFollowDepth(cfg.FollowDepth); err != nil {
+				return err
+			}
+		}
+		if s.SourceType == "sitemap" {
+			var cfg pipeline.SitemapConfig
+			if err := json.Unmarshal([]byte(s.ConfigJSON), &cfg); err != nil {
+				return fmt.Errorf("%w: sitemap config_json: %v", ErrInvalidInput, err)
+			}
+			if cfg.MaxURLs < 0 || cfg.MaxURLs > pipeline.MaxSitemapURLs {
+				return fmt.Errorf("%w: max_urls must be between 0 and %d", ErrInvalidInput, pipeline.MaxSitemapURLs)
+			}
+		}
+		if s.SourceType == "api" {
+			var cfg apifetch.Config
+			if err := json.Unmarshal([]byte(s.ConfigJSON), &cfg); err != nil {
+				return fmt.Errorf("%w: api config_json: %v", ErrInvalidInput, err)
+			}
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// configProxy returns the per-source proxy set in config_json, if any.
+func configProxy(configJSON string) string {
+	var cfg struct {
+		Proxy string `json:"proxy"`
+	}
+	_ = json.Unmarshal([]byte(configJSON), &cfg)
+	return cfg.Proxy
+}
+
+// validateHashIgnore checks the hash_ignore patterns of config_json.
+func validateHashIgnore(configJSON string) error {
+	var cfg struct {
+		HashIgnore []string `json:"hash_ignore"`
+	}
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		return fmt.Errorf("%w: config_json: %v", ErrInvalidInput, err)
+	}
+	for _, p := range cfg.HashIgnore {
+		if len(p) > maxPatternLen {
+			return fmt.Errorf("%w: hash_ignore pattern exceeds %d characters", ErrInvalidInput, maxPatternLen)
+		}
 	}
+	if _, err := fetch.CompileHashIgnore(cfg.HashIgnore); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// normalizeTag trims and lower-cases a tag so filters match regardless of input case.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
 
+// normalizeTags normalizes s.Tags in place, drops empty and duplicate tags,
+// and enforces the count and length limits.
+func normalizeTags(s *Source) error {
+	if len(s.Tags) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(s.Tags))
+	for _, t := range s.Tags {
+		t = normalizeTag(t)
+		if t == "" || slices.Contains(tags, t) {
+			continue
+		}
+		if len(t) > maxTagLen {
+			return fmt.Errorf("%w: tag exceeds %d characters", ErrInvalidInput, maxTagLen)
+		}
+		tags = append(tags, t)
+	}
+	if len(tags) > maxTags {
+		return fmt.Errorf("%w: at most %d tags per source", ErrInvalidInput, maxTags)
+	}
+	s.Tags = tags
+	return nil
+}
+
+// validateFetchAuth checks per-source headers and basic-auth credentials.
+// Reserved headers (Host, ...) are accepted but ignored by the fetcher.
+func validateFetchAuth(s *Source) error {
+	if len(s.Headers) > maxHeaders {
+		return fmt.Errorf("%w: at most %d headers per source", ErrInvalidInput, maxHeaders)
+	}
+	for name, value := range s.Headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("%w: invalid header name %q", ErrInvalidInput, name)
+		}
+		if len(value) > maxHeaderLen || !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("%w: invalid value for header %q", ErrInvalidInput, name)
+		}
+	}
+	if a := s.BasicAuth; a != nil && a.Username != "" {
+		if strings.Contains(a.Username, ":") {
+			return fmt.Errorf("%w: basic_auth username must not contain ':'", ErrInvalidInput)
+		}
+		if len(a.Username) > maxHeaderLen || len(a.Password) > maxHeaderLen {
+			return fmt.Errorf("%w: basic_auth credentials exceed %d characters", ErrInvalidInput, maxHeaderLen)
+		}
+	}
+	return nil
+}
+
+// validateQuestion validates a tracked question's follow depth and
+// notification rules.
+func validateQuestion(q *TrackedQuestion) error {
+	if err := validateFollowDepth(q.FollowDepth); err != nil {
+		return err
+	}
+	if q.NotifyMinResults < 0 {
+		return fmt.Errorf("%w: notify_min_results must be >= 0", ErrInvalidInput)
+	}
+	if q.NotifyPattern == "" {
+		return nil
+	}
+	if len(q.NotifyPattern) > maxPatternLen {
+		return fmt.Errorf("%w: notify_pattern exceeds %d characters", ErrInvalidInput, maxPatternLen)
+	}
+	if _, err := regexp.Compile(q.NotifyPattern); err != nil {
+		return fmt.Errorf("%w: notify_pattern: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// validateFollowDepth checks a link follow depth (web source config_json or
+// tracked question).
+func validateFollowDepth(depth int) error {
+	if depth < 0 || depth > crawl.MaxDepth {
+		return fmt.Errorf("%w: follow_depth must be between 0 and %d", ErrInvalidInput, crawl.MaxDepth)
+	}
 	return nil
 }
@@ -151,3 +151,54 @@ func TestValidateSourceInput_DefaultsNotValidated(t *testing.T) {
 		t.Errorf("empty source_type should fail: got %v", err)
 	}
 }
+
+func TestValidateSourceInput_RSSTitleField(t *testing.T) {
+	// WHAT: RSS title_field must name a known feed entry field.
+	// WHY: A typo would silently index the wrong label and degrade search.
+	ok := &Source{Name: "Feed", URL: "https://example.com/rss", SourceType: "rss", FetchInterval: 3600000,
+		ConfigJSON: `{"title_field":"description"}`}
+	if err := validateSourceInput(ok); err != nil {
+		t.Errorf("description should be accepted: %v", err)
+	}
+	bad := &Source{Name: "Feed", URL: "https://example.com/rss", SourceType: "rss", FetchInterval: 3600000,
+		ConfigJSON: `{"title_field":"summary"}`}
+	if err := validateSourceInput(bad); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got: %v", err)
+	}
+}
+
+func TestValidateSourceInput_APIJSONPath(t *testing.T) {
+	// WHAT: API item_path/field paths must compile, and field paths need item_path.
+	// WHY: A broken selector would otherwise only show up as fetch errors later.
+	ok := &Source{Name: "API", URL: "https://example.com/api", SourceType: "api", FetchInterval: 3600000,
+		ConfigJSON: `{"item_path":"$.data.items[*]","title_path":"$.name","body_path":"$.summary"}`}
+	if err := validateSourceInput(ok); err != nil {
+		t.Errorf("valid jsonpath config rejected: %v", err)
+	}
+	for _, cfg := range []string{
+		`{"item_path":"$.data.items[*","title_path":"$.name"}`,
+		`{"item_path":"$.data","title_path":"$..name"}`,
+		`{"result_path":"data","title_path":"$.name"}`,
+	} {
+		bad := &Source{Name: "API", URL: "https://example.com/api", SourceType: "api", FetchInterval: 3600000,
+			ConfigJSON: cfg}
+		if err := validateSourceInput(bad); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: expected ErrInvalidInput, got: %v", cfg, err)
+		}
+	}
+}
+
+func TestValidateSourceInput_SitemapMaxURLs(t *testing.T) {
+	// WHAT: sitemap sources are accepted and max_urls is bounded.
+	// WHY: An unbounded max_urls would let one source fan out into unlimited fetches.
+	ok := &Source{Name: "Site", URL: "https://example.com/sitemap.xml", SourceType: "sitemap", FetchInterval: 3600000,
+		ConfigJSON: `{"max_urls":200}`}
+	if err := validateSourceInput(ok); err != nil {
+		t.Errorf("sitemap source rejected: %v", err)
+	}
+	bad := &Source{Name: "Site", URL: "https://example.com/sitemap.xml", SourceType: "sitemap", FetchInterval: 3600000,
+		ConfigJSON: `{"max_urls":100000}`}
+	if err := validateSourceInput(bad); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got: %v", err)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hazyhaar/chrc/veille/internal/buffer"
 	"github.com/hazyhaar/chrc/veille/internal/fetch"
@@ -46,8 +49,27 @@ type Service struct {
 	catalogDB    *sql.DB              // optional — global engine/source catalog
 	audit        audit.Logger          // optional — audit trail
 	urlValidator func(string) error    // URL validation (default: horosafe.ValidateURL)
+	metrics      Metrics               // optional — operational metrics
+	notifier     func(ctx context.Context, n QuestionNotification) // optional — question run notifications
+	digestSender DigestSender                                      // optional — channel digests
+
+	postProcessors []namedPostProcessor // optional — extraction enrichment, in registration order
+	extractors     map[string]Extractor // optional — custom extractors by source or media type
+	content        *store.ContentStore  // set when Config.DedupContent — shared extraction bodies
+	quotas         *store.QuotaStore    // set with WithCatalogDB — per-dossier quota overrides
+	vectors        bool                 // router exposes embed + vector services, see Related
+	buffer         *buffer.Writer       // set when Config.BufferDir, closed by Close
+
+	lifeMu  sync.Mutex
+	cancel  context.CancelFunc // stops the loops launched by Start
+	closing bool               // set by Close: no new job starts
+	loops   sync.WaitGroup     // scheduler, sweeper, purger, digester
+	jobs    sync.WaitGroup     // in-flight pipeline jobs (processJob, FetchNow, RunQuestionNow)
 }
 
+// ErrClosed is returned by fetch operations once Close has been called.
+var ErrClosed = errors.New("veille: service closed")
+
 // New creates a veille Service.
 // If router is non-nil, ConnectivityBridge handlers are auto-discovered.
 func New(pool PoolResolver, cfg *Config, logger *slog.Logger, opts ...ServiceOption) (*Service, error) {
@@ -59,6 +81,11 @@ func New(pool PoolResolver, cfg *Config, logger *slog.Logger, opts ...ServiceOpt
 		logger = slog.Default()
 	}
 
+	if cfg.Fetch.Proxy != "" {
+		if _, err := fetch.ParseProxyURL(cfg.Fetch.Proxy); err != nil {
+			return nil, fmt.Errorf("veille: fetch %w", err)
+		}
+	}
 	f := fetch.New(cfg.Fetch)
 	p := pipeline.New(f, logger)
 
@@ -85,6 +112,7 @@ func New(pool PoolResolver, cfg *Config, logger *slog.Logger, opts ...ServiceOpt
 		newID:        idgen.New,
 		urlValidator: horosafe.ValidateURL,
 		sourceTypes:  types,
+		buffer:       buf,
 	}
 
 	// Apply options.
@@ -92,6 +120,52 @@ func New(pool PoolResolver, cfg *Config, logger *slog.Logger, opts ...ServiceOpt
 		opt(svc)
 	}
 
+	// Per-dossier quota overrides live in the catalog.
+	if svc.catalogDB != nil {
+		if err := store.ApplyQuotaSchema(svc.catalogDB); err != nil {
+			return nil, fmt.Errorf("veille: quota schema: %w", err)
+		}
+		svc.quotas = store.NewQuotaStore(svc.catalogDB)
+	}
+
+	// Shared content store for cross-dossier body dedup.
+	if cfg.DedupContent {
+		if svc.catalogDB == nil {
+			return nil, fmt.Errorf("veille: DedupContent requires WithCatalogDB")
+		}
+		if err := store.ApplyContentSchema(svc.catalogDB); err != nil {
+			return nil, fmt.Errorf("veille: content schema: %w", err)
+		}
+		svc.content = store.NewContentStore(svc.catalogDB)
+		p.SetContentStore(svc.content)
+	}
+
+	if cfg.RetainRawBodies {
+		p.SetRawBodyRetention(cfg.RawBodiesPerSource)
+	}
+	p.SetFollowLimits(cfg.followLimits())
+
+	switch cfg.SourceTypeMismatch {
+	case SourceTypeWarn, SourceTypeCorrect, SourceTypeReject:
+		p.SetTypeMismatchPolicy(cfg.SourceTypeMismatch)
+	default:
+		return nil, fmt.Errorf("veille: unknown SourceTypeMismatch %q", cfg.SourceTypeMismatch)
+	}
+
+	// Custom extractors; a new source type is fetched by the web handler.
+	for key, x := range svc.extractors {
+		p.RegisterExtractor(key, x)
+		if !strings.Contains(key, "/") {
+			svc.sourceTypes[key] = true
+		}
+	}
+
+	// Register post-processors before any handler can store extractions.
+	p.SetPostProcessTimeout(cfg.PostProcessTimeout)
+	for _, np := range svc.postProcessors {
+		p.RegisterPostProcessor(np.name, np.pp)
+	}
+
 	// Wire question handler: the runner needs store access via a closure.
 	engineLookup := func(ctx context.Context, id string) (*search.Engine, error) {
 		return svc.lookupSearchEngine(ctx, id)
@@ -100,6 +174,10 @@ func New(pool PoolResolver, cfg *Config, logger *slog.Logger, opts ...ServiceOpt
 		Engines: engineLookup,
 		Fetcher: f,
 		Buffer:  buf,
+		Notify:  svc.notifier,
+		Enrich:  p.PostProcess,
+		Content: svc.content,
+		Follow:  svc.config.followLimits(),
 		Logger:  logger,
 		NewID:   idgen.New,
 	})
@@ -110,6 +188,12 @@ func New(pool PoolResolver, cfg *Config, logger *slog.Logger, opts ...ServiceOpt
 		pipeline.DiscoverHandlers(p, svc.router)
 	}
 
+	// Embed stored extractions when the router exposes an embedder and a vector index.
+	if vectorsAvailable(svc.router) {
+		svc.vectors = true
+		p.SetIndexer(svc.indexExtraction)
+	}
+
 	// Sync all registered pipeline types into the validation set.
 	for _, t := range p.RegisteredTypes() {
 		svc.sourceTypes[t] = true
@@ -123,14 +207,26 @@ func New(pool PoolResolver, cfg *Config, logger *slog.Logger, opts ...ServiceOpt
 		return svc.listActiveShards(ctx)
 	}
 	sink := func(ctx context.Context, job *scheduler.Job) error {
-		return svc.processJob(ctx, job)
+		// A started job runs to completion on Close (bounded by the fetch
+		// timeout); only jobs not yet started are dropped.
+		return svc.processJob(context.WithoutCancel(ctx), job)
 	}
 	svc.scheduler = scheduler.New(resolve, list, sink, cfg.Scheduler, logger)
 
+	// Instrument scheduler and pipeline if a metrics sink is set.
+	if svc.metrics != nil {
+		p.SetMetrics(svc.metrics)
+		svc.scheduler.SetMetrics(svc.metrics)
+	}
+
 	// Create sweeper for periodic probe of broken sources.
 	svc.sweeper = repair.NewSweeper(pool, func(ctx context.Context) ([]string, error) {
 		return svc.listActiveShards(ctx)
 	}, logger, cfg.SweepInterval)
+	svc.sweeper.SetProbeBackoff(0, cfg.SweepMaxBackoff)
+	svc.sweeper.SetProbeHook(func(ctx context.Context, dossierID string, st *store.Store, r repair.SweepResult) {
+		svc.checkHealth(ctx, dossierID, st, r.SourceID)
+	})
 
 	return svc, nil
 }
@@ -153,6 +249,43 @@ func WithAudit(a audit.Logger) ServiceOption {
 	return func(svc *Service) { svc.audit = a }
 }
 
+// WithQuestionNotifier sets the callback fired after a tracked question run
+// whose new results meet the question's notification rules.
+func WithQuestionNotifier(fn func(ctx context.Context, n QuestionNotification)) ServiceOption {
+	return func(svc *Service) { svc.notifier = fn }
+}
+
+// WithPostProcessor registers a PostProcessor run on every new extraction
+// before storage. Processors run in registration order, each bounded by
+// Config.PostProcessTimeout.
+func WithPostProcessor(name string, pp PostProcessor) ServiceOption {
+	return func(svc *Service) {
+		svc.postProcessors = append(svc.postProcessors, namedPostProcessor{name: name, pp: pp})
+	}
+}
+
+// WithExtractor registers x for fetched pages of source type name ("web",
+// "sitemap", or a custom type) or, failing that, of media type name
+// ("application/pdf"). The source type wins over the media type; pages
+// matching neither use the built-in HTMLExtractor. A name without "/" that
+// is not a built-in type becomes a valid source type, fetched like web.
+// For rss sources, x applies to the pages followed with follow_links, not
+// to the feed itself.
+func WithExtractor(name string, x Extractor) ServiceOption {
+	return func(svc *Service) {
+		if svc.extractors == nil {
+			svc.extractors = make(map[string]Extractor)
+		}
+		svc.extractors[name] = x
+	}
+}
+
+// namedPostProcessor holds a PostProcessor until the pipeline is built.
+type namedPostProcessor struct {
+	name string
+	pp   PostProcessor
+}
+
 // WithURLValidator overrides the URL validation function (default: horosafe.ValidateURL).
 // Use in tests with httptest servers that listen on loopback addresses.
 func WithURLValidator(fn func(string) error) ServiceOption {
@@ -174,17 +307,82 @@ func (svc *Service) lookupSearchEngine(ctx context.Context, id string) (*search.
 	return nil, fmt.Errorf("engine lookup requires shard context (engine %q)", id)
 }
 
-// Start launches the background scheduler and sweeper. Non-blocking.
+// Start launches the background scheduler, sweeper, purger and, with
+// WithDigestSender, the digest loop. Non-blocking. Close stops them.
 func (svc *Service) Start(ctx context.Context) {
-	go svc.scheduler.Run(ctx)
+	svc.lifeMu.Lock()
+	defer svc.lifeMu.Unlock()
+	if svc.closing {
+		return
+	}
+	ctx, svc.cancel = context.WithCancel(ctx)
+	svc.goLoop(func() { svc.scheduler.Run(ctx) })
 	if svc.sweeper != nil {
-		go svc.sweeper.Run(ctx)
+		svc.goLoop(func() { svc.sweeper.Run(ctx) })
+	}
+	svc.goLoop(func() { svc.runPurger(ctx) })
+	if svc.digestSender != nil {
+		svc.goLoop(func() { svc.runDigester(ctx) })
 	}
 	svc.logger.Info("veille: started")
 }
 
-// Close shuts down the service.
+// goLoop runs fn in a goroutine tracked by Close.
+func (svc *Service) goLoop(fn func()) {
+	svc.loops.Add(1)
+	go func() {
+		defer svc.loops.Done()
+		fn()
+	}()
+}
+
+// beginJob registers an in-flight pipeline job; false once Close started.
+// Each true return must be paired with svc.jobs.Done.
+func (svc *Service) beginJob() bool {
+	svc.lifeMu.Lock()
+	defer svc.lifeMu.Unlock()
+	if svc.closing {
+		return false
+	}
+	svc.jobs.Add(1)
+	return true
+}
+
+// SchedulerStatus returns the scheduler's effective concurrency settings and
+// its in-flight jobs per dossier.
+func (svc *Service) SchedulerStatus() SchedulerStatus {
+	return svc.scheduler.Status()
+}
+
+// Close stops the background loops, waits up to Config.ShutdownTimeout for
+// in-flight fetch jobs to finish, then closes the buffer writer. Fetches
+// requested after Close return ErrClosed. Returns an error if jobs are still
+// running at the deadline; the buffer is then left open for them.
 func (svc *Service) Close() error {
+	svc.lifeMu.Lock()
+	svc.closing = true
+	if svc.cancel != nil {
+		svc.cancel()
+	}
+	svc.lifeMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		svc.loops.Wait()
+		svc.jobs.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(svc.config.ShutdownTimeout):
+		return fmt.Errorf("veille: close: in-flight jobs still running after %s", svc.config.ShutdownTimeout)
+	}
+
+	if svc.buffer != nil {
+		if err := svc.buffer.Close(); err != nil {
+			return fmt.Errorf("veille: close buffer: %w", err)
+		}
+	}
 	svc.logger.Info("veille: closed")
 	return nil
 }
@@ -198,14 +396,31 @@ func (svc *Service) resolveStore(ctx context.Context, dossierID string) (*store.
 	return store.NewStore(db), nil
 }
 
+type actorKey struct{}
+
+// WithActor attaches the authenticated user performing the call to ctx; the
+// audit entries of the mutations it triggers record that user. Set by the
+// auth layer in front of the service, like WithSearchScope.
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFrom returns the user set by WithActor, or "" (background jobs,
+// CLI commands, unauthenticated transports).
+func ActorFrom(ctx context.Context) string {
+	userID, _ := ctx.Value(actorKey{}).(string)
+	return userID
+}
+
 // auditLog emits an async audit entry if an audit logger is configured.
-func (svc *Service) auditLog(dossierID, action, params string) {
+// The entry's UserID is the actor of ctx; params carries the dossier_id.
+func (svc *Service) auditLog(ctx context.Context, action, params string) {
 	if svc.audit == nil {
 		return
 	}
 	svc.audit.LogAsync(&audit.Entry{
 		Action:     action,
-		UserID:     dossierID,
+		UserID:     ActorFrom(ctx),
 		Parameters: params,
 	})
 }
@@ -215,7 +430,7 @@ func (svc *Service) auditLog(dossierID, action, params string) {
 // validateSourceURL validates the URL of a source before insert or update.
 // Internal source types (question) use synthetic URLs that bypass SSRF checks.
 // Document sources are validated for path traversal.
-// All other sources are validated against SSRF.
+// All other sources are checked against the egress host lists, then SSRF.
 func (svc *Service) validateSourceURL(s *Source) error {
 	if s.URL == "" {
 		return nil
@@ -239,6 +454,17 @@ func (svc *Service) validateSourceURL(s *Source) error {
 		return nil
 	}
 
+	if err := svc.config.Fetch.CheckEgress(s.URL); err != nil {
+		return err
+	}
+	// A per-source proxy must not point at an internal host either.
+	if proxy := configProxy(s.ConfigJSON); proxy != "" && !svc.config.Fetch.AllowPrivateProxy {
+		if u, err := fetch.ParseProxyURL(proxy); err == nil {
+			if err := svc.urlValidator("http://" + u.Host); err != nil {
+				return err
+			}
+		}
+	}
 	// HTTP sources: validate against SSRF (private IPs, non-HTTP schemes).
 	return svc.urlValidator(s.URL)
 }
@@ -279,37 +505,80 @@ func (svc *Service) AddSource(ctx context.Context, dossierID string, s *Source)
 		return err
 	}
 
-	// Quota check.
+	// Quota check (catalog override, else MaxSourcesPerSpace).
 	count, err := st.CountSources(ctx)
 	if err != nil {
 		return fmt.Errorf("count sources: %w", err)
 	}
-	if count >= MaxSourcesPerSpace {
-		return fmt.Errorf("%w: maximum %d sources per space", ErrQuotaExceeded, MaxSourcesPerSpace)
+	limit, _, err := svc.maxSources(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return fmt.Errorf("%w: maximum %d sources for this dossier", ErrQuotaExceeded, limit)
 	}
 
-	// Dedup check.
+	// Dedup check (soft-deleted sources still own their URL until purged).
 	existing, _ := st.GetSourceByURL(ctx, s.URL)
 	if existing != nil {
+		if existing.DeletedAt != nil {
+			return fmt.Errorf("%w: %s (deleted source %s can be restored)", ErrDuplicateSource, s.URL, existing.ID)
+		}
 		return fmt.Errorf("%w: %s", ErrDuplicateSource, s.URL)
 	}
 
 	if err := st.InsertSource(ctx, s); err != nil {
 		return err
 	}
-	svc.auditLog(dossierID, "add_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q,"url":%q,"type":%q}`, dossierID, s.ID, s.URL, s.SourceType))
+	svc.auditLog(ctx, "add_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q,"url":%q,"type":%q}`, dossierID, s.ID, s.URL, s.SourceType))
 	return nil
 }
 
-// ListSources returns all sources in a dossier.
-func (svc *Service) ListSources(ctx context.Context, dossierID string) ([]*Source, error) {
+// ListOpts filters ListSources, Search and extraction listings.
+type ListOpts struct {
+	Tag  string // only sources carrying this tag; "" = no filter
+	Lang string // only extractions detected as this language (fr, en, ..., und); "" = no filter
+
+	Pinned bool // only pinned extractions (ListDossierExtractions)
+}
+
+// ListSources returns all sources in a dossier, optionally filtered by tag.
+func (svc *Service) ListSources(ctx context.Context, dossierID string, opts ...ListOpts) ([]*Source, error) {
 	st, err := svc.resolveStore(ctx, dossierID)
 	if err != nil {
 		return nil, err
 	}
+	if tag := listTag(opts); tag != "" {
+		return st.ListSourcesByTag(ctx, tag)
+	}
 	return st.ListSources(ctx)
 }
 
+// ListTags returns the distinct tags used by the live sources of a dossier.
+func (svc *Service) ListTags(ctx context.Context, dossierID string) ([]string, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	return st.ListDistinctTags(ctx)
+}
+
+// listTag returns the normalized tag filter of opts, if any.
+func listTag(opts []ListOpts) string {
+	if len(opts) == 0 {
+		return ""
+	}
+	return normalizeTag(opts[0].Tag)
+}
+
+// listLang returns the normalized language filter of opts, if any.
+func listLang(opts []ListOpts) string {
+	if len(opts) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(opts[0].Lang))
+}
+
 // UpdateSource updates a source's mutable fields.
 func (svc *Service) UpdateSource(ctx context.Context, dossierID string, s *Source) error {
 	st, err := svc.resolveStore(ctx, dossierID)
@@ -323,7 +592,7 @@ func (svc *Service) UpdateSource(ctx context.Context, dossierID string, s *Sourc
 		return err
 	}
 	if existing == nil {
-		return fmt.Errorf("source not found: %s", s.ID)
+		return fmt.Errorf("%w: %s", ErrSourceNotFound, s.ID)
 	}
 
 	// Merge: use existing values for unset fields so validation passes.
@@ -339,6 +608,12 @@ func (svc *Service) UpdateSource(ctx context.Context, dossierID string, s *Sourc
 	if s.URL == "" {
 		s.URL = existing.URL
 	}
+	if s.Tags == nil {
+		s.Tags = existing.Tags
+	}
+	if s.ConfigJSON == "" {
+		s.ConfigJSON = existing.ConfigJSON // also keeps stored headers and basic auth
+	}
 
 	// Validate merged input.
 	if err := validateSourceInput(s, svc.sourceTypes); err != nil {
@@ -368,23 +643,113 @@ func (svc *Service) UpdateSource(ctx context.Context, dossierID string, s *Sourc
 	if err := st.UpdateSource(ctx, s); err != nil {
 		return err
 	}
-	svc.auditLog(dossierID, "update_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, s.ID))
+	svc.auditLog(ctx, "update_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, s.ID))
 	return nil
 }
 
-// DeleteSource removes a source and all its content.
+// DeleteSource soft-deletes a source: it is hidden from listings and no longer
+// scheduled, but its extractions are kept until RestoreSource or the retention purge.
 func (svc *Service) DeleteSource(ctx context.Context, dossierID, sourceID string) error {
 	st, err := svc.resolveStore(ctx, dossierID)
 	if err != nil {
 		return err
 	}
+	src, err := st.GetSource(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	if src == nil || src.DeletedAt != nil {
+		return fmt.Errorf("%w: %s", ErrSourceNotFound, sourceID)
+	}
+	if err := st.SoftDeleteSource(ctx, sourceID); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "delete_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
+	return nil
+}
+
+// PurgeSource hard-deletes a source, live or soft-deleted, with all its
+// content, without waiting for the retention purge. Not restorable.
+func (svc *Service) PurgeSource(ctx context.Context, dossierID, sourceID string) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	src, err := st.GetSource(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		return fmt.Errorf("%w: %s", ErrSourceNotFound, sourceID)
+	}
+	var refs []string
+	if svc.content != nil {
+		if refs, err = st.ContentRefs(ctx, sourceID); err != nil {
+			return err
+		}
+	}
 	if err := st.DeleteSource(ctx, sourceID); err != nil {
 		return err
 	}
-	svc.auditLog(dossierID, "delete_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
+	svc.releaseContent(ctx, dossierID, refs)
+	svc.auditLog(ctx, "purge_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
+	return nil
+}
+
+// RestoreSource brings back a soft-deleted source with its extractions intact.
+func (svc *Service) RestoreSource(ctx context.Context, dossierID, sourceID string) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	src, err := st.GetSource(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	if src == nil || src.DeletedAt == nil {
+		return fmt.Errorf("%w: no deleted source %s", ErrSourceNotFound, sourceID)
+	}
+	if err := st.RestoreSource(ctx, sourceID); err != nil {
+		return err
+	}
+	svc.auditLog(ctx, "restore_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
 	return nil
 }
 
+// ListDeletedSources returns the soft-deleted (restorable) sources of a dossier.
+func (svc *Service) ListDeletedSources(ctx context.Context, dossierID string) ([]*Source, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	return st.ListDeletedSources(ctx)
+}
+
+// PurgeDeletedSources hard-deletes sources soft-deleted longer than
+// Config.SourceRetention ago, with all their content. Returns the purge count.
+func (svc *Service) PurgeDeletedSources(ctx context.Context, dossierID string) (int64, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-svc.config.SourceRetention).UnixMilli()
+	var refs []string
+	if svc.content != nil {
+		if refs, err = st.PurgeableContentRefs(ctx, cutoff); err != nil {
+			return 0, err
+		}
+	}
+	n, err := st.PurgeDeletedSources(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	svc.releaseContent(ctx, dossierID, refs)
+	if n > 0 {
+		svc.auditLog(ctx, "purge_sources", fmt.Sprintf(`{"dossier_id":%q,"purged":%d}`, dossierID, n))
+	}
+	return n, nil
+}
+
 // FetchNow triggers an immediate fetch for a source.
 func (svc *Service) FetchNow(ctx context.Context, dossierID, sourceID string) error {
 	st, err := svc.resolveStore(ctx, dossierID)
@@ -396,20 +761,29 @@ func (svc *Service) FetchNow(ctx context.Context, dossierID, sourceID string) er
 		return err
 	}
 	if src == nil {
-		return fmt.Errorf("source not found: %s", sourceID)
+		return fmt.Errorf("%w: %s", ErrSourceNotFound, sourceID)
 	}
-	svc.auditLog(dossierID, "fetch_now", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
-	return svc.pipeline.HandleJob(ctx, st, &pipeline.Job{
+	if !svc.beginJob() {
+		return ErrClosed
+	}
+	defer svc.jobs.Done()
+	svc.auditLog(ctx, "fetch_now", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
+	err = svc.pipeline.HandleJob(ctx, st, &pipeline.Job{
 		DossierID: dossierID,
 		SourceID:  sourceID,
 		URL:       src.URL,
 	})
+	svc.checkHealth(ctx, dossierID, st, sourceID)
+	return err
 }
 
 // --- Questions ---
 
 // AddQuestion adds a tracked question and creates its backing source.
 func (svc *Service) AddQuestion(ctx context.Context, dossierID string, q *TrackedQuestion) error {
+	if err := validateQuestion(q); err != nil {
+		return err
+	}
 	if q.ID == "" {
 		q.ID = svc.newID()
 	}
@@ -441,7 +815,7 @@ func (svc *Service) AddQuestion(ctx context.Context, dossierID string, q *Tracke
 	if err := st.InsertSource(ctx, src); err != nil {
 		return err
 	}
-	svc.auditLog(dossierID, "add_question", fmt.Sprintf(`{"dossier_id":%q,"question_id":%q}`, dossierID, q.ID))
+	svc.auditLog(ctx, "add_question", fmt.Sprintf(`{"dossier_id":%q,"question_id":%q}`, dossierID, q.ID))
 	return nil
 }
 
@@ -456,6 +830,9 @@ func (svc *Service) ListQuestions(ctx context.Context, dossierID string) ([]*Tra
 
 // UpdateQuestion updates a tracked question and syncs the backing source.
 func (svc *Service) UpdateQuestion(ctx context.Context, dossierID string, q *TrackedQuestion) error {
+	if err := validateQuestion(q); err != nil {
+		return err
+	}
 	st, err := svc.resolveStore(ctx, dossierID)
 	if err != nil {
 		return err
@@ -485,10 +862,17 @@ func (svc *Service) DeleteQuestion(ctx context.Context, dossierID, questionID st
 	if err := st.DeleteQuestion(ctx, questionID); err != nil {
 		return err
 	}
+	var refs []string
+	if svc.content != nil {
+		if refs, err = st.ContentRefs(ctx, questionID); err != nil {
+			return err
+		}
+	}
 	if err := st.DeleteSource(ctx, questionID); err != nil {
 		return err
 	}
-	svc.auditLog(dossierID, "delete_question", fmt.Sprintf(`{"dossier_id":%q,"question_id":%q}`, dossierID, questionID))
+	svc.releaseContent(ctx, dossierID, refs)
+	svc.auditLog(ctx, "delete_question", fmt.Sprintf(`{"dossier_id":%q,"question_id":%q}`, dossierID, questionID))
 	return nil
 }
 
@@ -526,15 +910,18 @@ func (svc *Service) RunQuestionNow(ctx context.Context, dossierID, questionID st
 		return storeEngineToSearch(se), nil
 	}
 
-	var buf *buffer.Writer
-	if svc.config.BufferDir != "" {
-		buf = buffer.NewWriter(svc.config.BufferDir)
+	if !svc.beginJob() {
+		return 0, ErrClosed
 	}
-
+	defer svc.jobs.Done()
 	runner := question.NewRunner(question.Config{
 		Engines: engineLookup,
 		Fetcher: svc.fetcher,
-		Buffer:  buf,
+		Buffer:  svc.buffer,
+		Notify:  svc.notifier,
+		Enrich:  svc.pipeline.PostProcess,
+		Content: svc.content,
+		Follow:  svc.config.followLimits(),
 		Logger:  svc.logger,
 		NewID:   idgen.New,
 	})
@@ -572,21 +959,131 @@ func storeEngineToSearch(se *store.SearchEngine) *search.Engine {
 // --- Read operations ---
 
 // Search performs FTS5 search on extractions.
-func (svc *Service) Search(ctx context.Context, dossierID, query string, limit int) ([]*SearchResult, error) {
+func (svc *Service) Search(ctx context.Context, dossierID, query string, limit int, opts ...ListOpts) ([]*SearchResult, error) {
 	st, err := svc.resolveStore(ctx, dossierID)
 	if err != nil {
 		return nil, err
 	}
-	return st.Search(ctx, query, limit)
+	results, err := st.SearchFiltered(ctx, query, listTag(opts), listLang(opts), limit)
+	if err != nil || svc.content == nil {
+		return results, err
+	}
+	return results, store.HydrateSearchResults(ctx, svc.content, results)
 }
 
-// ListExtractions returns extractions for a source.
-func (svc *Service) ListExtractions(ctx context.Context, dossierID, sourceID string, limit int) ([]*Extraction, error) {
+// ListExtractions returns extractions for a source, optionally filtered by language.
+func (svc *Service) ListExtractions(ctx context.Context, dossierID, sourceID string, limit int, opts ...ListOpts) ([]*Extraction, error) {
 	st, err := svc.resolveStore(ctx, dossierID)
 	if err != nil {
 		return nil, err
 	}
-	return st.ListExtractions(ctx, sourceID, limit)
+	exts, err := st.ListExtractionsLang(ctx, sourceID, listLang(opts), limit)
+	if err != nil || svc.content == nil {
+		return exts, err
+	}
+	return exts, store.HydrateContent(ctx, svc.content, exts)
+}
+
+// ListExtractionsPage is ListExtractions paginated by an opaque cursor: ""
+// for the first page or the nextCursor of the previous call; nextCursor is
+// "" on the last page. limit defaults to 50, at most 200.
+func (svc *Service) ListExtractionsPage(ctx context.Context, dossierID, sourceID string, limit int, cursor string, opts ...ListOpts) ([]*Extraction, string, error) {
+	limit = pageLimit(limit)
+	beforeAt, beforeID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, "", err
+	}
+	exts, err := st.ListExtractionsPage(ctx, sourceID, listLang(opts), limit, beforeAt, beforeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if svc.content != nil {
+		if err := store.HydrateContent(ctx, svc.content, exts); err != nil {
+			return nil, "", err
+		}
+	}
+	var next string
+	if len(exts) == limit {
+		last := exts[len(exts)-1]
+		next = encodeCursor(last.ExtractedAt, last.ID)
+	}
+	return exts, next, nil
+}
+
+// ListDossierExtractions returns extractions across all sources of a dossier,
+// newest first, with the source name and type. cursor is "" for the first
+// page or the nextCursor of the previous call; nextCursor is "" on the last page.
+// opts may restrict the listing to one language or to pinned extractions.
+func (svc *Service) ListDossierExtractions(ctx context.Context, dossierID string, limit int, cursor string, opts ...ListOpts) ([]*DossierExtraction, string, error) {
+	limit = pageLimit(limit)
+	beforeAt, beforeID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, "", err
+	}
+	pinnedOnly := len(opts) > 0 && opts[0].Pinned
+	items, err := st.ListDossierExtractions(ctx, limit, beforeAt, beforeID, listLang(opts), pinnedOnly)
+	if err != nil {
+		return nil, "", err
+	}
+	if svc.content != nil {
+		exts := make([]*Extraction, len(items))
+		for i, d := range items {
+			exts[i] = &d.Extraction
+		}
+		if err := store.HydrateContent(ctx, svc.content, exts); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var next string
+	if len(items) == limit {
+		last := items[len(items)-1]
+		next = encodeCursor(last.ExtractedAt, last.ID)
+	}
+	return items, next, nil
+}
+
+// DossierContentRefs returns the references a dossier's extractions hold on
+// shared bodies. Read them before deleting the dossier's shard, then pass
+// them to ReleaseContent. Returns nil without DedupContent.
+func (svc *Service) DossierContentRefs(ctx context.Context, dossierID string) ([]string, error) {
+	if svc.content == nil {
+		return nil, nil
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	return st.ContentRefs(ctx)
+}
+
+// ReleaseContent drops shared body references whose extractions were deleted
+// and removes blobs no longer referenced.
+func (svc *Service) ReleaseContent(ctx context.Context, refs []string) error {
+	if svc.content == nil {
+		return nil
+	}
+	return svc.content.Release(ctx, refs...)
+}
+
+// releaseContent drops shared body references after their extractions were
+// deleted. A failure only leaves blobs behind, so it is logged, not returned.
+func (svc *Service) releaseContent(ctx context.Context, dossierID string, refs []string) {
+	if svc.content == nil || len(refs) == 0 {
+		return
+	}
+	if err := svc.content.Release(ctx, refs...); err != nil {
+		svc.logger.Warn("veille: release shared content", "dossier_id", dossierID, "refs", len(refs), "error", err)
+	}
 }
 
 // Stats returns aggregate counters for a dossier.
@@ -598,6 +1095,69 @@ func (svc *Service) Stats(ctx context.Context, dossierID string) (*SpaceStats, e
 	return st.Stats(ctx)
 }
 
+// LogResultOpen records that an extraction was opened from a result list,
+// with the originating query (may be empty). The most-opened extractions
+// appear in Stats. Returns ErrExtractionNotFound if the extraction is not
+// in the dossier.
+func (svc *Service) LogResultOpen(ctx context.Context, dossierID, extractionID, query string) error {
+	query = strings.TrimSpace(query)
+	if len(query) > maxQueryLen {
+		return fmt.Errorf("%w: query exceeds %d bytes", ErrInvalidInput, maxQueryLen)
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	ok, err := st.LogResultOpen(ctx, extractionID, query)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrExtractionNotFound
+	}
+	return nil
+}
+
+// SetPinned pins or unpins an extraction. Pinned extractions survive
+// retention pruning and can be listed alone (ListOpts.Pinned). Returns
+// ErrExtractionNotFound if the extraction is not in the dossier.
+func (svc *Service) SetPinned(ctx context.Context, dossierID, extractionID string, pinned bool) error {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return err
+	}
+	ok, err := st.SetExtractionPinned(ctx, extractionID, pinned)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrExtractionNotFound
+	}
+	action := "pin_extraction"
+	if !pinned {
+		action = "unpin_extraction"
+	}
+	svc.auditLog(ctx, action, fmt.Sprintf(`{"dossier_id":%q,"extraction_id":%q}`, dossierID, extractionID))
+	return nil
+}
+
+// ListLinks returns the outbound links of an extraction, in page order.
+// Returns ErrExtractionNotFound if the extraction is not in the dossier.
+func (svc *Service) ListLinks(ctx context.Context, dossierID, extractionID string) ([]string, error) {
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := st.GetExtraction(ctx, extractionID)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrExtractionNotFound
+	}
+	return st.ListLinks(ctx, extractionID)
+}
+
 // FetchHistory returns fetch log entries for a source.
 func (svc *Service) FetchHistory(ctx context.Context, dossierID, sourceID string, limit int) ([]*FetchLogEntry, error) {
 	st, err := svc.resolveStore(ctx, dossierID)
@@ -607,6 +1167,30 @@ func (svc *Service) FetchHistory(ctx context.Context, dossierID, sourceID string
 	return st.FetchHistory(ctx, sourceID, limit)
 }
 
+// FetchHistoryPage is FetchHistory paginated by an opaque cursor, like
+// ListExtractionsPage.
+func (svc *Service) FetchHistoryPage(ctx context.Context, dossierID, sourceID string, limit int, cursor string) ([]*FetchLogEntry, string, error) {
+	limit = pageLimit(limit)
+	beforeAt, beforeID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	st, err := svc.resolveStore(ctx, dossierID)
+	if err != nil {
+		return nil, "", err
+	}
+	entries, err := st.FetchHistoryPage(ctx, sourceID, limit, beforeAt, beforeID)
+	if err != nil {
+		return nil, "", err
+	}
+	var next string
+	if len(entries) == limit {
+		last := entries[len(entries)-1]
+		next = encodeCursor(last.FetchedAt, last.ID)
+	}
+	return entries, next, nil
+}
+
 // SearchLog returns recent search log entries for a dossier.
 func (svc *Service) SearchLog(ctx context.Context, dossierID string, limit int) ([]SearchLogEntry, error) {
 	st, err := svc.resolveStore(ctx, dossierID)
@@ -660,6 +1244,45 @@ func lookupGlobalEngine(ctx context.Context, catalogDB *sql.DB, id string) (*sea
 	return e, nil
 }
 
+// --- Admin: schema migrations ---
+
+// MigrationStatus reports the schema migrations ApplySchema would run on one shard.
+// Error is set when the shard cannot be opened or the dry run fails.
+type MigrationStatus struct {
+	DossierID string   `json:"dossier_id"`
+	Pending   []string `json:"pending"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// CheckShardMigrations reports pending migrations for every active shard
+// without applying them. Shards that are up to date have an empty Pending list.
+func (svc *Service) CheckShardMigrations(ctx context.Context) ([]MigrationStatus, error) {
+	dossierIDs, err := svc.listActiveShards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]MigrationStatus, 0, len(dossierIDs))
+	for _, dossierID := range dossierIDs {
+		status := MigrationStatus{DossierID: dossierID, Pending: []string{}}
+		db, err := svc.pool.Resolve(ctx, dossierID)
+		if err != nil {
+			status.Error = err.Error()
+			results = append(results, status)
+			continue
+		}
+		pending, err := store.CheckMigrations(ctx, db)
+		if pending != nil {
+			status.Pending = pending
+		}
+		if err != nil {
+			status.Error = err.Error()
+		}
+		results = append(results, status)
+	}
+	return results, nil
+}
+
 // --- Admin: source health ---
 
 // SourceHealth is a broken source with its dossier context.
@@ -714,13 +1337,17 @@ func (svc *Service) ResetSource(ctx context.Context, dossierID, sourceID string)
 	if err := st.ResetSource(ctx, sourceID); err != nil {
 		return err
 	}
-	svc.auditLog(dossierID, "reset_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
+	svc.auditLog(ctx, "reset_source", fmt.Sprintf(`{"dossier_id":%q,"source_id":%q}`, dossierID, sourceID))
 	return nil
 }
 
 // --- Internal ---
 
 func (svc *Service) processJob(ctx context.Context, job *scheduler.Job) error {
+	if !svc.beginJob() {
+		return ErrClosed
+	}
+	defer svc.jobs.Done()
 	st, err := svc.resolveStore(ctx, job.DossierID)
 	if err != nil {
 		return err
@@ -742,9 +1369,46 @@ func (svc *Service) processJob(ctx context.Context, job *scheduler.Job) error {
 			}
 		}
 	}
+	svc.checkHealth(ctx, job.DossierID, st, job.SourceID)
 	return pipeErr
 }
 
+// runPurger periodically purges soft-deleted sources past retention and
+// prunes extractions outside dossier retention policies. Blocks until ctx is cancelled.
+func (svc *Service) runPurger(ctx context.Context) {
+	ticker := time.NewTicker(svc.config.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dossierIDs, err := svc.listActiveShards(ctx)
+			if err != nil {
+				svc.logger.Warn("purger: list shards", "error", err)
+				continue
+			}
+			for _, dossierID := range dossierIDs {
+				n, err := svc.PurgeDeletedSources(ctx, dossierID)
+				if err != nil {
+					svc.logger.Warn("purger: purge", "dossier_id", dossierID, "error", err)
+					continue
+				}
+				if n > 0 {
+					svc.logger.Info("purger: purged deleted sources", "dossier_id", dossierID, "count", n)
+				}
+				pruned, err := svc.PruneExtractions(ctx, dossierID)
+				if err != nil {
+					svc.logger.Warn("purger: prune extractions", "dossier_id", dossierID, "error", err)
+				}
+				if pruned > 0 {
+					svc.logger.Info("purger: pruned extractions", "dossier_id", dossierID, "count", pruned)
+				}
+			}
+		}
+	}
+}
+
 func (svc *Service) listActiveShards(ctx context.Context) ([]string, error) {
 	if svc.catalogDB == nil {
 		return nil, nil